@@ -59,6 +59,11 @@ func main() {
 	}
 	handlers := handler.NewHandlers(httpServer, services)
 
+	// Start job server once the services have registered their task handlers
+	if err := httpServer.Job.Start(); err != nil {
+		log.Fatal().Err(err).Msg("failed to start job server")
+	}
+
 	// Initialize router
 	r := router.NewRouter(httpServer, handlers, services)
 
@@ -30,6 +30,8 @@ type ErrorResponse struct {
 	Errors []FieldError `json:"errors"`
 	// action to be taken
 	Action *Action `json:"action"`
+	// current server state for conflict responses
+	Data any `json:"data,omitempty"`
 }
 
 func (e *ErrorResponse) Error() string {
@@ -50,6 +52,7 @@ func (e *ErrorResponse) WithMessage(message string) *ErrorResponse {
 		Override: e.Override,
 		Errors:   e.Errors,
 		Action:   e.Action,
+		Data:     e.Data,
 	}
 }
 
@@ -42,6 +42,35 @@ func NewNotFoundError(message string, override bool) *ErrorResponse {
 	}
 }
 
+func NewConflictError(message string, override bool, data any) *ErrorResponse {
+	return &ErrorResponse{
+		Message:  message,
+		Status:   http.StatusConflict,
+		Override: override,
+		Success:  false,
+		Data:     data,
+	}
+}
+
+func NewPreconditionFailedError(message string, override bool, data any) *ErrorResponse {
+	return &ErrorResponse{
+		Message:  message,
+		Status:   http.StatusPreconditionFailed,
+		Override: override,
+		Success:  false,
+		Data:     data,
+	}
+}
+
+func NewServiceUnavailableError(message string, override bool) *ErrorResponse {
+	return &ErrorResponse{
+		Message:  message,
+		Status:   http.StatusServiceUnavailable,
+		Override: override,
+		Success:  false,
+	}
+}
+
 func NewInternalServerError() *ErrorResponse {
 	return &ErrorResponse{
 		Message:  http.StatusText(http.StatusInternalServerError),
@@ -0,0 +1,72 @@
+package application
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/cache"
+)
+
+const accountAccessCacheKeyPrefix = "account_access:"
+
+// accountAccess is the part of a user that decides whether they may use the
+// API, cached so authenticated requests do not load the user every time.
+type accountAccess struct {
+	BannedAt       *time.Time `json:"bannedAt,omitempty"`
+	SuspendedAt    *time.Time `json:"suspendedAt,omitempty"`
+	SuspendedUntil *time.Time `json:"suspendedUntil,omitempty"`
+}
+
+func (a *accountAccess) user() *domain.User {
+	return &domain.User{BannedAt: a.BannedAt, SuspendedAt: a.SuspendedAt, SuspendedUntil: a.SuspendedUntil}
+}
+
+// AccountAccessCache caches the ban and suspension state of users. Moderation
+// evicts a user's entry whenever it changes, and a nil cache caches nothing.
+type AccountAccessCache struct {
+	cache cache.Cache
+}
+
+func NewAccountAccessCache(cacheClient cache.Cache) *AccountAccessCache {
+	if cacheClient == nil {
+		return nil
+	}
+	return &AccountAccessCache{cache: cacheClient}
+}
+
+func (c *AccountAccessCache) get(ctx context.Context, userID uuid.UUID) (*accountAccess, bool) {
+	if c == nil {
+		return nil, false
+	}
+
+	var access accountAccess
+	if err := c.cache.GetJSON(ctx, accountAccessCacheKey(userID), &access); err != nil {
+		return nil, false
+	}
+	return &access, true
+}
+
+func (c *AccountAccessCache) set(ctx context.Context, user *domain.User) {
+	if c == nil {
+		return
+	}
+
+	_ = c.cache.SetJSON(ctx, accountAccessCacheKey(user.ID), accountAccess{
+		BannedAt:       user.BannedAt,
+		SuspendedAt:    user.SuspendedAt,
+		SuspendedUntil: user.SuspendedUntil,
+	})
+}
+
+func (c *AccountAccessCache) evict(ctx context.Context, userID uuid.UUID) {
+	if c == nil {
+		return
+	}
+	_ = c.cache.Delete(ctx, accountAccessCacheKey(userID))
+}
+
+func accountAccessCacheKey(userID uuid.UUID) string {
+	return accountAccessCacheKeyPrefix + userID.String()
+}
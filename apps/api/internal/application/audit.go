@@ -0,0 +1,69 @@
+package application
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+)
+
+type AuditService interface {
+	// ListEvents returns the audit events matching input, newest first, and
+	// their total, or -1 when input.SkipCount is set.
+	ListEvents(ctx context.Context, input *applicationdto.ListAuditEventsInput) ([]domain.AuditEvent, int64, error)
+}
+
+type auditService struct {
+	repo port.AuditEventRepository
+}
+
+func NewAuditService(repo port.AuditEventRepository) AuditService {
+	return &auditService{repo: repo}
+}
+
+func (s *auditService) ListEvents(ctx context.Context, input *applicationdto.ListAuditEventsInput) ([]domain.AuditEvent, int64, error) {
+	if input == nil {
+		input = &applicationdto.ListAuditEventsInput{}
+	}
+	if input.From != nil && input.To != nil && !input.From.Before(*input.To) {
+		return nil, 0, errs.NewBadRequestError("from must be before to", true, nil, nil)
+	}
+
+	events, total, err := s.repo.List(ctx, port.AuditEventFilter{
+		ActorUserID: input.ActorUserID,
+		Action:      input.Action,
+		TargetType:  input.TargetType,
+		TargetID:    input.TargetID,
+		From:        input.From,
+		To:          input.To,
+		Limit:       input.Limit,
+		Offset:      input.Offset,
+		After:       input.After,
+		SkipCount:   input.SkipCount,
+	})
+	if err != nil {
+		return nil, 0, listingError(err)
+	}
+	return events, total, nil
+}
+
+// recordAuditEvent stores an audit event through repo, which callers bind to
+// the transaction of the action it records. An actorID of uuid.Nil records an
+// action the system took on its own.
+func recordAuditEvent(ctx context.Context, repo port.AuditEventRepository, actorID uuid.UUID, action domain.AuditAction, targetType domain.AuditTargetType, targetID *uuid.UUID, metadata map[string]any) error {
+	var actor *uuid.UUID
+	if actorID != uuid.Nil {
+		actor = &actorID
+	}
+	return repo.Store(ctx, &domain.AuditEvent{
+		ID:          uuid.New(),
+		ActorUserID: actor,
+		Action:      action,
+		TargetType:  targetType,
+		TargetID:    targetID,
+		Metadata:    metadata,
+	})
+}
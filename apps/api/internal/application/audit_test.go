@@ -0,0 +1,68 @@
+package application
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+type testAuditEventRepo struct {
+	events []domain.AuditEvent
+	filter port.AuditEventFilter
+}
+
+func (r *testAuditEventRepo) Store(ctx context.Context, event *domain.AuditEvent) error {
+	event.CreatedAt = time.Now().UTC()
+	r.events = append(r.events, *event)
+	return nil
+}
+
+func (r *testAuditEventRepo) List(ctx context.Context, filter port.AuditEventFilter) ([]domain.AuditEvent, int64, error) {
+	r.filter = filter
+	return r.events, int64(len(r.events)), nil
+}
+
+func TestAuditServiceListEvents_PassesFilter(t *testing.T) {
+	repo := &testAuditEventRepo{}
+	service := NewAuditService(repo)
+
+	actorID, targetID := uuid.New(), uuid.New()
+	from := time.Now().UTC().Add(-time.Hour)
+	to := from.Add(time.Hour)
+	_, _, err := service.ListEvents(context.Background(), &applicationdto.ListAuditEventsInput{
+		ActorUserID: &actorID,
+		Action:      domain.AuditActionShareTakenDown,
+		TargetType:  domain.AuditTargetTypeShare,
+		TargetID:    &targetID,
+		From:        &from,
+		To:          &to,
+		Limit:       10,
+		Offset:      20,
+	})
+	require.NoError(t, err)
+	require.Equal(t, port.AuditEventFilter{
+		ActorUserID: &actorID,
+		Action:      domain.AuditActionShareTakenDown,
+		TargetType:  domain.AuditTargetTypeShare,
+		TargetID:    &targetID,
+		From:        &from,
+		To:          &to,
+		Limit:       10,
+		Offset:      20,
+	}, repo.filter)
+}
+
+func TestAuditServiceListEvents_RejectsEmptyRange(t *testing.T) {
+	service := NewAuditService(&testAuditEventRepo{})
+
+	at := time.Now().UTC()
+	_, _, err := service.ListEvents(context.Background(), &applicationdto.ListAuditEventsInput{From: &at, To: &at})
+	requireHTTPStatus(t, err, http.StatusBadRequest)
+}
@@ -60,6 +60,7 @@ type googleStatePayload struct {
 type authService struct {
 	repo                 port.AuthRepository
 	sessionRepo          port.AuthSessionRepository
+	deviceRepo           port.DeviceRepository
 	verificationRepo     port.EmailVerificationRepository
 	taskEnqueuer         TaskEnqueuer
 	logger               *zerolog.Logger
@@ -144,9 +145,11 @@ type googleDeviceSession struct {
 	Status     GoogleDeviceAuthStatus
 	Result     *AuthResult
 	LastError  string
+	// ClientDevice is the device that started the flow; it completes in a browser.
+	ClientDevice *ClientDevice
 }
 
-func NewAuthService(cfg *config.AuthConfig, repo port.AuthRepository, sessionRepo port.AuthSessionRepository, verificationRepo port.EmailVerificationRepository, taskEnqueuer TaskEnqueuer, logger *zerolog.Logger) AuthService {
+func NewAuthService(cfg *config.AuthConfig, repo port.AuthRepository, sessionRepo port.AuthSessionRepository, deviceRepo port.DeviceRepository, verificationRepo port.EmailVerificationRepository, taskEnqueuer TaskEnqueuer, logger *zerolog.Logger) AuthService {
 	refreshTTL := cfg.RefreshTokenTTL
 	if refreshTTL <= 0 {
 		refreshTTL = 30 * 24 * time.Hour
@@ -163,6 +166,7 @@ func NewAuthService(cfg *config.AuthConfig, repo port.AuthRepository, sessionRep
 	return &authService{
 		repo:                 repo,
 		sessionRepo:          sessionRepo,
+		deviceRepo:           deviceRepo,
 		verificationRepo:     verificationRepo,
 		taskEnqueuer:         taskEnqueuer,
 		logger:               logger,
@@ -216,7 +220,7 @@ func (s *authService) Register(ctx context.Context, input applicationdto.Registe
 		return nil, errs.NewInternalServerError()
 	}
 
-	refreshToken, refreshExp, err := s.createSession(ctx, user, userAgent, ipAddress)
+	refreshToken, refreshExp, err := s.createSession(ctx, user, userAgent, ipAddress, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -246,6 +250,9 @@ func (s *authService) Login(ctx context.Context, input applicationdto.LoginInput
 	}
 
 	now := time.Now().UTC()
+	if err := checkAccountAccess(user, now); err != nil {
+		return nil, err
+	}
 	_ = s.repo.UpdateLoginAt(ctx, user.ID, now)
 
 	token, exp, err := s.generateToken(user)
@@ -253,7 +260,7 @@ func (s *authService) Login(ctx context.Context, input applicationdto.LoginInput
 		return nil, errs.NewInternalServerError()
 	}
 
-	refreshToken, refreshExp, err := s.createSession(ctx, user, userAgent, ipAddress)
+	refreshToken, refreshExp, err := s.createSession(ctx, user, userAgent, ipAddress, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -360,6 +367,9 @@ func (s *authService) loginWithGoogleClaims(ctx context.Context, subject, emailC
 	}
 
 	now := time.Now().UTC()
+	if err := checkAccountAccess(user, now); err != nil {
+		return nil, err
+	}
 	_ = s.repo.UpdateLoginAt(ctx, user.ID, now)
 	if user.EmailVerifiedAt == nil {
 		_ = s.repo.UpdateEmailVerifiedAt(ctx, user.ID, now)
@@ -371,7 +381,7 @@ func (s *authService) loginWithGoogleClaims(ctx context.Context, subject, emailC
 		return nil, errs.NewInternalServerError()
 	}
 
-	refreshToken, refreshExp, err := s.createSession(ctx, user, userAgent, ipAddress)
+	refreshToken, refreshExp, err := s.createSession(ctx, user, userAgent, ipAddress, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -553,12 +563,18 @@ func (s *authService) Refresh(ctx context.Context, refreshToken, userAgent, ipAd
 	if err != nil {
 		return nil, sqlerr.HandleError(err)
 	}
+	if err := checkAccountAccess(user, now); err != nil {
+		return nil, err
+	}
+	if err := s.checkSessionDevice(ctx, session); err != nil {
+		return nil, err
+	}
 
 	if err := s.sessionRepo.RevokeByID(ctx, session.ID, now); err != nil {
 		return nil, sqlerr.HandleError(err)
 	}
 
-	rotatedToken, rotatedExp, err := s.createSession(ctx, user, userAgent, ipAddress)
+	rotatedToken, rotatedExp, err := s.createSession(ctx, user, userAgent, ipAddress, session.DeviceID)
 	if err != nil {
 		return nil, err
 	}
@@ -647,11 +663,22 @@ func (s *authService) generateToken(user *domain.User) (string, time.Time, error
 	return signed, exp, nil
 }
 
-func (s *authService) createSession(ctx context.Context, user *domain.User, userAgent, ipAddress string) (string, time.Time, error) {
+// createSession issues a refresh token for a new session of user. The session
+// belongs to the device the request came from, or to deviceID when the client
+// did not name one.
+func (s *authService) createSession(ctx context.Context, user *domain.User, userAgent, ipAddress string, deviceID *uuid.UUID) (string, time.Time, error) {
 	if s.sessionRepo == nil || user == nil {
 		return "", time.Time{}, errs.NewInternalServerError()
 	}
 
+	registered, err := s.registerDevice(ctx, user.ID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if registered != nil {
+		deviceID = registered
+	}
+
 	refreshToken, err := generateRefreshToken()
 	if err != nil {
 		return "", time.Time{}, errs.NewInternalServerError()
@@ -671,6 +698,7 @@ func (s *authService) createSession(ctx context.Context, user *domain.User, user
 
 	session := &domain.AuthSession{
 		UserID:           user.ID,
+		DeviceID:         deviceID,
 		RefreshTokenHash: hashRefreshToken(refreshToken),
 		UserAgent:        agent,
 		IPAddress:        ip,
@@ -684,6 +712,49 @@ func (s *authService) createSession(ctx context.Context, user *domain.User, user
 	return refreshToken, expiresAt, nil
 }
 
+// registerDevice records the device named by the request context against the
+// user and returns its id, or nil when the client sent no device. A revoked
+// device cannot sign in again.
+func (s *authService) registerDevice(ctx context.Context, userID uuid.UUID) (*uuid.UUID, error) {
+	clientDevice, ok := ClientDeviceFromContext(ctx)
+	if !ok || s.deviceRepo == nil {
+		return nil, nil
+	}
+
+	device := &domain.Device{
+		UserID:   userID,
+		ClientID: clientDevice.ClientID,
+		Name:     clientDevice.Name,
+	}
+	if err := s.deviceRepo.Upsert(ctx, device); err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+	if device.RevokedAt != nil {
+		return nil, errs.NewForbiddenError("this device has been revoked", true)
+	}
+	return &device.ID, nil
+}
+
+// checkSessionDevice rejects a session whose device was revoked after the
+// session was last checked, as a device is revoked before its sessions are.
+func (s *authService) checkSessionDevice(ctx context.Context, session *domain.AuthSession) error {
+	if session.DeviceID == nil || s.deviceRepo == nil {
+		return nil
+	}
+
+	device, err := s.deviceRepo.GetByUserAndID(ctx, session.UserID, *session.DeviceID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return sqlerr.HandleError(err)
+	}
+	if device.RevokedAt != nil {
+		return errs.NewUnauthorizedError("Unauthorized", false)
+	}
+	return nil
+}
+
 func deriveUsername(email string) string {
 	parts := regexp.MustCompile("@").Split(email, 2)
 	if len(parts) > 0 && parts[0] != "" {
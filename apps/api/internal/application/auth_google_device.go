@@ -29,13 +29,18 @@ func (s *authService) StartGoogleDeviceAuth(ctx context.Context) (*GoogleDeviceA
 	s.deviceStateMu.Lock()
 	defer s.deviceStateMu.Unlock()
 
-	s.cleanupDeviceSessionsLocked(now)
-	s.deviceAuthStates[deviceCode] = &googleDeviceSession{
+	session := &googleDeviceSession{
 		DeviceCode: deviceCode,
 		State:      state,
 		ExpiresAt:  expiresAt,
 		Status:     GoogleDeviceAuthPending,
 	}
+	if clientDevice, ok := ClientDeviceFromContext(ctx); ok {
+		session.ClientDevice = &clientDevice
+	}
+
+	s.cleanupDeviceSessionsLocked(now)
+	s.deviceAuthStates[deviceCode] = session
 	s.deviceStateIndex[state] = deviceCode
 
 	interval := int(s.devicePollInterval.Seconds())
@@ -63,6 +68,9 @@ func (s *authService) CompleteGoogleDeviceAuth(ctx context.Context, code, state,
 	if err != nil {
 		return err
 	}
+	if session.ClientDevice != nil {
+		ctx = WithClientDevice(ctx, *session.ClientDevice)
+	}
 
 	token, err := s.googleOAuthConfig.Exchange(ctx, code)
 	if err != nil {
@@ -41,10 +41,11 @@ type mockVerificationRepo struct {
 }
 
 type mockSessionRepo struct {
-	createFn         func(ctx context.Context, session *domain.AuthSession) error
-	getByHashFn      func(ctx context.Context, hash string) (*domain.AuthSession, error)
-	revokeByIDFn     func(ctx context.Context, id uuid.UUID, revokedAt time.Time) error
-	revokeByUserIDFn func(ctx context.Context, userID uuid.UUID, revokedAt time.Time) error
+	createFn           func(ctx context.Context, session *domain.AuthSession) error
+	getByHashFn        func(ctx context.Context, hash string) (*domain.AuthSession, error)
+	revokeByIDFn       func(ctx context.Context, id uuid.UUID, revokedAt time.Time) error
+	revokeByUserIDFn   func(ctx context.Context, userID uuid.UUID, revokedAt time.Time) error
+	revokeByDeviceIDFn func(ctx context.Context, deviceID uuid.UUID, revokedAt time.Time) error
 }
 
 type mockTaskEnqueuer struct {
@@ -188,6 +189,13 @@ func (m *mockSessionRepo) RevokeByUserID(ctx context.Context, userID uuid.UUID,
 	return nil
 }
 
+func (m *mockSessionRepo) RevokeByDeviceID(ctx context.Context, deviceID uuid.UUID, revokedAt time.Time) error {
+	if m.revokeByDeviceIDFn != nil {
+		return m.revokeByDeviceIDFn(ctx, deviceID, revokedAt)
+	}
+	return nil
+}
+
 // Ensures Register hashes passwords and returns a signed token tied to the user ID.
 func TestAuthServiceRegister_HashesPasswordAndReturnsToken(t *testing.T) {
 	secret := "test-secret"
@@ -214,7 +222,7 @@ func TestAuthServiceRegister_HashesPasswordAndReturnsToken(t *testing.T) {
 		},
 	}
 
-	svc := NewAuthService(&config.AuthConfig{SecretKey: secret, AccessTokenTTL: ttl}, repo, sessionRepo, nil, nil, nil)
+	svc := NewAuthService(&config.AuthConfig{SecretKey: secret, AccessTokenTTL: ttl}, repo, sessionRepo, nil, nil, nil, nil)
 
 	result, err := svc.Register(ctx, applicationdto.RegisterInput{
 		Email:    "user@example.com",
@@ -254,7 +262,7 @@ func TestAuthServiceRegister_ShortPassword(t *testing.T) {
 		},
 	}
 
-	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, repo, nil, nil, nil, nil)
+	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, repo, nil, nil, nil, nil, nil)
 
 	_, err := svc.Register(ctx, applicationdto.RegisterInput{
 		Email:    "user@example.com",
@@ -279,7 +287,7 @@ func TestAuthServiceLogin_UserNotFound(t *testing.T) {
 		},
 	}
 
-	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, repo, nil, nil, nil, nil)
+	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, repo, nil, nil, nil, nil, nil)
 
 	_, err := svc.Login(ctx, applicationdto.LoginInput{
 		Identifier: "user@example.com",
@@ -310,7 +318,7 @@ func TestAuthServiceLogin_PasswordMismatch(t *testing.T) {
 		},
 	}
 
-	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, repo, nil, nil, nil, nil)
+	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, repo, nil, nil, nil, nil, nil)
 
 	_, err = svc.Login(ctx, applicationdto.LoginInput{
 		Identifier: "user",
@@ -352,7 +360,7 @@ func TestAuthServiceLogin_Success(t *testing.T) {
 			return nil
 		},
 	}
-	svc := NewAuthService(&config.AuthConfig{SecretKey: secret, AccessTokenTTL: time.Minute}, repo, sessionRepo, nil, nil, nil)
+	svc := NewAuthService(&config.AuthConfig{SecretKey: secret, AccessTokenTTL: time.Minute}, repo, sessionRepo, nil, nil, nil, nil)
 
 	result, err := svc.Login(ctx, applicationdto.LoginInput{
 		Identifier: "user@example.com",
@@ -374,11 +382,123 @@ func TestAuthServiceLogin_Success(t *testing.T) {
 	require.False(t, claims.IsAdmin)
 }
 
+// Ensures Login rejects suspended users without issuing a session.
+func TestAuthServiceLogin_SuspendedUser(t *testing.T) {
+	ctx := context.Background()
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	require.NoError(t, err)
+
+	suspendedAt := time.Now().UTC()
+	until := suspendedAt.Add(time.Hour)
+	repo := &mockAuthRepo{
+		getByEmailFn: func(_ context.Context, email string) (*domain.User, error) {
+			return &domain.User{ID: uuid.New(), Email: email, PasswordHash: string(hash), SuspendedAt: &suspendedAt, SuspendedUntil: &until}, nil
+		},
+	}
+	created := false
+	sessionRepo := &mockSessionRepo{
+		createFn: func(_ context.Context, session *domain.AuthSession) error {
+			created = true
+			return nil
+		},
+	}
+	svc := NewAuthService(&config.AuthConfig{SecretKey: "test", AccessTokenTTL: time.Minute}, repo, sessionRepo, nil, nil, nil, nil)
+
+	_, err = svc.Login(ctx, applicationdto.LoginInput{
+		Identifier: "user@example.com",
+		Password:   "password123",
+	}, "agent", "127.0.0.1")
+	require.False(t, created)
+
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusForbidden, httpErr.Status)
+	require.Contains(t, httpErr.Message, until.Format(time.RFC3339))
+}
+
+// Ensures Login registers the requesting device and links the session to it.
+func TestAuthServiceLogin_LinksSessionToDevice(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	require.NoError(t, err)
+
+	userID, deviceID := uuid.New(), uuid.New()
+	repo := &mockAuthRepo{
+		getByEmailFn: func(_ context.Context, email string) (*domain.User, error) {
+			return &domain.User{ID: userID, Email: email, PasswordHash: string(hash)}, nil
+		},
+	}
+	var session *domain.AuthSession
+	sessionRepo := &mockSessionRepo{
+		createFn: func(_ context.Context, created *domain.AuthSession) error {
+			session = created
+			return nil
+		},
+	}
+	deviceRepo := &testDeviceRepo{
+		upsertFn: func(_ context.Context, device *domain.Device) error {
+			require.Equal(t, userID, device.UserID)
+			require.Equal(t, "laptop", device.ClientID)
+			require.Equal(t, "Work laptop", device.Name)
+			device.ID = deviceID
+			return nil
+		},
+	}
+	svc := NewAuthService(&config.AuthConfig{SecretKey: "test", AccessTokenTTL: time.Minute}, repo, sessionRepo, deviceRepo, nil, nil, nil)
+
+	ctx := WithClientDevice(context.Background(), ClientDevice{ClientID: "laptop", Name: "Work laptop"})
+	_, err = svc.Login(ctx, applicationdto.LoginInput{
+		Identifier: "user@example.com",
+		Password:   "password123",
+	}, "agent", "127.0.0.1")
+	require.NoError(t, err)
+	require.NotNil(t, session)
+	require.Equal(t, &deviceID, session.DeviceID)
+}
+
+// Ensures Login refuses a device that has been revoked instead of reviving it.
+func TestAuthServiceLogin_RejectsRevokedDevice(t *testing.T) {
+	hash, err := bcrypt.GenerateFromPassword([]byte("password123"), bcrypt.DefaultCost)
+	require.NoError(t, err)
+
+	userID := uuid.New()
+	repo := &mockAuthRepo{
+		getByEmailFn: func(_ context.Context, email string) (*domain.User, error) {
+			return &domain.User{ID: userID, Email: email, PasswordHash: string(hash)}, nil
+		},
+	}
+	sessionRepo := &mockSessionRepo{
+		createFn: func(_ context.Context, created *domain.AuthSession) error {
+			t.Fatal("no session should be created for a revoked device")
+			return nil
+		},
+	}
+	revokedAt := time.Now().UTC().Add(-time.Hour)
+	deviceRepo := &testDeviceRepo{
+		upsertFn: func(_ context.Context, device *domain.Device) error {
+			device.ID = uuid.New()
+			device.RevokedAt = &revokedAt
+			return nil
+		},
+	}
+	svc := NewAuthService(&config.AuthConfig{SecretKey: "test", AccessTokenTTL: time.Minute}, repo, sessionRepo, deviceRepo, nil, nil, nil)
+
+	ctx := WithClientDevice(context.Background(), ClientDevice{ClientID: "laptop", Name: "Work laptop"})
+	_, err = svc.Login(ctx, applicationdto.LoginInput{
+		Identifier: "user@example.com",
+		Password:   "password123",
+	}, "agent", "127.0.0.1")
+
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusForbidden, httpErr.Status)
+}
+
 // Ensures StartGoogleAuth fails fast when Google auth is not configured.
 func TestAuthServiceStartGoogleAuth_ConfigMissing(t *testing.T) {
 	ctx := context.Background()
 
-	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, &mockAuthRepo{}, nil, nil, nil, nil)
+	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, &mockAuthRepo{}, nil, nil, nil, nil, nil)
 
 	_, err := svc.StartGoogleAuth(ctx)
 	require.Error(t, err)
@@ -405,6 +525,7 @@ func TestAuthServiceStartGoogleAuth_Success(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
 	).(*authService)
 
 	mockOAuth := &mockOAuthConfig{authURL: "https://accounts.google.com/o/oauth2/auth"}
@@ -460,6 +581,7 @@ func TestAuthServiceCompleteGoogleAuth_Success(t *testing.T) {
 		nil,
 		nil,
 		nil,
+		nil,
 	).(*authService)
 
 	oauthConfig := &mockOAuthConfig{
@@ -524,7 +646,7 @@ func TestAuthServiceVerifyEmail_Success(t *testing.T) {
 		},
 	}
 
-	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, repo, nil, verificationRepo, nil, nil)
+	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, repo, nil, nil, verificationRepo, nil, nil)
 
 	user, err := svc.VerifyEmail(ctx, applicationdto.VerifyEmailInput{
 		Email: "user@example.com",
@@ -551,7 +673,7 @@ func TestAuthServiceVerifyEmail_InvalidCode(t *testing.T) {
 		},
 	}
 
-	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, repo, nil, verificationRepo, nil, nil)
+	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, repo, nil, nil, verificationRepo, nil, nil)
 
 	_, err := svc.VerifyEmail(ctx, applicationdto.VerifyEmailInput{
 		Email: "user@example.com",
@@ -576,7 +698,7 @@ func TestAuthServiceRefresh_MissingToken(t *testing.T) {
 		},
 	}
 
-	svc := NewAuthService(&config.AuthConfig{SecretKey: "test", AccessTokenTTL: time.Minute}, &mockAuthRepo{}, sessionRepo, nil, nil, nil)
+	svc := NewAuthService(&config.AuthConfig{SecretKey: "test", AccessTokenTTL: time.Minute}, &mockAuthRepo{}, sessionRepo, nil, nil, nil, nil)
 
 	_, err := svc.Refresh(ctx, "", "agent", "127.0.0.1")
 	require.Error(t, err)
@@ -617,7 +739,7 @@ func TestAuthServiceRefresh_InvalidSession(t *testing.T) {
 				},
 			}
 
-			svc := NewAuthService(&config.AuthConfig{SecretKey: "test", AccessTokenTTL: time.Minute}, repo, sessionRepo, nil, nil, nil)
+			svc := NewAuthService(&config.AuthConfig{SecretKey: "test", AccessTokenTTL: time.Minute}, repo, sessionRepo, nil, nil, nil, nil)
 
 			_, err := svc.Refresh(ctx, "refresh-token", "agent", "127.0.0.1")
 			require.Error(t, err)
@@ -630,6 +752,70 @@ func TestAuthServiceRefresh_InvalidSession(t *testing.T) {
 	}
 }
 
+// Ensures Refresh refuses to rotate the session of a banned user.
+func TestAuthServiceRefresh_BannedUser(t *testing.T) {
+	ctx := context.Background()
+	bannedAt := time.Now().UTC()
+
+	repo := &mockAuthRepo{
+		getByIDFn: func(_ context.Context, id uuid.UUID) (*domain.User, error) {
+			return &domain.User{ID: id, BannedAt: &bannedAt}, nil
+		},
+	}
+	rotated := false
+	sessionRepo := &mockSessionRepo{
+		getByHashFn: func(_ context.Context, hash string) (*domain.AuthSession, error) {
+			return &domain.AuthSession{ID: uuid.New(), UserID: uuid.New(), ExpiresAt: bannedAt.Add(time.Hour)}, nil
+		},
+		revokeByIDFn: func(_ context.Context, id uuid.UUID, revokedAt time.Time) error {
+			rotated = true
+			return nil
+		},
+	}
+	svc := NewAuthService(&config.AuthConfig{SecretKey: "test", AccessTokenTTL: time.Minute}, repo, sessionRepo, nil, nil, nil, nil)
+
+	_, err := svc.Refresh(ctx, "refresh-token", "agent", "127.0.0.1")
+	require.False(t, rotated)
+
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusForbidden, httpErr.Status)
+}
+
+// Ensures Refresh rejects a session left behind on a revoked device.
+func TestAuthServiceRefresh_RevokedDevice(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+	deviceID := uuid.New()
+
+	repo := &mockAuthRepo{
+		getByIDFn: func(_ context.Context, id uuid.UUID) (*domain.User, error) {
+			return &domain.User{ID: id}, nil
+		},
+	}
+	rotated := false
+	sessionRepo := &mockSessionRepo{
+		getByHashFn: func(_ context.Context, hash string) (*domain.AuthSession, error) {
+			return &domain.AuthSession{ID: uuid.New(), UserID: uuid.New(), DeviceID: &deviceID, ExpiresAt: now.Add(time.Hour)}, nil
+		},
+		revokeByIDFn: func(_ context.Context, id uuid.UUID, revokedAt time.Time) error {
+			rotated = true
+			return nil
+		},
+	}
+	deviceRepo := &testDeviceRepo{
+		getByUserAndIDFn: func(_ context.Context, userID uuid.UUID, id uuid.UUID) (*domain.Device, error) {
+			require.Equal(t, deviceID, id)
+			return &domain.Device{ID: id, UserID: userID, RevokedAt: &now}, nil
+		},
+	}
+	svc := NewAuthService(&config.AuthConfig{SecretKey: "test", AccessTokenTTL: time.Minute}, repo, sessionRepo, deviceRepo, nil, nil, nil)
+
+	_, err := svc.Refresh(ctx, "refresh-token", "agent", "127.0.0.1")
+	require.False(t, rotated)
+	requireErrorStatus(t, err, http.StatusUnauthorized)
+}
+
 // Ensures Refresh rotates sessions and returns new tokens on success.
 func TestAuthServiceRefresh_Success(t *testing.T) {
 	ctx := context.Background()
@@ -664,7 +850,7 @@ func TestAuthServiceRefresh_Success(t *testing.T) {
 		},
 	}
 
-	svc := NewAuthService(&config.AuthConfig{SecretKey: "test", AccessTokenTTL: time.Minute}, repo, sessionRepo, nil, nil, nil)
+	svc := NewAuthService(&config.AuthConfig{SecretKey: "test", AccessTokenTTL: time.Minute}, repo, sessionRepo, nil, nil, nil, nil)
 
 	result, err := svc.Refresh(ctx, refreshToken, "agent", "127.0.0.1")
 	require.NoError(t, err)
@@ -695,7 +881,7 @@ func TestAuthServiceLogout_RevokesSession(t *testing.T) {
 		},
 	}
 
-	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, &mockAuthRepo{}, sessionRepo, nil, nil, nil)
+	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, &mockAuthRepo{}, sessionRepo, nil, nil, nil, nil)
 
 	err := svc.Logout(ctx, refreshToken)
 	require.NoError(t, err)
@@ -719,7 +905,7 @@ func TestAuthServiceLogout_AlreadyRevoked(t *testing.T) {
 		},
 	}
 
-	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, &mockAuthRepo{}, sessionRepo, nil, nil, nil)
+	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, &mockAuthRepo{}, sessionRepo, nil, nil, nil, nil)
 
 	err := svc.Logout(ctx, refreshToken)
 	require.NoError(t, err)
@@ -740,7 +926,7 @@ func TestAuthServiceLogoutAll(t *testing.T) {
 		},
 	}
 
-	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, &mockAuthRepo{}, sessionRepo, nil, nil, nil)
+	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, &mockAuthRepo{}, sessionRepo, nil, nil, nil, nil)
 
 	err := svc.LogoutAll(ctx, userID)
 	require.NoError(t, err)
@@ -759,7 +945,7 @@ func TestAuthServiceCurrentUser(t *testing.T) {
 		},
 	}
 
-	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, repo, nil, nil, nil, nil)
+	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, repo, nil, nil, nil, nil, nil)
 
 	user, err := svc.CurrentUser(ctx, userID)
 	require.NoError(t, err)
@@ -791,7 +977,7 @@ func TestAuthServiceResendVerification_AlreadyVerified(t *testing.T) {
 		},
 	}
 
-	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, repo, nil, verificationRepo, nil, nil)
+	svc := NewAuthService(&config.AuthConfig{SecretKey: "test"}, repo, nil, nil, verificationRepo, nil, nil)
 
 	err := svc.ResendVerification(ctx, userID)
 	require.NoError(t, err)
@@ -829,7 +1015,7 @@ func TestAuthServiceResendVerification_QueuesVerification(t *testing.T) {
 	}
 
 	enqueuer := &mockTaskEnqueuer{}
-	svc := NewAuthService(&config.AuthConfig{SecretKey: "test", EmailVerificationTTL: time.Hour}, repo, nil, verificationRepo, enqueuer, nil)
+	svc := NewAuthService(&config.AuthConfig{SecretKey: "test", EmailVerificationTTL: time.Hour}, repo, nil, nil, verificationRepo, enqueuer, nil)
 
 	err := svc.ResendVerification(ctx, userID)
 	require.NoError(t, err)
@@ -5,10 +5,16 @@ import (
 	_ "embed"
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/casbin/casbin/v2"
 	"github.com/casbin/casbin/v2/model"
 	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/sqlerr"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
 	"github.com/rs/zerolog"
 	"gorm.io/gorm"
 )
@@ -29,16 +35,43 @@ type AuthorizationObject struct {
 	Query  map[string]string `json:"query,omitempty"`
 }
 
+// AuthorizationPolicy is one rule of the policy. Subject, Object and Action are
+// expressions over the request, e.g. `keyMatch2(r.obj.Route, "/api/v1/admin/*")`,
+// and Effect is either "allow" or "deny". A request is allowed when some allow
+// rule matches it and no deny rule does.
+type AuthorizationPolicy struct {
+	Subject string `json:"subject"`
+	Object  string `json:"object"`
+	Action  string `json:"action"`
+	Effect  string `json:"effect"`
+}
+
+const (
+	AuthorizationEffectAllow = "allow"
+	AuthorizationEffectDeny  = "deny"
+)
+
+// authorizationPolicyReloadInterval bounds how long a rule changed through
+// another API instance takes to apply here.
+const authorizationPolicyReloadInterval = time.Minute
+
 type AuthorizationEnforcer interface {
 	Enforce(rvals ...any) (bool, error)
+	GetPolicy() ([][]string, error)
+	AddPolicy(params ...any) (bool, error)
+	RemovePolicy(params ...any) (bool, error)
 }
 
+// AuthorizationService enforces the policy held in memory by its enforcer. Rule
+// changes are written through the transactor along with their audit event, and
+// applied to the enforcer once committed.
 type AuthorizationService struct {
-	enforcer AuthorizationEnforcer
-	logger   *zerolog.Logger
+	enforcer   AuthorizationEnforcer
+	transactor port.ModerationTransactor
+	logger     *zerolog.Logger
 }
 
-func NewAuthorizationService(db *gorm.DB, logger *zerolog.Logger) (*AuthorizationService, error) {
+func NewAuthorizationService(db *gorm.DB, transactor port.ModerationTransactor, logger *zerolog.Logger) (*AuthorizationService, error) {
 	if db == nil {
 		return nil, errors.New("authorization: db is nil")
 	}
@@ -62,18 +95,21 @@ func NewAuthorizationService(db *gorm.DB, logger *zerolog.Logger) (*Authorizatio
 		return nil, fmt.Errorf("authorization: load policy: %w", err)
 	}
 
-	enforcer.EnableAutoSave(true)
+	enforcer.EnableAutoSave(false)
+	enforcer.StartAutoLoadPolicy(authorizationPolicyReloadInterval)
 
 	return &AuthorizationService{
-		enforcer: enforcer,
-		logger:   logger,
+		enforcer:   enforcer,
+		transactor: transactor,
+		logger:     logger,
 	}, nil
 }
 
-func NewAuthorizationServiceWithEnforcer(enforcer AuthorizationEnforcer, logger *zerolog.Logger) *AuthorizationService {
+func NewAuthorizationServiceWithEnforcer(enforcer AuthorizationEnforcer, transactor port.ModerationTransactor, logger *zerolog.Logger) *AuthorizationService {
 	return &AuthorizationService{
-		enforcer: enforcer,
-		logger:   logger,
+		enforcer:   enforcer,
+		transactor: transactor,
+		logger:     logger,
 	}
 }
 
@@ -89,3 +125,141 @@ func (a *AuthorizationService) Enforce(ctx context.Context, sub AuthorizationSub
 	}
 	return allowed, err
 }
+
+func (a *AuthorizationService) ListPolicies(ctx context.Context) ([]AuthorizationPolicy, error) {
+	_ = ctx
+	if a == nil || a.enforcer == nil {
+		return nil, errors.New("authorization: enforcer not initialized")
+	}
+
+	rules, err := a.enforcer.GetPolicy()
+	if err != nil {
+		return nil, fmt.Errorf("authorization: list policies: %w", err)
+	}
+
+	policies := make([]AuthorizationPolicy, 0, len(rules))
+	for _, rule := range rules {
+		if len(rule) < 4 {
+			continue
+		}
+		policies = append(policies, AuthorizationPolicy{Subject: rule[0], Object: rule[1], Action: rule[2], Effect: rule[3]})
+	}
+	return policies, nil
+}
+
+// AddPolicy checks the rule compiles before adding it, since a broken rule
+// would fail every authorization check.
+func (a *AuthorizationService) AddPolicy(ctx context.Context, policy AuthorizationPolicy) (*AuthorizationPolicy, error) {
+	if a == nil || a.enforcer == nil || a.transactor == nil {
+		return nil, errors.New("authorization: enforcer not initialized")
+	}
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return nil, errs.NewUnauthorizedError("Unauthorized", false)
+	}
+
+	policy = normalizeAuthorizationPolicy(policy)
+	if err := validateAuthorizationPolicy(policy); err != nil {
+		return nil, err
+	}
+
+	err := a.transactor.WithinTransaction(ctx, func(repos *port.ModerationRepositories) error {
+		added, err := repos.AuthorizationPolicy.Add(ctx, policy.rule())
+		if err != nil {
+			return err
+		}
+		if !added {
+			return errs.NewConflictError("authorization policy already exists", true, nil)
+		}
+		return recordAuditEvent(ctx, repos.AuditEvent, actor.UserID, domain.AuditActionAuthorizationPolicyAdded, domain.AuditTargetTypeAuthorizationPolicy, nil, policy.auditMetadata())
+	})
+	if err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+
+	if _, err := a.enforcer.AddPolicy(policy.Subject, policy.Object, policy.Action, policy.Effect); err != nil {
+		return nil, fmt.Errorf("authorization: add policy: %w", err)
+	}
+	return &policy, nil
+}
+
+func (a *AuthorizationService) RemovePolicy(ctx context.Context, policy AuthorizationPolicy) error {
+	if a == nil || a.enforcer == nil || a.transactor == nil {
+		return errors.New("authorization: enforcer not initialized")
+	}
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return errs.NewUnauthorizedError("Unauthorized", false)
+	}
+
+	policy = normalizeAuthorizationPolicy(policy)
+	err := a.transactor.WithinTransaction(ctx, func(repos *port.ModerationRepositories) error {
+		removed, err := repos.AuthorizationPolicy.Remove(ctx, policy.rule())
+		if err != nil {
+			return err
+		}
+		if !removed {
+			return errs.NewNotFoundError("authorization policy not found", true)
+		}
+		return recordAuditEvent(ctx, repos.AuditEvent, actor.UserID, domain.AuditActionAuthorizationPolicyRemoved, domain.AuditTargetTypeAuthorizationPolicy, nil, policy.auditMetadata())
+	})
+	if err != nil {
+		return sqlerr.HandleError(err)
+	}
+
+	if _, err := a.enforcer.RemovePolicy(policy.Subject, policy.Object, policy.Action, policy.Effect); err != nil {
+		return fmt.Errorf("authorization: remove policy: %w", err)
+	}
+	return nil
+}
+
+func (p AuthorizationPolicy) rule() []string {
+	return []string{p.Subject, p.Object, p.Action, p.Effect}
+}
+
+func (p AuthorizationPolicy) auditMetadata() map[string]any {
+	return map[string]any{
+		"subject": p.Subject,
+		"object":  p.Object,
+		"action":  p.Action,
+		"effect":  p.Effect,
+	}
+}
+
+func normalizeAuthorizationPolicy(policy AuthorizationPolicy) AuthorizationPolicy {
+	return AuthorizationPolicy{
+		Subject: strings.TrimSpace(policy.Subject),
+		Object:  strings.TrimSpace(policy.Object),
+		Action:  strings.TrimSpace(policy.Action),
+		Effect:  strings.ToLower(strings.TrimSpace(policy.Effect)),
+	}
+}
+
+// validateAuthorizationPolicy enforces a request against a scratch enforcer
+// holding only the rule. Casbin compiles a rule's expressions before evaluating
+// any of them, so a syntax error surfaces whatever the request is.
+func validateAuthorizationPolicy(policy AuthorizationPolicy) error {
+	if policy.Subject == "" || policy.Object == "" || policy.Action == "" {
+		return errs.NewBadRequestError("subject, object and action are required", true, nil, nil)
+	}
+	if policy.Effect != AuthorizationEffectAllow && policy.Effect != AuthorizationEffectDeny {
+		return errs.NewBadRequestError("effect must be either allow or deny", true, nil, nil)
+	}
+
+	modelConf, err := model.NewModelFromString(authorizationModelConf)
+	if err != nil {
+		return fmt.Errorf("authorization: load model: %w", err)
+	}
+	enforcer, err := casbin.NewEnforcer(modelConf)
+	if err != nil {
+		return fmt.Errorf("authorization: init enforcer: %w", err)
+	}
+	if _, err := enforcer.AddPolicy(policy.Subject, policy.Object, policy.Action, policy.Effect); err != nil {
+		return fmt.Errorf("authorization: add policy: %w", err)
+	}
+
+	if _, err := enforcer.Enforce(AuthorizationSubject{}, AuthorizationObject{}, ""); err != nil {
+		return errs.NewBadRequestError("invalid authorization policy: "+err.Error(), true, nil, nil)
+	}
+	return nil
+}
@@ -3,8 +3,17 @@ package application
 import (
 	"context"
 	"errors"
+	"net/http"
+	"os"
+	"regexp"
 	"testing"
 
+	"github.com/casbin/casbin/v2"
+	"github.com/casbin/casbin/v2/model"
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
 	"github.com/stretchr/testify/require"
 )
 
@@ -21,6 +30,78 @@ func (s *stubAuthorizationEnforcer) Enforce(rvals ...interface{}) (bool, error)
 	return s.allowed, s.err
 }
 
+func (s *stubAuthorizationEnforcer) GetPolicy() ([][]string, error) {
+	return nil, s.err
+}
+
+func (s *stubAuthorizationEnforcer) AddPolicy(params ...interface{}) (bool, error) {
+	return s.allowed, s.err
+}
+
+func (s *stubAuthorizationEnforcer) RemovePolicy(params ...interface{}) (bool, error) {
+	return s.allowed, s.err
+}
+
+// testAuthorizationPolicyRepo mirrors the rules of an in-memory enforcer, as the
+// casbin_rule table does for the enforcer of a running server.
+type testAuthorizationPolicyRepo struct {
+	enforcer *casbin.SyncedEnforcer
+}
+
+func (r *testAuthorizationPolicyRepo) Add(ctx context.Context, rule []string) (bool, error) {
+	exists, err := r.enforcer.HasPolicy(rule)
+	return !exists, err
+}
+
+func (r *testAuthorizationPolicyRepo) Remove(ctx context.Context, rule []string) (bool, error) {
+	return r.enforcer.HasPolicy(rule)
+}
+
+// defaultAuthorizationPoliciesMigrations are the migrations that seed the
+// default policy, in the order they apply.
+var defaultAuthorizationPoliciesMigrations = []string{
+	"../infrastructure/database/migrations/000005_default_authorization_policies.up.sql",
+	"../infrastructure/database/migrations/000011_catalog_authorization_policies.up.sql",
+}
+
+var migrationPolicyRule = regexp.MustCompile(`\('([^']*)', '([^']*)', '([^']*)', '(allow|deny)'\)`)
+
+// newPolicyAuthorizationService builds a service over an in-memory enforcer
+// holding the default policy shipped by the migrations.
+func newPolicyAuthorizationService(t *testing.T) (*AuthorizationService, *testAuditEventRepo) {
+	t.Helper()
+
+	modelConf, err := model.NewModelFromString(authorizationModelConf)
+	require.NoError(t, err)
+	enforcer, err := casbin.NewSyncedEnforcer(modelConf)
+	require.NoError(t, err)
+
+	for _, path := range defaultAuthorizationPoliciesMigrations {
+		migration, err := os.ReadFile(path)
+		require.NoError(t, err)
+		rules := migrationPolicyRule.FindAllStringSubmatch(string(migration), -1)
+		require.NotEmpty(t, rules)
+		for _, rule := range rules {
+			_, err := enforcer.AddPolicy(rule[1], rule[2], rule[3], rule[4])
+			require.NoError(t, err)
+		}
+	}
+
+	auditRepo := &testAuditEventRepo{}
+	transactor := &testModerationTransactor{repos: &port.ModerationRepositories{
+		AuthorizationPolicy: &testAuthorizationPolicyRepo{enforcer: enforcer},
+		AuditEvent:          auditRepo,
+	}}
+	return NewAuthorizationServiceWithEnforcer(enforcer, transactor, nil), auditRepo
+}
+
+func requireHTTPStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, status, httpErr.Status)
+}
+
 func TestAuthorizationServiceEnforce(t *testing.T) {
 	subject := AuthorizationSubject{ID: "user-1", Email: "user@example.com", IsAdmin: false}
 	obj := AuthorizationObject{Route: "/users/:id", Path: "/users/123"}
@@ -28,7 +109,7 @@ func TestAuthorizationServiceEnforce(t *testing.T) {
 
 	t.Run("returns allowed", func(t *testing.T) {
 		enforcer := &stubAuthorizationEnforcer{allowed: true}
-		svc := NewAuthorizationServiceWithEnforcer(enforcer, nil)
+		svc := NewAuthorizationServiceWithEnforcer(enforcer, nil, nil)
 
 		allowed, err := svc.Enforce(context.Background(), subject, obj, action)
 
@@ -44,7 +125,7 @@ func TestAuthorizationServiceEnforce(t *testing.T) {
 	t.Run("returns error", func(t *testing.T) {
 		expectedErr := errors.New("boom")
 		enforcer := &stubAuthorizationEnforcer{allowed: false, err: expectedErr}
-		svc := NewAuthorizationServiceWithEnforcer(enforcer, nil)
+		svc := NewAuthorizationServiceWithEnforcer(enforcer, nil, nil)
 
 		allowed, err := svc.Enforce(context.Background(), subject, obj, action)
 
@@ -62,3 +143,116 @@ func TestAuthorizationServiceEnforce(t *testing.T) {
 		require.False(t, allowed)
 	})
 }
+
+// Ensures the default policy lets users reach their own routes but not admin or user management ones.
+func TestDefaultAuthorizationPolicies(t *testing.T) {
+	svc, _ := newPolicyAuthorizationService(t)
+	subject := AuthorizationSubject{ID: "user-1", Email: "user@example.com"}
+
+	tests := []struct {
+		route   string
+		action  string
+		allowed bool
+	}{
+		{route: "/api/v1/ebooks/", action: "GET", allowed: true},
+		{route: "/api/v1/ebooks/:id", action: "DELETE", allowed: true},
+		{route: "/api/v1/users/preferences", action: "PATCH", allowed: true},
+		{route: "/api/v1/users/reader-state", action: "GET", allowed: true},
+		{route: "/api/v1/sync/events\\:batch", action: "POST", allowed: true},
+		{route: "/api/v1/authors/", action: "POST", allowed: true},
+		{route: "/api/v1/tags/:id", action: "GET", allowed: true},
+		{route: "/api/v1/authors/:id", action: "PATCH", allowed: false},
+		{route: "/api/v1/tags/:id/kill", action: "DELETE", allowed: false},
+		{route: "/api/v1/users/", action: "GET", allowed: false},
+		{route: "/api/v1/users/:id", action: "PATCH", allowed: false},
+		{route: "/api/v1/users/:id/kill", action: "DELETE", allowed: false},
+		{route: "/api/v1/admin/authorization/policies", action: "GET", allowed: false},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.action+" "+tc.route, func(t *testing.T) {
+			allowed, err := svc.Enforce(context.Background(), subject, AuthorizationObject{Route: tc.route}, tc.action)
+			require.NoError(t, err)
+			require.Equal(t, tc.allowed, allowed)
+		})
+	}
+}
+
+// Ensures policies can be added, listed and removed at runtime, and each change is audited.
+func TestAuthorizationServicePolicies(t *testing.T) {
+	adminID := uuid.New()
+	ctx := WithActor(context.Background(), Actor{UserID: adminID, IsAdmin: true})
+	svc, auditRepo := newPolicyAuthorizationService(t)
+	subject := AuthorizationSubject{ID: "user-1"}
+	obj := AuthorizationObject{Route: "/api/v1/ebooks/:id"}
+	policy := AuthorizationPolicy{
+		Subject: `r.sub.ID == "user-1"`,
+		Object:  `keyMatch(r.obj.Route, "/api/v1/ebooks/*")`,
+		Action:  `r.act == "DELETE"`,
+		Effect:  " Deny ",
+	}
+
+	added, err := svc.AddPolicy(ctx, policy)
+	require.NoError(t, err)
+	require.Equal(t, AuthorizationEffectDeny, added.Effect)
+
+	policies, err := svc.ListPolicies(ctx)
+	require.NoError(t, err)
+	require.Contains(t, policies, *added)
+
+	allowed, err := svc.Enforce(ctx, subject, obj, "DELETE")
+	require.NoError(t, err)
+	require.False(t, allowed)
+
+	_, err = svc.AddPolicy(ctx, policy)
+	requireHTTPStatus(t, err, http.StatusConflict)
+
+	require.NoError(t, svc.RemovePolicy(ctx, policy))
+	requireHTTPStatus(t, svc.RemovePolicy(ctx, policy), http.StatusNotFound)
+
+	allowed, err = svc.Enforce(ctx, subject, obj, "DELETE")
+	require.NoError(t, err)
+	require.True(t, allowed)
+
+	require.Len(t, auditRepo.events, 2)
+	require.Equal(t, domain.AuditActionAuthorizationPolicyAdded, auditRepo.events[0].Action)
+	require.Equal(t, domain.AuditActionAuthorizationPolicyRemoved, auditRepo.events[1].Action)
+	for _, event := range auditRepo.events {
+		require.Equal(t, &adminID, event.ActorUserID)
+		require.Equal(t, domain.AuditTargetTypeAuthorizationPolicy, event.TargetType)
+		require.Equal(t, policy.Object, event.Metadata["object"])
+		require.Equal(t, AuthorizationEffectDeny, event.Metadata["effect"])
+	}
+
+	_, err = svc.AddPolicy(context.Background(), policy)
+	requireHTTPStatus(t, err, http.StatusUnauthorized)
+}
+
+// Ensures rules that would break every authorization check are rejected.
+func TestAuthorizationServiceAddPolicy_Invalid(t *testing.T) {
+	ctx := WithActor(context.Background(), Actor{UserID: uuid.New(), IsAdmin: true})
+	svc, auditRepo := newPolicyAuthorizationService(t)
+	defaults, err := svc.ListPolicies(ctx)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name   string
+		policy AuthorizationPolicy
+	}{
+		{name: "unbalanced expression", policy: AuthorizationPolicy{Subject: "true", Object: `keyMatch(r.obj.Route, "/api/v1/*"`, Action: "true", Effect: "allow"}},
+		{name: "unknown effect", policy: AuthorizationPolicy{Subject: "true", Object: "true", Action: "true", Effect: "maybe"}},
+		{name: "missing action", policy: AuthorizationPolicy{Subject: "true", Object: "true", Effect: "allow"}},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := svc.AddPolicy(ctx, tc.policy)
+			requireHTTPStatus(t, err, http.StatusBadRequest)
+		})
+	}
+
+	policies, err := svc.ListPolicies(ctx)
+	require.NoError(t, err)
+	require.Equal(t, defaults, policies)
+	require.Empty(t, auditRepo.events)
+}
@@ -0,0 +1,65 @@
+package application
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+)
+
+const borrowLeaseAlgorithm = "Ed25519"
+
+// BorrowAccess is a borrow-bound grant to read a shared ebook. The lease is an
+// EdDSA-signed JWT that clients verify offline with the key from BorrowLeaseKey.
+// It expires well before the borrow is due, so clients renew it while online and
+// a returned, expired or moderated borrow stops being readable within one lease.
+type BorrowAccess struct {
+	BorrowID  string    `json:"borrowId"`
+	EbookID   string    `json:"ebookId"`
+	Lease     string    `json:"lease"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// BorrowLeaseKey is the public half of the key that signs borrow leases.
+type BorrowLeaseKey struct {
+	Algorithm string `json:"algorithm"`
+	PublicKey string `json:"publicKey"`
+}
+
+// BorrowLeaseSigner signs borrow leases with an Ed25519 key derived from a secret,
+// so every API instance sharing the secret issues leases under the same public key.
+type BorrowLeaseSigner struct {
+	privateKey ed25519.PrivateKey
+	ttl        time.Duration
+}
+
+func NewBorrowLeaseSigner(secret string, ttl time.Duration) *BorrowLeaseSigner {
+	seed := sha256.Sum256([]byte("libra-link borrow lease\n" + secret))
+	return &BorrowLeaseSigner{privateKey: ed25519.NewKeyFromSeed(seed[:]), ttl: ttl}
+}
+
+// ExpiresAt is when a lease issued now for a borrow due at dueAt expires.
+func (s *BorrowLeaseSigner) ExpiresAt(now time.Time, dueAt time.Time) time.Time {
+	if s.ttl > 0 && now.Add(s.ttl).Before(dueAt) {
+		return now.Add(s.ttl)
+	}
+	return dueAt
+}
+
+func (s *BorrowLeaseSigner) Sign(claims domain.BorrowLeaseClaims) (string, error) {
+	if s == nil {
+		return "", errors.New("borrow lease signer is not configured")
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodEdDSA, claims).SignedString(s.privateKey)
+}
+
+func (s *BorrowLeaseSigner) PublicKey() *BorrowLeaseKey {
+	return &BorrowLeaseKey{
+		Algorithm: borrowLeaseAlgorithm,
+		PublicKey: base64.StdEncoding.EncodeToString(s.privateKey.Public().(ed25519.PublicKey)),
+	}
+}
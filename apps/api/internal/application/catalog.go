@@ -0,0 +1,132 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/sqlerr"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"gorm.io/gorm"
+)
+
+// AuthorService manages the shared author catalog. Names are unique ignoring
+// case, so "ursula k. le guin" and "Ursula K. Le Guin" are the same author.
+type AuthorService interface {
+	ResourceService[domain.Author, *applicationdto.StoreAuthorInput, *applicationdto.UpdateAuthorInput]
+}
+
+type authorService struct {
+	ResourceService[domain.Author, *applicationdto.StoreAuthorInput, *applicationdto.UpdateAuthorInput]
+	repo port.AuthorRepository
+}
+
+func NewAuthorService(repo port.AuthorRepository) AuthorService {
+	return &authorService{
+		ResourceService: NewResourceService[domain.Author, *applicationdto.StoreAuthorInput, *applicationdto.UpdateAuthorInput]("author", repo),
+		repo:            repo,
+	}
+}
+
+func (s *authorService) Store(ctx context.Context, input *applicationdto.StoreAuthorInput) (*domain.Author, error) {
+	name, err := claimCatalogName(ctx, "author", input.Name, uuid.Nil, s.repo.GetByName)
+	if err != nil {
+		return nil, err
+	}
+
+	input.Name = name
+	return s.ResourceService.Store(ctx, input)
+}
+
+func (s *authorService) Update(ctx context.Context, id uuid.UUID, input *applicationdto.UpdateAuthorInput) (*domain.Author, error) {
+	if input != nil && input.Name != nil {
+		name, err := claimCatalogName(ctx, "author", *input.Name, id, s.repo.GetByName)
+		if err != nil {
+			return nil, err
+		}
+		input.Name = &name
+	}
+	return s.ResourceService.Update(ctx, id, input)
+}
+
+// TagService manages the shared tag catalog. Names are unique ignoring case.
+type TagService interface {
+	ResourceService[domain.Tag, *applicationdto.StoreTagInput, *applicationdto.UpdateTagInput]
+}
+
+type tagService struct {
+	ResourceService[domain.Tag, *applicationdto.StoreTagInput, *applicationdto.UpdateTagInput]
+	repo port.TagRepository
+}
+
+func NewTagService(repo port.TagRepository) TagService {
+	return &tagService{
+		ResourceService: NewResourceService[domain.Tag, *applicationdto.StoreTagInput, *applicationdto.UpdateTagInput]("tag", repo),
+		repo:            repo,
+	}
+}
+
+func (s *tagService) Store(ctx context.Context, input *applicationdto.StoreTagInput) (*domain.Tag, error) {
+	name, err := claimCatalogName(ctx, "tag", input.Name, uuid.Nil, s.repo.GetByName)
+	if err != nil {
+		return nil, err
+	}
+
+	input.Name = name
+	return s.ResourceService.Store(ctx, input)
+}
+
+func (s *tagService) Update(ctx context.Context, id uuid.UUID, input *applicationdto.UpdateTagInput) (*domain.Tag, error) {
+	if input != nil && input.Name != nil {
+		name, err := claimCatalogName(ctx, "tag", *input.Name, id, s.repo.GetByName)
+		if err != nil {
+			return nil, err
+		}
+		input.Name = &name
+	}
+	return s.ResourceService.Update(ctx, id, input)
+}
+
+// claimCatalogName normalizes name and fails with a conflict when another entry
+// than selfID already uses it, ignoring case.
+func claimCatalogName[T domain.BaseModel](ctx context.Context, resourceName string, name string, selfID uuid.UUID, getByName func(ctx context.Context, name string) (*T, error)) (string, error) {
+	name = normalizeCatalogName(name)
+	if name == "" {
+		return "", errs.NewBadRequestError("name is required", true, []errs.FieldError{{Field: "name", Error: "is required"}}, nil)
+	}
+
+	existing, err := getByName(ctx, name)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return "", sqlerr.HandleError(err)
+	}
+	if existing != nil && (*existing).GetID() != selfID {
+		return "", errs.NewConflictError(resourceName+" already exists", true, existing)
+	}
+	return name, nil
+}
+
+// normalizeCatalogName trims name and collapses the whitespace inside it.
+func normalizeCatalogName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// uniqueCatalogNames normalizes names and drops empty ones and the ones that
+// only differ from an earlier name by case, keeping the first spelling.
+func uniqueCatalogNames(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	unique := make([]string, 0, len(names))
+	for _, name := range names {
+		name = normalizeCatalogName(name)
+		key := strings.ToLower(name)
+		if name == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		unique = append(unique, name)
+	}
+	return unique
+}
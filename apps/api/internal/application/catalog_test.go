@@ -0,0 +1,137 @@
+package application
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/config"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/repository"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type testAuthorRepo struct {
+	*repository.MockResourceRepository[domain.Author]
+	ebookAuthors map[uuid.UUID][]uuid.UUID
+}
+
+func newTestAuthorRepo() *testAuthorRepo {
+	return &testAuthorRepo{
+		MockResourceRepository: repository.NewMockResourceRepository[domain.Author](false),
+		ebookAuthors:           map[uuid.UUID][]uuid.UUID{},
+	}
+}
+
+func (r *testAuthorRepo) Store(ctx context.Context, author *domain.Author) error {
+	if author.ID == uuid.Nil {
+		author.ID = uuid.New()
+	}
+	return r.MockResourceRepository.Store(ctx, author)
+}
+
+func (r *testAuthorRepo) GetByName(ctx context.Context, name string) (*domain.Author, error) {
+	authors, _, err := r.GetMany(ctx, repository.GetManyOptions{})
+	if err != nil {
+		return nil, err
+	}
+	for _, author := range authors {
+		if strings.EqualFold(author.Name, name) {
+			return &author, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *testAuthorRepo) FindOrCreateByNames(ctx context.Context, names []string) ([]domain.Author, error) {
+	authors := []domain.Author{}
+	for _, name := range names {
+		author, err := r.GetByName(ctx, name)
+		if err != nil {
+			author = &domain.Author{Name: name}
+			if err := r.Store(ctx, author); err != nil {
+				return nil, err
+			}
+		}
+		authors = append(authors, *author)
+	}
+	return authors, nil
+}
+
+func (r *testAuthorRepo) ReplaceEbookAuthors(ctx context.Context, ebookID uuid.UUID, authorIDs []uuid.UUID) error {
+	r.ebookAuthors[ebookID] = authorIDs
+	return nil
+}
+
+// Ensures author names are normalized and unique ignoring case.
+func TestAuthorServiceStore_RejectsNamesDifferingOnlyByCase(t *testing.T) {
+	ctx := context.Background()
+	svc := NewAuthorService(newTestAuthorRepo())
+
+	author, err := svc.Store(ctx, &applicationdto.StoreAuthorInput{Name: "  Ursula K.   Le Guin "})
+	require.NoError(t, err)
+	require.Equal(t, "Ursula K. Le Guin", author.Name)
+
+	_, err = svc.Store(ctx, &applicationdto.StoreAuthorInput{Name: "ursula k. le guin"})
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusConflict, httpErr.Status)
+
+	// renaming an author to a different spelling of its own name is fine
+	renamed := "URSULA K. LE GUIN"
+	updated, err := svc.Update(ctx, author.ID, &applicationdto.UpdateAuthorInput{Name: &renamed})
+	require.NoError(t, err)
+	require.Equal(t, author.ID, updated.ID)
+}
+
+// Ensures setting an ebook's authors reuses catalog entries and drops case-insensitive duplicates.
+func TestEbookServiceSetAuthors_ReusesAuthorsIgnoringCase(t *testing.T) {
+	repo := repository.NewMockResourceRepository[domain.Ebook](false)
+	ebook := &domain.Ebook{ID: uuid.New(), OwnerUserID: uuid.New(), Title: "Dune", Format: domain.EbookFormatEPUB}
+	require.NoError(t, repo.Store(context.Background(), ebook))
+
+	authorRepo := newTestAuthorRepo()
+	existing := &domain.Author{Name: "Frank Herbert"}
+	require.NoError(t, authorRepo.Store(context.Background(), existing))
+
+	svc := NewEbookService(repo, nil, nil, authorRepo, nil, nil, nil, &config.FileStorageConfig{}, nil, nil, nil)
+	ctx := WithActor(context.Background(), Actor{UserID: ebook.OwnerUserID})
+
+	_, err := svc.SetAuthors(ctx, &applicationdto.SetEbookNamesInput{
+		EbookID: ebook.ID,
+		Names:   []string{"frank herbert", "Brian Herbert", " FRANK  HERBERT ", ""},
+	})
+	require.NoError(t, err)
+
+	authorIDs := authorRepo.ebookAuthors[ebook.ID]
+	require.Len(t, authorIDs, 2)
+	require.Equal(t, existing.ID, authorIDs[0])
+
+	created, err := authorRepo.GetByID(context.Background(), authorIDs[1], nil)
+	require.NoError(t, err)
+	require.Equal(t, "Brian Herbert", created.Name)
+
+	// only the owner may change an ebook's authors
+	_, err = svc.SetAuthors(WithActor(context.Background(), Actor{UserID: uuid.New()}), &applicationdto.SetEbookNamesInput{EbookID: ebook.ID})
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusNotFound, httpErr.Status)
+}
+
+// Ensures catalog filters only bind the values they are given.
+func TestEbookCatalogFilterWheres(t *testing.T) {
+	require.Empty(t, EbookCatalogFilter{}.Wheres())
+
+	tagID := uuid.New()
+	wheres := EbookCatalogFilter{Authors: []string{" Frank  HERBERT"}, TagIDs: []uuid.UUID{tagID}}.Wheres()
+	require.Len(t, wheres, 2)
+	require.Contains(t, wheres[0].Query, "LOWER(authors.name) IN ?")
+	require.Equal(t, []any{[]string{"frank herbert"}}, wheres[0].Args)
+	require.Contains(t, wheres[1].Query, "ebook_tags.tag_id IN ?")
+	require.Equal(t, []any{[]uuid.UUID{tagID}}, wheres[1].Args)
+}
@@ -0,0 +1,136 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/sqlerr"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"gorm.io/gorm"
+)
+
+const (
+	maxDeviceClientIDLength = 128
+	maxDeviceNameLength     = 100
+	defaultDeviceName       = "Unknown device"
+)
+
+type clientDeviceKey struct{}
+
+// ClientDevice identifies the client app installation that sent a request.
+type ClientDevice struct {
+	ClientID string
+	Name     string
+}
+
+// NewClientDevice cleans the device id and name a client sent. It reports false
+// when the id is missing or too long to be one we issued.
+func NewClientDevice(clientID, name string) (ClientDevice, bool) {
+	clientID = strings.TrimSpace(clientID)
+	if clientID == "" || len(clientID) > maxDeviceClientIDLength {
+		return ClientDevice{}, false
+	}
+
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = defaultDeviceName
+	}
+	if utf8.RuneCountInString(name) > maxDeviceNameLength {
+		name = string([]rune(name)[:maxDeviceNameLength])
+	}
+	return ClientDevice{ClientID: clientID, Name: name}, true
+}
+
+func WithClientDevice(ctx context.Context, device ClientDevice) context.Context {
+	return context.WithValue(ctx, clientDeviceKey{}, device)
+}
+
+func ClientDeviceFromContext(ctx context.Context) (ClientDevice, bool) {
+	device, ok := ctx.Value(clientDeviceKey{}).(ClientDevice)
+	return device, ok
+}
+
+type DeviceService interface {
+	List(ctx context.Context, userID uuid.UUID) ([]domain.Device, error)
+	Rename(ctx context.Context, userID uuid.UUID, id uuid.UUID, name string) (*domain.Device, error)
+	// Revoke signs the device out by revoking every session it holds.
+	Revoke(ctx context.Context, userID uuid.UUID, id uuid.UUID) error
+}
+
+type deviceService struct {
+	repo        port.DeviceRepository
+	sessionRepo port.AuthSessionRepository
+}
+
+func NewDeviceService(repo port.DeviceRepository, sessionRepo port.AuthSessionRepository) DeviceService {
+	return &deviceService{repo: repo, sessionRepo: sessionRepo}
+}
+
+func (s *deviceService) List(ctx context.Context, userID uuid.UUID) ([]domain.Device, error) {
+	devices, err := s.repo.ListByUserID(ctx, userID)
+	if err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+
+	if current, ok := ClientDeviceFromContext(ctx); ok {
+		for i := range devices {
+			devices[i].Current = devices[i].ClientID == current.ClientID
+		}
+	}
+	return devices, nil
+}
+
+func (s *deviceService) Rename(ctx context.Context, userID uuid.UUID, id uuid.UUID, name string) (*domain.Device, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxDeviceNameLength {
+		return nil, errs.NewBadRequestError("device name must be between 1 and 100 characters", true, nil, nil)
+	}
+
+	device, err := s.getActive(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.Rename(ctx, device.ID, name); err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+
+	device.Name = name
+	if current, ok := ClientDeviceFromContext(ctx); ok {
+		device.Current = device.ClientID == current.ClientID
+	}
+	return device, nil
+}
+
+func (s *deviceService) Revoke(ctx context.Context, userID uuid.UUID, id uuid.UUID) error {
+	device, err := s.getActive(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+
+	// The device is revoked first, so no session can be started or refreshed on
+	// it while its sessions are being revoked.
+	now := time.Now().UTC()
+	if err := s.repo.Revoke(ctx, device.ID, now); err != nil {
+		return sqlerr.HandleError(err)
+	}
+	return sqlerr.HandleError(s.sessionRepo.RevokeByDeviceID(ctx, device.ID, now))
+}
+
+// getActive loads one of the user's devices, hiding other users' devices and
+// revoked ones behind the same not found error.
+func (s *deviceService) getActive(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*domain.Device, error) {
+	device, err := s.repo.GetByUserAndID(ctx, userID, id)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && device.RevokedAt != nil) {
+		return nil, errs.NewNotFoundError("device not found", true)
+	}
+	if err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+	return device, nil
+}
@@ -0,0 +1,156 @@
+package application
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type testDeviceRepo struct {
+	upsertFn         func(ctx context.Context, device *domain.Device) error
+	listByUserIDFn   func(ctx context.Context, userID uuid.UUID) ([]domain.Device, error)
+	getByUserAndIDFn func(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*domain.Device, error)
+	getByClientIDFn  func(ctx context.Context, userID uuid.UUID, clientID string) (*domain.Device, error)
+	recordSyncFn     func(ctx context.Context, userID uuid.UUID, clientID string, position *port.SyncEventCursor, syncedAt time.Time) error
+	getSyncHorizonFn func(ctx context.Context, userID uuid.UUID, activeSince time.Time) (*time.Time, error)
+	renamed          map[uuid.UUID]string
+	revoked          []uuid.UUID
+}
+
+func (r *testDeviceRepo) Upsert(ctx context.Context, device *domain.Device) error {
+	if r.upsertFn != nil {
+		return r.upsertFn(ctx, device)
+	}
+	if device.ID == uuid.Nil {
+		device.ID = uuid.New()
+	}
+	return nil
+}
+
+func (r *testDeviceRepo) ListByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Device, error) {
+	if r.listByUserIDFn != nil {
+		return r.listByUserIDFn(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (r *testDeviceRepo) GetByUserAndID(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*domain.Device, error) {
+	if r.getByUserAndIDFn != nil {
+		return r.getByUserAndIDFn(ctx, userID, id)
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *testDeviceRepo) GetByUserAndClientID(ctx context.Context, userID uuid.UUID, clientID string) (*domain.Device, error) {
+	if r.getByClientIDFn != nil {
+		return r.getByClientIDFn(ctx, userID, clientID)
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *testDeviceRepo) Rename(ctx context.Context, id uuid.UUID, name string) error {
+	if r.renamed == nil {
+		r.renamed = map[uuid.UUID]string{}
+	}
+	r.renamed[id] = name
+	return nil
+}
+
+func (r *testDeviceRepo) Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	r.revoked = append(r.revoked, id)
+	return nil
+}
+
+func (r *testDeviceRepo) RecordSync(ctx context.Context, userID uuid.UUID, clientID string, position *port.SyncEventCursor, syncedAt time.Time) error {
+	if r.recordSyncFn != nil {
+		return r.recordSyncFn(ctx, userID, clientID, position, syncedAt)
+	}
+	return nil
+}
+
+func (r *testDeviceRepo) GetSyncHorizon(ctx context.Context, userID uuid.UUID, activeSince time.Time) (*time.Time, error) {
+	if r.getSyncHorizonFn != nil {
+		return r.getSyncHorizonFn(ctx, userID, activeSince)
+	}
+	return nil, nil
+}
+
+// Ensures NewClientDevice rejects missing ids and fills in a default name.
+func TestNewClientDevice(t *testing.T) {
+	_, ok := NewClientDevice("  ", "Laptop")
+	require.False(t, ok)
+
+	device, ok := NewClientDevice(" device-1 ", "")
+	require.True(t, ok)
+	require.Equal(t, ClientDevice{ClientID: "device-1", Name: defaultDeviceName}, device)
+}
+
+// Ensures List flags the device that made the request.
+func TestDeviceServiceList_MarksCurrentDevice(t *testing.T) {
+	userID := uuid.New()
+	repo := &testDeviceRepo{
+		listByUserIDFn: func(ctx context.Context, id uuid.UUID) ([]domain.Device, error) {
+			require.Equal(t, userID, id)
+			return []domain.Device{
+				{ID: uuid.New(), UserID: userID, ClientID: "laptop"},
+				{ID: uuid.New(), UserID: userID, ClientID: "desktop"},
+			}, nil
+		},
+	}
+	service := NewDeviceService(repo, &mockSessionRepo{})
+
+	ctx := WithClientDevice(context.Background(), ClientDevice{ClientID: "desktop", Name: "Desktop"})
+	devices, err := service.List(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, devices, 2)
+	require.False(t, devices[0].Current)
+	require.True(t, devices[1].Current)
+}
+
+// Ensures Revoke revokes the device before its sessions.
+func TestDeviceServiceRevoke_RevokesSessions(t *testing.T) {
+	userID, deviceID := uuid.New(), uuid.New()
+	repo := &testDeviceRepo{
+		getByUserAndIDFn: func(ctx context.Context, uid uuid.UUID, id uuid.UUID) (*domain.Device, error) {
+			return &domain.Device{ID: id, UserID: uid}, nil
+		},
+	}
+	var revokedSessionsOf uuid.UUID
+	sessionRepo := &mockSessionRepo{
+		revokeByDeviceIDFn: func(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+			require.Equal(t, []uuid.UUID{id}, repo.revoked)
+			revokedSessionsOf = id
+			return nil
+		},
+	}
+	service := NewDeviceService(repo, sessionRepo)
+
+	require.NoError(t, service.Revoke(context.Background(), userID, deviceID))
+	require.Equal(t, deviceID, revokedSessionsOf)
+	require.Equal(t, []uuid.UUID{deviceID}, repo.revoked)
+}
+
+// Ensures revoked devices can no longer be renamed or revoked.
+func TestDeviceServiceRename_RevokedDeviceNotFound(t *testing.T) {
+	revokedAt := time.Now().UTC()
+	repo := &testDeviceRepo{
+		getByUserAndIDFn: func(ctx context.Context, uid uuid.UUID, id uuid.UUID) (*domain.Device, error) {
+			return &domain.Device{ID: id, UserID: uid, RevokedAt: &revokedAt}, nil
+		},
+	}
+	service := NewDeviceService(repo, &mockSessionRepo{})
+
+	_, err := service.Rename(context.Background(), uuid.New(), uuid.New(), "Laptop")
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusNotFound, httpErr.Status)
+	require.Empty(t, repo.renamed)
+}
@@ -0,0 +1,24 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+)
+
+// ListAuditEventsInput filters the audit log. From is inclusive and To exclusive.
+type ListAuditEventsInput struct {
+	ActorUserID *uuid.UUID
+	Action      domain.AuditAction
+	TargetType  domain.AuditTargetType
+	TargetID    *uuid.UUID
+	From        *time.Time
+	To          *time.Time
+	Limit       int
+	Offset      int
+	// After and SkipCount page the log as port.GetManyOptions does.
+	After     *port.ListCursor
+	SkipCount bool
+}
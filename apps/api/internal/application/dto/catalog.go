@@ -0,0 +1,69 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+)
+
+type StoreAuthorInput struct {
+	Name string
+}
+
+func (d *StoreAuthorInput) ToModel() *domain.Author {
+	return &domain.Author{Name: d.Name}
+}
+
+type UpdateAuthorInput struct {
+	Name *string
+}
+
+func (d *UpdateAuthorInput) ToModel() *domain.Author {
+	out := &domain.Author{}
+	if d.Name != nil {
+		out.Name = *d.Name
+	}
+	return out
+}
+
+func (d *UpdateAuthorInput) ToMap() map[string]any {
+	updates := map[string]any{}
+	if d.Name != nil {
+		updates["name"] = *d.Name
+	}
+	return updates
+}
+
+type StoreTagInput struct {
+	Name string
+}
+
+func (d *StoreTagInput) ToModel() *domain.Tag {
+	return &domain.Tag{Name: d.Name}
+}
+
+type UpdateTagInput struct {
+	Name *string
+}
+
+func (d *UpdateTagInput) ToModel() *domain.Tag {
+	out := &domain.Tag{}
+	if d.Name != nil {
+		out.Name = *d.Name
+	}
+	return out
+}
+
+func (d *UpdateTagInput) ToMap() map[string]any {
+	updates := map[string]any{}
+	if d.Name != nil {
+		updates["name"] = *d.Name
+	}
+	return updates
+}
+
+// SetEbookNamesInput replaces the authors or tags of an ebook with the ones
+// named Names, creating those that do not exist yet.
+type SetEbookNamesInput struct {
+	EbookID uuid.UUID
+	Names   []string
+}
@@ -1,6 +1,7 @@
 package dto
 
 import (
+	"io"
 	"time"
 
 	"github.com/google/uuid"
@@ -85,3 +86,11 @@ type AttachGoogleMetadataInput struct {
 	InfoLink      *string
 	RawPayload    map[string]any
 }
+
+type UploadEbookFileInput struct {
+	EbookID     uuid.UUID
+	UserID      uuid.UUID
+	Reader      io.Reader
+	Size        int64
+	ContentType string
+}
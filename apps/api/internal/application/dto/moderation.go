@@ -0,0 +1,60 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+)
+
+type ListShareReportsInput struct {
+	// Statuses defaults to the open queue: open and in_review reports.
+	Statuses []domain.ReportStatus
+	Reason   domain.ReportReason
+	Limit    int
+	Offset   int
+	// After and SkipCount page the queue as port.GetManyOptions does.
+	After     *port.ListCursor
+	SkipCount bool
+}
+
+type ResolveShareReportInput struct {
+	ReportID    uuid.UUID
+	ModeratorID uuid.UUID
+	Note        string
+	// ShareStatus takes the reported share down when set to disabled or removed.
+	ShareStatus domain.ShareStatus
+}
+
+type RejectShareReportInput struct {
+	ReportID    uuid.UUID
+	ModeratorID uuid.UUID
+	Note        string
+}
+
+type ReinstateShareInput struct {
+	ShareID     uuid.UUID
+	ModeratorID uuid.UUID
+	Note        string
+}
+
+type SuspendUserInput struct {
+	UserID      uuid.UUID
+	ModeratorID uuid.UUID
+	Reason      string
+	// Until ends the suspension; nil suspends the user until an admin lifts it.
+	Until *time.Time
+}
+
+type BanUserInput struct {
+	UserID      uuid.UUID
+	ModeratorID uuid.UUID
+	Reason      string
+}
+
+type ReinstateUserInput struct {
+	UserID      uuid.UUID
+	ModeratorID uuid.UUID
+	Note        string
+}
@@ -0,0 +1,25 @@
+package dto
+
+import (
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+)
+
+// SearchInput is a full-text search of the user's library or of the community
+// shares. An empty Scope searches the library.
+type SearchInput struct {
+	UserID uuid.UUID
+	Query  string
+	Scope  domain.SearchScope
+	Limit  int
+	Offset int
+}
+
+// SearchEbookContentInput is a full-text search inside the text of an ebook.
+type SearchEbookContentInput struct {
+	EbookID uuid.UUID
+	UserID  uuid.UUID
+	Query   string
+	Limit   int
+	Offset  int
+}
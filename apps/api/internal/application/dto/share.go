@@ -11,16 +11,12 @@ type StoreShareInput struct {
 	TitleOverride        *string
 	Description          *string
 	Visibility           domain.ShareVisibility
-	Status               domain.ShareStatus
 	BorrowDurationHours  int
 	MaxConcurrentBorrows int
 }
 
+// ToModel builds an active share. Only moderators change a share's status.
 func (d *StoreShareInput) ToModel() *domain.Share {
-	status := d.Status
-	if status == "" {
-		status = domain.ShareStatusActive
-	}
 	visibility := d.Visibility
 	if visibility == "" {
 		visibility = domain.ShareVisibilityPublic
@@ -32,7 +28,7 @@ func (d *StoreShareInput) ToModel() *domain.Share {
 		TitleOverride:        d.TitleOverride,
 		Description:          d.Description,
 		Visibility:           visibility,
-		Status:               status,
+		Status:               domain.ShareStatusActive,
 		BorrowDurationHours:  d.BorrowDurationHours,
 		MaxConcurrentBorrows: d.MaxConcurrentBorrows,
 	}
@@ -42,7 +38,6 @@ type UpdateShareInput struct {
 	TitleOverride        *string
 	Description          *string
 	Visibility           *domain.ShareVisibility
-	Status               *domain.ShareStatus
 	BorrowDurationHours  *int
 	MaxConcurrentBorrows *int
 }
@@ -58,9 +53,6 @@ func (d *UpdateShareInput) ToModel() *domain.Share {
 	if d.Visibility != nil {
 		out.Visibility = *d.Visibility
 	}
-	if d.Status != nil {
-		out.Status = *d.Status
-	}
 	if d.BorrowDurationHours != nil {
 		out.BorrowDurationHours = *d.BorrowDurationHours
 	}
@@ -81,9 +73,6 @@ func (d *UpdateShareInput) ToMap() map[string]any {
 	if d.Visibility != nil {
 		updates["visibility"] = *d.Visibility
 	}
-	if d.Status != nil {
-		updates["status"] = *d.Status
-	}
 	if d.BorrowDurationHours != nil {
 		updates["borrow_duration_hours"] = *d.BorrowDurationHours
 	}
@@ -2,7 +2,14 @@ package application
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
@@ -10,39 +17,104 @@ import (
 	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
 	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
 	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/config"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/googlebooks"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/storage"
+	"github.com/rs/zerolog"
 	"gorm.io/gorm"
 )
 
+// EbookFile is an open handle to an ebook's stored file. Callers must close Body.
+type EbookFile struct {
+	Ebook       *domain.Ebook
+	Body        io.ReadCloser
+	ContentType string
+	Size        int64
+}
+
+// EbookFileURL is a time-limited URL for reading an ebook's stored file directly from storage.
+type EbookFileURL struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
 type EbookService interface {
 	ResourceService[domain.Ebook, *applicationdto.StoreEbookInput, *applicationdto.UpdateEbookInput]
 	AttachMetadata(ctx context.Context, input *applicationdto.AttachGoogleMetadataInput) (*domain.EbookGoogleMetadata, error)
 	DetachMetadata(ctx context.Context, ebookID uuid.UUID) error
+	SearchGoogleBooks(ctx context.Context, query string, isbn string, limit int) ([]googlebooks.Volume, error)
+	AttachGoogleVolume(ctx context.Context, ebookID uuid.UUID, volumeID string) (*domain.EbookGoogleMetadata, error)
+	EnrichMetadata(ctx context.Context, ebookID uuid.UUID) (attached bool, suggested int, err error)
+	ListMetadataSuggestions(ctx context.Context, ebookID uuid.UUID) ([]domain.EbookMetadataSuggestion, error)
+	AcceptMetadataSuggestion(ctx context.Context, ebookID uuid.UUID, suggestionID uuid.UUID) (*domain.EbookGoogleMetadata, error)
+	DismissMetadataSuggestions(ctx context.Context, ebookID uuid.UUID) error
+	SetAuthors(ctx context.Context, input *applicationdto.SetEbookNamesInput) (*domain.Ebook, error)
+	SetTags(ctx context.Context, input *applicationdto.SetEbookNamesInput) (*domain.Ebook, error)
+	UploadFile(ctx context.Context, input *applicationdto.UploadEbookFileInput) (*domain.Ebook, error)
+	OpenFile(ctx context.Context, ebookID uuid.UUID, userID uuid.UUID) (*EbookFile, error)
+	FileURL(ctx context.Context, ebookID uuid.UUID, userID uuid.UUID) (*EbookFileURL, error)
+	IndexContent(ctx context.Context, ebookID uuid.UUID) (passages int, err error)
+	// SearchContent returns the passages of the ebook's text matching input, in
+	// reading order, and their total count.
+	SearchContent(ctx context.Context, input *applicationdto.SearchEbookContentInput) ([]domain.EbookPassageHit, int64, error)
 }
 
 type ebookService struct {
 	ResourceService[domain.Ebook, *applicationdto.StoreEbookInput, *applicationdto.UpdateEbookInput]
-	repo         port.EbookRepository
-	metadataRepo port.EbookGoogleMetadataRepository
+	repo           port.EbookRepository
+	metadataRepo   port.EbookGoogleMetadataRepository
+	suggestionRepo port.EbookMetadataSuggestionRepository
+	authorRepo     port.AuthorRepository
+	tagRepo        port.TagRepository
+	contentRepo    port.EbookContentRepository
+	storage        storage.Storage
+	storageCfg     *config.FileStorageConfig
+	googleBooks    GoogleBooksClient
+	taskEnqueuer   TaskEnqueuer
+	logger         *zerolog.Logger
 }
 
-func NewEbookService(repo port.EbookRepository, metadataRepo port.EbookGoogleMetadataRepository) EbookService {
+func NewEbookService(repo port.EbookRepository, metadataRepo port.EbookGoogleMetadataRepository, suggestionRepo port.EbookMetadataSuggestionRepository, authorRepo port.AuthorRepository, tagRepo port.TagRepository, contentRepo port.EbookContentRepository, fileStorage storage.Storage, storageCfg *config.FileStorageConfig, googleBooks GoogleBooksClient, taskEnqueuer TaskEnqueuer, logger *zerolog.Logger) EbookService {
 	return &ebookService{
-		ResourceService: NewResourceService[domain.Ebook, *applicationdto.StoreEbookInput, *applicationdto.UpdateEbookInput]("ebook", repo),
-		repo:            repo,
-		metadataRepo:    metadataRepo,
+		ResourceService: NewOwnedResourceService[domain.Ebook, *applicationdto.StoreEbookInput, *applicationdto.UpdateEbookInput]("ebook", repo, OwnershipPolicy[domain.Ebook]{
+			Column:  "owner_user_id",
+			OwnerID: func(e *domain.Ebook) uuid.UUID { return e.OwnerUserID },
+		}),
+		repo:           repo,
+		metadataRepo:   metadataRepo,
+		suggestionRepo: suggestionRepo,
+		authorRepo:     authorRepo,
+		tagRepo:        tagRepo,
+		contentRepo:    contentRepo,
+		storage:        fileStorage,
+		storageCfg:     storageCfg,
+		googleBooks:    googleBooks,
+		taskEnqueuer:   taskEnqueuer,
+		logger:         logger,
 	}
 }
 
+// Store creates the ebook and queues the lookup of its metadata.
+func (s *ebookService) Store(ctx context.Context, input *applicationdto.StoreEbookInput) (*domain.Ebook, error) {
+	ebook, err := s.ResourceService.Store(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	s.enqueueEnrichment(ctx, ebook.ID)
+	return ebook, nil
+}
+
 func (s *ebookService) AttachMetadata(ctx context.Context, input *applicationdto.AttachGoogleMetadataInput) (*domain.EbookGoogleMetadata, error) {
 	if input == nil {
 		return nil, errs.NewBadRequestError("metadata payload is required", true, nil, nil)
 	}
 
-	if _, err := s.repo.GetByID(ctx, input.EbookID, nil); err != nil {
-		return nil, sqlerr.HandleError(err)
+	if _, err := s.GetByID(ctx, input.EbookID, nil); err != nil {
+		return nil, err
 	}
 
-	metadata := &domain.EbookGoogleMetadata{
+	return s.storeMetadata(ctx, &domain.EbookGoogleMetadata{
 		EbookID:       input.EbookID,
 		GoogleBooksID: input.GoogleBooksID,
 		ISBN10:        input.ISBN10,
@@ -54,13 +126,22 @@ func (s *ebookService) AttachMetadata(ctx context.Context, input *applicationdto
 		ThumbnailURL:  input.ThumbnailURL,
 		InfoLink:      input.InfoLink,
 		RawPayload:    input.RawPayload,
-	}
+	})
+}
 
+// storeMetadata attaches metadata to its ebook, which settles any suggestions
+// enrichment left for it.
+func (s *ebookService) storeMetadata(ctx context.Context, metadata *domain.EbookGoogleMetadata) (*domain.EbookGoogleMetadata, error) {
 	if err := s.metadataRepo.Upsert(ctx, metadata); err != nil {
 		return nil, sqlerr.HandleError(err)
 	}
+	if s.suggestionRepo != nil {
+		if err := s.suggestionRepo.DeleteByEbookID(ctx, metadata.EbookID); err != nil {
+			return nil, sqlerr.HandleError(err)
+		}
+	}
 
-	stored, err := s.metadataRepo.GetByEbookID(ctx, input.EbookID)
+	stored, err := s.metadataRepo.GetByEbookID(ctx, metadata.EbookID)
 	if err != nil {
 		return nil, sqlerr.HandleError(err)
 	}
@@ -68,8 +149,8 @@ func (s *ebookService) AttachMetadata(ctx context.Context, input *applicationdto
 }
 
 func (s *ebookService) DetachMetadata(ctx context.Context, ebookID uuid.UUID) error {
-	if _, err := s.repo.GetByID(ctx, ebookID, nil); err != nil {
-		return sqlerr.HandleError(err)
+	if _, err := s.GetByID(ctx, ebookID, nil); err != nil {
+		return err
 	}
 
 	if err := s.metadataRepo.SoftDeleteByEbookID(ctx, ebookID); err != nil {
@@ -81,3 +162,169 @@ func (s *ebookService) DetachMetadata(ctx context.Context, ebookID uuid.UUID) er
 
 	return nil
 }
+
+func (s *ebookService) UploadFile(ctx context.Context, input *applicationdto.UploadEbookFileInput) (*domain.Ebook, error) {
+	if input == nil || input.Reader == nil {
+		return nil, errs.NewBadRequestError("file payload is required", true, nil, nil)
+	}
+	if s.storage == nil {
+		return nil, errs.NewInternalServerError()
+	}
+
+	ebook, err := s.repo.GetByID(ctx, input.EbookID, nil)
+	if err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+
+	if ebook.OwnerUserID != input.UserID {
+		return nil, errs.NewForbiddenError("not allowed to upload a file for this ebook", true)
+	}
+
+	if maxBytes := s.maxUploadBytes(); maxBytes > 0 && ebook.FileSizeBytes > maxBytes {
+		return nil, errs.NewBadRequestError("file exceeds the maximum upload size", true, nil, nil)
+	}
+
+	if input.Size > 0 && input.Size != ebook.FileSizeBytes {
+		return nil, errs.NewBadRequestError("file size does not match the ebook fileSizeBytes", true, nil, nil)
+	}
+
+	// Spool the upload to a temp file first so a bad upload never overwrites a
+	// previously stored copy, and so the checksum is known before anything is saved.
+	tmp, err := os.CreateTemp("", "ebook-upload-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	hasher := sha256.New()
+	written, err := io.Copy(io.MultiWriter(tmp, hasher), io.LimitReader(input.Reader, ebook.FileSizeBytes+1))
+	if err != nil {
+		return nil, errs.NewBadRequestError("failed to read uploaded file", true, nil, nil)
+	}
+
+	if written != ebook.FileSizeBytes {
+		return nil, errs.NewBadRequestError("file size does not match the ebook fileSizeBytes", true, nil, nil)
+	}
+
+	if !strings.EqualFold(hex.EncodeToString(hasher.Sum(nil)), ebook.ChecksumSHA256) {
+		return nil, errs.NewBadRequestError("file checksum does not match the ebook checksumSha256", true, nil, nil)
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.storage.Save(ctx, ebookFileKey(s.storageCfg, ebook), tmp, written, ebookContentType(ebook.Format)); err != nil {
+		return nil, err
+	}
+
+	// the package metadata of an EPUB, such as its ISBN, improves the lookup
+	if ebook.Format == domain.EbookFormatEPUB {
+		s.enqueueEnrichment(ctx, ebook.ID)
+	}
+	s.enqueueContentIndex(ctx, ebook.ID)
+	return ebook, nil
+}
+
+func (s *ebookService) OpenFile(ctx context.Context, ebookID uuid.UUID, userID uuid.UUID) (*EbookFile, error) {
+	if s.storage == nil {
+		return nil, errs.NewInternalServerError()
+	}
+
+	ebook, err := s.getReadableEbook(ctx, ebookID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := s.storage.Open(ctx, ebookFileKey(s.storageCfg, ebook))
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, errs.NewNotFoundError("ebook file has not been uploaded", true)
+		}
+		return nil, err
+	}
+
+	return &EbookFile{
+		Ebook:       ebook,
+		Body:        body,
+		ContentType: ebookContentType(ebook.Format),
+		Size:        ebook.FileSizeBytes,
+	}, nil
+}
+
+func (s *ebookService) FileURL(ctx context.Context, ebookID uuid.UUID, userID uuid.UUID) (*EbookFileURL, error) {
+	if s.storage == nil {
+		return nil, errs.NewInternalServerError()
+	}
+
+	ebook, err := s.getReadableEbook(ctx, ebookID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	key := ebookFileKey(s.storageCfg, ebook)
+	if _, err := s.storage.Stat(ctx, key); err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, errs.NewNotFoundError("ebook file has not been uploaded", true)
+		}
+		return nil, err
+	}
+
+	ttl := s.signedURLTTL()
+	url, err := s.storage.SignedURL(ctx, key, ttl)
+	if err != nil {
+		return nil, err
+	}
+
+	return &EbookFileURL{URL: url, ExpiresAt: time.Now().UTC().Add(ttl)}, nil
+}
+
+func (s *ebookService) getReadableEbook(ctx context.Context, ebookID uuid.UUID, userID uuid.UUID) (*domain.Ebook, error) {
+	ebook, err := s.repo.GetByID(ctx, ebookID, nil)
+	if err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+
+	if ebook.OwnerUserID != userID {
+		return nil, errs.NewForbiddenError("not allowed to download this ebook", true)
+	}
+	return ebook, nil
+}
+
+// ebookFileKey is the server-side object key for an ebook's file. It is derived
+// from the ebook rather than StorageKey, which clients use for their local copy.
+func ebookFileKey(storageCfg *config.FileStorageConfig, ebook *domain.Ebook) string {
+	prefix := ""
+	if storageCfg != nil {
+		prefix = storageCfg.PathPrefix
+	}
+	return path.Join(prefix, "ebooks", ebook.OwnerUserID.String(), ebook.ID.String()+"."+string(ebook.Format))
+}
+
+func (s *ebookService) maxUploadBytes() int64 {
+	if s.storageCfg == nil {
+		return 0
+	}
+	return int64(s.storageCfg.MaxUploadSizeMB) * 1024 * 1024
+}
+
+func (s *ebookService) signedURLTTL() time.Duration {
+	if s.storageCfg == nil || s.storageCfg.SignedURLTTL <= 0 {
+		return 15 * time.Minute
+	}
+	return s.storageCfg.SignedURLTTL
+}
+
+func ebookContentType(format domain.EbookFormat) string {
+	switch format {
+	case domain.EbookFormatEPUB:
+		return "application/epub+zip"
+	case domain.EbookFormatPDF:
+		return "application/pdf"
+	case domain.EbookFormatTXT:
+		return "text/plain; charset=utf-8"
+	default:
+		return "application/octet-stream"
+	}
+}
@@ -0,0 +1,126 @@
+package application
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/sqlerr"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+)
+
+// ebookCatalogPreloads loads the authors and tags of an ebook.
+var ebookCatalogPreloads = []string{"Authors", "Tags"}
+
+// SetAuthors replaces the ebook's authors with the ones named in input, adding
+// the names that are not in the catalog yet. An empty list clears them.
+func (s *ebookService) SetAuthors(ctx context.Context, input *applicationdto.SetEbookNamesInput) (*domain.Ebook, error) {
+	if input == nil {
+		return nil, errs.NewBadRequestError("authors payload is required", true, nil, nil)
+	}
+	if _, err := s.GetByID(ctx, input.EbookID, nil); err != nil {
+		return nil, err
+	}
+
+	authors, err := s.authorRepo.FindOrCreateByNames(ctx, uniqueCatalogNames(input.Names))
+	if err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+	authorIDs := make([]uuid.UUID, len(authors))
+	for i, author := range authors {
+		authorIDs[i] = author.ID
+	}
+	if err := s.authorRepo.ReplaceEbookAuthors(ctx, input.EbookID, authorIDs); err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+
+	return s.reloadWithCatalog(ctx, input.EbookID)
+}
+
+// SetTags replaces the ebook's tags with the ones named in input, adding the
+// names that are not in the catalog yet. An empty list clears them.
+func (s *ebookService) SetTags(ctx context.Context, input *applicationdto.SetEbookNamesInput) (*domain.Ebook, error) {
+	if input == nil {
+		return nil, errs.NewBadRequestError("tags payload is required", true, nil, nil)
+	}
+	if _, err := s.GetByID(ctx, input.EbookID, nil); err != nil {
+		return nil, err
+	}
+
+	tags, err := s.tagRepo.FindOrCreateByNames(ctx, uniqueCatalogNames(input.Names))
+	if err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+	tagIDs := make([]uuid.UUID, len(tags))
+	for i, tag := range tags {
+		tagIDs[i] = tag.ID
+	}
+	if err := s.tagRepo.ReplaceEbookTags(ctx, input.EbookID, tagIDs); err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+
+	return s.reloadWithCatalog(ctx, input.EbookID)
+}
+
+// reloadWithCatalog drops the cached copy of the ebook, which may hold its old
+// authors and tags, and loads it again along with them.
+func (s *ebookService) reloadWithCatalog(ctx context.Context, ebookID uuid.UUID) (*domain.Ebook, error) {
+	s.repo.EvictCache(ctx, ebookID)
+
+	ebook, err := s.repo.GetByID(ctx, ebookID, ebookCatalogPreloads)
+	if err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+	return ebook, nil
+}
+
+// EbookCatalogFilter narrows an ebook listing by authors and tags. An ebook
+// matches a list when it has any of the entries in it, and it has to match
+// every list that is set. Names are compared ignoring case.
+type EbookCatalogFilter struct {
+	AuthorIDs []uuid.UUID
+	Authors   []string
+	TagIDs    []uuid.UUID
+	Tags      []string
+}
+
+// Wheres translates the filter into where clauses over the ebooks table.
+func (f EbookCatalogFilter) Wheres() []port.WhereClause {
+	wheres := []port.WhereClause{}
+	if len(f.AuthorIDs) > 0 {
+		wheres = append(wheres, port.WhereClause{
+			Query: "EXISTS (SELECT 1 FROM ebook_authors WHERE ebook_authors.ebook_id = ebooks.id AND ebook_authors.author_id IN ?)",
+			Args:  []any{f.AuthorIDs},
+		})
+	}
+	if len(f.Authors) > 0 {
+		wheres = append(wheres, port.WhereClause{
+			Query: "EXISTS (SELECT 1 FROM ebook_authors JOIN authors ON authors.id = ebook_authors.author_id WHERE ebook_authors.ebook_id = ebooks.id AND authors.deleted_at IS NULL AND LOWER(authors.name) IN ?)",
+			Args:  []any{lowerCatalogNames(f.Authors)},
+		})
+	}
+	if len(f.TagIDs) > 0 {
+		wheres = append(wheres, port.WhereClause{
+			Query: "EXISTS (SELECT 1 FROM ebook_tags WHERE ebook_tags.ebook_id = ebooks.id AND ebook_tags.tag_id IN ?)",
+			Args:  []any{f.TagIDs},
+		})
+	}
+	if len(f.Tags) > 0 {
+		wheres = append(wheres, port.WhereClause{
+			Query: "EXISTS (SELECT 1 FROM ebook_tags JOIN tags ON tags.id = ebook_tags.tag_id WHERE ebook_tags.ebook_id = ebooks.id AND tags.deleted_at IS NULL AND LOWER(tags.name) IN ?)",
+			Args:  []any{lowerCatalogNames(f.Tags)},
+		})
+	}
+	return wheres
+}
+
+func lowerCatalogNames(names []string) []string {
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(normalizeCatalogName(name))
+	}
+	return lowered
+}
@@ -0,0 +1,174 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/sqlerr"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/booktext"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/job"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/storage"
+	"gorm.io/gorm"
+)
+
+// IndexContent extracts the text of the ebook's stored file and replaces the
+// ebook's passages with it. Ebooks that are gone, have no file yet or are
+// already indexed at their checksum are skipped. A file without extractable
+// text is recorded as failed rather than retried, as it would fail again.
+func (s *ebookService) IndexContent(ctx context.Context, ebookID uuid.UUID) (int, error) {
+	ebook, err := s.repo.GetByID(ctx, ebookID, nil)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	if s.storage == nil || s.contentRepo == nil {
+		return 0, fmt.Errorf("ebook content indexing is not available: %w", asynq.SkipRetry)
+	}
+
+	if index, err := s.contentRepo.GetIndexByEbookID(ctx, ebookID); err == nil {
+		if contentIndexCurrent(index, ebook) {
+			return index.PassageCount, nil
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, err
+	}
+
+	body, err := s.storage.Open(ctx, ebookFileKey(s.storageCfg, ebook))
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer body.Close()
+
+	// zip archives and PDFs are read from the end, so the file is spooled to disk first
+	tmp, err := os.CreateTemp("", "ebook-content-*")
+	if err != nil {
+		return 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, body)
+	if err != nil {
+		return 0, err
+	}
+
+	// uploads are verified against the ebook's checksum, which never changes, so
+	// the stored file is the one it identifies
+	index := &domain.EbookContentIndex{
+		EbookID:        ebook.ID,
+		ChecksumSHA256: strings.ToLower(ebook.ChecksumSHA256),
+		IndexedAt:      time.Now().UTC(),
+	}
+
+	doc, err := booktext.Extract(string(ebook.Format), tmp, size)
+	if err != nil {
+		message := err.Error()
+		index.Status = domain.EbookContentIndexStatusFailed
+		index.Error = &message
+		return 0, s.contentRepo.ReplaceIndex(ctx, index, nil)
+	}
+
+	passages := doc.Passages()
+	rows := make([]domain.EbookPassage, len(passages))
+	for i, passage := range passages {
+		rows[i] = domain.EbookPassage{
+			EbookID:  ebook.ID,
+			Position: i,
+			Location: passage.Location,
+			Content:  passage.Text,
+		}
+	}
+	index.Status = domain.EbookContentIndexStatusIndexed
+	index.PassageCount = len(rows)
+	if err := s.contentRepo.ReplaceIndex(ctx, index, rows); err != nil {
+		return 0, err
+	}
+	return len(rows), nil
+}
+
+// SearchContent searches the text of one of the user's ebooks. Ebooks whose
+// file has not been indexed yet are queued for indexing and fail with a
+// conflict, as do files without extractable text.
+func (s *ebookService) SearchContent(ctx context.Context, input *applicationdto.SearchEbookContentInput) ([]domain.EbookPassageHit, int64, error) {
+	if input == nil {
+		return nil, 0, errs.NewBadRequestError("search payload is required", true, nil, nil)
+	}
+	if s.storage == nil || s.contentRepo == nil {
+		return nil, 0, errs.NewInternalServerError()
+	}
+
+	query, err := newSearchQuery(input.Query, input.Limit, input.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	ebook, err := s.GetByID(ctx, input.EbookID, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	if ebook.OwnerUserID != input.UserID {
+		return nil, 0, errs.NewNotFoundError("ebook not found", true)
+	}
+
+	index, err := s.contentRepo.GetIndexByEbookID(ctx, ebook.ID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, sqlerr.HandleError(err)
+	}
+	if err != nil || !contentIndexCurrent(index, ebook) {
+		if _, err := s.storage.Stat(ctx, ebookFileKey(s.storageCfg, ebook)); err != nil {
+			if errors.Is(err, storage.ErrObjectNotFound) {
+				return nil, 0, errs.NewNotFoundError("ebook file has not been uploaded", true)
+			}
+			return nil, 0, err
+		}
+
+		// files uploaded before indexing existed are indexed on first search
+		s.enqueueContentIndex(ctx, ebook.ID)
+		return nil, 0, errs.NewConflictError("ebook content is being indexed, try again shortly", true, nil)
+	}
+	if index.Status == domain.EbookContentIndexStatusFailed {
+		return nil, 0, errs.NewConflictError("ebook file has no searchable text", true, index)
+	}
+
+	hits, total, err := s.contentRepo.SearchPassages(ctx, ebook.ID, query)
+	if err != nil {
+		return nil, 0, sqlerr.HandleError(err)
+	}
+	return hits, total, nil
+}
+
+// enqueueContentIndex queues the indexing of an ebook's text. Like enrichment,
+// a failure to queue it is only logged, and searching queues it again.
+func (s *ebookService) enqueueContentIndex(ctx context.Context, ebookID uuid.UUID) {
+	if s.taskEnqueuer == nil {
+		return
+	}
+
+	task, err := job.NewEbookContentIndexTask(ebookID)
+	if err == nil {
+		_, err = s.taskEnqueuer.EnqueueContext(ctx, task)
+	}
+	if err != nil && !errors.Is(err, asynq.ErrDuplicateTask) && s.logger != nil {
+		s.logger.Error().Err(err).Str("ebook_id", ebookID.String()).Msg("failed to queue ebook content indexing")
+	}
+}
+
+func contentIndexCurrent(index *domain.EbookContentIndex, ebook *domain.Ebook) bool {
+	return strings.EqualFold(index.ChecksumSHA256, ebook.ChecksumSHA256)
+}
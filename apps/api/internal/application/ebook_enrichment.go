@@ -0,0 +1,396 @@
+package application
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/sqlerr"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/epub"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/googlebooks"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/job"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/storage"
+	"gorm.io/gorm"
+)
+
+const (
+	// enrichmentAttachScore is the score from which a candidate is attached
+	// without asking the owner.
+	enrichmentAttachScore = 0.85
+	// enrichmentSuggestionScore is the least score a candidate needs to be suggested.
+	enrichmentSuggestionScore = 0.3
+	enrichmentMaxSuggestions  = 5
+	enrichmentSearchResults   = 10
+)
+
+// enrichmentHints is what is known about an ebook to look it up by.
+type enrichmentHints struct {
+	Title   string
+	Authors []string
+	// ISBN is normalized to ISBN-13.
+	ISBN string
+}
+
+type enrichmentCandidate struct {
+	Volume googlebooks.Volume
+	Score  float64
+}
+
+// EnrichMetadata looks the ebook up on Google Books by its ISBN, title and
+// authors, reading the EPUB package metadata when the file has been uploaded.
+// A confident match is attached; otherwise the likeliest candidates replace the
+// ebook's suggestions. Ebooks that are gone or already have metadata are skipped.
+func (s *ebookService) EnrichMetadata(ctx context.Context, ebookID uuid.UUID) (attached bool, suggested int, err error) {
+	ebook, err := s.repo.GetByID(ctx, ebookID, nil)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, 0, nil
+	}
+	if err != nil {
+		return false, 0, err
+	}
+
+	if _, err := s.metadataRepo.GetByEbookID(ctx, ebookID); err == nil {
+		return false, 0, nil
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, 0, err
+	}
+
+	if s.googleBooks == nil {
+		return false, 0, fmt.Errorf("google books lookups are not available: %w", asynq.SkipRetry)
+	}
+
+	hints, err := s.enrichmentHints(ctx, ebook)
+	if err != nil {
+		return false, 0, err
+	}
+
+	candidates, err := s.findCandidates(ctx, hints)
+	if err != nil {
+		return false, 0, err
+	}
+
+	if len(candidates) > 0 && candidates[0].Score >= enrichmentAttachScore {
+		if _, err := s.attachVolume(ctx, ebookID, candidates[0].Volume.ID); err != nil {
+			return false, 0, err
+		}
+		return true, 0, nil
+	}
+
+	suggestions := make([]domain.EbookMetadataSuggestion, 0, enrichmentMaxSuggestions)
+	for _, candidate := range candidates {
+		if candidate.Score < enrichmentSuggestionScore || len(suggestions) == enrichmentMaxSuggestions {
+			break
+		}
+		suggestions = append(suggestions, domain.EbookMetadataSuggestion{
+			ID:            uuid.New(),
+			EbookID:       ebookID,
+			GoogleBooksID: candidate.Volume.ID,
+			Title:         candidate.Volume.Title,
+			Authors:       candidate.Volume.Authors,
+			PublishedDate: candidate.Volume.PublishedDate,
+			ISBN13:        volumeISBN(&candidate.Volume),
+			ThumbnailURL:  candidate.Volume.ThumbnailURL,
+			Score:         candidate.Score,
+		})
+	}
+
+	if err := s.suggestionRepo.ReplaceByEbookID(ctx, ebookID, suggestions); err != nil {
+		return false, 0, err
+	}
+	return false, len(suggestions), nil
+}
+
+func (s *ebookService) ListMetadataSuggestions(ctx context.Context, ebookID uuid.UUID) ([]domain.EbookMetadataSuggestion, error) {
+	if _, err := s.GetByID(ctx, ebookID, nil); err != nil {
+		return nil, err
+	}
+
+	suggestions, err := s.suggestionRepo.ListByEbookID(ctx, ebookID)
+	if err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+	return suggestions, nil
+}
+
+// AcceptMetadataSuggestion attaches the suggested volume to the ebook, which
+// dismisses the ebook's other suggestions.
+func (s *ebookService) AcceptMetadataSuggestion(ctx context.Context, ebookID uuid.UUID, suggestionID uuid.UUID) (*domain.EbookGoogleMetadata, error) {
+	if _, err := s.GetByID(ctx, ebookID, nil); err != nil {
+		return nil, err
+	}
+
+	suggestion, err := s.suggestionRepo.GetByID(ctx, suggestionID)
+	if errors.Is(err, gorm.ErrRecordNotFound) || (err == nil && suggestion.EbookID != ebookID) {
+		return nil, errs.NewNotFoundError("metadata suggestion not found", true)
+	}
+	if err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+
+	return s.attachVolume(ctx, ebookID, suggestion.GoogleBooksID)
+}
+
+func (s *ebookService) DismissMetadataSuggestions(ctx context.Context, ebookID uuid.UUID) error {
+	if _, err := s.GetByID(ctx, ebookID, nil); err != nil {
+		return err
+	}
+
+	if err := s.suggestionRepo.DeleteByEbookID(ctx, ebookID); err != nil {
+		return sqlerr.HandleError(err)
+	}
+	return nil
+}
+
+// enqueueEnrichment queues the metadata lookup of an ebook. Enrichment is a
+// convenience, so a failure to queue it is only logged.
+func (s *ebookService) enqueueEnrichment(ctx context.Context, ebookID uuid.UUID) {
+	if s.taskEnqueuer == nil {
+		return
+	}
+
+	task, err := job.NewEbookEnrichmentTask(ebookID)
+	if err == nil {
+		_, err = s.taskEnqueuer.EnqueueContext(ctx, task)
+	}
+	if err != nil && !errors.Is(err, asynq.ErrDuplicateTask) && s.logger != nil {
+		s.logger.Error().Err(err).Str("ebook_id", ebookID.String()).Msg("failed to queue ebook enrichment")
+	}
+}
+
+// enrichmentHints collects the ebook's title, and the authors and ISBN of its
+// EPUB package when the file has been uploaded. A file that cannot be parsed
+// only costs the hints it would have given.
+func (s *ebookService) enrichmentHints(ctx context.Context, ebook *domain.Ebook) (*enrichmentHints, error) {
+	hints := &enrichmentHints{Title: strings.TrimSpace(ebook.Title)}
+	if ebook.Format != domain.EbookFormatEPUB || s.storage == nil {
+		return hints, nil
+	}
+
+	body, err := s.storage.Open(ctx, ebookFileKey(s.storageCfg, ebook))
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return hints, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	// zip archives are read from the end, so the file is spooled to disk first
+	tmp, err := os.CreateTemp("", "ebook-enrichment-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	size, err := io.Copy(tmp, body)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata, err := epub.ReadMetadata(tmp, size)
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Warn().Err(err).Str("ebook_id", ebook.ID.String()).Msg("could not read epub metadata")
+		}
+		return hints, nil
+	}
+
+	if hints.Title == "" {
+		hints.Title = metadata.Title
+	}
+	hints.Authors = metadata.Creators
+	for _, identifier := range metadata.Identifiers {
+		if isbn, ok := identifierISBN(identifier); ok {
+			hints.ISBN = isbn
+			break
+		}
+	}
+	return hints, nil
+}
+
+// findCandidates searches Google Books by ISBN and by title and author, and
+// returns every volume found, best match first.
+func (s *ebookService) findCandidates(ctx context.Context, hints *enrichmentHints) ([]enrichmentCandidate, error) {
+	queries := []string{}
+	if hints.ISBN != "" {
+		queries = append(queries, "isbn:"+hints.ISBN)
+	}
+	if title := strings.ReplaceAll(hints.Title, `"`, ""); title != "" {
+		query := `intitle:"` + title + `"`
+		if len(hints.Authors) > 0 {
+			query += ` inauthor:"` + strings.ReplaceAll(hints.Authors[0], `"`, "") + `"`
+		}
+		queries = append(queries, query)
+	}
+
+	candidates := []enrichmentCandidate{}
+	seen := map[string]bool{}
+	for _, query := range queries {
+		volumes, err := s.googleBooks.Search(ctx, query, enrichmentSearchResults)
+		if err != nil {
+			return nil, err
+		}
+		for _, volume := range volumes {
+			if volume.ID == "" || seen[volume.ID] {
+				continue
+			}
+			seen[volume.ID] = true
+			candidates = append(candidates, enrichmentCandidate{Volume: volume, Score: scoreCandidate(hints, &volume)})
+		}
+	}
+
+	// stable, so equally good volumes keep the order Google Books ranked them in
+	slices.SortStableFunc(candidates, func(a, b enrichmentCandidate) int {
+		return cmp.Compare(b.Score, a.Score)
+	})
+	return candidates, nil
+}
+
+// scoreCandidate rates how closely a volume matches the hints, from 0 to 1. An
+// ISBN match is near certain as long as the titles agree; without one the score
+// rests on the title and authors, and a title alone never reaches the
+// enrichmentAttachScore.
+func scoreCandidate(hints *enrichmentHints, volume *googlebooks.Volume) float64 {
+	title := titleSimilarity(hints.Title, volume)
+	switch {
+	case hints.ISBN != "" && hints.ISBN == derefString(volumeISBN(volume)):
+		return 0.7 + 0.3*title
+	case len(hints.Authors) > 0:
+		return 0.6*title + 0.4*authorSimilarity(hints.Authors, volume.Authors)
+	default:
+		return 0.8 * title
+	}
+}
+
+// titleSimilarity is the Dice coefficient of the words of the title and of the
+// volume's title, with and without its subtitle, whichever is higher.
+func titleSimilarity(title string, volume *googlebooks.Volume) float64 {
+	words := titleWords(title)
+	similarity := diceCoefficient(words, titleWords(volume.Title))
+	if volume.Subtitle != nil {
+		similarity = max(similarity, diceCoefficient(words, titleWords(volume.Title+" "+*volume.Subtitle)))
+	}
+	return similarity
+}
+
+// authorSimilarity is the share of authors whose last name appears among the
+// names of the volume's authors.
+func authorSimilarity(authors []string, volumeAuthors []string) float64 {
+	names := map[string]bool{}
+	for _, author := range volumeAuthors {
+		for _, word := range titleWords(author) {
+			names[word] = true
+		}
+	}
+
+	matched := 0
+	for _, author := range authors {
+		// EPUB creators are often filed as "Herbert, Frank"
+		if last, _, ok := strings.Cut(author, ","); ok {
+			author = last
+		} else if fields := strings.Fields(author); len(fields) > 0 {
+			author = fields[len(fields)-1]
+		}
+		if words := titleWords(author); len(words) > 0 && names[words[len(words)-1]] {
+			matched++
+		}
+	}
+	return float64(matched) / float64(len(authors))
+}
+
+func titleWords(value string) []string {
+	return strings.FieldsFunc(strings.ToLower(value), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func diceCoefficient(a []string, b []string) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+
+	counts := map[string]int{}
+	for _, word := range a {
+		counts[word]++
+	}
+	shared := 0
+	for _, word := range b {
+		if counts[word] > 0 {
+			counts[word]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(a)+len(b))
+}
+
+// volumeISBN returns the volume's ISBN-13, derived from its ISBN-10 if needed.
+func volumeISBN(volume *googlebooks.Volume) *string {
+	for _, isbn := range []*string{volume.ISBN13, volume.ISBN10} {
+		if isbn == nil {
+			continue
+		}
+		if normalized, ok := normalizeISBN(*isbn); ok {
+			isbn13 := toISBN13(normalized)
+			return &isbn13
+		}
+	}
+	return nil
+}
+
+// identifierISBN returns the ISBN-13 an EPUB identifier holds, if any. ISBNs are
+// marked with an ISBN scheme or written as an "urn:isbn:" URN.
+func identifierISBN(identifier epub.Identifier) (string, bool) {
+	value := identifier.Value
+	lower := strings.ToLower(value)
+	switch {
+	case strings.HasPrefix(lower, "urn:isbn:"):
+		value = value[len("urn:isbn:"):]
+	case strings.HasPrefix(lower, "isbn:"):
+		value = value[len("isbn:"):]
+	case !strings.EqualFold(identifier.Scheme, "isbn"):
+		return "", false
+	}
+
+	isbn, ok := normalizeISBN(value)
+	if !ok {
+		return "", false
+	}
+	return toISBN13(isbn), true
+}
+
+// toISBN13 converts a normalized ISBN-10 to its ISBN-13, and returns an ISBN-13
+// unchanged.
+func toISBN13(isbn string) string {
+	if len(isbn) != 10 {
+		return isbn
+	}
+
+	digits := "978" + isbn[:9]
+	sum := 0
+	for i, r := range digits {
+		weight := 1
+		if i%2 == 1 {
+			weight = 3
+		}
+		sum += int(r-'0') * weight
+	}
+	return digits + string(rune('0'+(10-sum%10)%10))
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -0,0 +1,131 @@
+package application
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/googlebooks"
+)
+
+// MaxGoogleBooksResults caps how many volumes a single search returns, which is
+// also the most Google Books returns per request.
+const MaxGoogleBooksResults = 40
+
+// GoogleBooksClient looks volumes up on Google Books. It returns
+// googlebooks.ErrVolumeNotFound for unknown volumes and wraps
+// googlebooks.ErrUnavailable when Google Books cannot answer.
+type GoogleBooksClient interface {
+	Search(ctx context.Context, query string, limit int) ([]googlebooks.Volume, error)
+	GetVolume(ctx context.Context, volumeID string) (*googlebooks.Volume, error)
+}
+
+// SearchGoogleBooks searches Google Books by free text or by ISBN; exactly one
+// of query and isbn must be given.
+func (s *ebookService) SearchGoogleBooks(ctx context.Context, query string, isbn string, limit int) ([]googlebooks.Volume, error) {
+	query = strings.TrimSpace(query)
+	isbn = strings.TrimSpace(isbn)
+	switch {
+	case query == "" && isbn == "":
+		return nil, errs.NewBadRequestError("either q or isbn is required", true, nil, nil)
+	case query != "" && isbn != "":
+		return nil, errs.NewBadRequestError("only one of q and isbn may be given", true, nil, nil)
+	}
+
+	if isbn != "" {
+		normalized, ok := normalizeISBN(isbn)
+		if !ok {
+			return nil, errs.NewBadRequestError("isbn must be a 10 or 13 digit ISBN", true, []errs.FieldError{{Field: "isbn", Error: "is invalid"}}, nil)
+		}
+		query = "isbn:" + normalized
+	}
+	if limit <= 0 || limit > MaxGoogleBooksResults {
+		limit = MaxGoogleBooksResults
+	}
+
+	if s.googleBooks == nil {
+		return nil, errs.NewServiceUnavailableError("Google Books lookups are not available", true)
+	}
+	volumes, err := s.googleBooks.Search(ctx, query, limit)
+	if err != nil {
+		return nil, googleBooksError(err)
+	}
+	return volumes, nil
+}
+
+// AttachGoogleVolume fetches a volume from Google Books and attaches its
+// metadata to the ebook, replacing any metadata attached before.
+func (s *ebookService) AttachGoogleVolume(ctx context.Context, ebookID uuid.UUID, volumeID string) (*domain.EbookGoogleMetadata, error) {
+	volumeID = strings.TrimSpace(volumeID)
+	if volumeID == "" {
+		return nil, errs.NewBadRequestError("volume id is required", true, nil, nil)
+	}
+
+	// Check the ebook first, so a missing or foreign ebook never costs a lookup.
+	if _, err := s.GetByID(ctx, ebookID, nil); err != nil {
+		return nil, err
+	}
+
+	return s.attachVolume(ctx, ebookID, volumeID)
+}
+
+func (s *ebookService) attachVolume(ctx context.Context, ebookID uuid.UUID, volumeID string) (*domain.EbookGoogleMetadata, error) {
+	if s.googleBooks == nil {
+		return nil, errs.NewServiceUnavailableError("Google Books lookups are not available", true)
+	}
+	volume, err := s.googleBooks.GetVolume(ctx, volumeID)
+	if err != nil {
+		return nil, googleBooksError(err)
+	}
+
+	return s.storeMetadata(ctx, googleVolumeMetadata(ebookID, volume))
+}
+
+func googleVolumeMetadata(ebookID uuid.UUID, volume *googlebooks.Volume) *domain.EbookGoogleMetadata {
+	return &domain.EbookGoogleMetadata{
+		EbookID:       ebookID,
+		GoogleBooksID: volume.ID,
+		ISBN10:        volume.ISBN10,
+		ISBN13:        volume.ISBN13,
+		Publisher:     volume.Publisher,
+		PublishedDate: volume.PublishedDate,
+		PageCount:     volume.PageCount,
+		Categories:    volume.Categories,
+		ThumbnailURL:  volume.ThumbnailURL,
+		InfoLink:      volume.InfoLink,
+		RawPayload:    volume.Raw,
+	}
+}
+
+func googleBooksError(err error) error {
+	if errors.Is(err, googlebooks.ErrVolumeNotFound) {
+		return errs.NewNotFoundError("Google Books volume not found", true)
+	}
+	return errs.NewServiceUnavailableError("Google Books is unavailable, please try again later", true)
+}
+
+// normalizeISBN strips separators from an ISBN-10 or ISBN-13 and reports whether
+// what remains is well formed.
+func normalizeISBN(isbn string) (string, bool) {
+	isbn = strings.ToUpper(strings.NewReplacer("-", "", " ", "").Replace(isbn))
+	switch len(isbn) {
+	case 10, 13:
+	default:
+		return "", false
+	}
+
+	for i, r := range isbn {
+		if r >= '0' && r <= '9' {
+			continue
+		}
+		// an ISBN-10 check digit of 10 is written as X
+		if r == 'X' && len(isbn) == 10 && i == 9 {
+			continue
+		}
+		return "", false
+	}
+	return isbn, true
+}
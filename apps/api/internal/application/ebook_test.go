@@ -0,0 +1,780 @@
+package application
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/config"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/cache"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/googlebooks"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/job"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/storage"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/repository"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type testStorage struct {
+	objects map[string][]byte
+}
+
+func newTestStorage() *testStorage {
+	return &testStorage{objects: map[string][]byte{}}
+}
+
+func (s *testStorage) Save(ctx context.Context, key string, reader io.Reader, size int64, contentType string) (*storage.Object, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	s.objects[key] = data
+	return &storage.Object{Path: key, URL: "/static/" + key, Size: int64(len(data))}, nil
+}
+
+func (s *testStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, storage.ErrObjectNotFound
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *testStorage) OpenRange(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, storage.ErrObjectNotFound
+	}
+	data = data[offset:]
+	if length >= 0 && length < int64(len(data)) {
+		data = data[:length]
+	}
+	return io.NopCloser(bytes.NewReader(data)), nil
+}
+
+func (s *testStorage) Stat(ctx context.Context, key string) (*storage.ObjectInfo, error) {
+	data, ok := s.objects[key]
+	if !ok {
+		return nil, storage.ErrObjectNotFound
+	}
+	return &storage.ObjectInfo{Key: key, Size: int64(len(data))}, nil
+}
+
+func (s *testStorage) SignedURL(ctx context.Context, key string, expiresIn time.Duration) (string, error) {
+	return "/files/" + key + "?signature=test", nil
+}
+
+func (s *testStorage) Delete(ctx context.Context, key string) error {
+	delete(s.objects, key)
+	return nil
+}
+
+func newEbookServiceForTest(t *testing.T, content []byte) (EbookService, *repository.MockResourceRepository[domain.Ebook], *testStorage, *domain.Ebook) {
+	t.Helper()
+
+	repo := repository.NewMockResourceRepository[domain.Ebook](false)
+	fileStorage := newTestStorage()
+	sum := sha256.Sum256(content)
+	ebook := &domain.Ebook{
+		ID:             uuid.New(),
+		OwnerUserID:    uuid.New(),
+		Title:          "Dune",
+		Format:         domain.EbookFormatEPUB,
+		StorageKey:     "/home/reader/books/dune.epub",
+		FileSizeBytes:  int64(len(content)),
+		ChecksumSHA256: hex.EncodeToString(sum[:]),
+	}
+	require.NoError(t, repo.Store(context.Background(), ebook))
+
+	svc := NewEbookService(repo, nil, nil, nil, nil, nil, fileStorage, &config.FileStorageConfig{PathPrefix: "uploads", MaxUploadSizeMB: 1}, nil, nil, nil)
+	return svc, repo, fileStorage, ebook
+}
+
+func TestEbookServiceUploadFile_StoresVerifiedFile(t *testing.T) {
+	content := []byte("epub-bytes")
+	svc, _, fileStorage, ebook := newEbookServiceForTest(t, content)
+
+	uploaded, err := svc.UploadFile(context.Background(), &applicationdto.UploadEbookFileInput{
+		EbookID: ebook.ID,
+		UserID:  ebook.OwnerUserID,
+		Reader:  bytes.NewReader(content),
+		Size:    int64(len(content)),
+	})
+	require.NoError(t, err)
+
+	expectedKey := "uploads/ebooks/" + ebook.OwnerUserID.String() + "/" + ebook.ID.String() + ".epub"
+	require.Equal(t, ebook.StorageKey, uploaded.StorageKey)
+	require.Equal(t, content, fileStorage.objects[expectedKey])
+
+	file, err := svc.OpenFile(context.Background(), ebook.ID, ebook.OwnerUserID)
+	require.NoError(t, err)
+	defer file.Body.Close()
+
+	downloaded, err := io.ReadAll(file.Body)
+	require.NoError(t, err)
+	require.Equal(t, content, downloaded)
+	require.Equal(t, "application/epub+zip", file.ContentType)
+}
+
+func TestEbookServiceUploadFile_RejectsChecksumMismatch(t *testing.T) {
+	content := []byte("epub-bytes")
+	svc, repo, fileStorage, ebook := newEbookServiceForTest(t, content)
+
+	_, err := svc.UploadFile(context.Background(), &applicationdto.UploadEbookFileInput{
+		EbookID: ebook.ID,
+		UserID:  ebook.OwnerUserID,
+		Reader:  bytes.NewReader([]byte("epub-byteX")),
+	})
+	require.Error(t, err)
+
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusBadRequest, httpErr.Status)
+	require.Empty(t, fileStorage.objects)
+
+	stored, err := repo.GetByID(context.Background(), ebook.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, ebook.StorageKey, stored.StorageKey)
+}
+
+func TestEbookServiceUploadFile_RejectsTruncatedStream(t *testing.T) {
+	content := []byte("epub-bytes")
+	svc, _, fileStorage, ebook := newEbookServiceForTest(t, content)
+
+	_, err := svc.UploadFile(context.Background(), &applicationdto.UploadEbookFileInput{
+		EbookID: ebook.ID,
+		UserID:  ebook.OwnerUserID,
+		Reader:  bytes.NewReader(content[:4]),
+	})
+	require.Error(t, err)
+
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusBadRequest, httpErr.Status)
+	require.Empty(t, fileStorage.objects)
+}
+
+func TestEbookServiceOpenFile_ForbidsNonOwner(t *testing.T) {
+	content := []byte("epub-bytes")
+	svc, _, _, ebook := newEbookServiceForTest(t, content)
+
+	_, err := svc.UploadFile(context.Background(), &applicationdto.UploadEbookFileInput{
+		EbookID: ebook.ID,
+		UserID:  ebook.OwnerUserID,
+		Reader:  bytes.NewReader(content),
+	})
+	require.NoError(t, err)
+
+	_, err = svc.OpenFile(context.Background(), ebook.ID, uuid.New())
+	require.Error(t, err)
+
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusForbidden, httpErr.Status)
+}
+
+type testMetadataRepo struct {
+	stored map[uuid.UUID]domain.EbookGoogleMetadata
+}
+
+func (r *testMetadataRepo) GetByEbookID(ctx context.Context, ebookID uuid.UUID) (*domain.EbookGoogleMetadata, error) {
+	metadata, ok := r.stored[ebookID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &metadata, nil
+}
+
+func (r *testMetadataRepo) Upsert(ctx context.Context, metadata *domain.EbookGoogleMetadata) error {
+	r.stored[metadata.EbookID] = *metadata
+	return nil
+}
+
+func (r *testMetadataRepo) SoftDeleteByEbookID(ctx context.Context, ebookID uuid.UUID) error {
+	delete(r.stored, ebookID)
+	return nil
+}
+
+type testCache struct {
+	entries map[string][]byte
+}
+
+func (c *testCache) Get(ctx context.Context, key string) ([]byte, error) {
+	data, ok := c.entries[key]
+	if !ok {
+		return nil, cache.ErrCacheMiss
+	}
+	return data, nil
+}
+
+func (c *testCache) Set(ctx context.Context, key string, value []byte, ttl ...time.Duration) error {
+	c.entries[key] = value
+	return nil
+}
+
+func (c *testCache) SetJSON(ctx context.Context, key string, value any, ttl ...time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	c.entries[key] = data
+	return nil
+}
+
+func (c *testCache) GetJSON(ctx context.Context, key string, dest any) error {
+	data, err := c.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func (c *testCache) Delete(ctx context.Context, keys ...string) error {
+	for _, key := range keys {
+		delete(c.entries, key)
+	}
+	return nil
+}
+
+type testSuggestionRepo struct {
+	suggestions []domain.EbookMetadataSuggestion
+}
+
+func (r *testSuggestionRepo) ListByEbookID(ctx context.Context, ebookID uuid.UUID) ([]domain.EbookMetadataSuggestion, error) {
+	listed := []domain.EbookMetadataSuggestion{}
+	for _, suggestion := range r.suggestions {
+		if suggestion.EbookID == ebookID {
+			listed = append(listed, suggestion)
+		}
+	}
+	return listed, nil
+}
+
+func (r *testSuggestionRepo) GetByID(ctx context.Context, id uuid.UUID) (*domain.EbookMetadataSuggestion, error) {
+	for _, suggestion := range r.suggestions {
+		if suggestion.ID == id {
+			return &suggestion, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *testSuggestionRepo) ReplaceByEbookID(ctx context.Context, ebookID uuid.UUID, suggestions []domain.EbookMetadataSuggestion) error {
+	_ = r.DeleteByEbookID(ctx, ebookID)
+	r.suggestions = append(r.suggestions, suggestions...)
+	return nil
+}
+
+func (r *testSuggestionRepo) DeleteByEbookID(ctx context.Context, ebookID uuid.UUID) error {
+	r.suggestions = slices.DeleteFunc(r.suggestions, func(suggestion domain.EbookMetadataSuggestion) bool {
+		return suggestion.EbookID == ebookID
+	})
+	return nil
+}
+
+type googleBooksTestFixture struct {
+	svc            EbookService
+	metadataRepo   *testMetadataRepo
+	suggestionRepo *testSuggestionRepo
+	storage        *testStorage
+	enqueuer       *mockTaskEnqueuer
+	ebook          *domain.Ebook
+	// ctx acts as the ebook's owner.
+	ctx context.Context
+}
+
+// newGoogleBooksEbookServiceForTest wires the ebook service to a Google Books
+// client talking to a local fake of the API.
+func newGoogleBooksEbookServiceForTest(t *testing.T, googleBooksAPI http.HandlerFunc) *googleBooksTestFixture {
+	t.Helper()
+
+	server := httptest.NewServer(googleBooksAPI)
+	t.Cleanup(server.Close)
+
+	repo := repository.NewMockResourceRepository[domain.Ebook](false)
+	ebook := &domain.Ebook{ID: uuid.New(), OwnerUserID: uuid.New(), Title: "Dune", Format: domain.EbookFormatEPUB}
+	require.NoError(t, repo.Store(context.Background(), ebook))
+
+	client := googlebooks.NewClient(&config.GoogleBooksConfig{
+		BaseURL:      server.URL,
+		CacheTTL:     time.Hour,
+		MaxRetries:   2,
+		RetryBackoff: time.Millisecond,
+	}, server.Client(), &testCache{entries: map[string][]byte{}}, nil)
+
+	f := &googleBooksTestFixture{
+		metadataRepo:   &testMetadataRepo{stored: map[uuid.UUID]domain.EbookGoogleMetadata{}},
+		suggestionRepo: &testSuggestionRepo{},
+		storage:        newTestStorage(),
+		enqueuer:       &mockTaskEnqueuer{},
+		ebook:          ebook,
+		ctx:            WithActor(context.Background(), Actor{UserID: ebook.OwnerUserID}),
+	}
+	f.svc = NewEbookService(repo, f.metadataRepo, f.suggestionRepo, nil, nil, nil, f.storage, &config.FileStorageConfig{PathPrefix: "uploads"}, client, f.enqueuer, nil)
+	return f
+}
+
+func TestEbookServiceAttachGoogleVolume_RetriesAndCachesVolume(t *testing.T) {
+	requests := 0
+	f := newGoogleBooksEbookServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		require.Equal(t, "/volumes/B1hSG45JCX4C", r.URL.Path)
+		_, _ = io.WriteString(w, `{
+			"id": "B1hSG45JCX4C",
+			"volumeInfo": {
+				"title": "Dune",
+				"authors": ["Frank Herbert"],
+				"publisher": "Penguin",
+				"publishedDate": "2005-08-02",
+				"pageCount": 896,
+				"categories": ["Fiction"],
+				"industryIdentifiers": [
+					{"type": "ISBN_10", "identifier": "0441172717"},
+					{"type": "ISBN_13", "identifier": "9780441172719"}
+				],
+				"imageLinks": {"thumbnail": "http://books.google.com/books/content?id=B1hSG45JCX4C"}
+			}
+		}`)
+	})
+
+	metadata, err := f.svc.AttachGoogleVolume(f.ctx, f.ebook.ID, "B1hSG45JCX4C")
+	require.NoError(t, err)
+	require.Equal(t, 2, requests)
+	require.Equal(t, "B1hSG45JCX4C", metadata.GoogleBooksID)
+	require.Equal(t, "0441172717", *metadata.ISBN10)
+	require.Equal(t, "9780441172719", *metadata.ISBN13)
+	require.Equal(t, 896, *metadata.PageCount)
+	require.Equal(t, []string{"Fiction"}, metadata.Categories)
+	require.Equal(t, "https://books.google.com/books/content?id=B1hSG45JCX4C", *metadata.ThumbnailURL)
+	require.Equal(t, "Dune", metadata.RawPayload["volumeInfo"].(map[string]any)["title"])
+
+	delete(f.metadataRepo.stored, f.ebook.ID)
+	_, err = f.svc.AttachGoogleVolume(f.ctx, f.ebook.ID, "B1hSG45JCX4C")
+	require.NoError(t, err)
+	require.Equal(t, 2, requests)
+	require.Contains(t, f.metadataRepo.stored, f.ebook.ID)
+}
+
+func TestEbookServiceAttachGoogleVolume_UnknownVolume(t *testing.T) {
+	f := newGoogleBooksEbookServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	_, err := f.svc.AttachGoogleVolume(f.ctx, f.ebook.ID, "missing")
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusNotFound, httpErr.Status)
+	require.Empty(t, f.metadataRepo.stored)
+}
+
+func TestEbookServiceMetadata_HidesOtherUsersEbooks(t *testing.T) {
+	f := newGoogleBooksEbookServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("metadata changes must not reach Google Books")
+	})
+	ctx := WithActor(context.Background(), Actor{UserID: uuid.New()})
+
+	_, err := f.svc.AttachMetadata(ctx, &applicationdto.AttachGoogleMetadataInput{EbookID: f.ebook.ID, GoogleBooksID: "B1hSG45JCX4C"})
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusNotFound, httpErr.Status)
+	require.Empty(t, f.metadataRepo.stored)
+
+	f.metadataRepo.stored[f.ebook.ID] = domain.EbookGoogleMetadata{EbookID: f.ebook.ID}
+	err = f.svc.DetachMetadata(ctx, f.ebook.ID)
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusNotFound, httpErr.Status)
+	require.Contains(t, f.metadataRepo.stored, f.ebook.ID)
+}
+
+func TestEbookServiceSearchGoogleBooks_DegradesWhenRateLimited(t *testing.T) {
+	requests := 0
+	f := newGoogleBooksEbookServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		require.Equal(t, "isbn:9780441172719", r.URL.Query().Get("q"))
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	_, err := f.svc.SearchGoogleBooks(f.ctx, "", "978-0-441-17271-9", 10)
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusServiceUnavailable, httpErr.Status)
+	require.Equal(t, 1, requests)
+
+	// the API waits out the rate limit instead of retrying into it
+	_, err = f.svc.SearchGoogleBooks(f.ctx, "", "9780441172719", 10)
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusServiceUnavailable, httpErr.Status)
+	require.Equal(t, 1, requests)
+}
+
+func TestEbookServiceSearchGoogleBooks_ValidatesQuery(t *testing.T) {
+	f := newGoogleBooksEbookServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("invalid searches must not reach Google Books")
+	})
+
+	for _, tc := range []struct{ query, isbn string }{{"", ""}, {"dune", "9780441172719"}, {"", "97804411727"}, {"", "04411727X7"}} {
+		_, err := f.svc.SearchGoogleBooks(f.ctx, tc.query, tc.isbn, 10)
+		var httpErr *errs.ErrorResponse
+		require.ErrorAs(t, err, &httpErr)
+		require.Equal(t, http.StatusBadRequest, httpErr.Status)
+	}
+}
+
+// testEPUB builds a minimal EPUB whose package metadata has the given creator and ISBN.
+func testEPUB(t *testing.T, creator string, isbn string) []byte {
+	t.Helper()
+
+	return testZip(t, []struct{ name, content string }{
+		{"mimetype", "application/epub+zip"},
+		{"META-INF/container.xml", `<?xml version="1.0"?>
+<container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container">
+	<rootfiles><rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/></rootfiles>
+</container>`},
+		{"OEBPS/content.opf", `<?xml version="1.0"?>
+<package xmlns="http://www.idpf.org/2007/opf" version="2.0" unique-identifier="bookid">
+	<metadata xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:opf="http://www.idpf.org/2007/opf">
+		<dc:title>Dune</dc:title>
+		<dc:creator opf:role="aut">` + creator + `</dc:creator>
+		<dc:identifier id="bookid">urn:uuid:8b1f3c52-6c1e-4c8a-9d43-3c1f0f7e2a10</dc:identifier>
+		<dc:identifier opf:scheme="ISBN">` + isbn + `</dc:identifier>
+	</metadata>
+</package>`},
+	})
+}
+
+// testZip builds a zip archive of the given files, in order.
+func testZip(t *testing.T, files []struct{ name, content string }) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		w, err := archive.Create(file.name)
+		require.NoError(t, err)
+		_, err = io.WriteString(w, file.content)
+		require.NoError(t, err)
+	}
+	require.NoError(t, archive.Close())
+	return buf.Bytes()
+}
+
+const testDuneVolume = `{
+	"id": "B1hSG45JCX4C",
+	"volumeInfo": {
+		"title": "Dune",
+		"authors": ["Frank Herbert"],
+		"industryIdentifiers": [{"type": "ISBN_10", "identifier": "0441172717"}]
+	}
+}`
+
+func TestEbookServiceEnrichMetadata_AttachesISBNMatchFromEPUB(t *testing.T) {
+	f := newGoogleBooksEbookServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/volumes/B1hSG45JCX4C":
+			_, _ = io.WriteString(w, testDuneVolume)
+		case r.URL.Query().Get("q") == "isbn:9780441172719":
+			_, _ = io.WriteString(w, `{"items": [`+testDuneVolume+`]}`)
+		case r.URL.Query().Get("q") == `intitle:"Dune" inauthor:"Herbert, Frank"`:
+			_, _ = io.WriteString(w, `{"items": [{"id": "dune-messiah", "volumeInfo": {"title": "Dune Messiah", "authors": ["Frank Herbert"]}}]}`)
+		default:
+			t.Errorf("unexpected Google Books request %s", r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+	f.storage.objects[ebookFileKey(&config.FileStorageConfig{PathPrefix: "uploads"}, f.ebook)] = testEPUB(t, "Herbert, Frank", "0-441-17271-7")
+	f.suggestionRepo.suggestions = []domain.EbookMetadataSuggestion{{ID: uuid.New(), EbookID: f.ebook.ID, GoogleBooksID: "stale"}}
+
+	attached, suggested, err := f.svc.EnrichMetadata(context.Background(), f.ebook.ID)
+	require.NoError(t, err)
+	require.True(t, attached)
+	require.Zero(t, suggested)
+	require.Equal(t, "B1hSG45JCX4C", f.metadataRepo.stored[f.ebook.ID].GoogleBooksID)
+	require.Empty(t, f.suggestionRepo.suggestions)
+
+	// attached metadata is never overwritten by a later run
+	attached, _, err = f.svc.EnrichMetadata(context.Background(), f.ebook.ID)
+	require.NoError(t, err)
+	require.False(t, attached)
+}
+
+func TestEbookServiceEnrichMetadata_SuggestsWeakMatchesForTheOwnerToAccept(t *testing.T) {
+	f := newGoogleBooksEbookServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/volumes/dune-2005":
+			_, _ = io.WriteString(w, `{"id": "dune-2005", "volumeInfo": {"title": "Dune"}}`)
+		case "/volumes":
+			require.Equal(t, `intitle:"Dune"`, r.URL.Query().Get("q"))
+			_, _ = io.WriteString(w, `{"items": [
+				{"id": "dune-messiah", "volumeInfo": {"title": "Dune Messiah"}},
+				{"id": "dune-2005", "volumeInfo": {"title": "Dune"}},
+				{"id": "cookbook", "volumeInfo": {"title": "The Cookbook"}}
+			]}`)
+		default:
+			t.Errorf("unexpected Google Books request %s", r.URL)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	})
+
+	attached, suggested, err := f.svc.EnrichMetadata(context.Background(), f.ebook.ID)
+	require.NoError(t, err)
+	require.False(t, attached)
+	require.Equal(t, 2, suggested)
+	require.Empty(t, f.metadataRepo.stored)
+
+	suggestions, err := f.svc.ListMetadataSuggestions(f.ctx, f.ebook.ID)
+	require.NoError(t, err)
+	require.Len(t, suggestions, 2)
+	require.Equal(t, "dune-2005", suggestions[0].GoogleBooksID)
+	require.InDelta(t, 0.8, suggestions[0].Score, 0.001)
+	require.Equal(t, "dune-messiah", suggestions[1].GoogleBooksID)
+
+	_, err = f.svc.AcceptMetadataSuggestion(WithActor(context.Background(), Actor{UserID: uuid.New()}), f.ebook.ID, suggestions[0].ID)
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusNotFound, httpErr.Status)
+
+	metadata, err := f.svc.AcceptMetadataSuggestion(f.ctx, f.ebook.ID, suggestions[0].ID)
+	require.NoError(t, err)
+	require.Equal(t, "dune-2005", metadata.GoogleBooksID)
+	require.Empty(t, f.suggestionRepo.suggestions)
+}
+
+func TestEbookServiceStore_QueuesEnrichment(t *testing.T) {
+	f := newGoogleBooksEbookServiceForTest(t, func(w http.ResponseWriter, r *http.Request) {
+		t.Error("storing an ebook must not wait for Google Books")
+	})
+
+	ebook, err := f.svc.Store(f.ctx, &applicationdto.StoreEbookInput{
+		OwnerUserID:    f.ebook.OwnerUserID,
+		Title:          "Children of Dune",
+		Format:         domain.EbookFormatEPUB,
+		StorageKey:     "/home/reader/books/children-of-dune.epub",
+		FileSizeBytes:  10,
+		ChecksumSHA256: strings.Repeat("a", 64),
+	})
+	require.NoError(t, err)
+	require.True(t, f.enqueuer.called)
+	require.Equal(t, job.TaskEbookEnrichment, f.enqueuer.task.Type())
+
+	var payload job.EbookEnrichmentPayload
+	require.NoError(t, json.Unmarshal(f.enqueuer.task.Payload(), &payload))
+	require.Equal(t, ebook.ID, payload.EbookID)
+}
+
+type testContentRepo struct {
+	indexes  map[uuid.UUID]domain.EbookContentIndex
+	passages map[uuid.UUID][]domain.EbookPassage
+	replaced int
+	query    port.SearchQuery
+}
+
+func (r *testContentRepo) GetIndexByEbookID(ctx context.Context, ebookID uuid.UUID) (*domain.EbookContentIndex, error) {
+	index, ok := r.indexes[ebookID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return &index, nil
+}
+
+func (r *testContentRepo) ReplaceIndex(ctx context.Context, index *domain.EbookContentIndex, passages []domain.EbookPassage) error {
+	r.replaced++
+	r.indexes[index.EbookID] = *index
+	r.passages[index.EbookID] = passages
+	return nil
+}
+
+// SearchPassages matches the passages containing the query, ignoring case.
+func (r *testContentRepo) SearchPassages(ctx context.Context, ebookID uuid.UUID, query port.SearchQuery) ([]domain.EbookPassageHit, int64, error) {
+	r.query = query
+	hits := []domain.EbookPassageHit{}
+	for _, passage := range r.passages[ebookID] {
+		if strings.Contains(strings.ToLower(passage.Content), strings.ToLower(query.Text)) {
+			hits = append(hits, domain.EbookPassageHit{Position: passage.Position, Location: passage.Location, Snippet: passage.Content, Rank: 0.1})
+		}
+	}
+	return hits, int64(len(hits)), nil
+}
+
+type contentTestFixture struct {
+	svc         EbookService
+	contentRepo *testContentRepo
+	storage     *testStorage
+	enqueuer    *mockTaskEnqueuer
+	ebook       *domain.Ebook
+}
+
+// newContentEbookServiceForTest stores an ebook of the given format whose file
+// is content, without uploading it.
+func newContentEbookServiceForTest(t *testing.T, format domain.EbookFormat, content []byte) *contentTestFixture {
+	t.Helper()
+
+	repo := repository.NewMockResourceRepository[domain.Ebook](false)
+	sum := sha256.Sum256(content)
+	ebook := &domain.Ebook{
+		ID:             uuid.New(),
+		OwnerUserID:    uuid.New(),
+		Title:          "Dune",
+		Format:         format,
+		FileSizeBytes:  int64(len(content)),
+		ChecksumSHA256: hex.EncodeToString(sum[:]),
+	}
+	require.NoError(t, repo.Store(context.Background(), ebook))
+
+	f := &contentTestFixture{
+		contentRepo: &testContentRepo{indexes: map[uuid.UUID]domain.EbookContentIndex{}, passages: map[uuid.UUID][]domain.EbookPassage{}},
+		storage:     newTestStorage(),
+		enqueuer:    &mockTaskEnqueuer{},
+		ebook:       ebook,
+	}
+	f.svc = NewEbookService(repo, nil, nil, nil, nil, f.contentRepo, f.storage, &config.FileStorageConfig{PathPrefix: "uploads", MaxUploadSizeMB: 1}, nil, f.enqueuer, nil)
+	return f
+}
+
+func (f *contentTestFixture) upload(t *testing.T, content []byte) {
+	t.Helper()
+	_, err := f.svc.UploadFile(context.Background(), &applicationdto.UploadEbookFileInput{
+		EbookID: f.ebook.ID,
+		UserID:  f.ebook.OwnerUserID,
+		Reader:  bytes.NewReader(content),
+	})
+	require.NoError(t, err)
+}
+
+func TestEbookServiceIndexContent_IndexesTXTParagraphsByLocation(t *testing.T) {
+	content := []byte("In the week before their departure to Arrakis,\nwhen all the final scurrying about had reached a nearly unbearable frenzy,\n\nThe spice must flow.\n")
+	f := newContentEbookServiceForTest(t, domain.EbookFormatTXT, content)
+	f.upload(t, content)
+
+	require.True(t, f.enqueuer.called)
+	require.Equal(t, job.TaskEbookContentIndex, f.enqueuer.task.Type())
+
+	passages, err := f.svc.IndexContent(context.Background(), f.ebook.ID)
+	require.NoError(t, err)
+	require.Equal(t, 2, passages)
+	require.Equal(t, []domain.EbookPassage{
+		{EbookID: f.ebook.ID, Position: 0, Location: "fmt=txt;line=0", Content: "In the week before their departure to Arrakis, when all the final scurrying about had reached a nearly unbearable frenzy,"},
+		{EbookID: f.ebook.ID, Position: 1, Location: "fmt=txt;line=3", Content: "The spice must flow."},
+	}, f.contentRepo.passages[f.ebook.ID])
+
+	index := f.contentRepo.indexes[f.ebook.ID]
+	require.Equal(t, domain.EbookContentIndexStatusIndexed, index.Status)
+	require.Equal(t, f.ebook.ChecksumSHA256, index.ChecksumSHA256)
+
+	// the file is only indexed again once it changes
+	passages, err = f.svc.IndexContent(context.Background(), f.ebook.ID)
+	require.NoError(t, err)
+	require.Equal(t, 2, passages)
+	require.Equal(t, 1, f.contentRepo.replaced)
+}
+
+func TestEbookServiceIndexContent_LocatesEPUBPassagesLikeTheReader(t *testing.T) {
+	content := testZip(t, []struct{ name, content string }{
+		{"mimetype", "application/epub+zip"},
+		{"META-INF/container.xml", `<?xml version="1.0"?><container version="1.0" xmlns="urn:oasis:names:tc:opendocument:xmlns:container"><rootfiles><rootfile full-path="OEBPS/content.opf" media-type="application/oebps-package+xml"/></rootfiles></container>`},
+		{"OEBPS/content.opf", `<?xml version="1.0"?><package version="2.0" xmlns="http://www.idpf.org/2007/opf"><manifest><item id="ch1" href="ch1.xhtml" media-type="application/xhtml+xml"/><item id="ch2" href="ch2.xhtml" media-type="application/xhtml+xml"/></manifest><spine><itemref idref="ch1"/><itemref idref="ch2"/></spine></package>`},
+		{"OEBPS/ch1.xhtml", `<html><body><h1>Book One</h1><p>A beginning is the time<br/>for taking the most delicate care.</p></body></html>`},
+		{"OEBPS/ch2.xhtml", `<html><body><p>The spice must flow.</p></body></html>`},
+	})
+	f := newContentEbookServiceForTest(t, domain.EbookFormatEPUB, content)
+	f.upload(t, content)
+
+	_, err := f.svc.IndexContent(context.Background(), f.ebook.ID)
+	require.NoError(t, err)
+
+	// line 0 is the reader's heading of the first chapter and line 4 of the second
+	locations := []string{}
+	for _, passage := range f.contentRepo.passages[f.ebook.ID] {
+		locations = append(locations, passage.Location+" "+passage.Content)
+	}
+	require.Equal(t, []string{
+		"fmt=epub;spine=0;offset=1;line=1 Book One",
+		"fmt=epub;spine=0;offset=2;line=2 A beginning is the time",
+		"fmt=epub;spine=0;offset=3;line=3 for taking the most delicate care.",
+		"fmt=epub;spine=1;offset=1;line=5 The spice must flow.",
+	}, locations)
+}
+
+func TestEbookServiceIndexContent_RecordsFilesWithoutText(t *testing.T) {
+	content := []byte("%PDF-1.4 not really")
+	f := newContentEbookServiceForTest(t, domain.EbookFormatPDF, content)
+
+	// nothing to index before the file is uploaded
+	passages, err := f.svc.IndexContent(context.Background(), f.ebook.ID)
+	require.NoError(t, err)
+	require.Zero(t, passages)
+	require.Zero(t, f.contentRepo.replaced)
+
+	f.upload(t, content)
+	passages, err = f.svc.IndexContent(context.Background(), f.ebook.ID)
+	require.NoError(t, err)
+	require.Zero(t, passages)
+
+	index := f.contentRepo.indexes[f.ebook.ID]
+	require.Equal(t, domain.EbookContentIndexStatusFailed, index.Status)
+	require.NotNil(t, index.Error)
+	require.Empty(t, f.contentRepo.passages[f.ebook.ID])
+}
+
+func TestEbookServiceSearchContent(t *testing.T) {
+	content := []byte("The sleeper must awaken.\n\nThe spice must flow.\n")
+	f := newContentEbookServiceForTest(t, domain.EbookFormatTXT, content)
+	search := func(userID uuid.UUID, q string) ([]domain.EbookPassageHit, int64, error) {
+		ctx := WithActor(context.Background(), Actor{UserID: userID})
+		return f.svc.SearchContent(ctx, &applicationdto.SearchEbookContentInput{EbookID: f.ebook.ID, UserID: userID, Query: q, Limit: 20})
+	}
+
+	_, _, err := search(f.ebook.OwnerUserID, "spice")
+	requireErrorStatus(t, err, http.StatusNotFound)
+
+	// a file uploaded before it could be indexed is queued on first search
+	f.storage.objects[ebookFileKey(&config.FileStorageConfig{PathPrefix: "uploads"}, f.ebook)] = content
+	_, _, err = search(f.ebook.OwnerUserID, "spice")
+	requireErrorStatus(t, err, http.StatusConflict)
+	require.True(t, f.enqueuer.called)
+	require.Equal(t, job.TaskEbookContentIndex, f.enqueuer.task.Type())
+
+	var payload job.EbookContentIndexPayload
+	require.NoError(t, json.Unmarshal(f.enqueuer.task.Payload(), &payload))
+	require.Equal(t, f.ebook.ID, payload.EbookID)
+
+	_, err = f.svc.IndexContent(context.Background(), f.ebook.ID)
+	require.NoError(t, err)
+
+	hits, total, err := search(f.ebook.OwnerUserID, "  spice ")
+	require.NoError(t, err)
+	require.EqualValues(t, 1, total)
+	require.Equal(t, "fmt=txt;line=2", hits[0].Location)
+	require.Equal(t, port.SearchQuery{Text: "spice", Limit: 20}, f.contentRepo.query)
+
+	// other users' ebooks are hidden rather than forbidden
+	_, _, err = search(uuid.New(), "spice")
+	requireErrorStatus(t, err, http.StatusNotFound)
+
+	_, _, err = search(f.ebook.OwnerUserID, " ")
+	requireErrorStatus(t, err, http.StatusBadRequest)
+
+	message := "pdf: no extractable text"
+	f.contentRepo.indexes[f.ebook.ID] = domain.EbookContentIndex{EbookID: f.ebook.ID, ChecksumSHA256: f.ebook.ChecksumSHA256, Status: domain.EbookContentIndexStatusFailed, Error: &message}
+	_, _, err = search(f.ebook.OwnerUserID, "spice")
+	requireErrorStatus(t, err, http.StatusConflict)
+}
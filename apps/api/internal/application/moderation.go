@@ -0,0 +1,386 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/sqlerr"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/rs/zerolog"
+)
+
+// ShareReportResolution is a resolved report and, when the share was taken
+// down, the share and the borrows revoked and other pending reports resolved
+// with it.
+type ShareReportResolution struct {
+	Report          *domain.ShareReport  `json:"report"`
+	Share           *domain.Share        `json:"share,omitempty"`
+	RevokedBorrows  []domain.Borrow      `json:"revokedBorrows"`
+	ResolvedReports []domain.ShareReport `json:"resolvedReports"`
+}
+
+// ShareReinstatement is a share put back in circulation and the pending reports
+// dismissed with it.
+type ShareReinstatement struct {
+	Share            *domain.Share        `json:"share"`
+	DismissedReports []domain.ShareReport `json:"dismissedReports"`
+}
+
+type ModerationService interface {
+	// ListReports returns the report queue, oldest first.
+	ListReports(ctx context.Context, input *applicationdto.ListShareReportsInput) ([]domain.ShareReport, int64, error)
+	// ClaimReport puts an open report in review by the moderator, so other
+	// moderators leave it alone.
+	ClaimReport(ctx context.Context, reportID uuid.UUID, moderatorID uuid.UUID) (*domain.ShareReport, error)
+	ResolveReport(ctx context.Context, input *applicationdto.ResolveShareReportInput) (*ShareReportResolution, error)
+	RejectReport(ctx context.Context, input *applicationdto.RejectShareReportInput) (*domain.ShareReport, error)
+	// ReinstateShare reactivates a disabled share, clears the flags of its
+	// borrows and rejects its pending reports.
+	ReinstateShare(ctx context.Context, input *applicationdto.ReinstateShareInput) (*ShareReinstatement, error)
+	// SuspendUser suspends the user until input.Until, or until lifted when it is
+	// nil. The user is signed out everywhere and their active shares are disabled.
+	SuspendUser(ctx context.Context, input *applicationdto.SuspendUserInput) (*UserRestriction, error)
+	// BanUser permanently bans the user, with the same effects as a suspension.
+	BanUser(ctx context.Context, input *applicationdto.BanUserInput) (*UserRestriction, error)
+	// ReinstateUser lifts the user's suspension or ban. Shares disabled with it
+	// stay disabled until a moderator reinstates them.
+	ReinstateUser(ctx context.Context, input *applicationdto.ReinstateUserInput) (*domain.User, error)
+}
+
+type moderationService struct {
+	userRepo     port.UserRepository
+	shareRepo    port.ShareRepository
+	borrowRepo   port.BorrowRepository
+	reportRepo   port.ShareReportRepository
+	transactor   port.ModerationTransactor
+	accessCache  *AccountAccessCache
+	taskEnqueuer TaskEnqueuer
+	logger       *zerolog.Logger
+}
+
+func NewModerationService(userRepo port.UserRepository, shareRepo port.ShareRepository, borrowRepo port.BorrowRepository, reportRepo port.ShareReportRepository, transactor port.ModerationTransactor, accessCache *AccountAccessCache, taskEnqueuer TaskEnqueuer, logger *zerolog.Logger) ModerationService {
+	return &moderationService{
+		userRepo:     userRepo,
+		shareRepo:    shareRepo,
+		borrowRepo:   borrowRepo,
+		reportRepo:   reportRepo,
+		transactor:   transactor,
+		accessCache:  accessCache,
+		taskEnqueuer: taskEnqueuer,
+		logger:       logger,
+	}
+}
+
+func (s *moderationService) ListReports(ctx context.Context, input *applicationdto.ListShareReportsInput) ([]domain.ShareReport, int64, error) {
+	if input == nil {
+		input = &applicationdto.ListShareReportsInput{}
+	}
+
+	statuses := input.Statuses
+	if len(statuses) == 0 {
+		statuses = []domain.ReportStatus{domain.ReportStatusOpen, domain.ReportStatusInReview}
+	}
+	filters := map[string]any{"status": statuses}
+	if input.Reason != "" {
+		filters["reason"] = input.Reason
+	}
+
+	reports, total, err := s.reportRepo.GetMany(ctx, port.GetManyOptions{
+		Filters:        filters,
+		OrderBy:        "created_at",
+		OrderDirection: "asc",
+		Limit:          input.Limit,
+		Offset:         input.Offset,
+		After:          input.After,
+		SkipCount:      input.SkipCount,
+	})
+	if err != nil {
+		return nil, 0, listingError(err)
+	}
+	return reports, total, nil
+}
+
+func (s *moderationService) ClaimReport(ctx context.Context, reportID uuid.UUID, moderatorID uuid.UUID) (*domain.ShareReport, error) {
+	var report *domain.ShareReport
+	err := s.transactor.WithinTransaction(ctx, func(repos *port.ModerationRepositories) error {
+		current, err := lockReviewableReport(ctx, repos, reportID, moderatorID)
+		if err != nil {
+			return err
+		}
+
+		report, err = repos.ShareReport.Update(ctx, *current, map[string]any{
+			"status":              domain.ReportStatusInReview,
+			"reviewed_by_user_id": moderatorID,
+		})
+		return err
+	})
+	if err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+
+	s.reportRepo.EvictCache(ctx, reportID)
+	return report, nil
+}
+
+// ResolveReport closes the report and, when asked to, takes the share down and
+// revokes its active borrows in the same transaction. The share's other pending
+// reports are resolved with the takedown, and a removed share stays removed.
+func (s *moderationService) ResolveReport(ctx context.Context, input *applicationdto.ResolveShareReportInput) (*ShareReportResolution, error) {
+	if input == nil {
+		return nil, errs.NewBadRequestError("resolution payload is required", true, nil, nil)
+	}
+	if strings.TrimSpace(input.Note) == "" {
+		return nil, errs.NewBadRequestError("resolution note is required", true, nil, nil)
+	}
+	if input.ShareStatus != "" && input.ShareStatus != domain.ShareStatusDisabled && input.ShareStatus != domain.ShareStatusRemoved {
+		return nil, errs.NewBadRequestError("share status must be either disabled or removed", true, nil, nil)
+	}
+
+	now := time.Now().UTC()
+	resolution := &ShareReportResolution{RevokedBorrows: []domain.Borrow{}, ResolvedReports: []domain.ShareReport{}}
+	err := s.transactor.WithinTransaction(ctx, func(repos *port.ModerationRepositories) error {
+		if input.ShareStatus == "" {
+			report, err := closeReport(ctx, repos, input.ReportID, input.ModeratorID, domain.ReportStatusResolved, input.Note, now)
+			if err != nil {
+				return err
+			}
+			resolution.Report = report
+			return nil
+		}
+
+		// The share is locked before the report, in the same order as reporting
+		// and reinstating take their locks.
+		pending, err := repos.ShareReport.GetByID(ctx, input.ReportID, nil)
+		if err != nil {
+			return err
+		}
+		share, err := repos.Share.GetForUpdate(ctx, pending.ShareID)
+		if err != nil {
+			return err
+		}
+		report, err := closeReport(ctx, repos, input.ReportID, input.ModeratorID, domain.ReportStatusResolved, input.Note, now)
+		if err != nil {
+			return err
+		}
+		resolution.Report = report
+
+		status := input.ShareStatus
+		if share.Status == domain.ShareStatusRemoved {
+			status = domain.ShareStatusRemoved
+		}
+		if status == share.Status {
+			resolution.Share = share
+		} else {
+			if resolution.Share, err = repos.Share.Update(ctx, *share, map[string]any{"status": status}); err != nil {
+				return err
+			}
+			err = recordAuditEvent(ctx, repos.AuditEvent, input.ModeratorID, domain.AuditActionShareTakenDown, domain.AuditTargetTypeShare, &share.ID, map[string]any{
+				"reportId":       report.ID,
+				"previousStatus": share.Status,
+				"status":         status,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		revoked, err := repos.Borrow.RevokeActiveByShare(ctx, share.ID, now)
+		if err != nil {
+			return err
+		}
+		for i := range revoked {
+			err := recordAuditEvent(ctx, repos.AuditEvent, input.ModeratorID, domain.AuditActionBorrowRevoked, domain.AuditTargetTypeBorrow, &revoked[i].ID, map[string]any{
+				"reportId":       report.ID,
+				"shareId":        share.ID,
+				"borrowerUserId": revoked[i].BorrowerUserID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		resolution.RevokedBorrows = append(resolution.RevokedBorrows, revoked...)
+
+		resolved, err := repos.ShareReport.ClosePendingByShare(ctx, share.ID, domain.ReportStatusResolved, input.ModeratorID, input.Note, now)
+		if err != nil {
+			return err
+		}
+		for i := range resolved {
+			err := recordAuditEvent(ctx, repos.AuditEvent, input.ModeratorID, domain.AuditActionReportResolved, domain.AuditTargetTypeShareReport, &resolved[i].ID, map[string]any{
+				"shareId":  resolved[i].ShareID,
+				"reason":   resolved[i].Reason,
+				"note":     input.Note,
+				"reportId": report.ID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		resolution.ResolvedReports = append(resolution.ResolvedReports, resolved...)
+		return nil
+	})
+	if err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+
+	s.reportRepo.EvictCache(ctx, input.ReportID)
+	if resolution.Share != nil {
+		s.shareRepo.EvictCache(ctx, resolution.Share.ID)
+	}
+	for i := range resolution.RevokedBorrows {
+		s.borrowRepo.EvictCache(ctx, resolution.RevokedBorrows[i].ID)
+	}
+	for i := range resolution.ResolvedReports {
+		s.reportRepo.EvictCache(ctx, resolution.ResolvedReports[i].ID)
+	}
+	return resolution, nil
+}
+
+func (s *moderationService) RejectReport(ctx context.Context, input *applicationdto.RejectShareReportInput) (*domain.ShareReport, error) {
+	if input == nil {
+		return nil, errs.NewBadRequestError("rejection payload is required", true, nil, nil)
+	}
+	if strings.TrimSpace(input.Note) == "" {
+		return nil, errs.NewBadRequestError("resolution note is required", true, nil, nil)
+	}
+
+	var report *domain.ShareReport
+	err := s.transactor.WithinTransaction(ctx, func(repos *port.ModerationRepositories) error {
+		var err error
+		report, err = closeReport(ctx, repos, input.ReportID, input.ModeratorID, domain.ReportStatusRejected, input.Note, time.Now().UTC())
+		return err
+	})
+	if err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+
+	s.reportRepo.EvictCache(ctx, input.ReportID)
+	return report, nil
+}
+
+func (s *moderationService) ReinstateShare(ctx context.Context, input *applicationdto.ReinstateShareInput) (*ShareReinstatement, error) {
+	if input == nil {
+		return nil, errs.NewBadRequestError("reinstatement payload is required", true, nil, nil)
+	}
+	if strings.TrimSpace(input.Note) == "" {
+		return nil, errs.NewBadRequestError("resolution note is required", true, nil, nil)
+	}
+
+	now := time.Now().UTC()
+	reinstatement := &ShareReinstatement{}
+	var unflagged []domain.Borrow
+	err := s.transactor.WithinTransaction(ctx, func(repos *port.ModerationRepositories) error {
+		share, err := repos.Share.GetForUpdate(ctx, input.ShareID)
+		if err != nil {
+			return err
+		}
+		switch share.Status {
+		case domain.ShareStatusDisabled:
+		case domain.ShareStatusActive:
+			return errs.NewConflictError("share is already active", true, nil)
+		default:
+			// A moderator removed it on purpose, so it is not reinstated.
+			return errs.NewConflictError("only disabled shares can be reinstated", true, nil)
+		}
+
+		if reinstatement.Share, err = repos.Share.Update(ctx, *share, map[string]any{"status": domain.ShareStatusActive}); err != nil {
+			return err
+		}
+		if unflagged, err = repos.Borrow.UnflagByShare(ctx, share.ID); err != nil {
+			return err
+		}
+		if reinstatement.DismissedReports, err = repos.ShareReport.ClosePendingByShare(ctx, share.ID, domain.ReportStatusRejected, input.ModeratorID, input.Note, now); err != nil {
+			return err
+		}
+
+		err = recordAuditEvent(ctx, repos.AuditEvent, input.ModeratorID, domain.AuditActionShareReinstated, domain.AuditTargetTypeShare, &share.ID, map[string]any{
+			"previousStatus": share.Status,
+			"status":         domain.ShareStatusActive,
+			"note":           input.Note,
+		})
+		if err != nil {
+			return err
+		}
+		for i := range reinstatement.DismissedReports {
+			report := &reinstatement.DismissedReports[i]
+			err := recordAuditEvent(ctx, repos.AuditEvent, input.ModeratorID, domain.AuditActionReportRejected, domain.AuditTargetTypeShareReport, &report.ID, map[string]any{
+				"shareId": report.ShareID,
+				"reason":  report.Reason,
+				"note":    input.Note,
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+
+	s.shareRepo.EvictCache(ctx, input.ShareID)
+	for i := range unflagged {
+		s.borrowRepo.EvictCache(ctx, unflagged[i].ID)
+	}
+	for i := range reinstatement.DismissedReports {
+		s.reportRepo.EvictCache(ctx, reinstatement.DismissedReports[i].ID)
+	}
+	return reinstatement, nil
+}
+
+// closeReport moves the report to its final status and records the decision.
+func closeReport(ctx context.Context, repos *port.ModerationRepositories, reportID uuid.UUID, moderatorID uuid.UUID, status domain.ReportStatus, note string, now time.Time) (*domain.ShareReport, error) {
+	report, err := lockReviewableReport(ctx, repos, reportID, moderatorID)
+	if err != nil {
+		return nil, err
+	}
+
+	report, err = repos.ShareReport.Update(ctx, *report, map[string]any{
+		"status":              status,
+		"reviewed_by_user_id": moderatorID,
+		"reviewed_at":         now,
+		"resolution_note":     note,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	action := domain.AuditActionReportResolved
+	if status == domain.ReportStatusRejected {
+		action = domain.AuditActionReportRejected
+	}
+	err = recordAuditEvent(ctx, repos.AuditEvent, moderatorID, action, domain.AuditTargetTypeShareReport, &report.ID, map[string]any{
+		"shareId": report.ShareID,
+		"reason":  report.Reason,
+		"note":    note,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return report, nil
+}
+
+// lockReviewableReport locks the report and checks the moderator may work it:
+// it is open, or already in review by the same moderator.
+func lockReviewableReport(ctx context.Context, repos *port.ModerationRepositories, reportID uuid.UUID, moderatorID uuid.UUID) (*domain.ShareReport, error) {
+	report, err := repos.ShareReport.GetForUpdate(ctx, reportID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch report.Status {
+	case domain.ReportStatusOpen:
+		return report, nil
+	case domain.ReportStatusInReview:
+		if report.ReviewedByUserID == nil || *report.ReviewedByUserID == moderatorID {
+			return report, nil
+		}
+		return nil, errs.NewConflictError("report is in review by another moderator", true, nil)
+	default:
+		return nil, errs.NewConflictError("report is already "+string(report.Status), true, nil)
+	}
+}
@@ -0,0 +1,478 @@
+package application
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/job"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/repository"
+	"github.com/stretchr/testify/require"
+)
+
+type testModerationTransactor struct {
+	repos *port.ModerationRepositories
+}
+
+func (t *testModerationTransactor) WithinTransaction(ctx context.Context, fn func(repos *port.ModerationRepositories) error) error {
+	return fn(t.repos)
+}
+
+type moderationServiceFixture struct {
+	service         ModerationService
+	userRepo        *testUserRepo
+	shareRepo       *testShareRepo
+	borrowRepo      *testBorrowRepo
+	reportRepo      *testShareReportRepo
+	auditRepo       *testAuditEventRepo
+	accessCache     *AccountAccessCache
+	enqueuer        *mockTaskEnqueuer
+	revokedSessions []uuid.UUID
+}
+
+func newModerationServiceFixture() *moderationServiceFixture {
+	fixture := &moderationServiceFixture{enqueuer: &mockTaskEnqueuer{}}
+	userRepo := newTestUserRepo()
+	sessionRepo := &mockSessionRepo{
+		revokeByUserIDFn: func(_ context.Context, userID uuid.UUID, _ time.Time) error {
+			fixture.revokedSessions = append(fixture.revokedSessions, userID)
+			return nil
+		},
+	}
+	shareRepo := &testShareRepo{MockResourceRepository: repository.NewMockResourceRepository[domain.Share](false)}
+	borrowRepo := &testBorrowRepo{MockResourceRepository: repository.NewMockResourceRepository[domain.Borrow](false)}
+	reportRepo := &testShareReportRepo{MockResourceRepository: repository.NewMockResourceRepository[domain.ShareReport](false)}
+	auditRepo := &testAuditEventRepo{}
+	transactor := &testModerationTransactor{repos: &port.ModerationRepositories{
+		User:        userRepo,
+		AuthSession: sessionRepo,
+		Share:       shareRepo,
+		Borrow:      borrowRepo,
+		ShareReport: reportRepo,
+		AuditEvent:  auditRepo,
+	}}
+
+	fixture.accessCache = NewAccountAccessCache(&testCache{entries: map[string][]byte{}})
+	fixture.service = NewModerationService(userRepo, shareRepo, borrowRepo, reportRepo, transactor, fixture.accessCache, fixture.enqueuer, nil)
+	fixture.userRepo = userRepo
+	fixture.shareRepo = shareRepo
+	fixture.borrowRepo = borrowRepo
+	fixture.reportRepo = reportRepo
+	fixture.auditRepo = auditRepo
+	return fixture
+}
+
+func newReportedShareForTest(t *testing.T, fixture *moderationServiceFixture) (*domain.Share, *domain.ShareReport) {
+	t.Helper()
+	ctx := context.Background()
+
+	share := &domain.Share{
+		ID:                   uuid.New(),
+		EbookID:              uuid.New(),
+		OwnerUserID:          uuid.New(),
+		Status:               domain.ShareStatusActive,
+		BorrowDurationHours:  24,
+		MaxConcurrentBorrows: 2,
+	}
+	require.NoError(t, fixture.shareRepo.Store(ctx, share))
+
+	report := &domain.ShareReport{
+		ID:             uuid.New(),
+		ShareID:        share.ID,
+		ReporterUserID: uuid.New(),
+		Reason:         domain.ReportReasonCopyright,
+		Status:         domain.ReportStatusOpen,
+	}
+	require.NoError(t, fixture.reportRepo.Store(ctx, report))
+	return share, report
+}
+
+func TestModerationServiceClaimReport_ConflictsForOtherModerator(t *testing.T) {
+	ctx := context.Background()
+	fixture := newModerationServiceFixture()
+	_, report := newReportedShareForTest(t, fixture)
+
+	moderatorID := uuid.New()
+	claimed, err := fixture.service.ClaimReport(ctx, report.ID, moderatorID)
+	require.NoError(t, err)
+	require.Equal(t, domain.ReportStatusInReview, claimed.Status)
+	require.NotNil(t, claimed.ReviewedByUserID)
+	require.Equal(t, moderatorID, *claimed.ReviewedByUserID)
+
+	// Claiming again as the same moderator is a no-op.
+	_, err = fixture.service.ClaimReport(ctx, report.ID, moderatorID)
+	require.NoError(t, err)
+
+	_, err = fixture.service.ClaimReport(ctx, report.ID, uuid.New())
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusConflict, httpErr.Status)
+}
+
+func TestModerationServiceResolveReport_TakesDownShare(t *testing.T) {
+	ctx := context.Background()
+	fixture := newModerationServiceFixture()
+	share, report := newReportedShareForTest(t, fixture)
+
+	now := time.Now().UTC()
+	active := &domain.Borrow{
+		ID:             uuid.New(),
+		ShareID:        share.ID,
+		BorrowerUserID: uuid.New(),
+		StartedAt:      now,
+		DueAt:          now.Add(24 * time.Hour),
+		Status:         domain.BorrowStatusActive,
+	}
+	returned := &domain.Borrow{
+		ID:             uuid.New(),
+		ShareID:        share.ID,
+		BorrowerUserID: uuid.New(),
+		StartedAt:      now.Add(-48 * time.Hour),
+		DueAt:          now.Add(-24 * time.Hour),
+		Status:         domain.BorrowStatusReturned,
+	}
+	otherShare := &domain.Borrow{
+		ID:             uuid.New(),
+		ShareID:        uuid.New(),
+		BorrowerUserID: uuid.New(),
+		StartedAt:      now,
+		DueAt:          now.Add(24 * time.Hour),
+		Status:         domain.BorrowStatusActive,
+	}
+	for _, borrow := range []*domain.Borrow{active, returned, otherShare} {
+		require.NoError(t, fixture.borrowRepo.Store(ctx, borrow))
+	}
+
+	moderatorID := uuid.New()
+	resolution, err := fixture.service.ResolveReport(ctx, &applicationdto.ResolveShareReportInput{
+		ReportID:    report.ID,
+		ModeratorID: moderatorID,
+		Note:        "Confirmed copyright infringement",
+		ShareStatus: domain.ShareStatusRemoved,
+	})
+	require.NoError(t, err)
+	require.Equal(t, domain.ReportStatusResolved, resolution.Report.Status)
+	require.NotNil(t, resolution.Report.ReviewedAt)
+	require.NotNil(t, resolution.Report.ResolutionNote)
+	require.Equal(t, "Confirmed copyright infringement", *resolution.Report.ResolutionNote)
+	require.NotNil(t, resolution.Share)
+	require.Equal(t, domain.ShareStatusRemoved, resolution.Share.Status)
+	require.Len(t, resolution.RevokedBorrows, 1)
+	require.Equal(t, active.ID, resolution.RevokedBorrows[0].ID)
+
+	stored, err := fixture.borrowRepo.GetByID(ctx, returned.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, domain.BorrowStatusReturned, stored.Status)
+
+	stored, err = fixture.borrowRepo.GetByID(ctx, otherShare.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, domain.BorrowStatusActive, stored.Status)
+
+	events := fixture.auditRepo.events
+	require.Len(t, events, 3)
+	require.Equal(t, domain.AuditActionReportResolved, events[0].Action)
+	require.Equal(t, report.ID, *events[0].TargetID)
+	require.Equal(t, domain.AuditActionShareTakenDown, events[1].Action)
+	require.Equal(t, share.ID, *events[1].TargetID)
+	require.Equal(t, domain.ShareStatusActive, events[1].Metadata["previousStatus"])
+	require.Equal(t, domain.AuditActionBorrowRevoked, events[2].Action)
+	require.Equal(t, active.ID, *events[2].TargetID)
+	for _, event := range events {
+		require.Equal(t, &moderatorID, event.ActorUserID)
+	}
+
+	// A closed report cannot be worked again.
+	_, err = fixture.service.RejectReport(ctx, &applicationdto.RejectShareReportInput{
+		ReportID:    report.ID,
+		ModeratorID: moderatorID,
+		Note:        "Changed my mind",
+	})
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusConflict, httpErr.Status)
+}
+
+func TestModerationServiceResolveReport_KeepsRemovedShareRemoved(t *testing.T) {
+	ctx := context.Background()
+	fixture := newModerationServiceFixture()
+	share, report := newReportedShareForTest(t, fixture)
+	_, err := fixture.shareRepo.Update(ctx, *share, map[string]any{"status": domain.ShareStatusRemoved})
+	require.NoError(t, err)
+
+	sibling := &domain.ShareReport{
+		ID:             uuid.New(),
+		ShareID:        share.ID,
+		ReporterUserID: uuid.New(),
+		Reason:         domain.ReportReasonCopyright,
+		Status:         domain.ReportStatusOpen,
+	}
+	require.NoError(t, fixture.reportRepo.Store(ctx, sibling))
+
+	moderatorID := uuid.New()
+	resolution, err := fixture.service.ResolveReport(ctx, &applicationdto.ResolveShareReportInput{
+		ReportID:    report.ID,
+		ModeratorID: moderatorID,
+		Note:        "Same upload as before",
+		ShareStatus: domain.ShareStatusDisabled,
+	})
+	require.NoError(t, err)
+	require.Equal(t, domain.ShareStatusRemoved, resolution.Share.Status)
+	require.Len(t, resolution.ResolvedReports, 1)
+	require.Equal(t, sibling.ID, resolution.ResolvedReports[0].ID)
+	require.Equal(t, domain.ReportStatusResolved, resolution.ResolvedReports[0].Status)
+
+	stored, err := fixture.shareRepo.GetByID(ctx, share.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, domain.ShareStatusRemoved, stored.Status)
+
+	events := fixture.auditRepo.events
+	require.Len(t, events, 2)
+	require.Equal(t, domain.AuditActionReportResolved, events[0].Action)
+	require.Equal(t, report.ID, *events[0].TargetID)
+	require.Equal(t, domain.AuditActionReportResolved, events[1].Action)
+	require.Equal(t, sibling.ID, *events[1].TargetID)
+}
+
+func TestModerationServiceRejectReport_LeavesShareActive(t *testing.T) {
+	ctx := context.Background()
+	fixture := newModerationServiceFixture()
+	share, report := newReportedShareForTest(t, fixture)
+
+	_, err := fixture.service.RejectReport(ctx, &applicationdto.RejectShareReportInput{
+		ReportID:    report.ID,
+		ModeratorID: uuid.New(),
+	})
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusBadRequest, httpErr.Status)
+
+	rejected, err := fixture.service.RejectReport(ctx, &applicationdto.RejectShareReportInput{
+		ReportID:    report.ID,
+		ModeratorID: uuid.New(),
+		Note:        "Share is the owner's own work",
+	})
+	require.NoError(t, err)
+	require.Equal(t, domain.ReportStatusRejected, rejected.Status)
+	require.Len(t, fixture.auditRepo.events, 1)
+	require.Equal(t, domain.AuditActionReportRejected, fixture.auditRepo.events[0].Action)
+
+	stored, err := fixture.shareRepo.GetByID(ctx, share.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, domain.ShareStatusActive, stored.Status)
+}
+
+func TestModerationServiceReinstateShare_DismissesPendingReports(t *testing.T) {
+	ctx := context.Background()
+	fixture := newModerationServiceFixture()
+	share, report := newReportedShareForTest(t, fixture)
+
+	_, err := fixture.service.ReinstateShare(ctx, &applicationdto.ReinstateShareInput{
+		ShareID:     share.ID,
+		ModeratorID: uuid.New(),
+		Note:        "Nothing to reinstate",
+	})
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusConflict, httpErr.Status)
+
+	flaggedAt := time.Now().UTC()
+	_, err = fixture.shareRepo.Update(ctx, *share, map[string]any{"status": domain.ShareStatusDisabled})
+	require.NoError(t, err)
+	borrow := &domain.Borrow{
+		ID:             uuid.New(),
+		ShareID:        share.ID,
+		BorrowerUserID: uuid.New(),
+		Status:         domain.BorrowStatusActive,
+		DueAt:          flaggedAt.Add(time.Hour),
+		FlaggedAt:      &flaggedAt,
+	}
+	require.NoError(t, fixture.borrowRepo.Store(ctx, borrow))
+
+	moderatorID := uuid.New()
+	reinstatement, err := fixture.service.ReinstateShare(ctx, &applicationdto.ReinstateShareInput{
+		ShareID:     share.ID,
+		ModeratorID: moderatorID,
+		Note:        "Reports were coordinated",
+	})
+	require.NoError(t, err)
+	require.Equal(t, domain.ShareStatusActive, reinstatement.Share.Status)
+	require.Len(t, reinstatement.DismissedReports, 1)
+	require.Equal(t, report.ID, reinstatement.DismissedReports[0].ID)
+	require.Equal(t, domain.ReportStatusRejected, reinstatement.DismissedReports[0].Status)
+
+	storedBorrow, err := fixture.borrowRepo.GetByID(ctx, borrow.ID, nil)
+	require.NoError(t, err)
+	require.Nil(t, storedBorrow.FlaggedAt)
+
+	events := fixture.auditRepo.events
+	require.Len(t, events, 2)
+	require.Equal(t, domain.AuditActionShareReinstated, events[0].Action)
+	require.Equal(t, share.ID, *events[0].TargetID)
+	require.Equal(t, domain.AuditActionReportRejected, events[1].Action)
+	require.Equal(t, report.ID, *events[1].TargetID)
+	for _, event := range events {
+		require.Equal(t, &moderatorID, event.ActorUserID)
+	}
+}
+
+func TestModerationServiceReinstateShare_LeavesRemovedSharesAlone(t *testing.T) {
+	ctx := context.Background()
+	fixture := newModerationServiceFixture()
+	share, _ := newReportedShareForTest(t, fixture)
+	_, err := fixture.shareRepo.Update(ctx, *share, map[string]any{"status": domain.ShareStatusRemoved})
+	require.NoError(t, err)
+
+	_, err = fixture.service.ReinstateShare(ctx, &applicationdto.ReinstateShareInput{
+		ShareID:     share.ID,
+		ModeratorID: uuid.New(),
+		Note:        "Reports were coordinated",
+	})
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusConflict, httpErr.Status)
+
+	stored, err := fixture.shareRepo.GetByID(ctx, share.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, domain.ShareStatusRemoved, stored.Status)
+	require.Empty(t, fixture.auditRepo.events)
+}
+
+func TestModerationServiceSuspendUser_SignsOutAndDisablesShares(t *testing.T) {
+	ctx := context.Background()
+	fixture := newModerationServiceFixture()
+
+	user := &domain.User{ID: uuid.New(), Email: "reader@example.com", Username: "reader"}
+	require.NoError(t, fixture.userRepo.Store(ctx, user))
+	owned := &domain.Share{ID: uuid.New(), OwnerUserID: user.ID, Status: domain.ShareStatusActive}
+	other := &domain.Share{ID: uuid.New(), OwnerUserID: uuid.New(), Status: domain.ShareStatusActive}
+	require.NoError(t, fixture.shareRepo.Store(ctx, owned))
+	require.NoError(t, fixture.shareRepo.Store(ctx, other))
+
+	moderatorID := uuid.New()
+	_, err := fixture.service.SuspendUser(ctx, &applicationdto.SuspendUserInput{
+		UserID:      moderatorID,
+		ModeratorID: moderatorID,
+		Reason:      "Testing",
+	})
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusBadRequest, httpErr.Status)
+
+	until := time.Now().UTC().Add(24 * time.Hour)
+	restriction, err := fixture.service.SuspendUser(ctx, &applicationdto.SuspendUserInput{
+		UserID:      user.ID,
+		ModeratorID: moderatorID,
+		Reason:      "Sharing copyrighted books",
+		Until:       &until,
+	})
+	require.NoError(t, err)
+	require.True(t, restriction.User.IsSuspended(time.Now().UTC()))
+	require.Equal(t, "Sharing copyrighted books", *restriction.User.SuspensionReason)
+	require.Len(t, restriction.DisabledShares, 1)
+	require.Equal(t, owned.ID, restriction.DisabledShares[0].ID)
+	require.Equal(t, []uuid.UUID{user.ID}, fixture.revokedSessions)
+
+	stored, err := fixture.shareRepo.GetByID(ctx, other.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, domain.ShareStatusActive, stored.Status)
+
+	require.Len(t, fixture.auditRepo.events, 1)
+	event := fixture.auditRepo.events[0]
+	require.Equal(t, domain.AuditActionUserSuspended, event.Action)
+	require.Equal(t, domain.AuditTargetTypeUser, event.TargetType)
+	require.Equal(t, user.ID, *event.TargetID)
+	require.Equal(t, &moderatorID, event.ActorUserID)
+
+	require.True(t, fixture.enqueuer.called)
+	require.Equal(t, job.TaskEmailAccountRestricted, fixture.enqueuer.task.Type())
+}
+
+func TestModerationServiceBanUser_ReinstateLiftsBan(t *testing.T) {
+	ctx := context.Background()
+	fixture := newModerationServiceFixture()
+
+	user := &domain.User{ID: uuid.New(), Email: "reader@example.com", Username: "reader"}
+	require.NoError(t, fixture.userRepo.Store(ctx, user))
+
+	moderatorID := uuid.New()
+	_, err := fixture.service.ReinstateUser(ctx, &applicationdto.ReinstateUserInput{
+		UserID:      user.ID,
+		ModeratorID: moderatorID,
+		Note:        "Nothing to lift",
+	})
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusConflict, httpErr.Status)
+
+	restriction, err := fixture.service.BanUser(ctx, &applicationdto.BanUserInput{
+		UserID:      user.ID,
+		ModeratorID: moderatorID,
+		Reason:      "Repeated abuse",
+	})
+	require.NoError(t, err)
+	require.True(t, restriction.User.IsBanned())
+
+	// A banned user cannot be suspended or banned again.
+	_, err = fixture.service.SuspendUser(ctx, &applicationdto.SuspendUserInput{
+		UserID:      user.ID,
+		ModeratorID: moderatorID,
+		Reason:      "Repeated abuse",
+	})
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusConflict, httpErr.Status)
+
+	reinstated, err := fixture.service.ReinstateUser(ctx, &applicationdto.ReinstateUserInput{
+		UserID:      user.ID,
+		ModeratorID: moderatorID,
+		Note:        "Appeal accepted",
+	})
+	require.NoError(t, err)
+	require.False(t, reinstated.IsBanned())
+	require.Nil(t, reinstated.BanReason)
+
+	events := fixture.auditRepo.events
+	require.Len(t, events, 2)
+	require.Equal(t, domain.AuditActionUserBanned, events[0].Action)
+	require.Equal(t, domain.AuditActionUserReinstated, events[1].Action)
+}
+
+func TestModerationServiceBanUser_EvictsCachedAccess(t *testing.T) {
+	ctx := context.Background()
+	fixture := newModerationServiceFixture()
+	users := NewUserService(fixture.userRepo, fixture.accessCache)
+
+	user := &domain.User{ID: uuid.New(), Email: "reader@example.com", Username: "reader"}
+	require.NoError(t, fixture.userRepo.Store(ctx, user))
+	require.NoError(t, users.CheckAccess(ctx, user.ID))
+	_, cached := fixture.accessCache.get(ctx, user.ID)
+	require.True(t, cached)
+
+	moderatorID := uuid.New()
+	_, err := fixture.service.BanUser(ctx, &applicationdto.BanUserInput{
+		UserID:      user.ID,
+		ModeratorID: moderatorID,
+		Reason:      "Repeated abuse",
+	})
+	require.NoError(t, err)
+
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, users.CheckAccess(ctx, user.ID), &httpErr)
+	require.Equal(t, http.StatusForbidden, httpErr.Status)
+	// The ban is served from the cache from here on.
+	require.ErrorAs(t, users.CheckAccess(ctx, user.ID), &httpErr)
+	require.Equal(t, http.StatusForbidden, httpErr.Status)
+
+	_, err = fixture.service.ReinstateUser(ctx, &applicationdto.ReinstateUserInput{
+		UserID:      user.ID,
+		ModeratorID: moderatorID,
+		Note:        "Appeal accepted",
+	})
+	require.NoError(t, err)
+	require.NoError(t, users.CheckAccess(ctx, user.ID))
+}
@@ -0,0 +1,207 @@
+package application
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/sqlerr"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/job"
+)
+
+// UserRestriction is a suspended or banned user and the shares disabled with it.
+type UserRestriction struct {
+	User           *domain.User   `json:"user"`
+	DisabledShares []domain.Share `json:"disabledShares"`
+}
+
+func (s *moderationService) SuspendUser(ctx context.Context, input *applicationdto.SuspendUserInput) (*UserRestriction, error) {
+	if input == nil {
+		return nil, errs.NewBadRequestError("suspension payload is required", true, nil, nil)
+	}
+	if strings.TrimSpace(input.Reason) == "" {
+		return nil, errs.NewBadRequestError("suspension reason is required", true, nil, nil)
+	}
+
+	now := time.Now().UTC()
+	if input.Until != nil && !input.Until.After(now) {
+		return nil, errs.NewBadRequestError("suspension end must be in the future", true, nil, nil)
+	}
+
+	updates := map[string]any{
+		"suspended_at":      &now,
+		"suspended_until":   input.Until,
+		"suspension_reason": &input.Reason,
+	}
+	metadata := map[string]any{"reason": input.Reason}
+	if input.Until != nil {
+		metadata["until"] = input.Until.UTC()
+	}
+
+	restriction, err := s.restrictUser(ctx, input.UserID, input.ModeratorID, domain.AuditActionUserSuspended, updates, metadata, now)
+	if err != nil {
+		return nil, err
+	}
+
+	details := "The suspension lasts until an administrator lifts it."
+	if input.Until != nil {
+		details = fmt.Sprintf("The suspension ends on %s.", input.Until.UTC().Format("Jan 2, 2006 at 15:04 UTC"))
+	}
+	s.notifyAccountRestricted(ctx, restriction.User, "Your account has been suspended", input.Reason, details)
+	return restriction, nil
+}
+
+func (s *moderationService) BanUser(ctx context.Context, input *applicationdto.BanUserInput) (*UserRestriction, error) {
+	if input == nil {
+		return nil, errs.NewBadRequestError("ban payload is required", true, nil, nil)
+	}
+	if strings.TrimSpace(input.Reason) == "" {
+		return nil, errs.NewBadRequestError("ban reason is required", true, nil, nil)
+	}
+
+	now := time.Now().UTC()
+	updates := map[string]any{
+		"banned_at":  &now,
+		"ban_reason": &input.Reason,
+	}
+	metadata := map[string]any{"reason": input.Reason}
+
+	restriction, err := s.restrictUser(ctx, input.UserID, input.ModeratorID, domain.AuditActionUserBanned, updates, metadata, now)
+	if err != nil {
+		return nil, err
+	}
+
+	s.notifyAccountRestricted(ctx, restriction.User, "Your account has been banned", input.Reason, "This ban is permanent.")
+	return restriction, nil
+}
+
+// restrictUser applies a suspension or ban to the user, signs them out of every
+// session and disables their active shares, along with its audit event.
+func (s *moderationService) restrictUser(ctx context.Context, userID uuid.UUID, moderatorID uuid.UUID, action domain.AuditAction, updates map[string]any, metadata map[string]any, now time.Time) (*UserRestriction, error) {
+	if userID == moderatorID {
+		return nil, errs.NewBadRequestError("you cannot restrict your own account", true, nil, nil)
+	}
+
+	restriction := &UserRestriction{}
+	err := s.transactor.WithinTransaction(ctx, func(repos *port.ModerationRepositories) error {
+		user, err := repos.User.GetForUpdate(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if user.IsBanned() {
+			return errs.NewConflictError("user is already banned", true, nil)
+		}
+
+		if restriction.User, err = repos.User.Update(ctx, *user, updates); err != nil {
+			return err
+		}
+		if err := repos.AuthSession.RevokeByUserID(ctx, user.ID, now); err != nil {
+			return err
+		}
+		if restriction.DisabledShares, err = repos.Share.DisableActiveByOwner(ctx, user.ID); err != nil {
+			return err
+		}
+
+		shareIDs := make([]uuid.UUID, len(restriction.DisabledShares))
+		for i := range restriction.DisabledShares {
+			shareIDs[i] = restriction.DisabledShares[i].ID
+		}
+		metadata["disabledShareIds"] = shareIDs
+		return recordAuditEvent(ctx, repos.AuditEvent, moderatorID, action, domain.AuditTargetTypeUser, &user.ID, metadata)
+	})
+	if err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+
+	s.userRepo.EvictCache(ctx, userID)
+	s.accessCache.evict(ctx, userID)
+	for i := range restriction.DisabledShares {
+		s.shareRepo.EvictCache(ctx, restriction.DisabledShares[i].ID)
+	}
+	return restriction, nil
+}
+
+func (s *moderationService) ReinstateUser(ctx context.Context, input *applicationdto.ReinstateUserInput) (*domain.User, error) {
+	if input == nil {
+		return nil, errs.NewBadRequestError("reinstatement payload is required", true, nil, nil)
+	}
+	if strings.TrimSpace(input.Note) == "" {
+		return nil, errs.NewBadRequestError("resolution note is required", true, nil, nil)
+	}
+
+	var reinstated *domain.User
+	err := s.transactor.WithinTransaction(ctx, func(repos *port.ModerationRepositories) error {
+		user, err := repos.User.GetForUpdate(ctx, input.UserID)
+		if err != nil {
+			return err
+		}
+		if user.BannedAt == nil && user.SuspendedAt == nil {
+			return errs.NewConflictError("user is not suspended or banned", true, nil)
+		}
+
+		reinstated, err = repos.User.Update(ctx, *user, map[string]any{
+			"suspended_at":      nil,
+			"suspended_until":   nil,
+			"suspension_reason": nil,
+			"banned_at":         nil,
+			"ban_reason":        nil,
+		})
+		if err != nil {
+			return err
+		}
+
+		return recordAuditEvent(ctx, repos.AuditEvent, input.ModeratorID, domain.AuditActionUserReinstated, domain.AuditTargetTypeUser, &user.ID, map[string]any{
+			"wasBanned":    user.BannedAt != nil,
+			"wasSuspended": user.SuspendedAt != nil,
+			"note":         input.Note,
+		})
+	})
+	if err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+
+	s.userRepo.EvictCache(ctx, input.UserID)
+	s.accessCache.evict(ctx, input.UserID)
+	return reinstated, nil
+}
+
+// notifyAccountRestricted emails the user about their restriction. The
+// restriction already took effect, so a failure to queue the email is only logged.
+func (s *moderationService) notifyAccountRestricted(ctx context.Context, user *domain.User, title, reason, details string) {
+	if s.taskEnqueuer == nil || user == nil || user.Email == "" {
+		return
+	}
+
+	task, err := job.NewAccountRestrictedTask(job.AccountRestrictedPayload{
+		To:       user.Email,
+		Username: user.Username,
+		Title:    title,
+		Reason:   reason,
+		Details:  details,
+	})
+	if err == nil {
+		_, err = s.taskEnqueuer.EnqueueContext(ctx, task)
+	}
+	if err != nil && s.logger != nil {
+		s.logger.Error().Err(err).Str("user_id", user.ID.String()).Msg("failed to queue account restricted email")
+	}
+}
+
+// checkAccountAccess rejects a user who is banned or suspended at now.
+func checkAccountAccess(user *domain.User, now time.Time) error {
+	switch {
+	case user.IsBanned():
+		return errs.NewForbiddenError("Your account has been banned", true)
+	case user.IsSuspended(now) && user.SuspendedUntil != nil:
+		return errs.NewForbiddenError(fmt.Sprintf("Your account is suspended until %s", user.SuspendedUntil.UTC().Format(time.RFC3339)), true)
+	case user.IsSuspended(now):
+		return errs.NewForbiddenError("Your account is suspended", true)
+	}
+	return nil
+}
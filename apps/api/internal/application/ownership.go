@@ -0,0 +1,62 @@
+package application
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+)
+
+type actorKey struct{}
+
+// Actor is the authenticated user a request acts on behalf of.
+type Actor struct {
+	UserID  uuid.UUID
+	IsAdmin bool
+}
+
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	actor, ok := ctx.Value(actorKey{}).(Actor)
+	return actor, ok && actor.UserID != uuid.Nil
+}
+
+// OwnershipPolicy scopes a resource to the user owning each row. Non-admin
+// actors only list and read their own rows, and anything else is reported as
+// not found so other users' ids are not disclosed. With PublicRead every row
+// stays readable and mutating another user's row is forbidden instead.
+type OwnershipPolicy[T domain.BaseModel] struct {
+	// Column holds the owner's user id, e.g. "owner_user_id".
+	Column     string
+	OwnerID    func(entity *T) uuid.UUID
+	PublicRead bool
+}
+
+// scope returns the actor the policy applies to, or nil when the actor may
+// access every row.
+func (p *OwnershipPolicy[T]) scope(ctx context.Context) (*Actor, error) {
+	if p == nil {
+		return nil, nil
+	}
+
+	actor, ok := ActorFromContext(ctx)
+	if !ok {
+		return nil, errs.NewUnauthorizedError("Unauthorized", false)
+	}
+	if actor.IsAdmin {
+		return nil, nil
+	}
+	return &actor, nil
+}
+
+func (p *OwnershipPolicy[T]) canRead(actor *Actor, entity *T) bool {
+	return actor == nil || p.PublicRead || p.OwnerID(entity) == actor.UserID
+}
+
+func (p *OwnershipPolicy[T]) canMutate(actor *Actor, entity *T) bool {
+	return actor == nil || p.OwnerID(entity) == actor.UserID
+}
@@ -0,0 +1,205 @@
+package port
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type FilterOperator string
+
+const (
+	FilterOperatorEq   FilterOperator = "eq"
+	FilterOperatorNe   FilterOperator = "ne"
+	FilterOperatorIn   FilterOperator = "in"
+	FilterOperatorGt   FilterOperator = "gt"
+	FilterOperatorGte  FilterOperator = "gte"
+	FilterOperatorLt   FilterOperator = "lt"
+	FilterOperatorLte  FilterOperator = "lte"
+	FilterOperatorLike FilterOperator = "like"
+	// FilterOperatorNull matches rows where the field is null for "true" and
+	// where it is not for "false".
+	FilterOperatorNull FilterOperator = "null"
+)
+
+type FilterType string
+
+const (
+	FilterTypeString FilterType = "string"
+	FilterTypeUUID   FilterType = "uuid"
+	FilterTypeInt    FilterType = "int"
+	FilterTypeNumber FilterType = "number"
+	FilterTypeBool   FilterType = "bool"
+	FilterTypeTime   FilterType = "time"
+)
+
+// FilterField declares a field clients may filter a listing by. Column is
+// written into the query as is, so it must never come from a request.
+type FilterField struct {
+	Column string
+	Type   FilterType
+	// Values restricts string fields to an enum.
+	Values []string
+	// Nullable allows the null operator.
+	Nullable bool
+}
+
+// Operators returns the operators the field accepts, which depend on its type.
+func (f FilterField) Operators() []FilterOperator {
+	operators := []FilterOperator{FilterOperatorEq, FilterOperatorNe, FilterOperatorIn}
+	switch f.Type {
+	case FilterTypeInt, FilterTypeNumber, FilterTypeTime:
+		operators = append(operators, FilterOperatorGt, FilterOperatorGte, FilterOperatorLt, FilterOperatorLte)
+	case FilterTypeString:
+		if len(f.Values) == 0 {
+			operators = append(operators, FilterOperatorLike)
+		}
+	case FilterTypeBool:
+		operators = []FilterOperator{FilterOperatorEq, FilterOperatorNe}
+	}
+	if f.Nullable {
+		operators = append(operators, FilterOperatorNull)
+	}
+	return operators
+}
+
+// FilterSpec is the allowlist of fields a listing can be filtered by, keyed by
+// the name clients use for the field.
+type FilterSpec map[string]FilterField
+
+// Filter is a single condition requested by a client.
+type Filter struct {
+	Field    string
+	Operator FilterOperator
+	Value    string
+}
+
+// FilterError reports a filter that is not allowed or has an invalid value.
+type FilterError struct {
+	Field   string
+	Message string
+}
+
+func (e *FilterError) Error() string {
+	return fmt.Sprintf("filter %s: %s", e.Field, e.Message)
+}
+
+// Wheres translates filters into where clauses, rejecting fields missing from
+// the spec, operators the field does not accept and values of the wrong type.
+// Values are always bound as arguments.
+func (s FilterSpec) Wheres(filters []Filter) ([]WhereClause, error) {
+	wheres := make([]WhereClause, 0, len(filters))
+	for _, filter := range filters {
+		field, ok := s[filter.Field]
+		if !ok {
+			return nil, &FilterError{Field: filter.Field, Message: "is not filterable"}
+		}
+
+		operator := filter.Operator
+		if operator == "" {
+			operator = FilterOperatorEq
+		}
+		if !slices.Contains(field.Operators(), operator) {
+			return nil, &FilterError{Field: filter.Field, Message: fmt.Sprintf("does not support the %q operator", operator)}
+		}
+
+		where, err := field.where(operator, filter.Value)
+		if err != nil {
+			return nil, &FilterError{Field: filter.Field, Message: err.Error()}
+		}
+		wheres = append(wheres, where)
+	}
+	return wheres, nil
+}
+
+func (f FilterField) where(operator FilterOperator, raw string) (WhereClause, error) {
+	switch operator {
+	case FilterOperatorNull:
+		isNull, err := strconv.ParseBool(raw)
+		if err != nil {
+			return WhereClause{}, fmt.Errorf("expected true or false")
+		}
+		if isNull {
+			return WhereClause{Query: f.Column + " IS NULL"}, nil
+		}
+		return WhereClause{Query: f.Column + " IS NOT NULL"}, nil
+	case FilterOperatorIn:
+		parts := strings.Split(raw, ",")
+		values := make([]any, 0, len(parts))
+		for _, part := range parts {
+			value, err := f.parse(strings.TrimSpace(part))
+			if err != nil {
+				return WhereClause{}, err
+			}
+			values = append(values, value)
+		}
+		return WhereClause{Query: f.Column + " IN ?", Args: []any{values}}, nil
+	case FilterOperatorLike:
+		pattern := "%" + escapeLikePattern(strings.ToLower(raw)) + "%"
+		return WhereClause{Query: "LOWER(" + f.Column + ") LIKE ? ESCAPE '\\'", Args: []any{pattern}}, nil
+	}
+
+	value, err := f.parse(raw)
+	if err != nil {
+		return WhereClause{}, err
+	}
+	comparisons := map[FilterOperator]string{
+		FilterOperatorEq:  "=",
+		FilterOperatorNe:  "<>",
+		FilterOperatorGt:  ">",
+		FilterOperatorGte: ">=",
+		FilterOperatorLt:  "<",
+		FilterOperatorLte: "<=",
+	}
+	return WhereClause{Query: f.Column + " " + comparisons[operator] + " ?", Args: []any{value}}, nil
+}
+
+func (f FilterField) parse(raw string) (any, error) {
+	switch f.Type {
+	case FilterTypeUUID:
+		id, err := uuid.Parse(raw)
+		if err != nil {
+			return nil, fmt.Errorf("expected UUID")
+		}
+		return id, nil
+	case FilterTypeInt:
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected integer")
+		}
+		return value, nil
+	case FilterTypeNumber:
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return nil, fmt.Errorf("expected number")
+		}
+		return value, nil
+	case FilterTypeBool:
+		value, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, fmt.Errorf("expected true or false")
+		}
+		return value, nil
+	case FilterTypeTime:
+		value, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			return nil, fmt.Errorf("expected RFC3339 datetime")
+		}
+		return value.UTC(), nil
+	}
+
+	if len(f.Values) > 0 && !slices.Contains(f.Values, raw) {
+		return nil, fmt.Errorf("expected one of %s", strings.Join(f.Values, ", "))
+	}
+	return raw, nil
+}
+
+// escapeLikePattern escapes the wildcards of a LIKE pattern, so values match
+// literally.
+func escapeLikePattern(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
@@ -24,6 +24,25 @@ type AuthSessionRepository interface {
 	GetByRefreshTokenHash(ctx context.Context, hash string) (*domain.AuthSession, error)
 	RevokeByID(ctx context.Context, id uuid.UUID, revokedAt time.Time) error
 	RevokeByUserID(ctx context.Context, userID uuid.UUID, revokedAt time.Time) error
+	RevokeByDeviceID(ctx context.Context, deviceID uuid.UUID, revokedAt time.Time) error
+}
+
+type DeviceRepository interface {
+	// Upsert registers the user's device by client id, or marks an existing one
+	// as seen and active again, and loads the stored row into device.
+	Upsert(ctx context.Context, device *domain.Device) error
+	ListByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Device, error)
+	GetByUserAndID(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*domain.Device, error)
+	GetByUserAndClientID(ctx context.Context, userID uuid.UUID, clientID string) (*domain.Device, error)
+	Rename(ctx context.Context, id uuid.UUID, name string) error
+	Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error
+	// RecordSync marks the user's active device as synced at syncedAt and moves
+	// its checkpoint forward to position, when given.
+	RecordSync(ctx context.Context, userID uuid.UUID, clientID string, position *SyncEventCursor, syncedAt time.Time) error
+	// GetSyncHorizon returns the oldest checkpoint among the user's active devices
+	// seen since activeSince, counting a device without one as the epoch, or nil
+	// when the user has no such device.
+	GetSyncHorizon(ctx context.Context, userID uuid.UUID, activeSince time.Time) (*time.Time, error)
 }
 
 type EmailVerificationRepository interface {
@@ -35,26 +54,63 @@ type EmailVerificationRepository interface {
 
 type UserRepository interface {
 	ResourceRepository[domain.User]
+	// GetForUpdate locks the user until the surrounding transaction ends.
+	GetForUpdate(ctx context.Context, id uuid.UUID) (*domain.User, error)
 }
 
 type EbookRepository interface {
 	ResourceRepository[domain.Ebook]
 }
 
+type AuthorRepository interface {
+	ResourceRepository[domain.Author]
+	// GetByName finds the author with the name, ignoring case.
+	GetByName(ctx context.Context, name string) (*domain.Author, error)
+	// FindOrCreateByNames returns the authors with the names, ignoring case, and
+	// creates those that do not exist yet.
+	FindOrCreateByNames(ctx context.Context, names []string) ([]domain.Author, error)
+	// ReplaceEbookAuthors sets the authors of the ebook to exactly authorIDs.
+	ReplaceEbookAuthors(ctx context.Context, ebookID uuid.UUID, authorIDs []uuid.UUID) error
+}
+
+type TagRepository interface {
+	ResourceRepository[domain.Tag]
+	// GetByName finds the tag with the name, ignoring case.
+	GetByName(ctx context.Context, name string) (*domain.Tag, error)
+	// FindOrCreateByNames returns the tags with the names, ignoring case, and
+	// creates those that do not exist yet.
+	FindOrCreateByNames(ctx context.Context, names []string) ([]domain.Tag, error)
+	// ReplaceEbookTags sets the tags of the ebook to exactly tagIDs.
+	ReplaceEbookTags(ctx context.Context, ebookID uuid.UUID, tagIDs []uuid.UUID) error
+}
+
 type EbookGoogleMetadataRepository interface {
 	GetByEbookID(ctx context.Context, ebookID uuid.UUID) (*domain.EbookGoogleMetadata, error)
 	Upsert(ctx context.Context, metadata *domain.EbookGoogleMetadata) error
 	SoftDeleteByEbookID(ctx context.Context, ebookID uuid.UUID) error
 }
 
+type EbookMetadataSuggestionRepository interface {
+	// ListByEbookID lists the ebook's suggestions, best match first.
+	ListByEbookID(ctx context.Context, ebookID uuid.UUID) ([]domain.EbookMetadataSuggestion, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*domain.EbookMetadataSuggestion, error)
+	// ReplaceByEbookID swaps every suggestion of the ebook for suggestions.
+	ReplaceByEbookID(ctx context.Context, ebookID uuid.UUID, suggestions []domain.EbookMetadataSuggestion) error
+	DeleteByEbookID(ctx context.Context, ebookID uuid.UUID) error
+}
+
 type UserPreferencesRepository interface {
 	GetByUserID(ctx context.Context, userID uuid.UUID) (*domain.UserPreferences, error)
-	Upsert(ctx context.Context, prefs *domain.UserPreferences) error
+	// Upsert inserts prefs, or writes them over the stored row and increments
+	// its RowVersion. With ifVersion, the stored row is only written while it is
+	// still at that version, and ErrStaleRowVersion is returned otherwise.
+	Upsert(ctx context.Context, prefs *domain.UserPreferences, ifVersion *int64) error
 }
 
 type UserReaderStateRepository interface {
 	GetByUserID(ctx context.Context, userID uuid.UUID) (*domain.UserReaderState, error)
-	Upsert(ctx context.Context, state *domain.UserReaderState) error
+	// Upsert stores state as UserPreferencesRepository.Upsert stores preferences.
+	Upsert(ctx context.Context, state *domain.UserReaderState, ifVersion *int64) error
 }
 
 type ReadingProgressRepository interface {
@@ -72,12 +128,23 @@ type AnnotationRepository interface {
 
 type ShareRepository interface {
 	ResourceRepository[domain.Share]
+	// GetForUpdate locks the share until the surrounding transaction ends.
+	GetForUpdate(ctx context.Context, id uuid.UUID) (*domain.Share, error)
+	// DisableActiveByOwner disables every active share of the user and returns them.
+	DisableActiveByOwner(ctx context.Context, ownerID uuid.UUID) ([]domain.Share, error)
 }
 
 type BorrowRepository interface {
 	ResourceRepository[domain.Borrow]
 	CountActiveByShare(ctx context.Context, shareID uuid.UUID) (int64, error)
 	GetActiveByShareAndBorrower(ctx context.Context, shareID uuid.UUID, borrowerID uuid.UUID) (*domain.Borrow, error)
+	ExpireOverdue(ctx context.Context, now time.Time, limit int) ([]domain.Borrow, error)
+	// RevokeActiveByShare revokes every active borrow of the share and returns them.
+	RevokeActiveByShare(ctx context.Context, shareID uuid.UUID, now time.Time) ([]domain.Borrow, error)
+	// FlagActiveByShare flags every unflagged active borrow of the share and returns them.
+	FlagActiveByShare(ctx context.Context, shareID uuid.UUID, now time.Time) ([]domain.Borrow, error)
+	// UnflagByShare clears the flag of every borrow of the share and returns them.
+	UnflagByShare(ctx context.Context, shareID uuid.UUID) ([]domain.Borrow, error)
 }
 
 type ShareReviewRepository interface {
@@ -87,12 +154,37 @@ type ShareReviewRepository interface {
 
 type ShareReportRepository interface {
 	ResourceRepository[domain.ShareReport]
+	// GetForUpdate locks the report until the surrounding transaction ends.
+	GetForUpdate(ctx context.Context, id uuid.UUID) (*domain.ShareReport, error)
+	// CountPendingReporters counts the distinct users with an open or in review
+	// report of the reason against the share.
+	CountPendingReporters(ctx context.Context, shareID uuid.UUID, reason domain.ReportReason) (int64, error)
+	// ClosePendingByShare moves every open or in review report of the share to
+	// status, resolved or rejected, with the moderator's note and returns them.
+	ClosePendingByShare(ctx context.Context, shareID uuid.UUID, status domain.ReportStatus, moderatorID uuid.UUID, note string, now time.Time) ([]domain.ShareReport, error)
+}
+
+// SyncEventCursor is a position in a user's event stream, which is ordered by
+// server timestamp and then by id.
+type SyncEventCursor struct {
+	ServerTimestamp time.Time
+	ID              uuid.UUID
 }
 
 type SyncEventRepository interface {
 	ResourceRepository[domain.SyncEvent]
 	GetByUserAndIdempotencyKey(ctx context.Context, userID uuid.UUID, idempotencyKey string) (*domain.SyncEvent, error)
-	ListSince(ctx context.Context, userID uuid.UUID, since *time.Time, limit int) ([]domain.SyncEvent, error)
+	ListAfter(ctx context.Context, userID uuid.UUID, after *SyncEventCursor, limit int) ([]domain.SyncEvent, error)
+	GetLatestByUserID(ctx context.Context, userID uuid.UUID) (*domain.SyncEvent, error)
+	// ListCompactableUsers pages through users that have more than one event for
+	// the same entity, in user id order.
+	ListCompactableUsers(ctx context.Context, afterUserID uuid.UUID, limit int) ([]uuid.UUID, error)
+	// ListSupersededUntil returns the user's events at or before until for every
+	// entity with more than one such event, grouped by entity and oldest first.
+	ListSupersededUntil(ctx context.Context, userID uuid.UUID, until time.Time) ([]domain.SyncEvent, error)
+	// Collapse replaces keep's payload and deletes the events it supersedes.
+	Collapse(ctx context.Context, keep *domain.SyncEvent, supersededIDs []uuid.UUID) error
+	DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error)
 }
 
 type SyncCheckpointRepository interface {
@@ -100,22 +192,127 @@ type SyncCheckpointRepository interface {
 	Upsert(ctx context.Context, checkpoint *domain.SyncCheckpoint) error
 }
 
+// SyncRepositories are the repositories a sync event reads and writes, bound to one transaction.
+type SyncRepositories struct {
+	SyncEvent       SyncEventRepository
+	SyncCheckpoint  SyncCheckpointRepository
+	ReadingProgress ReadingProgressRepository
+	Bookmark        BookmarkRepository
+	Annotation      AnnotationRepository
+	UserPreferences UserPreferencesRepository
+	UserReaderState UserReaderStateRepository
+	Ebook           EbookRepository
+}
+
+// SyncTransactor applies sync events atomically. WithinTransaction serializes
+// transactions per user, rolls back when fn returns an error, and evicts entityID
+// from the entity caches once the transaction commits.
+type SyncTransactor interface {
+	WithinTransaction(ctx context.Context, userID uuid.UUID, entityID uuid.UUID, fn func(repos *SyncRepositories) error) error
+}
+
+// AuditEventFilter narrows an audit event listing. Zero fields match every event.
+type AuditEventFilter struct {
+	ActorUserID *uuid.UUID
+	Action      domain.AuditAction
+	TargetType  domain.AuditTargetType
+	TargetID    *uuid.UUID
+	From        *time.Time
+	To          *time.Time
+	Limit       int
+	Offset      int
+	// After continues the listing from a created_at cursor, ignoring Offset.
+	After *ListCursor
+	// SkipCount leaves the total uncounted, and List returns -1 for it.
+	SkipCount bool
+}
+
+// AuditEventRepository stores audit events. Events are never updated or deleted.
+type AuditEventRepository interface {
+	Store(ctx context.Context, event *domain.AuditEvent) error
+	// List returns the events matching filter, newest first, and their total count.
+	List(ctx context.Context, filter AuditEventFilter) ([]domain.AuditEvent, int64, error)
+}
+
+// SearchQuery is a full-text search. Text is parsed as a web search query, so
+// it may quote phrases, join words with "or" and exclude them with "-".
+type SearchQuery struct {
+	Text   string
+	Limit  int
+	Offset int
+}
+
+type SearchRepository interface {
+	// SearchLibrary returns the user's ebooks matching query, best match first,
+	// and their total count.
+	SearchLibrary(ctx context.Context, userID uuid.UUID, query SearchQuery) ([]domain.SearchHit, int64, error)
+	// SearchCommunity returns the active public shares matching query, by their
+	// own title and description or their ebook's, best match first, and their
+	// total count.
+	SearchCommunity(ctx context.Context, query SearchQuery) ([]domain.SearchHit, int64, error)
+}
+
+type EbookContentRepository interface {
+	GetIndexByEbookID(ctx context.Context, ebookID uuid.UUID) (*domain.EbookContentIndex, error)
+	// ReplaceIndex swaps every passage of the ebook for passages and records
+	// index, at once.
+	ReplaceIndex(ctx context.Context, index *domain.EbookContentIndex, passages []domain.EbookPassage) error
+	// SearchPassages returns the ebook's passages matching query, in reading
+	// order, and their total count.
+	SearchPassages(ctx context.Context, ebookID uuid.UUID, query SearchQuery) ([]domain.EbookPassageHit, int64, error)
+}
+
+// AuthorizationPolicyRepository persists authorization policy rules. Add and
+// Remove report whether the rule was actually added or removed.
+type AuthorizationPolicyRepository interface {
+	Add(ctx context.Context, rule []string) (bool, error)
+	Remove(ctx context.Context, rule []string) (bool, error)
+}
+
+// ModerationRepositories are the repositories an administrative action writes,
+// bound to one transaction, so its audit event commits along with it.
+type ModerationRepositories struct {
+	User                UserRepository
+	AuthSession         AuthSessionRepository
+	Share               ShareRepository
+	Borrow              BorrowRepository
+	ShareReport         ShareReportRepository
+	AuthorizationPolicy AuthorizationPolicyRepository
+	AuditEvent          AuditEventRepository
+}
+
+// ModerationTransactor applies an administrative action atomically and rolls back
+// when fn returns an error. The repositories skip the cache, so callers evict
+// what they changed once the transaction commits.
+type ModerationTransactor interface {
+	WithinTransaction(ctx context.Context, fn func(repos *ModerationRepositories) error) error
+}
+
 type Repositories struct {
-	Auth              AuthRepository
-	AuthSession       AuthSessionRepository
-	User              UserRepository
-	EmailVerification EmailVerificationRepository
-	Ebook             EbookRepository
-	EbookMetadata     EbookGoogleMetadataRepository
-	UserPreferences   UserPreferencesRepository
-	UserReaderState   UserReaderStateRepository
-	ReadingProgress   ReadingProgressRepository
-	Bookmark          BookmarkRepository
-	Annotation        AnnotationRepository
-	Share             ShareRepository
-	Borrow            BorrowRepository
-	ShareReview       ShareReviewRepository
-	ShareReport       ShareReportRepository
-	SyncEvent         SyncEventRepository
-	SyncCheckpoint    SyncCheckpointRepository
+	Auth                 AuthRepository
+	AuthSession          AuthSessionRepository
+	Device               DeviceRepository
+	User                 UserRepository
+	EmailVerification    EmailVerificationRepository
+	Ebook                EbookRepository
+	Author               AuthorRepository
+	Tag                  TagRepository
+	EbookMetadata        EbookGoogleMetadataRepository
+	EbookSuggestion      EbookMetadataSuggestionRepository
+	UserPreferences      UserPreferencesRepository
+	UserReaderState      UserReaderStateRepository
+	ReadingProgress      ReadingProgressRepository
+	Bookmark             BookmarkRepository
+	Annotation           AnnotationRepository
+	Share                ShareRepository
+	Borrow               BorrowRepository
+	ShareReview          ShareReviewRepository
+	ShareReport          ShareReportRepository
+	SyncEvent            SyncEventRepository
+	SyncCheckpoint       SyncCheckpointRepository
+	SyncTransactor       SyncTransactor
+	AuditEvent           AuditEventRepository
+	ModerationTransactor ModerationTransactor
+	Search               SearchRepository
+	EbookContent         EbookContentRepository
 }
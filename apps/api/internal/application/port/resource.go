@@ -2,6 +2,8 @@ package port
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"strings"
 
 	"github.com/google/uuid"
@@ -13,8 +15,20 @@ type ResourceRepository[T domain.BaseModel] interface {
 	GetByID(ctx context.Context, id uuid.UUID, preloads []string) (*T, error)
 	GetMany(ctx context.Context, opts GetManyOptions) ([]T, int64, error)
 	Update(ctx context.Context, entity T, updates ...map[string]any) (*T, error)
+	// UpdateVersioned applies updates to entity and increments its RowVersion
+	// in the same statement. With ifVersion, the row is only updated while it
+	// is still at that version, and ErrStaleRowVersion is returned otherwise.
+	// Models without a RowVersion are updated as by Update.
+	UpdateVersioned(ctx context.Context, entity T, updates map[string]any, ifVersion *int64) (*T, error)
 	Destroy(ctx context.Context, id uuid.UUID) error
 	Kill(ctx context.Context, id uuid.UUID) error
+	// DestroyVersioned soft-deletes the row as Destroy does. With ifVersion,
+	// the row is only deleted while it is still at that version, and
+	// ErrStaleRowVersion is returned otherwise.
+	DestroyVersioned(ctx context.Context, id uuid.UUID, ifVersion *int64) error
+	// KillVersioned deletes the row for good as Kill does, with ifVersion as
+	// DestroyVersioned takes it.
+	KillVersioned(ctx context.Context, id uuid.UUID, ifVersion *int64) error
 	Restore(ctx context.Context, id uuid.UUID) (*T, error)
 	CacheEnabled() bool
 	EvictCache(ctx context.Context, id uuid.UUID)
@@ -39,8 +53,40 @@ type GetManyOptions struct {
 	OrderDirection string
 	Limit          int
 	Offset         int
+	// WithDeleted includes soft-deleted rows.
+	WithDeleted bool
+	// After continues a keyset listing from a cursor, returning only the rows
+	// ordered after it by (OrderBy, id). Offset is ignored, and OrderBy must not
+	// be a nullable column.
+	After *ListCursor
+	// SkipCount leaves the total uncounted, and GetMany returns -1 for it.
+	SkipCount bool
 }
 
+// ListCursor is the position of a keyset listing: the OrderBy value and id of
+// the last row handed out. It is compared as is, so a listing continues past a
+// row that has since been changed or deleted.
+type ListCursor struct {
+	// Value is the JSON encoding of the row's OrderBy value.
+	Value json.RawMessage
+	ID    uuid.UUID
+}
+
+var (
+	// ErrInvalidOrderBy is returned by GetMany when OrderBy is not a column of
+	// the listed resource.
+	ErrInvalidOrderBy = errors.New("orderBy is not a sortable column")
+	// ErrNullableOrderBy is returned by GetMany when a keyset listing is
+	// ordered by a nullable column, whose null rows a cursor cannot pass.
+	ErrNullableOrderBy = errors.New("orderBy is nullable and cannot be paged by cursor")
+	// ErrStaleRowVersion is returned by conditional writes when the row is no
+	// longer at the version the write was based on.
+	ErrStaleRowVersion = errors.New("row version is stale")
+	// ErrInvalidCursor is returned by GetMany when the cursor value does not
+	// decode to the type of the OrderBy column.
+	ErrInvalidCursor = errors.New("cursor value does not match orderBy")
+)
+
 func (o *GetManyOptions) Normalize() {
 	if o.Limit <= 0 {
 		o.Limit = 20
@@ -54,6 +100,10 @@ func (o *GetManyOptions) Normalize() {
 	if o.OrderBy == "" {
 		o.OrderBy = "created_at"
 	}
+
+	if o.After != nil {
+		o.Offset = 0
+	}
 }
 
 func ParsePreloads(raw string) []string {
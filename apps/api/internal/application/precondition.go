@@ -0,0 +1,62 @@
+package application
+
+import (
+	"context"
+	"slices"
+
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+)
+
+type ifMatchKey struct{}
+
+// WithIfMatch makes the updates and deletes done with ctx conditional on the
+// row still being at one of versions, as a client sends them in If-Match. An
+// empty versions never matches.
+func WithIfMatch(ctx context.Context, versions []int64) context.Context {
+	if versions == nil {
+		versions = []int64{}
+	}
+	return context.WithValue(ctx, ifMatchKey{}, versions)
+}
+
+// checkIfMatch fails with 412 Precondition Failed when ctx carries an If-Match
+// that entity's current version does not satisfy. Models without a RowVersion
+// hand out no ETag, so an If-Match on them is ignored. The error carries the
+// current entity, so the client can reconcile its edit with it.
+func checkIfMatch(ctx context.Context, entity any) error {
+	versions, ok := ctx.Value(ifMatchKey{}).([]int64)
+	if !ok {
+		return nil
+	}
+
+	versioned, ok := entity.(domain.VersionedModel)
+	if !ok || slices.Contains(versions, versioned.GetRowVersion()) {
+		return nil
+	}
+	return modifiedSinceRead(entity)
+}
+
+// modifiedSinceRead is the 412 Precondition Failed of a write whose row is no
+// longer at the version the client read, carrying the current row.
+func modifiedSinceRead(current any) error {
+	return errs.NewPreconditionFailedError("resource was modified since it was read", true, current)
+}
+
+// hasIfMatch reports whether ctx carries an If-Match to check.
+func hasIfMatch(ctx context.Context) bool {
+	_, ok := ctx.Value(ifMatchKey{}).([]int64)
+	return ok
+}
+
+// ifMatchVersion is the version a write of entity must still find its row at:
+// the version entity was read at when ctx carries an If-Match, so a concurrent
+// write in between fails the precondition, or nil for an unconditional write.
+func ifMatchVersion(ctx context.Context, entity any) *int64 {
+	versioned, ok := entity.(domain.VersionedModel)
+	if !ok || !hasIfMatch(ctx) {
+		return nil
+	}
+	version := versioned.GetRowVersion()
+	return &version
+}
@@ -0,0 +1,144 @@
+package application
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/repository"
+	"github.com/stretchr/testify/require"
+)
+
+// Ensures Update only applies over the version the client read, and bumps it.
+func TestResourceServiceUpdate_IfMatch(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMockResourceRepository[domain.Bookmark](false)
+	bookmark := &domain.Bookmark{ID: uuid.New(), Location: "loc-1", RowVersion: 1}
+	require.NoError(t, repo.Store(ctx, bookmark))
+
+	svc := NewResourceService[domain.Bookmark, *applicationdto.StoreBookmarkInput, *applicationdto.UpdateBookmarkInput]("bookmark", repo)
+	label := "intro"
+
+	_, err := svc.Update(WithIfMatch(ctx, []int64{2}), bookmark.ID, &applicationdto.UpdateBookmarkInput{Label: &label})
+	requireErrorStatus(t, err, http.StatusPreconditionFailed)
+
+	updated, err := svc.Update(WithIfMatch(ctx, []int64{1}), bookmark.ID, &applicationdto.UpdateBookmarkInput{Label: &label})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), updated.RowVersion)
+	require.Equal(t, "intro", *updated.Label)
+
+	_, err = svc.Update(WithIfMatch(ctx, []int64{1}), bookmark.ID, &applicationdto.UpdateBookmarkInput{Label: &label})
+	requireErrorStatus(t, err, http.StatusPreconditionFailed)
+}
+
+// Ensures Destroy refuses to delete a row changed since the client read it.
+func TestResourceServiceDestroy_IfMatch(t *testing.T) {
+	ctx := context.Background()
+	repo := repository.NewMockResourceRepository[domain.Bookmark](false)
+	bookmark := &domain.Bookmark{ID: uuid.New(), Location: "loc-1", RowVersion: 3}
+	require.NoError(t, repo.Store(ctx, bookmark))
+
+	svc := NewResourceService[domain.Bookmark, *applicationdto.StoreBookmarkInput, *applicationdto.UpdateBookmarkInput]("bookmark", repo)
+
+	err := svc.Destroy(WithIfMatch(ctx, []int64{2}), bookmark.ID)
+	requireErrorStatus(t, err, http.StatusPreconditionFailed)
+
+	err = svc.Destroy(WithIfMatch(ctx, []int64{}), bookmark.ID)
+	requireErrorStatus(t, err, http.StatusPreconditionFailed)
+
+	require.NoError(t, svc.Destroy(WithIfMatch(ctx, []int64{2, 3}), bookmark.ID))
+}
+
+// Ensures an If-Match on a resource without a row version is ignored, as it
+// never hands out an ETag to match.
+func TestResourceService_IfMatchIgnoredWhenUnversioned(t *testing.T) {
+	ctx := WithIfMatch(context.Background(), []int64{1})
+	repo := repository.NewMockResourceRepository[domain.Tag](false)
+	tag := &domain.Tag{ID: uuid.New(), Name: "fantasy"}
+	require.NoError(t, repo.Store(ctx, tag))
+
+	svc := NewResourceService[domain.Tag, *applicationdto.StoreTagInput, *applicationdto.UpdateTagInput]("tag", repo)
+	name := "sci-fi"
+
+	updated, err := svc.Update(ctx, tag.ID, &applicationdto.UpdateTagInput{Name: &name})
+	require.NoError(t, err)
+	require.Equal(t, "sci-fi", updated.Name)
+
+	require.NoError(t, svc.Destroy(ctx, tag.ID))
+}
+
+// Ensures a preferences patch based on a stale version is rejected.
+func TestUserPreferencesServicePatch_IfMatch(t *testing.T) {
+	service := NewUserPreferencesService(newTestUserPreferencesRepo())
+	userID := uuid.New()
+	mode := domain.ReadingModeZen
+
+	_, err := service.Patch(WithIfMatch(context.Background(), []int64{2}), userID, &applicationdto.UpdateUserPreferencesInput{ReadingMode: &mode})
+	requireErrorStatus(t, err, http.StatusPreconditionFailed)
+
+	updated, err := service.Patch(WithIfMatch(context.Background(), []int64{1}), userID, &applicationdto.UpdateUserPreferencesInput{ReadingMode: &mode})
+	require.NoError(t, err)
+	require.Equal(t, int64(2), updated.RowVersion)
+}
+
+// staleReadBookmarkRepo serves reads from a snapshot taken before a concurrent
+// write, as a cached or racing read would.
+type staleReadBookmarkRepo struct {
+	*repository.MockResourceRepository[domain.Bookmark]
+	snapshot domain.Bookmark
+}
+
+func (r *staleReadBookmarkRepo) GetByID(ctx context.Context, id uuid.UUID, preloads []string) (*domain.Bookmark, error) {
+	snapshot := r.snapshot
+	return &snapshot, nil
+}
+
+func (r *staleReadBookmarkRepo) GetMany(ctx context.Context, opts port.GetManyOptions) ([]domain.Bookmark, int64, error) {
+	return []domain.Bookmark{r.snapshot}, 1, nil
+}
+
+// Ensures an If-Match write loses to a concurrent write made after its read.
+func TestResourceServiceUpdate_IfMatchRacesConcurrentWrite(t *testing.T) {
+	ctx := context.Background()
+	repo := &staleReadBookmarkRepo{MockResourceRepository: repository.NewMockResourceRepository[domain.Bookmark](false)}
+	bookmark := domain.Bookmark{ID: uuid.New(), Location: "loc-1", RowVersion: 1}
+	repo.snapshot = bookmark
+	bookmark.RowVersion = 2
+	require.NoError(t, repo.Store(ctx, &bookmark))
+
+	svc := NewResourceService[domain.Bookmark, *applicationdto.StoreBookmarkInput, *applicationdto.UpdateBookmarkInput]("bookmark", repo)
+	label := "intro"
+
+	_, err := svc.Update(WithIfMatch(ctx, []int64{1}), bookmark.ID, &applicationdto.UpdateBookmarkInput{Label: &label})
+	requireErrorStatus(t, err, http.StatusPreconditionFailed)
+
+	updated, err := svc.Update(ctx, bookmark.ID, &applicationdto.UpdateBookmarkInput{Label: &label})
+	require.NoError(t, err)
+	require.Equal(t, int64(3), updated.RowVersion)
+}
+
+// Ensures an If-Match delete loses to a concurrent write made after its read.
+func TestResourceServiceDestroy_IfMatchRacesConcurrentWrite(t *testing.T) {
+	ctx := context.Background()
+	repo := &staleReadBookmarkRepo{MockResourceRepository: repository.NewMockResourceRepository[domain.Bookmark](false)}
+	bookmark := domain.Bookmark{ID: uuid.New(), Location: "loc-1", RowVersion: 1}
+	repo.snapshot = bookmark
+	bookmark.RowVersion = 2
+	require.NoError(t, repo.Store(ctx, &bookmark))
+
+	svc := NewResourceService[domain.Bookmark, *applicationdto.StoreBookmarkInput, *applicationdto.UpdateBookmarkInput]("bookmark", repo)
+
+	err := svc.Destroy(WithIfMatch(ctx, []int64{1}), bookmark.ID)
+	requireErrorStatus(t, err, http.StatusPreconditionFailed)
+	_, err = repo.MockResourceRepository.GetByID(ctx, bookmark.ID, nil)
+	require.NoError(t, err)
+
+	err = svc.Kill(WithIfMatch(ctx, []int64{1}), bookmark.ID)
+	requireErrorStatus(t, err, http.StatusPreconditionFailed)
+	_, err = repo.MockResourceRepository.GetByID(ctx, bookmark.ID, nil)
+	require.NoError(t, err)
+}
@@ -44,16 +44,69 @@ type userReaderStateService struct {
 	repo port.UserReaderStateRepository
 }
 
-func NewReadingProgressService(repo port.ReadingProgressRepository) ReadingProgressService {
-	return NewResourceService[domain.ReadingProgress, *applicationdto.StoreReadingProgressInput, *applicationdto.UpdateReadingProgressInput]("reading_progress", repo)
+func NewReadingProgressService(repo port.ReadingProgressRepository, ebookRepo port.EbookRepository) ReadingProgressService {
+	return &ebookScopedService[domain.ReadingProgress, *applicationdto.StoreReadingProgressInput, *applicationdto.UpdateReadingProgressInput]{
+		ResourceService: NewOwnedResourceService[domain.ReadingProgress, *applicationdto.StoreReadingProgressInput, *applicationdto.UpdateReadingProgressInput]("reading_progress", repo, OwnershipPolicy[domain.ReadingProgress]{
+			Column:  "user_id",
+			OwnerID: func(e *domain.ReadingProgress) uuid.UUID { return e.UserID },
+		}),
+		ebookRepo: ebookRepo,
+		ebookOf:   func(e *domain.ReadingProgress) (uuid.UUID, uuid.UUID) { return e.EbookID, e.UserID },
+	}
+}
+
+func NewBookmarkService(repo port.BookmarkRepository, ebookRepo port.EbookRepository) BookmarkService {
+	return &ebookScopedService[domain.Bookmark, *applicationdto.StoreBookmarkInput, *applicationdto.UpdateBookmarkInput]{
+		ResourceService: NewOwnedResourceService[domain.Bookmark, *applicationdto.StoreBookmarkInput, *applicationdto.UpdateBookmarkInput]("bookmark", repo, OwnershipPolicy[domain.Bookmark]{
+			Column:  "user_id",
+			OwnerID: func(e *domain.Bookmark) uuid.UUID { return e.UserID },
+		}),
+		ebookRepo: ebookRepo,
+		ebookOf:   func(e *domain.Bookmark) (uuid.UUID, uuid.UUID) { return e.EbookID, e.UserID },
+	}
+}
+
+func NewAnnotationService(repo port.AnnotationRepository, ebookRepo port.EbookRepository) AnnotationService {
+	return &ebookScopedService[domain.Annotation, *applicationdto.StoreAnnotationInput, *applicationdto.UpdateAnnotationInput]{
+		ResourceService: NewOwnedResourceService[domain.Annotation, *applicationdto.StoreAnnotationInput, *applicationdto.UpdateAnnotationInput]("annotation", repo, OwnershipPolicy[domain.Annotation]{
+			Column:  "user_id",
+			OwnerID: func(e *domain.Annotation) uuid.UUID { return e.UserID },
+		}),
+		ebookRepo: ebookRepo,
+		ebookOf:   func(e *domain.Annotation) (uuid.UUID, uuid.UUID) { return e.EbookID, e.UserID },
+	}
+}
+
+// ebookScopedService stores and updates a user's reading data only on an ebook
+// of that user, as sync does, so it cannot be attached to another user's ebook.
+type ebookScopedService[T domain.BaseModel, S applicationdto.StoreDTO[T], U applicationdto.UpdateDTO[T]] struct {
+	ResourceService[T, S, U]
+	ebookRepo port.EbookRepository
+	// ebookOf returns the ebook the entity refers to and the user owning it.
+	ebookOf func(entity *T) (ebookID uuid.UUID, userID uuid.UUID)
+}
+
+func (s *ebookScopedService[T, S, U]) Store(ctx context.Context, dto S) (*T, error) {
+	if err := s.requireOwnedEbook(ctx, dto.ToModel()); err != nil {
+		return nil, err
+	}
+	return s.ResourceService.Store(ctx, dto)
 }
 
-func NewBookmarkService(repo port.BookmarkRepository) BookmarkService {
-	return NewResourceService[domain.Bookmark, *applicationdto.StoreBookmarkInput, *applicationdto.UpdateBookmarkInput]("bookmark", repo)
+func (s *ebookScopedService[T, S, U]) Update(ctx context.Context, id uuid.UUID, dto U) (*T, error) {
+	entity, err := s.GetByID(ctx, id, nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.requireOwnedEbook(ctx, entity); err != nil {
+		return nil, err
+	}
+	return s.ResourceService.Update(ctx, id, dto)
 }
 
-func NewAnnotationService(repo port.AnnotationRepository) AnnotationService {
-	return NewResourceService[domain.Annotation, *applicationdto.StoreAnnotationInput, *applicationdto.UpdateAnnotationInput]("annotation", repo)
+func (s *ebookScopedService[T, S, U]) requireOwnedEbook(ctx context.Context, entity *T) error {
+	ebookID, userID := s.ebookOf(entity)
+	return sqlerr.HandleError(requireOwnedEbook(ctx, s.ebookRepo, ebookID, userID))
 }
 
 func NewUserPreferencesService(repo port.UserPreferencesRepository) UserPreferencesService {
@@ -77,7 +130,7 @@ func (s *userPreferencesService) GetByUserID(ctx context.Context, userID uuid.UU
 				TypographyProfile: domain.TypographyProfileComfortable,
 				RowVersion:        1,
 			}
-			if err := s.repo.Upsert(ctx, defaults); err != nil {
+			if err := s.repo.Upsert(ctx, defaults, nil); err != nil {
 				return nil, sqlerr.HandleError(err)
 			}
 			return defaults, nil
@@ -99,6 +152,9 @@ func (s *userPreferencesService) Patch(ctx context.Context, userID uuid.UUID, in
 	if err != nil {
 		return nil, err
 	}
+	if err := checkIfMatch(ctx, prefs); err != nil {
+		return nil, err
+	}
 
 	if input.ReadingMode != nil {
 		prefs.ReadingMode = *input.ReadingMode
@@ -121,10 +177,13 @@ func (s *userPreferencesService) Patch(ctx context.Context, userID uuid.UUID, in
 		}
 	}
 
-	prefs.RowVersion++
 	prefs.UpdatedAt = time.Now().UTC()
 
-	if err := s.repo.Upsert(ctx, prefs); err != nil {
+	if err := s.repo.Upsert(ctx, prefs, ifMatchVersion(ctx, prefs)); err != nil {
+		if errors.Is(err, port.ErrStaleRowVersion) {
+			current, _ := s.repo.GetByUserID(ctx, userID)
+			return nil, modifiedSinceRead(current)
+		}
 		return nil, sqlerr.HandleError(err)
 	}
 
@@ -140,7 +199,7 @@ func (s *userReaderStateService) GetByUserID(ctx context.Context, userID uuid.UU
 				ReadingMode: domain.ReadingModeNormal,
 				RowVersion:  1,
 			}
-			if err := s.repo.Upsert(ctx, defaults); err != nil {
+			if err := s.repo.Upsert(ctx, defaults, nil); err != nil {
 				return nil, sqlerr.HandleError(err)
 			}
 			return defaults, nil
@@ -159,6 +218,9 @@ func (s *userReaderStateService) Patch(ctx context.Context, userID uuid.UUID, in
 	if err != nil {
 		return nil, err
 	}
+	if err := checkIfMatch(ctx, state); err != nil {
+		return nil, err
+	}
 
 	if input.CurrentEbookID != nil {
 		state.CurrentEbookID = input.CurrentEbookID
@@ -173,10 +235,13 @@ func (s *userReaderStateService) Patch(ctx context.Context, userID uuid.UUID, in
 		state.LastOpenedAt = input.LastOpenedAt
 	}
 
-	state.RowVersion++
 	state.UpdatedAt = time.Now().UTC()
 
-	if err := s.repo.Upsert(ctx, state); err != nil {
+	if err := s.repo.Upsert(ctx, state, ifMatchVersion(ctx, state)); err != nil {
+		if errors.Is(err, port.ErrStaleRowVersion) {
+			current, _ := s.repo.GetByUserID(ctx, userID)
+			return nil, modifiedSinceRead(current)
+		}
 		return nil, sqlerr.HandleError(err)
 	}
 
@@ -8,7 +8,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
 	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
 	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/repository"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
@@ -29,11 +31,17 @@ func (r *testUserPreferencesRepo) GetByUserID(ctx context.Context, userID uuid.U
 	return prefs, nil
 }
 
-func (r *testUserPreferencesRepo) Upsert(ctx context.Context, prefs *domain.UserPreferences) error {
+func (r *testUserPreferencesRepo) Upsert(ctx context.Context, prefs *domain.UserPreferences, ifVersion *int64) error {
 	clone := *prefs
 	if clone.ThemeOverrides == nil {
 		clone.ThemeOverrides = map[string]string{}
 	}
+	if stored, ok := r.data[prefs.UserID]; ok {
+		if ifVersion != nil && stored.RowVersion != *ifVersion {
+			return port.ErrStaleRowVersion
+		}
+		clone.RowVersion = stored.RowVersion + 1
+	}
 	r.data[prefs.UserID] = &clone
 	return nil
 }
@@ -54,8 +62,14 @@ func (r *testUserReaderStateRepo) GetByUserID(ctx context.Context, userID uuid.U
 	return state, nil
 }
 
-func (r *testUserReaderStateRepo) Upsert(ctx context.Context, state *domain.UserReaderState) error {
+func (r *testUserReaderStateRepo) Upsert(ctx context.Context, state *domain.UserReaderState, ifVersion *int64) error {
 	clone := *state
+	if stored, ok := r.data[state.UserID]; ok {
+		if ifVersion != nil && stored.RowVersion != *ifVersion {
+			return port.ErrStaleRowVersion
+		}
+		clone.RowVersion = stored.RowVersion + 1
+	}
 	r.data[state.UserID] = &clone
 	return nil
 }
@@ -107,3 +121,36 @@ func TestUserPreferencesServicePatch_RejectsNilPayload(t *testing.T) {
 	require.ErrorAs(t, err, &httpErr)
 	require.Equal(t, http.StatusBadRequest, httpErr.Status)
 }
+
+// Ensures bookmarks can neither be stored on nor kept on another user's ebook.
+func TestBookmarkService_RequiresOwnedEbook(t *testing.T) {
+	userID, otherID := uuid.New(), uuid.New()
+	ctx := WithActor(context.Background(), Actor{UserID: userID})
+
+	ebookRepo := repository.NewMockResourceRepository[domain.Ebook](false)
+	owned := &domain.Ebook{ID: uuid.New(), OwnerUserID: userID}
+	foreign := &domain.Ebook{ID: uuid.New(), OwnerUserID: otherID}
+	require.NoError(t, ebookRepo.Store(ctx, owned))
+	require.NoError(t, ebookRepo.Store(ctx, foreign))
+
+	repo := repository.NewMockResourceRepository[domain.Bookmark](false)
+	svc := NewBookmarkService(repo, ebookRepo)
+
+	_, err := svc.Store(ctx, &applicationdto.StoreBookmarkInput{UserID: userID, EbookID: foreign.ID, Location: "loc-1"})
+	requireErrorStatus(t, err, http.StatusNotFound)
+	_, err = svc.Store(ctx, &applicationdto.StoreBookmarkInput{UserID: userID, EbookID: uuid.New(), Location: "loc-1"})
+	requireErrorStatus(t, err, http.StatusNotFound)
+
+	bookmark, err := svc.Store(ctx, &applicationdto.StoreBookmarkInput{UserID: userID, EbookID: owned.ID, Location: "loc-1"})
+	require.NoError(t, err)
+
+	label := "intro"
+	_, err = svc.Update(ctx, bookmark.ID, &applicationdto.UpdateBookmarkInput{Label: &label})
+	require.NoError(t, err)
+
+	// a bookmark stored before the check cannot be updated either
+	stray := &domain.Bookmark{ID: uuid.New(), UserID: userID, EbookID: foreign.ID, Location: "loc-2"}
+	require.NoError(t, repo.Store(ctx, stray))
+	_, err = svc.Update(ctx, stray.ID, &applicationdto.UpdateBookmarkInput{Label: &label})
+	requireErrorStatus(t, err, http.StatusNotFound)
+}
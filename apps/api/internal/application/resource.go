@@ -2,6 +2,8 @@ package application
 
 import (
 	"context"
+	"errors"
+	"maps"
 
 	"github.com/google/uuid"
 	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
@@ -25,12 +27,19 @@ type ResourceService[T domain.BaseModel, S applicationdto.StoreDTO[T], U applica
 type resourceService[T domain.BaseModel, S applicationdto.StoreDTO[T], U applicationdto.UpdateDTO[T]] struct {
 	repo         port.ResourceRepository[T]
 	resourceName string
+	ownership    *OwnershipPolicy[T]
 }
 
 func NewResourceService[T domain.BaseModel, S applicationdto.StoreDTO[T], U applicationdto.UpdateDTO[T]](resourceName string, repo port.ResourceRepository[T]) ResourceService[T, S, U] {
 	return &resourceService[T, S, U]{resourceName: resourceName, repo: repo}
 }
 
+// NewOwnedResourceService builds a resource service whose rows are scoped to
+// their owners by policy. Every call then requires an actor in ctx.
+func NewOwnedResourceService[T domain.BaseModel, S applicationdto.StoreDTO[T], U applicationdto.UpdateDTO[T]](resourceName string, repo port.ResourceRepository[T], policy OwnershipPolicy[T]) ResourceService[T, S, U] {
+	return &resourceService[T, S, U]{resourceName: resourceName, repo: repo, ownership: &policy}
+}
+
 func (s *resourceService[T, S, U]) Store(ctx context.Context, dto S) (*T, error) {
 	entity := dto.ToModel()
 	if err := s.repo.Store(ctx, entity); err != nil {
@@ -40,17 +49,38 @@ func (s *resourceService[T, S, U]) Store(ctx context.Context, dto S) (*T, error)
 }
 
 func (s *resourceService[T, S, U]) GetByID(ctx context.Context, id uuid.UUID, preloads []string) (*T, error) {
+	actor, err := s.ownership.scope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	entity, err := s.repo.GetByID(ctx, id, preloads)
 	if err != nil {
 		return nil, sqlerr.HandleError(err)
 	}
+	if !s.ownership.canRead(actor, entity) {
+		return nil, s.notFound()
+	}
 	return entity, nil
 }
 
 func (s *resourceService[T, S, U]) GetMany(ctx context.Context, opts port.GetManyOptions) ([]T, int64, error) {
+	actor, err := s.ownership.scope(ctx)
+	if err != nil {
+		return nil, 0, err
+	}
+	if actor != nil && !s.ownership.PublicRead {
+		filters := maps.Clone(opts.Filters)
+		if filters == nil {
+			filters = map[string]any{}
+		}
+		filters[s.ownership.Column] = actor.UserID
+		opts.Filters = filters
+	}
+
 	entities, total, err := s.repo.GetMany(ctx, opts)
 	if err != nil {
-		return nil, 0, sqlerr.HandleError(err)
+		return nil, 0, listingError(err)
 	}
 	return entities, total, nil
 }
@@ -58,16 +88,31 @@ func (s *resourceService[T, S, U]) GetMany(ctx context.Context, opts port.GetMan
 func (s *resourceService[T, S, U]) Update(ctx context.Context, id uuid.UUID, dto U) (*T, error) {
 	updates := dto.ToMap()
 
+	actor, err := s.ownership.scope(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	entity, err := s.repo.GetByID(ctx, id, nil)
 	if err != nil {
 		return nil, sqlerr.HandleError(err)
 	}
+	if err := s.checkMutation(actor, entity); err != nil {
+		return nil, err
+	}
+	if err := checkIfMatch(ctx, entity); err != nil {
+		return nil, err
+	}
 
 	if len(updates) == 0 {
 		return entity, nil
 	}
 
-	updatedEntity, err := s.repo.Update(ctx, *entity, updates)
+	updatedEntity, err := s.repo.UpdateVersioned(ctx, *entity, updates, ifMatchVersion(ctx, entity))
+	if errors.Is(err, port.ErrStaleRowVersion) {
+		current, _ := s.repo.GetByID(ctx, id, nil)
+		return nil, modifiedSinceRead(current)
+	}
 	if err != nil {
 		return nil, sqlerr.HandleError(err)
 	}
@@ -76,9 +121,22 @@ func (s *resourceService[T, S, U]) Update(ctx context.Context, id uuid.UUID, dto
 }
 
 func (s *resourceService[T, S, U]) Destroy(ctx context.Context, id uuid.UUID) error {
-	if err := s.repo.Destroy(ctx, id); err != nil {
+	entity, err := s.authorizeMutation(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.DestroyVersioned(ctx, id, ifMatchVersion(ctx, entity))
+	if errors.Is(err, port.ErrStaleRowVersion) {
+		current, err := s.repo.GetByID(ctx, id, nil)
+		if err != nil {
+			return s.notFound()
+		}
+		return modifiedSinceRead(current)
+	}
+	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return errs.NewNotFoundError(s.resourceName+" not found", true)
+			return s.notFound()
 		}
 		return sqlerr.HandleError(err)
 	}
@@ -86,9 +144,22 @@ func (s *resourceService[T, S, U]) Destroy(ctx context.Context, id uuid.UUID) er
 }
 
 func (s *resourceService[T, S, U]) Kill(ctx context.Context, id uuid.UUID) error {
-	if err := s.repo.Kill(ctx, id); err != nil {
+	entity, err := s.authorizeMutation(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.KillVersioned(ctx, id, ifMatchVersion(ctx, entity))
+	if errors.Is(err, port.ErrStaleRowVersion) {
+		current, err := s.getWithDeleted(ctx, id)
+		if err != nil {
+			return err
+		}
+		return modifiedSinceRead(current)
+	}
+	if err != nil {
 		if err == gorm.ErrRecordNotFound {
-			return errs.NewNotFoundError(s.resourceName+" not found", true)
+			return s.notFound()
 		}
 		return sqlerr.HandleError(err)
 	}
@@ -96,6 +167,10 @@ func (s *resourceService[T, S, U]) Kill(ctx context.Context, id uuid.UUID) error
 }
 
 func (s *resourceService[T, S, U]) Restore(ctx context.Context, id uuid.UUID, preloads []string) (*T, error) {
+	if _, err := s.authorizeMutation(ctx, id); err != nil {
+		return nil, err
+	}
+
 	entity, err := s.repo.Restore(ctx, id)
 	if err != nil {
 		return nil, sqlerr.HandleError(err)
@@ -110,3 +185,75 @@ func (s *resourceService[T, S, U]) Restore(ctx context.Context, id uuid.UUID, pr
 	}
 	return entity, nil
 }
+
+// authorizeMutation checks the actor may change the row id, which may already
+// be soft-deleted when it is killed or restored, and that the row satisfies
+// the If-Match in ctx. It returns the row when it had to read it, which is the
+// version a conditional write must still find.
+func (s *resourceService[T, S, U]) authorizeMutation(ctx context.Context, id uuid.UUID) (*T, error) {
+	actor, err := s.ownership.scope(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if actor == nil && !hasIfMatch(ctx) {
+		return nil, nil
+	}
+
+	entity, err := s.getWithDeleted(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if actor != nil {
+		if err := s.checkMutation(actor, entity); err != nil {
+			return nil, err
+		}
+	}
+	if err := checkIfMatch(ctx, entity); err != nil {
+		return nil, err
+	}
+	return entity, nil
+}
+
+// getWithDeleted reads the row id, even when it is soft-deleted.
+func (s *resourceService[T, S, U]) getWithDeleted(ctx context.Context, id uuid.UUID) (*T, error) {
+	entities, _, err := s.repo.GetMany(ctx, port.GetManyOptions{
+		Filters:     map[string]any{"id": id},
+		WithDeleted: true,
+		Limit:       1,
+		SkipCount:   true,
+	})
+	if err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+	if len(entities) == 0 {
+		return nil, s.notFound()
+	}
+	return &entities[0], nil
+}
+
+func (s *resourceService[T, S, U]) checkMutation(actor *Actor, entity *T) error {
+	if !s.ownership.canRead(actor, entity) {
+		return s.notFound()
+	}
+	if !s.ownership.canMutate(actor, entity) {
+		return errs.NewForbiddenError("not allowed to modify this "+s.resourceName, true)
+	}
+	return nil
+}
+
+// listingError maps the listing options GetMany rejects to bad requests.
+func listingError(err error) error {
+	switch {
+	case errors.Is(err, port.ErrInvalidOrderBy):
+		return errs.NewBadRequestError("invalid orderBy", true, []errs.FieldError{{Field: "orderBy", Error: "is not a sortable column"}}, nil)
+	case errors.Is(err, port.ErrNullableOrderBy):
+		return errs.NewBadRequestError("invalid orderBy", true, []errs.FieldError{{Field: "orderBy", Error: "is nullable and cannot be paged by cursor"}}, nil)
+	case errors.Is(err, port.ErrInvalidCursor):
+		return errs.NewBadRequestError("invalid cursor", true, []errs.FieldError{{Field: "cursor", Error: "does not match orderBy"}}, nil)
+	}
+	return sqlerr.HandleError(err)
+}
+
+func (s *resourceService[T, S, U]) notFound() error {
+	return errs.NewNotFoundError(s.resourceName+" not found", true)
+}
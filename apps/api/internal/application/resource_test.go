@@ -7,14 +7,16 @@ import (
 
 	"github.com/google/uuid"
 	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/repository"
 
 	"github.com/stretchr/testify/require"
 )
 
 type testEntity struct {
-	ID   uuid.UUID
-	Name string
+	ID      uuid.UUID
+	OwnerID uuid.UUID
+	Name    string
 }
 
 func (m testEntity) GetID() uuid.UUID {
@@ -59,6 +61,31 @@ func (m *trackingResourceRepo) Update(ctx context.Context, entity testEntity, up
 	return m.MockResourceRepository.Update(ctx, entity, updates...)
 }
 
+type capturingResourceRepo struct {
+	*repository.MockResourceRepository[testEntity]
+	opts *port.GetManyOptions
+}
+
+func (m *capturingResourceRepo) GetMany(ctx context.Context, opts port.GetManyOptions) ([]testEntity, int64, error) {
+	*m.opts = opts
+	return m.MockResourceRepository.GetMany(ctx, opts)
+}
+
+func testOwnershipPolicy(publicRead bool) OwnershipPolicy[testEntity] {
+	return OwnershipPolicy[testEntity]{
+		Column:     "owner_id",
+		OwnerID:    func(e *testEntity) uuid.UUID { return e.OwnerID },
+		PublicRead: publicRead,
+	}
+}
+
+func requireErrorStatus(t *testing.T, err error, status int) {
+	t.Helper()
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, status, httpErr.Status)
+}
+
 // Ensures GetByID maps not-found repository errors to HTTP 404 responses.
 func TestResourceServiceGetByID_NotFound(t *testing.T) {
 	ctx := context.Background()
@@ -94,3 +121,96 @@ func TestResourceServiceUpdate_NoUpdates(t *testing.T) {
 	require.NotNil(t, updated)
 	require.Equal(t, "current", updated.Name)
 }
+
+// Ensures GetMany limits non-admin actors to their own rows and leaves admins unscoped.
+func TestOwnedResourceServiceGetMany_ScopesToActor(t *testing.T) {
+	userID := uuid.New()
+	var captured port.GetManyOptions
+	repo := &capturingResourceRepo{
+		MockResourceRepository: repository.NewMockResourceRepository[testEntity](false),
+		opts:                   &captured,
+	}
+	svc := NewOwnedResourceService[testEntity, testStoreDTO, testUpdateDTO]("widget", repo, testOwnershipPolicy(false))
+
+	filters := map[string]any{"name": "shelf"}
+	_, _, err := svc.GetMany(WithActor(context.Background(), Actor{UserID: userID}), port.GetManyOptions{Filters: filters})
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"name": "shelf", "owner_id": userID}, captured.Filters)
+	require.Equal(t, map[string]any{"name": "shelf"}, filters)
+
+	_, _, err = svc.GetMany(WithActor(context.Background(), Actor{UserID: userID, IsAdmin: true}), port.GetManyOptions{Filters: filters})
+	require.NoError(t, err)
+	require.Equal(t, filters, captured.Filters)
+}
+
+// Ensures owned resources reject calls without an authenticated actor.
+func TestOwnedResourceServiceGetMany_MissingActor(t *testing.T) {
+	repo := repository.NewMockResourceRepository[testEntity](false)
+	svc := NewOwnedResourceService[testEntity, testStoreDTO, testUpdateDTO]("widget", repo, testOwnershipPolicy(false))
+
+	_, _, err := svc.GetMany(context.Background(), port.GetManyOptions{})
+	requireErrorStatus(t, err, http.StatusUnauthorized)
+}
+
+// Ensures another user's row is reported as not found and left untouched.
+func TestOwnedResourceService_HidesOtherUsersRows(t *testing.T) {
+	ctx := WithActor(context.Background(), Actor{UserID: uuid.New()})
+	entity := testEntity{ID: uuid.New(), OwnerID: uuid.New(), Name: "current"}
+	repo := repository.NewMockResourceRepository[testEntity](false)
+	require.NoError(t, repo.Store(ctx, &entity))
+	svc := NewOwnedResourceService[testEntity, testStoreDTO, testUpdateDTO]("widget", repo, testOwnershipPolicy(false))
+
+	_, err := svc.GetByID(ctx, entity.ID, nil)
+	requireErrorStatus(t, err, http.StatusNotFound)
+
+	name := "renamed"
+	_, err = svc.Update(ctx, entity.ID, testUpdateDTO{Name: &name})
+	requireErrorStatus(t, err, http.StatusNotFound)
+
+	requireErrorStatus(t, svc.Destroy(ctx, entity.ID), http.StatusNotFound)
+	requireErrorStatus(t, svc.Kill(ctx, entity.ID), http.StatusNotFound)
+
+	stored, err := repo.GetByID(ctx, entity.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, "current", stored.Name)
+}
+
+// Ensures publicly readable rows can be read by anyone but only changed by their owner.
+func TestOwnedResourceService_PublicReadForbidsMutation(t *testing.T) {
+	ctx := WithActor(context.Background(), Actor{UserID: uuid.New()})
+	entity := testEntity{ID: uuid.New(), OwnerID: uuid.New(), Name: "current"}
+	repo := repository.NewMockResourceRepository[testEntity](false)
+	require.NoError(t, repo.Store(ctx, &entity))
+	svc := NewOwnedResourceService[testEntity, testStoreDTO, testUpdateDTO]("widget", repo, testOwnershipPolicy(true))
+
+	found, err := svc.GetByID(ctx, entity.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, entity.ID, found.ID)
+
+	name := "renamed"
+	_, err = svc.Update(ctx, entity.ID, testUpdateDTO{Name: &name})
+	requireErrorStatus(t, err, http.StatusForbidden)
+	requireErrorStatus(t, svc.Destroy(ctx, entity.ID), http.StatusForbidden)
+}
+
+// Ensures owners and admins can restore and kill soft-deleted rows.
+func TestOwnedResourceService_MutatesSoftDeletedRows(t *testing.T) {
+	ownerID := uuid.New()
+	entity := testEntity{ID: uuid.New(), OwnerID: ownerID, Name: "current"}
+	repo := repository.NewMockResourceRepository[testEntity](false)
+	require.NoError(t, repo.Store(context.Background(), &entity))
+	svc := NewOwnedResourceService[testEntity, testStoreDTO, testUpdateDTO]("widget", repo, testOwnershipPolicy(false))
+
+	ownerCtx := WithActor(context.Background(), Actor{UserID: ownerID})
+	require.NoError(t, svc.Destroy(ownerCtx, entity.ID))
+
+	_, err := svc.Restore(WithActor(context.Background(), Actor{UserID: uuid.New()}), entity.ID, nil)
+	requireErrorStatus(t, err, http.StatusNotFound)
+
+	restored, err := svc.Restore(ownerCtx, entity.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, entity.ID, restored.ID)
+
+	require.NoError(t, svc.Destroy(ownerCtx, entity.ID))
+	require.NoError(t, svc.Kill(WithActor(context.Background(), Actor{UserID: uuid.New(), IsAdmin: true}), entity.ID))
+}
@@ -0,0 +1,69 @@
+package application
+
+import (
+	"context"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/sqlerr"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+)
+
+const maxSearchQueryLength = 256
+
+type SearchService interface {
+	// Search returns the ebooks of the user's library, or the community shares,
+	// matching input, best match first, and their total count.
+	Search(ctx context.Context, input *applicationdto.SearchInput) ([]domain.SearchHit, int64, error)
+}
+
+type searchService struct {
+	repo port.SearchRepository
+}
+
+func NewSearchService(repo port.SearchRepository) SearchService {
+	return &searchService{repo: repo}
+}
+
+func (s *searchService) Search(ctx context.Context, input *applicationdto.SearchInput) ([]domain.SearchHit, int64, error) {
+	if input == nil {
+		return nil, 0, errs.NewBadRequestError("search payload is required", true, nil, nil)
+	}
+
+	query, err := newSearchQuery(input.Query, input.Limit, input.Offset)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var (
+		hits  []domain.SearchHit
+		total int64
+	)
+	switch input.Scope {
+	case "", domain.SearchScopeLibrary:
+		hits, total, err = s.repo.SearchLibrary(ctx, input.UserID, query)
+	case domain.SearchScopeCommunity:
+		hits, total, err = s.repo.SearchCommunity(ctx, query)
+	default:
+		return nil, 0, errs.NewBadRequestError("invalid scope value", true, []errs.FieldError{{Field: "scope", Error: "must be library or community"}}, nil)
+	}
+	if err != nil {
+		return nil, 0, sqlerr.HandleError(err)
+	}
+	return hits, total, nil
+}
+
+// newSearchQuery validates the q parameter of a search.
+func newSearchQuery(q string, limit int, offset int) (port.SearchQuery, error) {
+	text := strings.TrimSpace(q)
+	switch {
+	case text == "":
+		return port.SearchQuery{}, errs.NewBadRequestError("q is required", true, []errs.FieldError{{Field: "q", Error: "is required"}}, nil)
+	case utf8.RuneCountInString(text) > maxSearchQueryLength:
+		return port.SearchQuery{}, errs.NewBadRequestError("q is too long", true, []errs.FieldError{{Field: "q", Error: "must be at most 256 characters"}}, nil)
+	}
+	return port.SearchQuery{Text: text, Limit: limit, Offset: offset}, nil
+}
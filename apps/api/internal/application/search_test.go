@@ -0,0 +1,63 @@
+package application
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+type testSearchRepo struct {
+	scope  domain.SearchScope
+	userID uuid.UUID
+	query  port.SearchQuery
+}
+
+func (r *testSearchRepo) SearchLibrary(ctx context.Context, userID uuid.UUID, query port.SearchQuery) ([]domain.SearchHit, int64, error) {
+	r.scope, r.userID, r.query = domain.SearchScopeLibrary, userID, query
+	return []domain.SearchHit{{Scope: domain.SearchScopeLibrary}}, 1, nil
+}
+
+func (r *testSearchRepo) SearchCommunity(ctx context.Context, query port.SearchQuery) ([]domain.SearchHit, int64, error) {
+	r.scope, r.query = domain.SearchScopeCommunity, query
+	return []domain.SearchHit{{Scope: domain.SearchScopeCommunity}}, 1, nil
+}
+
+// Ensures Search routes to the requested scope, the library by default, with the trimmed query.
+func TestSearchServiceSearch_Scopes(t *testing.T) {
+	repo := &testSearchRepo{}
+	service := NewSearchService(repo)
+	userID := uuid.New()
+
+	_, _, err := service.Search(context.Background(), &applicationdto.SearchInput{UserID: userID, Query: "  dune  ", Limit: 10, Offset: 20})
+	require.NoError(t, err)
+	require.Equal(t, domain.SearchScopeLibrary, repo.scope)
+	require.Equal(t, userID, repo.userID)
+	require.Equal(t, port.SearchQuery{Text: "dune", Limit: 10, Offset: 20}, repo.query)
+
+	hits, total, err := service.Search(context.Background(), &applicationdto.SearchInput{UserID: userID, Query: "dune", Scope: domain.SearchScopeCommunity})
+	require.NoError(t, err)
+	require.Equal(t, domain.SearchScopeCommunity, repo.scope)
+	require.Equal(t, int64(1), total)
+	require.Equal(t, domain.SearchScopeCommunity, hits[0].Scope)
+}
+
+// Ensures Search rejects empty or overlong queries and unknown scopes.
+func TestSearchServiceSearch_ValidatesInput(t *testing.T) {
+	service := NewSearchService(&testSearchRepo{})
+
+	for _, input := range []applicationdto.SearchInput{
+		{Query: "   "},
+		{Query: strings.Repeat("a", maxSearchQueryLength+1)},
+		{Query: "dune", Scope: "everything"},
+	} {
+		_, _, err := service.Search(context.Background(), &input)
+		requireErrorStatus(t, err, http.StatusBadRequest)
+	}
+}
@@ -2,7 +2,10 @@ package application
 
 import (
 	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/cache"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/googlebooks"
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/job"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/pubsub"
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/server"
 )
 
@@ -10,6 +13,8 @@ type Services struct {
 	Auth            AuthService
 	User            UserService
 	Ebook           EbookService
+	Author          AuthorService
+	Tag             TagService
 	Share           ShareService
 	ReadingProgress ReadingProgressService
 	Bookmark        BookmarkService
@@ -17,6 +22,10 @@ type Services struct {
 	UserPreferences UserPreferencesService
 	UserReaderState UserReaderStateService
 	Sync            SyncService
+	Device          DeviceService
+	Moderation      ModerationService
+	Audit           AuditService
+	Search          SearchService
 	Authorization   *AuthorizationService
 	Job             *job.JobService
 }
@@ -26,26 +35,51 @@ func NewServices(s *server.Server, repos *port.Repositories) (*Services, error)
 	if s.Job != nil {
 		enqueuer = s.Job.Client
 	}
-	authService := NewAuthService(&s.Config.Auth, repos.Auth, repos.AuthSession, repos.EmailVerification, enqueuer, s.Logger)
-	userService := NewUserService(repos.User)
-	ebookService := NewEbookService(repos.Ebook, repos.EbookMetadata)
-	shareService := NewShareService(repos.Share, repos.Borrow, repos.ShareReview, repos.ShareReport)
-	readingProgressService := NewReadingProgressService(repos.ReadingProgress)
-	bookmarkService := NewBookmarkService(repos.Bookmark)
-	annotationService := NewAnnotationService(repos.Annotation)
+	authService := NewAuthService(&s.Config.Auth, repos.Auth, repos.AuthSession, repos.Device, repos.EmailVerification, enqueuer, s.Logger)
+	var redisCache cache.Cache
+	if s.Redis != nil {
+		redisCache = cache.NewRedisCache(s.Redis, &s.Config.Cache, s.Logger)
+	}
+	accountAccessCache := NewAccountAccessCache(redisCache)
+	userService := NewUserService(repos.User, accountAccessCache)
+	googleBooksClient := googlebooks.NewClient(&s.Config.GoogleBooks, nil, redisCache, s.Logger)
+	ebookService := NewEbookService(repos.Ebook, repos.EbookMetadata, repos.EbookSuggestion, repos.Author, repos.Tag, repos.EbookContent, s.Storage, &s.Config.FileStorage, googleBooksClient, enqueuer, s.Logger)
+	authorService := NewAuthorService(repos.Author)
+	tagService := NewTagService(repos.Tag)
+	shareService := NewShareService(repos.Share, repos.Borrow, repos.ShareReview, repos.ShareReport, repos.Ebook, s.Storage, &s.Config.FileStorage, NewBorrowLeaseSigner(s.Config.Auth.BorrowLeaseSecret, s.Config.Auth.BorrowLeaseTTL), repos.ModerationTransactor, &s.Config.Moderation)
+	readingProgressService := NewReadingProgressService(repos.ReadingProgress, repos.Ebook)
+	bookmarkService := NewBookmarkService(repos.Bookmark, repos.Ebook)
+	annotationService := NewAnnotationService(repos.Annotation, repos.Ebook)
 	userPreferencesService := NewUserPreferencesService(repos.UserPreferences)
 	userReaderStateService := NewUserReaderStateService(repos.UserReaderState)
-	syncService := NewSyncService(repos.SyncEvent, repos.SyncCheckpoint)
-	authorizationService, err := NewAuthorizationService(s.DB.DB, s.Logger)
+	var syncNotifier SyncNotifier
+	if s.Redis != nil {
+		syncNotifier = pubsub.NewSyncNotifier(s.Redis, s.Logger)
+	}
+	deviceService := NewDeviceService(repos.Device, repos.AuthSession)
+	moderationService := NewModerationService(repos.User, repos.Share, repos.Borrow, repos.ShareReport, repos.ModerationTransactor, accountAccessCache, enqueuer, s.Logger)
+	auditService := NewAuditService(repos.AuditEvent)
+	searchService := NewSearchService(repos.Search)
+	syncService := NewSyncService(repos.SyncEvent, repos.Device, repos.SyncTransactor, syncNotifier)
+	authorizationService, err := NewAuthorizationService(s.DB.DB, repos.ModerationTransactor, s.Logger)
 	if err != nil {
 		return nil, err
 	}
 
+	if s.Job != nil {
+		s.Job.RegisterBorrowExpiryHandler(shareService)
+		s.Job.RegisterSyncCompactionHandler(syncService)
+		s.Job.RegisterEbookEnrichmentHandler(ebookService)
+		s.Job.RegisterEbookContentIndexHandler(ebookService)
+	}
+
 	return &Services{
 		Job:             s.Job,
 		Auth:            authService,
 		User:            userService,
 		Ebook:           ebookService,
+		Author:          authorService,
+		Tag:             tagService,
 		Share:           shareService,
 		ReadingProgress: readingProgressService,
 		Bookmark:        bookmarkService,
@@ -53,6 +87,10 @@ func NewServices(s *server.Server, repos *port.Repositories) (*Services, error)
 		UserPreferences: userPreferencesService,
 		UserReaderState: userReaderStateService,
 		Sync:            syncService,
+		Device:          deviceService,
+		Moderation:      moderationService,
+		Audit:           auditService,
+		Search:          searchService,
 		Authorization:   authorizationService,
 	}, nil
 }
@@ -5,11 +5,15 @@ import (
 	"errors"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
 	"github.com/jeheskielSunloy77/libra-link/internal/app/sqlerr"
 	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
 	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
 	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/config"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/storage"
 	"gorm.io/gorm"
 )
 
@@ -19,23 +23,46 @@ type ShareService interface {
 	ReturnBorrow(ctx context.Context, input *applicationdto.ReturnBorrowInput) (*domain.Borrow, error)
 	UpsertReview(ctx context.Context, input *applicationdto.UpsertShareReviewInput) (*domain.ShareReview, error)
 	CreateReport(ctx context.Context, input *applicationdto.CreateShareReportInput) (*domain.ShareReport, error)
+	ExpireOverdueBorrows(ctx context.Context, now time.Time) (int, error)
+	IssueBorrowAccess(ctx context.Context, borrowID uuid.UUID, userID uuid.UUID) (*BorrowAccess, error)
+	OpenBorrowedFile(ctx context.Context, borrowID uuid.UUID, userID uuid.UUID) (*EbookFile, error)
+	BorrowLeaseKey() *BorrowLeaseKey
 }
 
+const borrowExpiryBatchSize = 200
+
 type shareService struct {
 	ResourceService[domain.Share, *applicationdto.StoreShareInput, *applicationdto.UpdateShareInput]
 	shareRepo  port.ShareRepository
 	borrowRepo port.BorrowRepository
 	reviewRepo port.ShareReviewRepository
 	reportRepo port.ShareReportRepository
+	ebookRepo  port.EbookRepository
+	storage    storage.Storage
+	storageCfg *config.FileStorageConfig
+	leases     *BorrowLeaseSigner
+	transactor port.ModerationTransactor
+	thresholds config.TakedownThresholdsConfig
 }
 
-func NewShareService(shareRepo port.ShareRepository, borrowRepo port.BorrowRepository, reviewRepo port.ShareReviewRepository, reportRepo port.ShareReportRepository) ShareService {
+func NewShareService(shareRepo port.ShareRepository, borrowRepo port.BorrowRepository, reviewRepo port.ShareReviewRepository, reportRepo port.ShareReportRepository, ebookRepo port.EbookRepository, fileStorage storage.Storage, storageCfg *config.FileStorageConfig, leases *BorrowLeaseSigner, transactor port.ModerationTransactor, moderationCfg *config.ModerationConfig) ShareService {
 	return &shareService{
-		ResourceService: NewResourceService[domain.Share, *applicationdto.StoreShareInput, *applicationdto.UpdateShareInput]("share", shareRepo),
-		shareRepo:       shareRepo,
-		borrowRepo:      borrowRepo,
-		reviewRepo:      reviewRepo,
-		reportRepo:      reportRepo,
+		ResourceService: NewOwnedResourceService[domain.Share, *applicationdto.StoreShareInput, *applicationdto.UpdateShareInput]("share", shareRepo, OwnershipPolicy[domain.Share]{
+			Column:  "owner_user_id",
+			OwnerID: func(e *domain.Share) uuid.UUID { return e.OwnerUserID },
+			// Shares are browsed by the whole community.
+			PublicRead: true,
+		}),
+		shareRepo:  shareRepo,
+		borrowRepo: borrowRepo,
+		reviewRepo: reviewRepo,
+		reportRepo: reportRepo,
+		ebookRepo:  ebookRepo,
+		storage:    fileStorage,
+		storageCfg: storageCfg,
+		leases:     leases,
+		transactor: transactor,
+		thresholds: moderationCfg.TakedownThresholds,
 	}
 }
 
@@ -173,15 +200,15 @@ func (s *shareService) UpsertReview(ctx context.Context, input *applicationdto.U
 	return updated, nil
 }
 
+// CreateReport files a report and, once enough distinct users have an open
+// report of its reason against the share, disables the share pending review and
+// flags its active borrows. The share stays locked meanwhile, so concurrent
+// reports take it down once.
 func (s *shareService) CreateReport(ctx context.Context, input *applicationdto.CreateShareReportInput) (*domain.ShareReport, error) {
 	if input == nil {
 		return nil, errs.NewBadRequestError("report payload is required", true, nil, nil)
 	}
 
-	if _, err := s.shareRepo.GetByID(ctx, input.ShareID, nil); err != nil {
-		return nil, sqlerr.HandleError(err)
-	}
-
 	report := &domain.ShareReport{
 		ShareID:        input.ShareID,
 		ReporterUserID: input.ReporterUserID,
@@ -189,9 +216,162 @@ func (s *shareService) CreateReport(ctx context.Context, input *applicationdto.C
 		Details:        input.Details,
 		Status:         domain.ReportStatusOpen,
 	}
-	if err := s.reportRepo.Store(ctx, report); err != nil {
+	var (
+		takenDown *domain.Share
+		flagged   []domain.Borrow
+	)
+	err := s.transactor.WithinTransaction(ctx, func(repos *port.ModerationRepositories) error {
+		share, err := repos.Share.GetForUpdate(ctx, input.ShareID)
+		if err != nil {
+			return err
+		}
+		if err := repos.ShareReport.Store(ctx, report); err != nil {
+			return err
+		}
+		if share.Status != domain.ShareStatusActive {
+			return nil
+		}
+
+		reporters, err := repos.ShareReport.CountPendingReporters(ctx, share.ID, report.Reason)
+		if err != nil {
+			return err
+		}
+		threshold := s.thresholds.ForReason(report.Reason)
+		if threshold <= 0 || reporters < int64(threshold) {
+			return nil
+		}
+
+		if takenDown, err = repos.Share.Update(ctx, *share, map[string]any{"status": domain.ShareStatusDisabled}); err != nil {
+			return err
+		}
+		if flagged, err = repos.Borrow.FlagActiveByShare(ctx, share.ID, time.Now().UTC()); err != nil {
+			return err
+		}
+		return recordAuditEvent(ctx, repos.AuditEvent, uuid.Nil, domain.AuditActionShareTakenDown, domain.AuditTargetTypeShare, &share.ID, map[string]any{
+			"previousStatus": share.Status,
+			"status":         domain.ShareStatusDisabled,
+			"reason":         report.Reason,
+			"reporters":      reporters,
+			"flaggedBorrows": len(flagged),
+		})
+	})
+	if err != nil {
 		return nil, sqlerr.HandleError(err)
 	}
 
+	if takenDown != nil {
+		s.shareRepo.EvictCache(ctx, takenDown.ID)
+	}
+	for i := range flagged {
+		s.borrowRepo.EvictCache(ctx, flagged[i].ID)
+	}
 	return report, nil
 }
+
+func (s *shareService) ExpireOverdueBorrows(ctx context.Context, now time.Time) (int, error) {
+	total := 0
+	for {
+		expired, err := s.borrowRepo.ExpireOverdue(ctx, now, borrowExpiryBatchSize)
+		if err != nil {
+			return total, sqlerr.HandleError(err)
+		}
+
+		total += len(expired)
+		if len(expired) < borrowExpiryBatchSize {
+			return total, nil
+		}
+	}
+}
+
+func (s *shareService) IssueBorrowAccess(ctx context.Context, borrowID uuid.UUID, userID uuid.UUID) (*BorrowAccess, error) {
+	borrow, share, err := s.getReadableBorrow(ctx, borrowID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	expiresAt := s.leases.ExpiresAt(now, borrow.DueAt.UTC())
+	lease, err := s.leases.Sign(domain.BorrowLeaseClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   borrow.BorrowerUserID.String(),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+			IssuedAt:  jwt.NewNumericDate(now),
+		},
+		BorrowID: borrow.ID,
+		ShareID:  share.ID,
+		EbookID:  share.EbookID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &BorrowAccess{
+		BorrowID:  borrow.ID.String(),
+		EbookID:   share.EbookID.String(),
+		Lease:     lease,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func (s *shareService) OpenBorrowedFile(ctx context.Context, borrowID uuid.UUID, userID uuid.UUID) (*EbookFile, error) {
+	if s.storage == nil {
+		return nil, errs.NewInternalServerError()
+	}
+
+	_, share, err := s.getReadableBorrow(ctx, borrowID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	ebook, err := s.ebookRepo.GetByID(ctx, share.EbookID, nil)
+	if err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+
+	body, err := s.storage.Open(ctx, ebookFileKey(s.storageCfg, ebook))
+	if err != nil {
+		if errors.Is(err, storage.ErrObjectNotFound) {
+			return nil, errs.NewNotFoundError("ebook file has not been uploaded", true)
+		}
+		return nil, err
+	}
+
+	return &EbookFile{
+		Ebook:       ebook,
+		Body:        body,
+		ContentType: ebookContentType(ebook.Format),
+		Size:        ebook.FileSizeBytes,
+	}, nil
+}
+
+func (s *shareService) BorrowLeaseKey() *BorrowLeaseKey {
+	return s.leases.PublicKey()
+}
+
+// getReadableBorrow loads a borrow for its borrower and checks that it still grants
+// access. The borrow and share statuses are read on every call, so returning or
+// expiring a borrow, or taking its share down, revokes access immediately.
+func (s *shareService) getReadableBorrow(ctx context.Context, borrowID uuid.UUID, userID uuid.UUID) (*domain.Borrow, *domain.Share, error) {
+	borrow, err := s.borrowRepo.GetByID(ctx, borrowID, nil)
+	if err != nil {
+		return nil, nil, sqlerr.HandleError(err)
+	}
+
+	if borrow.BorrowerUserID != userID {
+		return nil, nil, errs.NewForbiddenError("not allowed to access this borrow", true)
+	}
+
+	if borrow.Status != domain.BorrowStatusActive || !time.Now().Before(borrow.DueAt) {
+		return nil, nil, errs.NewForbiddenError("borrow is no longer active", true)
+	}
+
+	share, err := s.shareRepo.GetByID(ctx, borrow.ShareID, nil)
+	if err != nil {
+		return nil, nil, sqlerr.HandleError(err)
+	}
+
+	if share.Status != domain.ShareStatusActive {
+		return nil, nil, errs.NewForbiddenError("share is no longer available", true)
+	}
+	return borrow, share, nil
+}
@@ -2,19 +2,55 @@ package application
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"errors"
+	"io"
 	"net/http"
 	"testing"
+	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
 	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
 	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/config"
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/repository"
 	"github.com/stretchr/testify/require"
 	"gorm.io/gorm"
 )
 
+type testShareRepo struct {
+	*repository.MockResourceRepository[domain.Share]
+}
+
+func (r *testShareRepo) GetForUpdate(ctx context.Context, id uuid.UUID) (*domain.Share, error) {
+	return r.GetByID(ctx, id, nil)
+}
+
+func (r *testShareRepo) DisableActiveByOwner(ctx context.Context, ownerID uuid.UUID) ([]domain.Share, error) {
+	items, _, err := r.GetMany(ctx, repository.GetManyOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	disabled := make([]domain.Share, 0)
+	for i := range items {
+		if items[i].OwnerUserID != ownerID || items[i].Status != domain.ShareStatusActive {
+			continue
+		}
+
+		updated, err := r.Update(ctx, items[i], map[string]any{"status": domain.ShareStatusDisabled})
+		if err != nil {
+			return nil, err
+		}
+		disabled = append(disabled, *updated)
+	}
+	return disabled, nil
+}
+
 type testBorrowRepo struct {
 	*repository.MockResourceRepository[domain.Borrow]
 }
@@ -49,6 +85,158 @@ func (r *testBorrowRepo) GetActiveByShareAndBorrower(ctx context.Context, shareI
 	return nil, gorm.ErrRecordNotFound
 }
 
+func (r *testBorrowRepo) ExpireOverdue(ctx context.Context, now time.Time, limit int) ([]domain.Borrow, error) {
+	items, _, err := r.GetMany(ctx, repository.GetManyOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	expired := make([]domain.Borrow, 0)
+	for i := range items {
+		if len(expired) >= limit {
+			break
+		}
+		if items[i].Status != domain.BorrowStatusActive || items[i].DueAt.After(now) {
+			continue
+		}
+
+		updated, err := r.Update(ctx, items[i], map[string]any{
+			"status":     domain.BorrowStatusExpired,
+			"expired_at": &now,
+		})
+		if err != nil {
+			return nil, err
+		}
+		expired = append(expired, *updated)
+	}
+	return expired, nil
+}
+
+func (r *testBorrowRepo) RevokeActiveByShare(ctx context.Context, shareID uuid.UUID, now time.Time) ([]domain.Borrow, error) {
+	items, _, err := r.GetMany(ctx, repository.GetManyOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	revoked := make([]domain.Borrow, 0)
+	for i := range items {
+		if items[i].ShareID != shareID || items[i].Status != domain.BorrowStatusActive {
+			continue
+		}
+
+		updated, err := r.Update(ctx, items[i], map[string]any{"status": domain.BorrowStatusRevoked})
+		if err != nil {
+			return nil, err
+		}
+		revoked = append(revoked, *updated)
+	}
+	return revoked, nil
+}
+
+func (r *testBorrowRepo) FlagActiveByShare(ctx context.Context, shareID uuid.UUID, now time.Time) ([]domain.Borrow, error) {
+	items, _, err := r.GetMany(ctx, repository.GetManyOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	flagged := make([]domain.Borrow, 0)
+	for i := range items {
+		if items[i].ShareID != shareID || items[i].Status != domain.BorrowStatusActive || items[i].FlaggedAt != nil {
+			continue
+		}
+
+		updated, err := r.Update(ctx, items[i], map[string]any{"flagged_at": &now})
+		if err != nil {
+			return nil, err
+		}
+		flagged = append(flagged, *updated)
+	}
+	return flagged, nil
+}
+
+func (r *testBorrowRepo) UnflagByShare(ctx context.Context, shareID uuid.UUID) ([]domain.Borrow, error) {
+	items, _, err := r.GetMany(ctx, repository.GetManyOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	unflagged := make([]domain.Borrow, 0)
+	for i := range items {
+		if items[i].ShareID != shareID || items[i].FlaggedAt == nil {
+			continue
+		}
+
+		updated, err := r.Update(ctx, items[i], map[string]any{"flagged_at": (*time.Time)(nil)})
+		if err != nil {
+			return nil, err
+		}
+		unflagged = append(unflagged, *updated)
+	}
+	return unflagged, nil
+}
+
+type testShareReportRepo struct {
+	*repository.MockResourceRepository[domain.ShareReport]
+}
+
+// Store assigns an id like the real repository does, so reports created by the
+// service do not overwrite each other.
+func (r *testShareReportRepo) Store(ctx context.Context, report *domain.ShareReport) error {
+	if report.ID == uuid.Nil {
+		report.ID = uuid.New()
+	}
+	return r.MockResourceRepository.Store(ctx, report)
+}
+
+func (r *testShareReportRepo) GetForUpdate(ctx context.Context, id uuid.UUID) (*domain.ShareReport, error) {
+	return r.GetByID(ctx, id, nil)
+}
+
+func (r *testShareReportRepo) CountPendingReporters(ctx context.Context, shareID uuid.UUID, reason domain.ReportReason) (int64, error) {
+	items, _, err := r.GetMany(ctx, repository.GetManyOptions{})
+	if err != nil {
+		return 0, err
+	}
+
+	reporters := map[uuid.UUID]struct{}{}
+	for i := range items {
+		if items[i].ShareID == shareID && items[i].Reason == reason && isPendingReport(items[i].Status) {
+			reporters[items[i].ReporterUserID] = struct{}{}
+		}
+	}
+	return int64(len(reporters)), nil
+}
+
+func (r *testShareReportRepo) ClosePendingByShare(ctx context.Context, shareID uuid.UUID, status domain.ReportStatus, moderatorID uuid.UUID, note string, now time.Time) ([]domain.ShareReport, error) {
+	items, _, err := r.GetMany(ctx, repository.GetManyOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	closed := make([]domain.ShareReport, 0)
+	for i := range items {
+		if items[i].ShareID != shareID || !isPendingReport(items[i].Status) {
+			continue
+		}
+
+		updated, err := r.Update(ctx, items[i], map[string]any{
+			"status":              status,
+			"reviewed_by_user_id": &moderatorID,
+			"reviewed_at":         &now,
+			"resolution_note":     &note,
+		})
+		if err != nil {
+			return nil, err
+		}
+		closed = append(closed, *updated)
+	}
+	return closed, nil
+}
+
+func isPendingReport(status domain.ReportStatus) bool {
+	return status == domain.ReportStatusOpen || status == domain.ReportStatusInReview
+}
+
 type testShareReviewRepo struct {
 	*repository.MockResourceRepository[domain.ShareReview]
 }
@@ -68,13 +256,48 @@ func (r *testShareReviewRepo) GetByShareAndUser(ctx context.Context, shareID uui
 	return nil, gorm.ErrRecordNotFound
 }
 
+type shareServiceFixture struct {
+	service    ShareService
+	shareRepo  *testShareRepo
+	borrowRepo *testBorrowRepo
+	reportRepo *testShareReportRepo
+	auditRepo  *testAuditEventRepo
+	ebookRepo  *repository.MockResourceRepository[domain.Ebook]
+	storage    *testStorage
+	leases     *BorrowLeaseSigner
+}
+
 func newShareServiceForTest() ShareService {
-	shareRepo := repository.NewMockResourceRepository[domain.Share](false)
+	return newShareServiceFixture().service
+}
+
+func newShareServiceFixture() *shareServiceFixture {
+	shareRepo := &testShareRepo{MockResourceRepository: repository.NewMockResourceRepository[domain.Share](false)}
 	borrowRepo := &testBorrowRepo{MockResourceRepository: repository.NewMockResourceRepository[domain.Borrow](false)}
 	reviewRepo := &testShareReviewRepo{MockResourceRepository: repository.NewMockResourceRepository[domain.ShareReview](false)}
-	reportRepo := repository.NewMockResourceRepository[domain.ShareReport](false)
+	reportRepo := &testShareReportRepo{MockResourceRepository: repository.NewMockResourceRepository[domain.ShareReport](false)}
+	ebookRepo := repository.NewMockResourceRepository[domain.Ebook](false)
+	fileStorage := newTestStorage()
+	leases := NewBorrowLeaseSigner("test-secret", time.Hour)
+	auditRepo := &testAuditEventRepo{}
+	transactor := &testModerationTransactor{repos: &port.ModerationRepositories{
+		Share:       shareRepo,
+		Borrow:      borrowRepo,
+		ShareReport: reportRepo,
+		AuditEvent:  auditRepo,
+	}}
+	moderationCfg := &config.ModerationConfig{TakedownThresholds: config.TakedownThresholdsConfig{Copyright: 2, Abuse: 5, Spam: 10, Other: 10}}
 
-	return NewShareService(shareRepo, borrowRepo, reviewRepo, reportRepo)
+	return &shareServiceFixture{
+		service:    NewShareService(shareRepo, borrowRepo, reviewRepo, reportRepo, ebookRepo, fileStorage, &config.FileStorageConfig{PathPrefix: "uploads"}, leases, transactor, moderationCfg),
+		shareRepo:  shareRepo,
+		borrowRepo: borrowRepo,
+		reportRepo: reportRepo,
+		auditRepo:  auditRepo,
+		ebookRepo:  ebookRepo,
+		storage:    fileStorage,
+		leases:     leases,
+	}
 }
 
 func TestShareServiceBorrow_RejectsOwnerBorrowingOwnShare(t *testing.T) {
@@ -164,3 +387,234 @@ func TestShareServiceBorrow_RejectsWhenShareAtCapacity(t *testing.T) {
 	require.True(t, errors.As(err, &httpErr))
 	require.Equal(t, http.StatusBadRequest, httpErr.Status)
 }
+
+func TestShareServiceExpireOverdueBorrows_ExpiresOnlyOverdueActive(t *testing.T) {
+	ctx := context.Background()
+	fixture := newShareServiceFixture()
+	service, borrowRepo := fixture.service, fixture.borrowRepo
+
+	share, err := service.Store(ctx, &applicationdto.StoreShareInput{
+		EbookID:              uuid.New(),
+		OwnerUserID:          uuid.New(),
+		BorrowDurationHours:  24,
+		MaxConcurrentBorrows: 2,
+	})
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	overdue := &domain.Borrow{
+		ID:             uuid.New(),
+		ShareID:        share.ID,
+		BorrowerUserID: uuid.New(),
+		StartedAt:      now.Add(-25 * time.Hour),
+		DueAt:          now.Add(-time.Hour),
+		Status:         domain.BorrowStatusActive,
+	}
+	current := &domain.Borrow{
+		ID:             uuid.New(),
+		ShareID:        share.ID,
+		BorrowerUserID: uuid.New(),
+		StartedAt:      now,
+		DueAt:          now.Add(24 * time.Hour),
+		Status:         domain.BorrowStatusActive,
+	}
+	require.NoError(t, borrowRepo.Store(ctx, overdue))
+	require.NoError(t, borrowRepo.Store(ctx, current))
+
+	expiredCount, err := service.ExpireOverdueBorrows(ctx, now)
+	require.NoError(t, err)
+	require.Equal(t, 1, expiredCount)
+
+	stored, err := borrowRepo.GetByID(ctx, overdue.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, domain.BorrowStatusExpired, stored.Status)
+	require.NotNil(t, stored.ExpiredAt)
+
+	stored, err = borrowRepo.GetByID(ctx, current.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, domain.BorrowStatusActive, stored.Status)
+
+	activeCount, err := borrowRepo.CountActiveByShare(ctx, share.ID)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), activeCount)
+
+	// A second sweep finds nothing left to expire.
+	expiredCount, err = service.ExpireOverdueBorrows(ctx, now)
+	require.NoError(t, err)
+	require.Zero(t, expiredCount)
+}
+
+func newBorrowedEbookForTest(t *testing.T, fixture *shareServiceFixture) (*domain.Share, *domain.Borrow, []byte) {
+	t.Helper()
+	ctx := context.Background()
+
+	content := []byte("borrowed-epub")
+	ebook := &domain.Ebook{
+		ID:            uuid.New(),
+		OwnerUserID:   uuid.New(),
+		Title:         "Dune",
+		Format:        domain.EbookFormatEPUB,
+		FileSizeBytes: int64(len(content)),
+	}
+	require.NoError(t, fixture.ebookRepo.Store(ctx, ebook))
+	fixture.storage.objects[ebookFileKey(&config.FileStorageConfig{PathPrefix: "uploads"}, ebook)] = content
+
+	share, err := fixture.service.Store(ctx, &applicationdto.StoreShareInput{
+		EbookID:              ebook.ID,
+		OwnerUserID:          ebook.OwnerUserID,
+		BorrowDurationHours:  24,
+		MaxConcurrentBorrows: 1,
+	})
+	require.NoError(t, err)
+
+	now := time.Now().UTC()
+	borrow := &domain.Borrow{
+		ID:             uuid.New(),
+		ShareID:        share.ID,
+		BorrowerUserID: uuid.New(),
+		StartedAt:      now,
+		DueAt:          now.Add(24 * time.Hour),
+		Status:         domain.BorrowStatusActive,
+	}
+	require.NoError(t, fixture.borrowRepo.Store(ctx, borrow))
+	return share, borrow, content
+}
+
+func TestShareServiceIssueBorrowAccess_SignsVerifiableLease(t *testing.T) {
+	ctx := context.Background()
+	fixture := newShareServiceFixture()
+	share, borrow, content := newBorrowedEbookForTest(t, fixture)
+
+	access, err := fixture.service.IssueBorrowAccess(ctx, borrow.ID, borrow.BorrowerUserID)
+	require.NoError(t, err)
+	require.Equal(t, share.EbookID.String(), access.EbookID)
+	require.WithinDuration(t, time.Now().Add(time.Hour), access.ExpiresAt, time.Minute)
+	require.True(t, access.ExpiresAt.Before(borrow.DueAt))
+
+	key := fixture.service.BorrowLeaseKey()
+	publicKey, err := base64.StdEncoding.DecodeString(key.PublicKey)
+	require.NoError(t, err)
+
+	claims := &domain.BorrowLeaseClaims{}
+	_, err = jwt.ParseWithClaims(access.Lease, claims, func(token *jwt.Token) (any, error) {
+		return ed25519.PublicKey(publicKey), nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodEdDSA.Alg()}))
+	require.NoError(t, err)
+	require.Equal(t, borrow.ID, claims.BorrowID)
+	require.Equal(t, borrow.BorrowerUserID.String(), claims.Subject)
+
+	file, err := fixture.service.OpenBorrowedFile(ctx, borrow.ID, borrow.BorrowerUserID)
+	require.NoError(t, err)
+	defer file.Body.Close()
+
+	downloaded, err := io.ReadAll(file.Body)
+	require.NoError(t, err)
+	require.Equal(t, content, downloaded)
+}
+
+func TestShareServiceOpenBorrowedFile_RevokedAfterReturn(t *testing.T) {
+	ctx := context.Background()
+	fixture := newShareServiceFixture()
+	_, borrow, _ := newBorrowedEbookForTest(t, fixture)
+
+	_, err := fixture.service.OpenBorrowedFile(ctx, borrow.ID, uuid.New())
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusForbidden, httpErr.Status)
+
+	_, err = fixture.service.ReturnBorrow(ctx, &applicationdto.ReturnBorrowInput{
+		BorrowID:       borrow.ID,
+		BorrowerUserID: borrow.BorrowerUserID,
+	})
+	require.NoError(t, err)
+
+	_, err = fixture.service.OpenBorrowedFile(ctx, borrow.ID, borrow.BorrowerUserID)
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusForbidden, httpErr.Status)
+
+	_, err = fixture.service.IssueBorrowAccess(ctx, borrow.ID, borrow.BorrowerUserID)
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, http.StatusForbidden, httpErr.Status)
+}
+
+func TestShareServiceIssueBorrowAccess_RevokedWhenShareTakenDown(t *testing.T) {
+	ctx := context.Background()
+	fixture := newShareServiceFixture()
+	share, borrow, _ := newBorrowedEbookForTest(t, fixture)
+
+	_, err := fixture.shareRepo.Update(ctx, *share, map[string]any{"status": domain.ShareStatusRemoved})
+	require.NoError(t, err)
+
+	_, err = fixture.service.IssueBorrowAccess(ctx, borrow.ID, borrow.BorrowerUserID)
+	requireErrorStatus(t, err, http.StatusForbidden)
+
+	_, err = fixture.service.OpenBorrowedFile(ctx, borrow.ID, borrow.BorrowerUserID)
+	requireErrorStatus(t, err, http.StatusForbidden)
+}
+
+func TestShareServiceCreateReport_TakesShareDownAtThreshold(t *testing.T) {
+	ctx := context.Background()
+	fixture := newShareServiceFixture()
+
+	share := &domain.Share{
+		ID:                   uuid.New(),
+		EbookID:              uuid.New(),
+		OwnerUserID:          uuid.New(),
+		Status:               domain.ShareStatusActive,
+		BorrowDurationHours:  24,
+		MaxConcurrentBorrows: 2,
+	}
+	require.NoError(t, fixture.shareRepo.Store(ctx, share))
+
+	borrow := &domain.Borrow{
+		ID:             uuid.New(),
+		ShareID:        share.ID,
+		BorrowerUserID: uuid.New(),
+		Status:         domain.BorrowStatusActive,
+		DueAt:          time.Now().Add(time.Hour),
+	}
+	require.NoError(t, fixture.borrowRepo.Store(ctx, borrow))
+
+	report := func(reporterID uuid.UUID, reason domain.ReportReason) {
+		t.Helper()
+		_, err := fixture.service.CreateReport(ctx, &applicationdto.CreateShareReportInput{
+			ShareID:        share.ID,
+			ReporterUserID: reporterID,
+			Reason:         reason,
+		})
+		require.NoError(t, err)
+	}
+	requireStatus := func(expected domain.ShareStatus) {
+		t.Helper()
+		stored, err := fixture.shareRepo.GetByID(ctx, share.ID, nil)
+		require.NoError(t, err)
+		require.Equal(t, expected, stored.Status)
+	}
+
+	// Repeated reports by one user and reports for other reasons do not add up.
+	firstReporterID, secondReporterID := uuid.New(), uuid.New()
+	report(firstReporterID, domain.ReportReasonCopyright)
+	report(firstReporterID, domain.ReportReasonCopyright)
+	report(secondReporterID, domain.ReportReasonSpam)
+	requireStatus(domain.ShareStatusActive)
+	require.Empty(t, fixture.auditRepo.events)
+
+	report(secondReporterID, domain.ReportReasonCopyright)
+	requireStatus(domain.ShareStatusDisabled)
+
+	stored, err := fixture.borrowRepo.GetByID(ctx, borrow.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, domain.BorrowStatusActive, stored.Status)
+	require.NotNil(t, stored.FlaggedAt)
+
+	require.Len(t, fixture.auditRepo.events, 1)
+	event := fixture.auditRepo.events[0]
+	require.Equal(t, domain.AuditActionShareTakenDown, event.Action)
+	require.Equal(t, share.ID, *event.TargetID)
+	require.Nil(t, event.ActorUserID)
+	require.Equal(t, domain.ReportReasonCopyright, event.Metadata["reason"])
+
+	// Reports against a share that is already down are stored without another takedown.
+	report(uuid.New(), domain.ReportReasonCopyright)
+	require.Len(t, fixture.auditRepo.events, 1)
+}
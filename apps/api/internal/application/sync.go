@@ -2,7 +2,11 @@ package application
 
 import (
 	"context"
+	"encoding/base64"
 	"errors"
+	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,59 +18,268 @@ import (
 	"gorm.io/gorm"
 )
 
+// MaxSyncBatchEvents caps how many events a single batch push may carry.
+const MaxSyncBatchEvents = 100
+
+type SyncEventStatus string
+
+const (
+	SyncEventStatusApplied    SyncEventStatus = "applied"
+	SyncEventStatusDuplicate  SyncEventStatus = "duplicate"
+	SyncEventStatusSuperseded SyncEventStatus = "superseded"
+	SyncEventStatusConflict   SyncEventStatus = "conflict"
+	SyncEventStatusRejected   SyncEventStatus = "rejected"
+)
+
+// SyncEventResult reports the outcome of one event in a batch push. Event is set
+// for applied, duplicate and superseded events, Conflict for conflicts, and
+// Message for conflicts and rejections. A superseded event lost last-writer-wins
+// to newer server state and was neither applied nor recorded.
+type SyncEventResult struct {
+	IdempotencyKey string            `json:"idempotencyKey"`
+	Status         SyncEventStatus   `json:"status"`
+	Event          *domain.SyncEvent `json:"event,omitempty"`
+	Conflict       *SyncConflict     `json:"conflict,omitempty"`
+	Message        string            `json:"message,omitempty"`
+}
+
 type SyncService interface {
 	StoreEvent(ctx context.Context, input *applicationdto.StoreSyncEventInput) (*domain.SyncEvent, error)
-	ListEvents(ctx context.Context, userID uuid.UUID, since *time.Time, limit int) ([]domain.SyncEvent, error)
+	StoreEvents(ctx context.Context, inputs []*applicationdto.StoreSyncEventInput) ([]SyncEventResult, error)
+	ListEvents(ctx context.Context, userID uuid.UUID, since *time.Time, cursor string, limit int) (*SyncEventPage, error)
+	SubscribeEvents(ctx context.Context, userID uuid.UUID) (<-chan domain.SyncEvent, error)
+	// CheckSubscription fails once the device named by ctx, which holds a
+	// subscription, has been revoked.
+	CheckSubscription(ctx context.Context, userID uuid.UUID) error
+	Snapshot(ctx context.Context, userID uuid.UUID) (*SyncSnapshot, error)
+	CompactEvents(ctx context.Context, expireBefore time.Time) (collapsed int, expired int, err error)
+}
+
+// SyncNotifier fans newly stored sync events out to the user's live subscribers
+// on every API instance. Delivery is best-effort: a subscriber that misses an
+// event catches up by listing events from its checkpoint.
+type SyncNotifier interface {
+	Publish(ctx context.Context, event *domain.SyncEvent)
+	// Subscribe returns a channel of the user's events that is closed once ctx
+	// is done or the subscription is lost.
+	Subscribe(ctx context.Context, userID uuid.UUID) (<-chan domain.SyncEvent, error)
+}
+
+// SyncEventPage is one page of a user's event stream. NextCursor resumes after
+// the last event of the page and is safe to store even when HasMore is false.
+type SyncEventPage struct {
+	Events     []domain.SyncEvent
+	NextCursor string
+	HasMore    bool
 }
 
 type syncService struct {
-	eventRepo      port.SyncEventRepository
-	checkpointRepo port.SyncCheckpointRepository
+	eventRepo  port.SyncEventRepository
+	deviceRepo port.DeviceRepository
+	transactor port.SyncTransactor
+	notifier   SyncNotifier
 }
 
-func NewSyncService(eventRepo port.SyncEventRepository, checkpointRepo port.SyncCheckpointRepository) SyncService {
-	return &syncService{eventRepo: eventRepo, checkpointRepo: checkpointRepo}
+func NewSyncService(eventRepo port.SyncEventRepository, deviceRepo port.DeviceRepository, transactor port.SyncTransactor, notifier SyncNotifier) SyncService {
+	return &syncService{eventRepo: eventRepo, deviceRepo: deviceRepo, transactor: transactor, notifier: notifier}
 }
 
 func (s *syncService) StoreEvent(ctx context.Context, input *applicationdto.StoreSyncEventInput) (*domain.SyncEvent, error) {
-	if input == nil {
-		return nil, errs.NewBadRequestError("sync event payload is required", true, nil, nil)
+	event, _, err := s.applyEvent(ctx, input)
+	return event, err
+}
+
+// StoreEvents applies events in order, each in its own transaction. Conflicts and
+// client errors are reported per event and do not stop the batch; a server error
+// aborts it, and the client can safely resend since applied events are idempotent.
+func (s *syncService) StoreEvents(ctx context.Context, inputs []*applicationdto.StoreSyncEventInput) ([]SyncEventResult, error) {
+	if len(inputs) == 0 {
+		return nil, errs.NewBadRequestError("at least one sync event is required", true, nil, nil)
+	}
+	if len(inputs) > MaxSyncBatchEvents {
+		return nil, errs.NewBadRequestError(fmt.Sprintf("a sync batch may contain at most %d events", MaxSyncBatchEvents), true, nil, nil)
 	}
 
-	existing, err := s.eventRepo.GetByUserAndIdempotencyKey(ctx, input.UserID, input.IdempotencyKey)
-	if err == nil {
-		return existing, nil
+	results := make([]SyncEventResult, 0, len(inputs))
+	for _, input := range inputs {
+		if input == nil {
+			return nil, errs.NewBadRequestError("sync event payload is required", true, nil, nil)
+		}
+
+		result := SyncEventResult{IdempotencyKey: input.IdempotencyKey}
+		event, status, err := s.applyEvent(ctx, input)
+		if err != nil {
+			var httpErr *errs.ErrorResponse
+			if !errors.As(err, &httpErr) || httpErr.Status >= http.StatusInternalServerError {
+				return nil, err
+			}
+
+			result.Status = SyncEventStatusRejected
+			result.Message = httpErr.Message
+			if conflict, ok := httpErr.Data.(*SyncConflict); ok {
+				result.Status = SyncEventStatusConflict
+				result.Conflict = conflict
+			}
+			results = append(results, result)
+			continue
+		}
+
+		result.Status = status
+		result.Event = event
+		results = append(results, result)
 	}
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		return nil, sqlerr.HandleError(err)
+
+	return results, nil
+}
+
+// applyEvent records a sync event and applies it to its target entity in a single
+// transaction. A replayed idempotency key returns the stored event as a duplicate
+// and leaves every entity untouched. A superseded event is returned without
+// being recorded, so other devices never pull a write the server discarded.
+func (s *syncService) applyEvent(ctx context.Context, input *applicationdto.StoreSyncEventInput) (event *domain.SyncEvent, status SyncEventStatus, err error) {
+	if input == nil {
+		return nil, "", errs.NewBadRequestError("sync event payload is required", true, nil, nil)
 	}
 
-	event := input.ToModel()
-	if event.ID == uuid.Nil {
-		event.ID = uuid.New()
+	err = s.transactor.WithinTransaction(ctx, input.UserID, input.EntityID, func(repos *port.SyncRepositories) error {
+		existing, err := repos.SyncEvent.GetByUserAndIdempotencyKey(ctx, input.UserID, input.IdempotencyKey)
+		if err == nil {
+			event, status = existing, SyncEventStatusDuplicate
+			return nil
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		event = input.ToModel()
+		if event.ID == uuid.Nil {
+			event.ID = uuid.New()
+		}
+		event.ServerTimestamp = time.Now().UTC()
+
+		if err := applySyncEvent(ctx, repos, event); err != nil {
+			if errors.Is(err, errSyncEventSuperseded) {
+				status = SyncEventStatusSuperseded
+				return nil
+			}
+			return err
+		}
+		status = SyncEventStatusApplied
+		if err := repos.SyncEvent.Store(ctx, event); err != nil {
+			return err
+		}
+
+		return repos.SyncCheckpoint.Upsert(ctx, &domain.SyncCheckpoint{
+			UserID:              input.UserID,
+			LastServerTimestamp: event.ServerTimestamp,
+			LastEventID:         &event.ID,
+		})
+	})
+	if err != nil {
+		return nil, "", sqlerr.HandleError(err)
 	}
-	event.ServerTimestamp = time.Now().UTC()
 
-	if err := s.eventRepo.Store(ctx, event); err != nil {
-		return nil, sqlerr.HandleError(err)
+	if status == SyncEventStatusApplied && s.notifier != nil {
+		s.notifier.Publish(ctx, event)
 	}
+	return event, status, nil
+}
 
-	checkpoint := &domain.SyncCheckpoint{
-		UserID:              input.UserID,
-		LastServerTimestamp: event.ServerTimestamp,
-		LastEventID:         &event.ID,
+// ListEvents returns the user's events after cursor, or after since when no
+// cursor is given, oldest first. Fetching one extra row tells whether more remain.
+// A device only lists from a position it has stored everything before, so that
+// position becomes the requesting device's checkpoint.
+func (s *syncService) ListEvents(ctx context.Context, userID uuid.UUID, since *time.Time, cursor string, limit int) (*SyncEventPage, error) {
+	after, err := decodeSyncEventCursor(cursor)
+	if err != nil {
+		return nil, err
+	}
+	if after == nil && since != nil {
+		// Every id sorts before the max UUID, so this lists events strictly after since.
+		after = &port.SyncEventCursor{ServerTimestamp: *since, ID: uuid.Max}
 	}
-	if err := s.checkpointRepo.Upsert(ctx, checkpoint); err != nil {
+
+	events, err := s.eventRepo.ListAfter(ctx, userID, after, limit+1)
+	if err != nil {
 		return nil, sqlerr.HandleError(err)
 	}
 
-	return event, nil
+	if device, ok := ClientDeviceFromContext(ctx); ok && s.deviceRepo != nil {
+		if err := s.deviceRepo.RecordSync(ctx, userID, device.ClientID, after, time.Now().UTC()); err != nil {
+			return nil, sqlerr.HandleError(err)
+		}
+	}
+
+	page := &SyncEventPage{Events: events, NextCursor: cursor}
+	if len(events) > limit {
+		page.Events = events[:limit]
+		page.HasMore = true
+	}
+	if n := len(page.Events); n > 0 {
+		page.NextCursor = EncodeSyncEventCursor(page.Events[n-1])
+	}
+	return page, nil
 }
 
-func (s *syncService) ListEvents(ctx context.Context, userID uuid.UUID, since *time.Time, limit int) ([]domain.SyncEvent, error) {
-	events, err := s.eventRepo.ListSince(ctx, userID, since, limit)
+// SubscribeEvents streams the user's events as they are stored, until ctx is done.
+func (s *syncService) SubscribeEvents(ctx context.Context, userID uuid.UUID) (<-chan domain.SyncEvent, error) {
+	if s.notifier == nil {
+		return nil, errs.NewServiceUnavailableError("live sync is not available", true)
+	}
+
+	events, err := s.notifier.Subscribe(ctx, userID)
 	if err != nil {
-		return nil, sqlerr.HandleError(err)
+		return nil, errs.NewServiceUnavailableError("live sync is not available", true)
 	}
 	return events, nil
 }
+
+// EncodeSyncEventCursor returns the cursor that lists the events after event.
+func (s *syncService) CheckSubscription(ctx context.Context, userID uuid.UUID) error {
+	device, ok := ClientDeviceFromContext(ctx)
+	if !ok || s.deviceRepo == nil {
+		return nil
+	}
+
+	stored, err := s.deviceRepo.GetByUserAndClientID(ctx, userID, device.ClientID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return sqlerr.HandleError(err)
+	}
+	if stored.RevokedAt != nil {
+		return errs.NewForbiddenError("this device has been revoked", true)
+	}
+	return nil
+}
+
+func EncodeSyncEventCursor(event domain.SyncEvent) string {
+	raw := event.ServerTimestamp.UTC().Format(time.RFC3339Nano) + "/" + event.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeSyncEventCursor(cursor string) (*port.SyncEventCursor, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	invalid := errs.NewBadRequestError("invalid sync cursor", true, nil, nil)
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalid
+	}
+	rawTimestamp, rawID, ok := strings.Cut(string(raw), "/")
+	if !ok {
+		return nil, invalid
+	}
+	timestamp, err := time.Parse(time.RFC3339Nano, rawTimestamp)
+	if err != nil {
+		return nil, invalid
+	}
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return nil, invalid
+	}
+	return &port.SyncEventCursor{ServerTimestamp: timestamp, ID: id}, nil
+}
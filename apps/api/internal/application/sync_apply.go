@@ -0,0 +1,346 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"gorm.io/gorm"
+)
+
+// errSyncEventSuperseded reports a last-writer-wins event that is older than the
+// state it targets. The newer state is kept and the event is dropped.
+var errSyncEventSuperseded = errors.New("sync event is superseded by a newer write")
+
+// SyncConflict is returned as the data of a 409 response when a sync event
+// cannot be applied. Current holds the server's copy of the entity, or is empty
+// when the entity no longer exists.
+type SyncConflict struct {
+	EntityType domain.SyncEntityType `json:"entityType"`
+	EntityID   uuid.UUID             `json:"entityId"`
+	Current    any                   `json:"current,omitempty"`
+}
+
+type syncProgressPayload struct {
+	EbookID         *uuid.UUID          `json:"ebookId"`
+	Location        *string             `json:"location"`
+	ProgressPercent *float64            `json:"progressPercent"`
+	ReadingMode     *domain.ReadingMode `json:"readingMode"`
+	LastReadAt      *time.Time          `json:"lastReadAt"`
+}
+
+type syncBookmarkPayload struct {
+	EbookID  *uuid.UUID `json:"ebookId"`
+	Location *string    `json:"location"`
+	Label    *string    `json:"label"`
+}
+
+type syncAnnotationPayload struct {
+	EbookID       *uuid.UUID `json:"ebookId"`
+	LocationStart *string    `json:"locationStart"`
+	LocationEnd   *string    `json:"locationEnd"`
+	HighlightText *string    `json:"highlightText"`
+	Note          *string    `json:"note"`
+	Color         *string    `json:"color"`
+}
+
+type syncPreferencePayload struct {
+	ReadingMode       *domain.ReadingMode       `json:"readingMode"`
+	ZenRestoreOnOpen  *bool                     `json:"zenRestoreOnOpen"`
+	ThemeMode         *domain.ThemeMode         `json:"themeMode"`
+	ThemeOverrides    map[string]string         `json:"themeOverrides"`
+	TypographyProfile *domain.TypographyProfile `json:"typographyProfile"`
+}
+
+type syncReaderStatePayload struct {
+	CurrentEbookID  *uuid.UUID          `json:"currentEbookId"`
+	CurrentLocation *string             `json:"currentLocation"`
+	ReadingMode     *domain.ReadingMode `json:"readingMode"`
+	LastOpenedAt    *time.Time          `json:"lastOpenedAt"`
+}
+
+// applySyncEvent writes event to its target entity. Progress, bookmarks and
+// annotations require the event's BaseVersion to match the stored RowVersion;
+// preferences and reader state are last-writer-wins on the client timestamp, and
+// an event older than the stored state fails with errSyncEventSuperseded.
+func applySyncEvent(ctx context.Context, repos *port.SyncRepositories, event *domain.SyncEvent) error {
+	switch event.EntityType {
+	case domain.SyncEntityTypeProgress:
+		return applyProgressEvent(ctx, repos.ReadingProgress, repos.Ebook, event)
+	case domain.SyncEntityTypeBookmark:
+		return applyBookmarkEvent(ctx, repos.Bookmark, repos.Ebook, event)
+	case domain.SyncEntityTypeAnnotation:
+		return applyAnnotationEvent(ctx, repos.Annotation, repos.Ebook, event)
+	case domain.SyncEntityTypePreference:
+		return applyPreferenceEvent(ctx, repos.UserPreferences, event)
+	case domain.SyncEntityTypeReader:
+		return applyReaderStateEvent(ctx, repos.UserReaderState, event)
+	default:
+		return errs.NewBadRequestError("unsupported sync entity type", true, nil, nil)
+	}
+}
+
+func applyProgressEvent(ctx context.Context, repo port.ReadingProgressRepository, ebookRepo port.EbookRepository, event *domain.SyncEvent) error {
+	var payload syncProgressPayload
+	if err := decodeSyncPayload(event, &payload); err != nil {
+		return err
+	}
+
+	return applyVersionedSyncEvent(ctx, repo, event, versionedSyncEntity[domain.ReadingProgress]{
+		owner:   func(m *domain.ReadingProgress) uuid.UUID { return m.UserID },
+		version: func(m *domain.ReadingProgress) int64 { return m.RowVersion },
+		create: func() (*domain.ReadingProgress, error) {
+			if payload.EbookID == nil || payload.Location == nil {
+				return nil, errs.NewBadRequestError("progress payload requires ebookId and location", true, nil, nil)
+			}
+			if err := requireOwnedEbook(ctx, ebookRepo, *payload.EbookID, event.UserID); err != nil {
+				return nil, err
+			}
+			input := applicationdto.StoreReadingProgressInput{
+				UserID:          event.UserID,
+				EbookID:         *payload.EbookID,
+				Location:        *payload.Location,
+				ProgressPercent: payload.ProgressPercent,
+				ReadingMode:     domain.ReadingModeNormal,
+				LastReadAt:      payload.LastReadAt,
+			}
+			if payload.ReadingMode != nil {
+				input.ReadingMode = *payload.ReadingMode
+			}
+			model := input.ToModel()
+			model.ID = event.EntityID
+			model.RowVersion = 1
+			return model, nil
+		},
+		updates: func() map[string]any {
+			input := applicationdto.UpdateReadingProgressInput{
+				Location:        payload.Location,
+				ProgressPercent: payload.ProgressPercent,
+				ReadingMode:     payload.ReadingMode,
+				LastReadAt:      payload.LastReadAt,
+			}
+			return input.ToMap()
+		},
+	})
+}
+
+func applyBookmarkEvent(ctx context.Context, repo port.BookmarkRepository, ebookRepo port.EbookRepository, event *domain.SyncEvent) error {
+	var payload syncBookmarkPayload
+	if err := decodeSyncPayload(event, &payload); err != nil {
+		return err
+	}
+
+	return applyVersionedSyncEvent(ctx, repo, event, versionedSyncEntity[domain.Bookmark]{
+		owner:   func(m *domain.Bookmark) uuid.UUID { return m.UserID },
+		version: func(m *domain.Bookmark) int64 { return m.RowVersion },
+		create: func() (*domain.Bookmark, error) {
+			if payload.EbookID == nil || payload.Location == nil {
+				return nil, errs.NewBadRequestError("bookmark payload requires ebookId and location", true, nil, nil)
+			}
+			if err := requireOwnedEbook(ctx, ebookRepo, *payload.EbookID, event.UserID); err != nil {
+				return nil, err
+			}
+			input := applicationdto.StoreBookmarkInput{
+				UserID:   event.UserID,
+				EbookID:  *payload.EbookID,
+				Location: *payload.Location,
+				Label:    payload.Label,
+			}
+			model := input.ToModel()
+			model.ID = event.EntityID
+			model.RowVersion = 1
+			return model, nil
+		},
+		updates: func() map[string]any {
+			input := applicationdto.UpdateBookmarkInput{Label: payload.Label}
+			return input.ToMap()
+		},
+	})
+}
+
+func applyAnnotationEvent(ctx context.Context, repo port.AnnotationRepository, ebookRepo port.EbookRepository, event *domain.SyncEvent) error {
+	var payload syncAnnotationPayload
+	if err := decodeSyncPayload(event, &payload); err != nil {
+		return err
+	}
+
+	return applyVersionedSyncEvent(ctx, repo, event, versionedSyncEntity[domain.Annotation]{
+		owner:   func(m *domain.Annotation) uuid.UUID { return m.UserID },
+		version: func(m *domain.Annotation) int64 { return m.RowVersion },
+		create: func() (*domain.Annotation, error) {
+			if payload.EbookID == nil || payload.LocationStart == nil || payload.LocationEnd == nil {
+				return nil, errs.NewBadRequestError("annotation payload requires ebookId, locationStart and locationEnd", true, nil, nil)
+			}
+			if err := requireOwnedEbook(ctx, ebookRepo, *payload.EbookID, event.UserID); err != nil {
+				return nil, err
+			}
+			input := applicationdto.StoreAnnotationInput{
+				UserID:        event.UserID,
+				EbookID:       *payload.EbookID,
+				LocationStart: *payload.LocationStart,
+				LocationEnd:   *payload.LocationEnd,
+				HighlightText: payload.HighlightText,
+				Note:          payload.Note,
+				Color:         payload.Color,
+			}
+			model := input.ToModel()
+			model.ID = event.EntityID
+			model.RowVersion = 1
+			return model, nil
+		},
+		updates: func() map[string]any {
+			input := applicationdto.UpdateAnnotationInput{
+				LocationStart: payload.LocationStart,
+				LocationEnd:   payload.LocationEnd,
+				HighlightText: payload.HighlightText,
+				Note:          payload.Note,
+				Color:         payload.Color,
+			}
+			return input.ToMap()
+		},
+	})
+}
+
+type versionedSyncEntity[T domain.BaseModel] struct {
+	owner   func(m *T) uuid.UUID
+	version func(m *T) int64
+	create  func() (*T, error)
+	updates func() map[string]any
+}
+
+func applyVersionedSyncEvent[T domain.BaseModel](ctx context.Context, repo port.ResourceRepository[T], event *domain.SyncEvent, entity versionedSyncEntity[T]) error {
+	current, err := repo.GetByID(ctx, event.EntityID, nil)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	if current == nil {
+		switch {
+		case event.BaseVersion != nil:
+			// The client edited or deleted a version the server no longer has.
+			return syncConflictError(event, nil)
+		case event.Operation == domain.SyncOperationDelete:
+			return nil
+		}
+
+		model, err := entity.create()
+		if err != nil {
+			return err
+		}
+		return repo.Store(ctx, model)
+	}
+
+	if entity.owner(current) != event.UserID {
+		return errs.NewForbiddenError("not allowed to sync this entity", true)
+	}
+	if event.BaseVersion == nil || *event.BaseVersion != entity.version(current) {
+		return syncConflictError(event, current)
+	}
+
+	if event.Operation == domain.SyncOperationDelete {
+		return repo.Destroy(ctx, event.EntityID)
+	}
+
+	updates := entity.updates()
+	updates["row_version"] = entity.version(current) + 1
+	updates["updated_at"] = time.Now().UTC()
+	_, err = repo.Update(ctx, *current, updates)
+	return err
+}
+
+func applyPreferenceEvent(ctx context.Context, repo port.UserPreferencesRepository, event *domain.SyncEvent) error {
+	if event.Operation != domain.SyncOperationUpsert {
+		return errs.NewBadRequestError("preferences cannot be deleted", true, nil, nil)
+	}
+
+	var payload syncPreferencePayload
+	if err := decodeSyncPayload(event, &payload); err != nil {
+		return err
+	}
+
+	current, err := repo.GetByUserID(ctx, event.UserID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if current != nil && event.ClientTimestamp.Before(current.UpdatedAt) {
+		return errSyncEventSuperseded
+	}
+
+	_, err = NewUserPreferencesService(repo).Patch(ctx, event.UserID, &applicationdto.UpdateUserPreferencesInput{
+		ReadingMode:       payload.ReadingMode,
+		ZenRestoreOnOpen:  payload.ZenRestoreOnOpen,
+		ThemeMode:         payload.ThemeMode,
+		ThemeOverrides:    payload.ThemeOverrides,
+		TypographyProfile: payload.TypographyProfile,
+	})
+	return err
+}
+
+func applyReaderStateEvent(ctx context.Context, repo port.UserReaderStateRepository, event *domain.SyncEvent) error {
+	if event.Operation != domain.SyncOperationUpsert {
+		return errs.NewBadRequestError("reader state cannot be deleted", true, nil, nil)
+	}
+
+	var payload syncReaderStatePayload
+	if err := decodeSyncPayload(event, &payload); err != nil {
+		return err
+	}
+
+	current, err := repo.GetByUserID(ctx, event.UserID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if current != nil && event.ClientTimestamp.Before(current.UpdatedAt) {
+		return errSyncEventSuperseded
+	}
+
+	_, err = NewUserReaderStateService(repo).Patch(ctx, event.UserID, &applicationdto.UpdateUserReaderStateInput{
+		CurrentEbookID:  payload.CurrentEbookID,
+		CurrentLocation: payload.CurrentLocation,
+		ReadingMode:     payload.ReadingMode,
+		LastOpenedAt:    payload.LastOpenedAt,
+	})
+	return err
+}
+
+// requireOwnedEbook fails with not found unless the ebook exists and belongs to
+// userID, so a sync event cannot attach data to another user's ebook.
+func requireOwnedEbook(ctx context.Context, repo port.EbookRepository, ebookID uuid.UUID, userID uuid.UUID) error {
+	ebook, err := repo.GetByID(ctx, ebookID, nil)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	if ebook == nil || ebook.OwnerUserID != userID {
+		return errs.NewNotFoundError("ebook not found", true)
+	}
+	return nil
+}
+
+func decodeSyncPayload(event *domain.SyncEvent, out any) error {
+	if len(event.Payload) == 0 {
+		return nil
+	}
+
+	raw, err := json.Marshal(event.Payload)
+	if err != nil {
+		return errs.NewBadRequestError("invalid sync payload", true, nil, nil)
+	}
+	if err := json.Unmarshal(raw, out); err != nil {
+		return errs.NewBadRequestError("invalid sync payload for "+string(event.EntityType), true, nil, nil)
+	}
+	return nil
+}
+
+func syncConflictError(event *domain.SyncEvent, current any) error {
+	conflict := &SyncConflict{EntityType: event.EntityType, EntityID: event.EntityID}
+	if current != nil {
+		conflict.Current = current
+	}
+	return errs.NewConflictError("sync event conflicts with the current server state", true, conflict)
+}
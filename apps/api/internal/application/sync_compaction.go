@@ -0,0 +1,216 @@
+package application
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"maps"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/sqlerr"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"gorm.io/gorm"
+)
+
+const syncCompactionBatchSize = 500
+
+// SyncSnapshot is the current state of every synced entity of a user. A new
+// device stores it and then lists events from Cursor instead of replaying the
+// whole history.
+type SyncSnapshot struct {
+	Cursor          string                   `json:"cursor"`
+	ReadingProgress []domain.ReadingProgress `json:"readingProgress"`
+	Bookmarks       []domain.Bookmark        `json:"bookmarks"`
+	Annotations     []domain.Annotation      `json:"annotations"`
+	Preferences     *domain.UserPreferences  `json:"preferences,omitempty"`
+	ReaderState     *domain.UserReaderState  `json:"readerState,omitempty"`
+}
+
+// Snapshot reads the user's synced entities while holding the user's sync lock,
+// so no event can land between the cursor and the state it describes.
+func (s *syncService) Snapshot(ctx context.Context, userID uuid.UUID) (*SyncSnapshot, error) {
+	snapshot := &SyncSnapshot{}
+	err := s.transactor.WithinTransaction(ctx, userID, uuid.Nil, func(repos *port.SyncRepositories) error {
+		latest, err := repos.SyncEvent.GetLatestByUserID(ctx, userID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if latest != nil {
+			snapshot.Cursor = EncodeSyncEventCursor(*latest)
+		}
+
+		if snapshot.ReadingProgress, err = listAllByUser(ctx, repos.ReadingProgress, userID); err != nil {
+			return err
+		}
+		if snapshot.Bookmarks, err = listAllByUser(ctx, repos.Bookmark, userID); err != nil {
+			return err
+		}
+		if snapshot.Annotations, err = listAllByUser(ctx, repos.Annotation, userID); err != nil {
+			return err
+		}
+
+		if snapshot.Preferences, err = repos.UserPreferences.GetByUserID(ctx, userID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		if snapshot.ReaderState, err = repos.UserReaderState.GetByUserID(ctx, userID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, sqlerr.HandleError(err)
+	}
+
+	return snapshot, nil
+}
+
+// CompactEvents deletes events stored before expireBefore, then collapses each
+// entity's superseded events into its latest one. Only events every active device
+// has pulled are collapsed, and their payloads are merged, so a device that
+// pulled some of them still ends up with the same state.
+func (s *syncService) CompactEvents(ctx context.Context, expireBefore time.Time) (collapsed int, expired int, err error) {
+	for {
+		deleted, err := s.eventRepo.DeleteBefore(ctx, expireBefore, syncCompactionBatchSize)
+		if err != nil {
+			return collapsed, expired, sqlerr.HandleError(err)
+		}
+		expired += int(deleted)
+		if deleted < syncCompactionBatchSize {
+			break
+		}
+	}
+
+	after := uuid.Nil
+	for {
+		userIDs, err := s.eventRepo.ListCompactableUsers(ctx, after, syncCompactionBatchSize)
+		if err != nil {
+			return collapsed, expired, sqlerr.HandleError(err)
+		}
+
+		for _, userID := range userIDs {
+			n, err := s.compactUserEvents(ctx, userID, expireBefore)
+			if err != nil {
+				return collapsed, expired, sqlerr.HandleError(err)
+			}
+			collapsed += n
+		}
+
+		if len(userIDs) < syncCompactionBatchSize {
+			return collapsed, expired, nil
+		}
+		after = userIDs[len(userIDs)-1]
+	}
+}
+
+// compactUserEvents collapses the user's events up to the oldest checkpoint of
+// the devices seen since activeSince. Devices idle for longer lose their history
+// to expiry anyway. A device that never pulled holds compaction back entirely,
+// and so does having no active device, since nothing then says which events
+// have been pulled.
+func (s *syncService) compactUserEvents(ctx context.Context, userID uuid.UUID, activeSince time.Time) (int, error) {
+	var horizon *time.Time
+	if s.deviceRepo != nil {
+		var err error
+		if horizon, err = s.deviceRepo.GetSyncHorizon(ctx, userID, activeSince); err != nil {
+			return 0, err
+		}
+		if horizon == nil {
+			return 0, nil
+		}
+	}
+
+	collapsed := 0
+	err := s.transactor.WithinTransaction(ctx, userID, uuid.Nil, func(repos *port.SyncRepositories) error {
+		checkpoint, err := repos.SyncCheckpoint.GetByUserID(ctx, userID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		until := checkpoint.LastServerTimestamp
+		if horizon != nil && horizon.Before(until) {
+			until = *horizon
+		}
+
+		events, err := repos.SyncEvent.ListSupersededUntil(ctx, userID, until)
+		if err != nil {
+			return err
+		}
+
+		for start := 0; start < len(events); {
+			end := start + 1
+			for end < len(events) && events[end].EntityType == events[start].EntityType && events[end].EntityID == events[start].EntityID {
+				end++
+			}
+
+			group := events[start:end]
+			start = end
+			if len(group) < 2 {
+				continue
+			}
+
+			keep, supersededIDs := collapseSyncEvents(group)
+			if err := repos.SyncEvent.Collapse(ctx, keep, supersededIDs); err != nil {
+				return err
+			}
+			collapsed += len(supersededIDs)
+		}
+		return nil
+	})
+	return collapsed, err
+}
+
+// collapseSyncEvents folds the events of one entity, oldest first, into the
+// latest of them. Payloads are partial updates, so the kept event carries every
+// field written since the entity was last deleted; a delete drops earlier fields.
+func collapseSyncEvents(group []domain.SyncEvent) (*domain.SyncEvent, []uuid.UUID) {
+	payload := map[string]any{}
+	supersededIDs := make([]uuid.UUID, 0, len(group)-1)
+	for i, event := range group {
+		if event.Operation == domain.SyncOperationDelete {
+			payload = map[string]any{}
+		}
+		maps.Copy(payload, event.Payload)
+		if i < len(group)-1 {
+			supersededIDs = append(supersededIDs, event.ID)
+		}
+	}
+
+	keep := group[len(group)-1]
+	keep.Payload = payload
+	return &keep, supersededIDs
+}
+
+// listAllByUser pages through every live entity owned by userID. The pages are
+// ordered by id, so the cursor of each is the id of its last entity.
+func listAllByUser[T domain.BaseModel](ctx context.Context, repo port.ResourceRepository[T], userID uuid.UUID) ([]T, error) {
+	all := []T{}
+	var after *port.ListCursor
+	for {
+		page, _, err := repo.GetMany(ctx, port.GetManyOptions{
+			Filters:        map[string]any{"user_id": userID},
+			OrderBy:        "id",
+			OrderDirection: "asc",
+			Limit:          syncCompactionBatchSize,
+			After:          after,
+			SkipCount:      true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, page...)
+		if len(page) < syncCompactionBatchSize {
+			return all, nil
+		}
+		last := page[len(page)-1].GetID()
+		value, err := json.Marshal(last)
+		if err != nil {
+			return nil, err
+		}
+		after = &port.ListCursor{Value: value, ID: last}
+	}
+}
@@ -2,12 +2,15 @@ package application
 
 import (
 	"context"
+	"errors"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
 	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
 	"github.com/jeheskielSunloy77/libra-link/internal/domain"
 	infrarepo "github.com/jeheskielSunloy77/libra-link/internal/infrastructure/repository"
 	"github.com/stretchr/testify/require"
@@ -17,7 +20,13 @@ import (
 type testSyncEventRepo struct {
 	*infrarepo.MockResourceRepository[domain.SyncEvent]
 	getByUserAndIdempotencyKeyFn func(ctx context.Context, userID uuid.UUID, idempotencyKey string) (*domain.SyncEvent, error)
-	listSinceFn                  func(ctx context.Context, userID uuid.UUID, since *time.Time, limit int) ([]domain.SyncEvent, error)
+	listAfterFn                  func(ctx context.Context, userID uuid.UUID, after *port.SyncEventCursor, limit int) ([]domain.SyncEvent, error)
+	getLatestByUserIDFn          func(ctx context.Context, userID uuid.UUID) (*domain.SyncEvent, error)
+	listCompactableUsersFn       func(ctx context.Context, afterUserID uuid.UUID, limit int) ([]uuid.UUID, error)
+	listSupersededUntilFn        func(ctx context.Context, userID uuid.UUID, until time.Time) ([]domain.SyncEvent, error)
+	deleteBeforeFn               func(ctx context.Context, before time.Time, limit int) (int64, error)
+	collapsed                    map[uuid.UUID][]uuid.UUID
+	collapsedPayloads            map[uuid.UUID]map[string]any
 	storeFn                      func(ctx context.Context, entity *domain.SyncEvent) error
 	storeCalls                   int
 	storedEvent                  *domain.SyncEvent
@@ -46,13 +55,51 @@ func (r *testSyncEventRepo) GetByUserAndIdempotencyKey(ctx context.Context, user
 	return nil, gorm.ErrRecordNotFound
 }
 
-func (r *testSyncEventRepo) ListSince(ctx context.Context, userID uuid.UUID, since *time.Time, limit int) ([]domain.SyncEvent, error) {
-	if r.listSinceFn != nil {
-		return r.listSinceFn(ctx, userID, since, limit)
+func (r *testSyncEventRepo) ListAfter(ctx context.Context, userID uuid.UUID, after *port.SyncEventCursor, limit int) ([]domain.SyncEvent, error) {
+	if r.listAfterFn != nil {
+		return r.listAfterFn(ctx, userID, after, limit)
 	}
 	return nil, nil
 }
 
+func (r *testSyncEventRepo) GetLatestByUserID(ctx context.Context, userID uuid.UUID) (*domain.SyncEvent, error) {
+	if r.getLatestByUserIDFn != nil {
+		return r.getLatestByUserIDFn(ctx, userID)
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *testSyncEventRepo) ListCompactableUsers(ctx context.Context, afterUserID uuid.UUID, limit int) ([]uuid.UUID, error) {
+	if r.listCompactableUsersFn != nil {
+		return r.listCompactableUsersFn(ctx, afterUserID, limit)
+	}
+	return nil, nil
+}
+
+func (r *testSyncEventRepo) ListSupersededUntil(ctx context.Context, userID uuid.UUID, until time.Time) ([]domain.SyncEvent, error) {
+	if r.listSupersededUntilFn != nil {
+		return r.listSupersededUntilFn(ctx, userID, until)
+	}
+	return nil, nil
+}
+
+func (r *testSyncEventRepo) Collapse(ctx context.Context, keep *domain.SyncEvent, supersededIDs []uuid.UUID) error {
+	if r.collapsed == nil {
+		r.collapsed = map[uuid.UUID][]uuid.UUID{}
+		r.collapsedPayloads = map[uuid.UUID]map[string]any{}
+	}
+	r.collapsed[keep.ID] = supersededIDs
+	r.collapsedPayloads[keep.ID] = keep.Payload
+	return nil
+}
+
+func (r *testSyncEventRepo) DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	if r.deleteBeforeFn != nil {
+		return r.deleteBeforeFn(ctx, before, limit)
+	}
+	return 0, nil
+}
+
 type testSyncCheckpointRepo struct {
 	getByUserIDFn func(ctx context.Context, userID uuid.UUID) (*domain.SyncCheckpoint, error)
 	upsertFn      func(ctx context.Context, checkpoint *domain.SyncCheckpoint) error
@@ -77,8 +124,47 @@ func (r *testSyncCheckpointRepo) Upsert(ctx context.Context, checkpoint *domain.
 	return nil
 }
 
+type testReadingProgressRepo struct {
+	*infrarepo.MockResourceRepository[domain.ReadingProgress]
+}
+
+func (r *testReadingProgressRepo) GetByUserAndEbook(ctx context.Context, userID uuid.UUID, ebookID uuid.UUID) (*domain.ReadingProgress, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+// testSyncTransactor runs fn directly against in-memory repositories.
+type testSyncTransactor struct {
+	repos *port.SyncRepositories
+}
+
+func newTestSyncTransactor(eventRepo *testSyncEventRepo, checkpointRepo *testSyncCheckpointRepo) *testSyncTransactor {
+	return &testSyncTransactor{repos: &port.SyncRepositories{
+		SyncEvent:       eventRepo,
+		SyncCheckpoint:  checkpointRepo,
+		ReadingProgress: &testReadingProgressRepo{infrarepo.NewMockResourceRepository[domain.ReadingProgress](false)},
+		Bookmark:        infrarepo.NewMockResourceRepository[domain.Bookmark](false),
+		Annotation:      infrarepo.NewMockResourceRepository[domain.Annotation](false),
+		UserPreferences: newTestUserPreferencesRepo(),
+		UserReaderState: newTestUserReaderStateRepo(),
+		Ebook:           infrarepo.NewMockResourceRepository[domain.Ebook](false),
+	}}
+}
+
+// storeTestSyncEbook stores an ebook owned by userID for sync events to reference.
+func storeTestSyncEbook(t *testing.T, transactor *testSyncTransactor, userID uuid.UUID) uuid.UUID {
+	t.Helper()
+	ebook := &domain.Ebook{ID: uuid.New(), OwnerUserID: userID, Title: "Dune", Format: domain.EbookFormatEPUB}
+	require.NoError(t, transactor.repos.Ebook.Store(context.Background(), ebook))
+	return ebook.ID
+}
+
+func (t *testSyncTransactor) WithinTransaction(ctx context.Context, userID uuid.UUID, entityID uuid.UUID, fn func(repos *port.SyncRepositories) error) error {
+	return fn(t.repos)
+}
+
 func TestSyncServiceStoreEvent_RejectsNilInput(t *testing.T) {
-	service := NewSyncService(newTestSyncEventRepo(), &testSyncCheckpointRepo{})
+	eventRepo := newTestSyncEventRepo()
+	service := NewSyncService(eventRepo, nil, newTestSyncTransactor(eventRepo, &testSyncCheckpointRepo{}), nil)
 
 	_, err := service.StoreEvent(context.Background(), nil)
 	require.Error(t, err)
@@ -91,7 +177,7 @@ func TestSyncServiceStoreEvent_RejectsNilInput(t *testing.T) {
 func TestSyncServiceStoreEvent_ReturnsExistingForIdempotencyKey(t *testing.T) {
 	eventRepo := newTestSyncEventRepo()
 	checkpointRepo := &testSyncCheckpointRepo{}
-	service := NewSyncService(eventRepo, checkpointRepo)
+	service := NewSyncService(eventRepo, nil, newTestSyncTransactor(eventRepo, checkpointRepo), nil)
 
 	existing := &domain.SyncEvent{
 		ID:             uuid.New(),
@@ -119,7 +205,7 @@ func TestSyncServiceStoreEvent_ReturnsExistingForIdempotencyKey(t *testing.T) {
 func TestSyncServiceStoreEvent_AssignsIDAndUpdatesCheckpoint(t *testing.T) {
 	eventRepo := newTestSyncEventRepo()
 	checkpointRepo := &testSyncCheckpointRepo{}
-	service := NewSyncService(eventRepo, checkpointRepo)
+	service := NewSyncService(eventRepo, nil, newTestSyncTransactor(eventRepo, checkpointRepo), nil)
 
 	eventRepo.getByUserAndIdempotencyKeyFn = func(ctx context.Context, userID uuid.UUID, idempotencyKey string) (*domain.SyncEvent, error) {
 		return nil, gorm.ErrRecordNotFound
@@ -147,3 +233,486 @@ func TestSyncServiceStoreEvent_AssignsIDAndUpdatesCheckpoint(t *testing.T) {
 	require.NotNil(t, checkpointRepo.lastUpserted.LastEventID)
 	require.Equal(t, stored.ID, *checkpointRepo.lastUpserted.LastEventID)
 }
+
+type testSyncNotifier struct {
+	published []*domain.SyncEvent
+}
+
+func (n *testSyncNotifier) Publish(ctx context.Context, event *domain.SyncEvent) {
+	n.published = append(n.published, event)
+}
+
+func (n *testSyncNotifier) Subscribe(ctx context.Context, userID uuid.UUID) (<-chan domain.SyncEvent, error) {
+	return nil, errors.New("not implemented")
+}
+
+func TestSyncServiceStoreEvent_PublishesOnlyNewEvents(t *testing.T) {
+	eventRepo := newTestSyncEventRepo()
+	notifier := &testSyncNotifier{}
+	service := NewSyncService(eventRepo, nil, newTestSyncTransactor(eventRepo, &testSyncCheckpointRepo{}), notifier)
+
+	var stored *domain.SyncEvent
+	eventRepo.getByUserAndIdempotencyKeyFn = func(ctx context.Context, userID uuid.UUID, idempotencyKey string) (*domain.SyncEvent, error) {
+		if stored != nil {
+			return stored, nil
+		}
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	input := &applicationdto.StoreSyncEventInput{
+		UserID:          uuid.New(),
+		EntityType:      domain.SyncEntityTypeReader,
+		EntityID:        uuid.New(),
+		Operation:       domain.SyncOperationUpsert,
+		ClientTimestamp: time.Now().UTC(),
+		IdempotencyKey:  uuid.NewString(),
+	}
+
+	stored, err := service.StoreEvent(context.Background(), input)
+	require.NoError(t, err)
+	require.Len(t, notifier.published, 1)
+	require.Equal(t, stored.ID, notifier.published[0].ID)
+
+	_, err = service.StoreEvent(context.Background(), input)
+	require.NoError(t, err)
+	require.Len(t, notifier.published, 1)
+}
+
+func TestSyncServiceSubscribeEvents_UnavailableWithoutNotifier(t *testing.T) {
+	eventRepo := newTestSyncEventRepo()
+	service := NewSyncService(eventRepo, nil, newTestSyncTransactor(eventRepo, &testSyncCheckpointRepo{}), nil)
+
+	_, err := service.SubscribeEvents(context.Background(), uuid.New())
+
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, 503, httpErr.Status)
+}
+
+func TestSyncServiceStoreEvent_AppliesBookmarkWithRowVersion(t *testing.T) {
+	eventRepo := newTestSyncEventRepo()
+	transactor := newTestSyncTransactor(eventRepo, &testSyncCheckpointRepo{})
+	service := NewSyncService(eventRepo, nil, transactor, nil)
+
+	userID := uuid.New()
+	bookmarkID := uuid.New()
+	ebookID := storeTestSyncEbook(t, transactor, userID)
+	_, err := service.StoreEvent(context.Background(), &applicationdto.StoreSyncEventInput{
+		UserID:          userID,
+		EntityType:      domain.SyncEntityTypeBookmark,
+		EntityID:        bookmarkID,
+		Operation:       domain.SyncOperationUpsert,
+		Payload:         map[string]any{"ebookId": ebookID.String(), "location": "chapter-1"},
+		ClientTimestamp: time.Now().UTC(),
+		IdempotencyKey:  uuid.NewString(),
+	})
+	require.NoError(t, err)
+
+	baseVersion := int64(1)
+	_, err = service.StoreEvent(context.Background(), &applicationdto.StoreSyncEventInput{
+		UserID:          userID,
+		EntityType:      domain.SyncEntityTypeBookmark,
+		EntityID:        bookmarkID,
+		Operation:       domain.SyncOperationUpsert,
+		Payload:         map[string]any{"label": "favourite"},
+		BaseVersion:     &baseVersion,
+		ClientTimestamp: time.Now().UTC(),
+		IdempotencyKey:  uuid.NewString(),
+	})
+	require.NoError(t, err)
+
+	bookmark, err := transactor.repos.Bookmark.GetByID(context.Background(), bookmarkID, nil)
+	require.NoError(t, err)
+	require.Equal(t, userID, bookmark.UserID)
+	require.Equal(t, int64(2), bookmark.RowVersion)
+	require.NotNil(t, bookmark.Label)
+	require.Equal(t, "favourite", *bookmark.Label)
+	require.Equal(t, 2, eventRepo.storeCalls)
+}
+
+func TestSyncServiceStoreEvent_RejectsStaleRowVersion(t *testing.T) {
+	eventRepo := newTestSyncEventRepo()
+	checkpointRepo := &testSyncCheckpointRepo{}
+	transactor := newTestSyncTransactor(eventRepo, checkpointRepo)
+	service := NewSyncService(eventRepo, nil, transactor, nil)
+
+	current := &domain.Bookmark{ID: uuid.New(), UserID: uuid.New(), EbookID: uuid.New(), Location: "chapter-2", RowVersion: 3}
+	require.NoError(t, transactor.repos.Bookmark.Store(context.Background(), current))
+
+	baseVersion := int64(2)
+	_, err := service.StoreEvent(context.Background(), &applicationdto.StoreSyncEventInput{
+		UserID:          current.UserID,
+		EntityType:      domain.SyncEntityTypeBookmark,
+		EntityID:        current.ID,
+		Operation:       domain.SyncOperationDelete,
+		BaseVersion:     &baseVersion,
+		ClientTimestamp: time.Now().UTC(),
+		IdempotencyKey:  uuid.NewString(),
+	})
+	require.Error(t, err)
+
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, 409, httpErr.Status)
+
+	conflict, ok := httpErr.Data.(*SyncConflict)
+	require.True(t, ok)
+	require.Equal(t, current.ID, conflict.EntityID)
+	require.Equal(t, int64(3), conflict.Current.(*domain.Bookmark).RowVersion)
+
+	_, err = transactor.repos.Bookmark.GetByID(context.Background(), current.ID, nil)
+	require.NoError(t, err)
+	require.Equal(t, 0, eventRepo.storeCalls)
+	require.Equal(t, 0, checkpointRepo.upsertCalls)
+}
+
+func TestSyncServiceStoreEvent_SupersedesOutdatedReaderState(t *testing.T) {
+	eventRepo := newTestSyncEventRepo()
+	transactor := newTestSyncTransactor(eventRepo, &testSyncCheckpointRepo{})
+	service := NewSyncService(eventRepo, nil, transactor, nil)
+
+	userID := uuid.New()
+	location := "chapter-9"
+	require.NoError(t, transactor.repos.UserReaderState.Upsert(context.Background(), &domain.UserReaderState{
+		UserID:          userID,
+		CurrentLocation: &location,
+		ReadingMode:     domain.ReadingModeNormal,
+		RowVersion:      4,
+		UpdatedAt:       time.Now().UTC(),
+	}, nil))
+
+	_, err := service.StoreEvent(context.Background(), &applicationdto.StoreSyncEventInput{
+		UserID:          userID,
+		EntityType:      domain.SyncEntityTypeReader,
+		EntityID:        userID,
+		Operation:       domain.SyncOperationUpsert,
+		Payload:         map[string]any{"currentLocation": "chapter-1"},
+		ClientTimestamp: time.Now().UTC().Add(-time.Hour),
+		IdempotencyKey:  uuid.NewString(),
+	})
+	require.NoError(t, err)
+
+	state, err := transactor.repos.UserReaderState.GetByUserID(context.Background(), userID)
+	require.NoError(t, err)
+	require.Equal(t, location, *state.CurrentLocation)
+	require.Equal(t, 0, eventRepo.storeCalls)
+}
+
+func TestSyncServiceStoreEvent_RejectsAnotherUsersEbook(t *testing.T) {
+	eventRepo := newTestSyncEventRepo()
+	transactor := newTestSyncTransactor(eventRepo, &testSyncCheckpointRepo{})
+	service := NewSyncService(eventRepo, nil, transactor, nil)
+
+	ebookID := storeTestSyncEbook(t, transactor, uuid.New())
+	_, err := service.StoreEvent(context.Background(), &applicationdto.StoreSyncEventInput{
+		UserID:          uuid.New(),
+		EntityType:      domain.SyncEntityTypeAnnotation,
+		EntityID:        uuid.New(),
+		Operation:       domain.SyncOperationUpsert,
+		Payload:         map[string]any{"ebookId": ebookID.String(), "locationStart": "a", "locationEnd": "b"},
+		ClientTimestamp: time.Now().UTC(),
+		IdempotencyKey:  uuid.NewString(),
+	})
+	requireErrorStatus(t, err, http.StatusNotFound)
+	require.Equal(t, 0, eventRepo.storeCalls)
+}
+
+func TestSyncServiceStoreEvents_ReportsPerEventStatus(t *testing.T) {
+	eventRepo := newTestSyncEventRepo()
+	transactor := newTestSyncTransactor(eventRepo, &testSyncCheckpointRepo{})
+	service := NewSyncService(eventRepo, nil, transactor, nil)
+
+	byKey := map[string]*domain.SyncEvent{}
+	eventRepo.storeFn = func(ctx context.Context, entity *domain.SyncEvent) error {
+		byKey[entity.IdempotencyKey] = entity
+		return nil
+	}
+	eventRepo.getByUserAndIdempotencyKeyFn = func(ctx context.Context, userID uuid.UUID, idempotencyKey string) (*domain.SyncEvent, error) {
+		if event, ok := byKey[idempotencyKey]; ok {
+			return event, nil
+		}
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	userID := uuid.New()
+	bookmarkID := uuid.New()
+	ebookID := storeTestSyncEbook(t, transactor, userID)
+	staleVersion := int64(7)
+	newInput := func(key string, operation domain.SyncOperation, payload map[string]any, baseVersion *int64) *applicationdto.StoreSyncEventInput {
+		return &applicationdto.StoreSyncEventInput{
+			UserID:          userID,
+			EntityType:      domain.SyncEntityTypeBookmark,
+			EntityID:        bookmarkID,
+			Operation:       operation,
+			Payload:         payload,
+			BaseVersion:     baseVersion,
+			ClientTimestamp: time.Now().UTC(),
+			IdempotencyKey:  key,
+		}
+	}
+
+	results, err := service.StoreEvents(context.Background(), []*applicationdto.StoreSyncEventInput{
+		newInput("create-bookmark", domain.SyncOperationUpsert, map[string]any{"ebookId": ebookID.String(), "location": "chapter-1"}, nil),
+		newInput("create-bookmark", domain.SyncOperationUpsert, map[string]any{"ebookId": ebookID.String(), "location": "chapter-1"}, nil),
+		newInput("stale-delete", domain.SyncOperationDelete, nil, &staleVersion),
+		newInput("bad-payload", domain.SyncOperationUpsert, map[string]any{"label": 42}, nil),
+	})
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	require.Equal(t, SyncEventStatusApplied, results[0].Status)
+	require.NotNil(t, results[0].Event)
+
+	require.Equal(t, SyncEventStatusDuplicate, results[1].Status)
+	require.Equal(t, results[0].Event.ID, results[1].Event.ID)
+
+	require.Equal(t, SyncEventStatusConflict, results[2].Status)
+	require.NotNil(t, results[2].Conflict)
+	require.Equal(t, int64(1), results[2].Conflict.Current.(*domain.Bookmark).RowVersion)
+
+	require.Equal(t, SyncEventStatusRejected, results[3].Status)
+	require.NotEmpty(t, results[3].Message)
+	require.Nil(t, results[3].Event)
+
+	require.Equal(t, 1, eventRepo.storeCalls)
+}
+
+func TestSyncServiceListEvents_PagesWithCursor(t *testing.T) {
+	eventRepo := newTestSyncEventRepo()
+	service := NewSyncService(eventRepo, nil, newTestSyncTransactor(eventRepo, &testSyncCheckpointRepo{}), nil)
+
+	// Two events share a timestamp so the id tiebreak decides the page boundary.
+	at := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	events := []domain.SyncEvent{
+		{ID: uuid.MustParse("00000000-0000-0000-0000-000000000001"), ServerTimestamp: at},
+		{ID: uuid.MustParse("00000000-0000-0000-0000-000000000002"), ServerTimestamp: at},
+		{ID: uuid.MustParse("00000000-0000-0000-0000-000000000003"), ServerTimestamp: at.Add(time.Second)},
+	}
+	eventRepo.listAfterFn = func(ctx context.Context, userID uuid.UUID, after *port.SyncEventCursor, limit int) ([]domain.SyncEvent, error) {
+		var page []domain.SyncEvent
+		for _, event := range events {
+			if after != nil {
+				if event.ServerTimestamp.Before(after.ServerTimestamp) {
+					continue
+				}
+				if event.ServerTimestamp.Equal(after.ServerTimestamp) && event.ID.String() <= after.ID.String() {
+					continue
+				}
+			}
+			if len(page) < limit {
+				page = append(page, event)
+			}
+		}
+		return page, nil
+	}
+
+	first, err := service.ListEvents(context.Background(), uuid.New(), nil, "", 2)
+	require.NoError(t, err)
+	require.Len(t, first.Events, 2)
+	require.True(t, first.HasMore)
+	require.NotEmpty(t, first.NextCursor)
+
+	second, err := service.ListEvents(context.Background(), uuid.New(), nil, first.NextCursor, 2)
+	require.NoError(t, err)
+	require.Len(t, second.Events, 1)
+	require.Equal(t, events[2].ID, second.Events[0].ID)
+	require.False(t, second.HasMore)
+
+	since, err := service.ListEvents(context.Background(), uuid.New(), &at, "", 2)
+	require.NoError(t, err)
+	require.Len(t, since.Events, 1)
+	require.Equal(t, events[2].ID, since.Events[0].ID)
+
+	_, err = service.ListEvents(context.Background(), uuid.New(), nil, "not-a-cursor", 2)
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, err, &httpErr)
+	require.Equal(t, 400, httpErr.Status)
+}
+
+func TestSyncServiceListEvents_RecordsDeviceCheckpoint(t *testing.T) {
+	userID := uuid.New()
+	eventRepo := newTestSyncEventRepo()
+	var recorded *port.SyncEventCursor
+	deviceRepo := &testDeviceRepo{
+		recordSyncFn: func(ctx context.Context, id uuid.UUID, clientID string, position *port.SyncEventCursor, syncedAt time.Time) error {
+			require.Equal(t, userID, id)
+			require.Equal(t, "laptop", clientID)
+			recorded = position
+			return nil
+		},
+	}
+	service := NewSyncService(eventRepo, deviceRepo, newTestSyncTransactor(eventRepo, &testSyncCheckpointRepo{}), nil)
+
+	acked := domain.SyncEvent{ID: uuid.New(), ServerTimestamp: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	ctx := WithClientDevice(context.Background(), ClientDevice{ClientID: "laptop", Name: "Laptop"})
+	_, err := service.ListEvents(ctx, userID, nil, EncodeSyncEventCursor(acked), 10)
+	require.NoError(t, err)
+	require.Equal(t, &port.SyncEventCursor{ServerTimestamp: acked.ServerTimestamp, ID: acked.ID}, recorded)
+}
+
+// Ensures CheckSubscription fails only once the subscribing device is revoked.
+func TestSyncServiceCheckSubscription_RejectsRevokedDevice(t *testing.T) {
+	userID := uuid.New()
+	device := &domain.Device{ID: uuid.New(), UserID: userID, ClientID: "laptop"}
+	deviceRepo := &testDeviceRepo{
+		getByClientIDFn: func(ctx context.Context, id uuid.UUID, clientID string) (*domain.Device, error) {
+			require.Equal(t, userID, id)
+			require.Equal(t, "laptop", clientID)
+			return device, nil
+		},
+	}
+	eventRepo := newTestSyncEventRepo()
+	service := NewSyncService(eventRepo, deviceRepo, newTestSyncTransactor(eventRepo, &testSyncCheckpointRepo{}), nil)
+	ctx := WithClientDevice(context.Background(), ClientDevice{ClientID: "laptop", Name: "Laptop"})
+
+	require.NoError(t, service.CheckSubscription(ctx, userID))
+	require.NoError(t, service.CheckSubscription(context.Background(), userID))
+
+	revokedAt := time.Now().UTC()
+	device.RevokedAt = &revokedAt
+	requireErrorStatus(t, service.CheckSubscription(ctx, userID), http.StatusForbidden)
+}
+
+func TestCollapseSyncEvents_MergesPayloadsSinceLastDelete(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New(), uuid.New()}
+	keep, supersededIDs := collapseSyncEvents([]domain.SyncEvent{
+		{ID: ids[0], Operation: domain.SyncOperationUpsert, Payload: map[string]any{"label": "old", "location": "ch-1"}},
+		{ID: ids[1], Operation: domain.SyncOperationDelete},
+		{ID: ids[2], Operation: domain.SyncOperationUpsert, Payload: map[string]any{"location": "ch-2", "ebookId": "ebook-1"}},
+		{ID: ids[3], Operation: domain.SyncOperationUpsert, Payload: map[string]any{"location": "ch-3"}},
+	})
+
+	require.Equal(t, ids[3], keep.ID)
+	require.Equal(t, ids[:3], supersededIDs)
+	require.Equal(t, map[string]any{"location": "ch-3", "ebookId": "ebook-1"}, keep.Payload)
+}
+
+func TestSyncServiceCompactEvents_CollapsesUpToCheckpoint(t *testing.T) {
+	userID := uuid.New()
+	horizon := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	eventRepo := newTestSyncEventRepo()
+	checkpointRepo := &testSyncCheckpointRepo{
+		getByUserIDFn: func(ctx context.Context, id uuid.UUID) (*domain.SyncCheckpoint, error) {
+			return &domain.SyncCheckpoint{UserID: id, LastServerTimestamp: horizon}, nil
+		},
+	}
+	service := NewSyncService(eventRepo, nil, newTestSyncTransactor(eventRepo, checkpointRepo), nil)
+
+	expireBefore := horizon.Add(-90 * 24 * time.Hour)
+	eventRepo.deleteBeforeFn = func(ctx context.Context, before time.Time, limit int) (int64, error) {
+		require.Equal(t, expireBefore, before)
+		return 2, nil
+	}
+	eventRepo.listCompactableUsersFn = func(ctx context.Context, afterUserID uuid.UUID, limit int) ([]uuid.UUID, error) {
+		return []uuid.UUID{userID}, nil
+	}
+
+	readerState, bookmark := uuid.New(), uuid.New()
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	eventRepo.listSupersededUntilFn = func(ctx context.Context, id uuid.UUID, until time.Time) ([]domain.SyncEvent, error) {
+		require.Equal(t, userID, id)
+		require.Equal(t, horizon, until)
+		return []domain.SyncEvent{
+			{ID: first, EntityType: domain.SyncEntityTypeReader, EntityID: readerState, Operation: domain.SyncOperationUpsert, Payload: map[string]any{"readingMode": "zen"}},
+			{ID: second, EntityType: domain.SyncEntityTypeReader, EntityID: readerState, Operation: domain.SyncOperationUpsert, Payload: map[string]any{"currentLocation": "ch-9"}},
+			{ID: third, EntityType: domain.SyncEntityTypeBookmark, EntityID: bookmark, Operation: domain.SyncOperationUpsert},
+		}, nil
+	}
+
+	collapsed, expired, err := service.CompactEvents(context.Background(), expireBefore)
+	require.NoError(t, err)
+	require.Equal(t, 1, collapsed)
+	require.Equal(t, 2, expired)
+	require.Equal(t, map[uuid.UUID][]uuid.UUID{second: {first}}, eventRepo.collapsed)
+	require.Equal(t, map[string]any{"readingMode": "zen", "currentLocation": "ch-9"}, eventRepo.collapsedPayloads[second])
+}
+
+func TestSyncServiceCompactEvents_StopsAtOldestDeviceCheckpoint(t *testing.T) {
+	userID := uuid.New()
+	checkpoint := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	deviceHorizon := checkpoint.Add(-time.Hour)
+	expireBefore := checkpoint.Add(-90 * 24 * time.Hour)
+
+	eventRepo := newTestSyncEventRepo()
+	checkpointRepo := &testSyncCheckpointRepo{
+		getByUserIDFn: func(ctx context.Context, id uuid.UUID) (*domain.SyncCheckpoint, error) {
+			return &domain.SyncCheckpoint{UserID: id, LastServerTimestamp: checkpoint}, nil
+		},
+	}
+	deviceRepo := &testDeviceRepo{
+		getSyncHorizonFn: func(ctx context.Context, id uuid.UUID, activeSince time.Time) (*time.Time, error) {
+			require.Equal(t, expireBefore, activeSince)
+			return &deviceHorizon, nil
+		},
+	}
+	service := NewSyncService(eventRepo, deviceRepo, newTestSyncTransactor(eventRepo, checkpointRepo), nil)
+
+	eventRepo.deleteBeforeFn = func(ctx context.Context, before time.Time, limit int) (int64, error) {
+		return 0, nil
+	}
+	eventRepo.listCompactableUsersFn = func(ctx context.Context, afterUserID uuid.UUID, limit int) ([]uuid.UUID, error) {
+		return []uuid.UUID{userID}, nil
+	}
+	var until time.Time
+	eventRepo.listSupersededUntilFn = func(ctx context.Context, id uuid.UUID, at time.Time) ([]domain.SyncEvent, error) {
+		until = at
+		return nil, nil
+	}
+
+	_, _, err := service.CompactEvents(context.Background(), expireBefore)
+	require.NoError(t, err)
+	require.Equal(t, deviceHorizon, until)
+}
+
+func TestSyncServiceCompactEvents_SkipsUsersWithoutActiveDevices(t *testing.T) {
+	userID := uuid.New()
+	eventRepo := newTestSyncEventRepo()
+	checkpointRepo := &testSyncCheckpointRepo{
+		getByUserIDFn: func(ctx context.Context, id uuid.UUID) (*domain.SyncCheckpoint, error) {
+			return &domain.SyncCheckpoint{UserID: id, LastServerTimestamp: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}, nil
+		},
+	}
+	deviceRepo := &testDeviceRepo{
+		getSyncHorizonFn: func(ctx context.Context, id uuid.UUID, activeSince time.Time) (*time.Time, error) {
+			return nil, nil
+		},
+	}
+	service := NewSyncService(eventRepo, deviceRepo, newTestSyncTransactor(eventRepo, checkpointRepo), nil)
+
+	eventRepo.deleteBeforeFn = func(ctx context.Context, before time.Time, limit int) (int64, error) {
+		return 0, nil
+	}
+	eventRepo.listCompactableUsersFn = func(ctx context.Context, afterUserID uuid.UUID, limit int) ([]uuid.UUID, error) {
+		return []uuid.UUID{userID}, nil
+	}
+	eventRepo.listSupersededUntilFn = func(ctx context.Context, id uuid.UUID, at time.Time) ([]domain.SyncEvent, error) {
+		t.Fatal("events must not be collapsed without a device horizon")
+		return nil, nil
+	}
+
+	collapsed, _, err := service.CompactEvents(context.Background(), time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Zero(t, collapsed)
+}
+
+func TestSyncServiceSnapshot_ReturnsStateAndCursor(t *testing.T) {
+	userID := uuid.New()
+	eventRepo := newTestSyncEventRepo()
+	transactor := newTestSyncTransactor(eventRepo, &testSyncCheckpointRepo{})
+	service := NewSyncService(eventRepo, nil, transactor, nil)
+
+	latest := domain.SyncEvent{ID: uuid.New(), UserID: userID, ServerTimestamp: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)}
+	eventRepo.getLatestByUserIDFn = func(ctx context.Context, id uuid.UUID) (*domain.SyncEvent, error) {
+		return &latest, nil
+	}
+	bookmark := &domain.Bookmark{ID: uuid.New(), UserID: userID, EbookID: uuid.New(), Location: "ch-1", RowVersion: 2}
+	require.NoError(t, transactor.repos.Bookmark.Store(context.Background(), bookmark))
+
+	snapshot, err := service.Snapshot(context.Background(), userID)
+	require.NoError(t, err)
+	require.Equal(t, EncodeSyncEventCursor(latest), snapshot.Cursor)
+	require.Len(t, snapshot.Bookmarks, 1)
+	require.Equal(t, bookmark.ID, snapshot.Bookmarks[0].ID)
+	require.Empty(t, snapshot.ReadingProgress)
+	require.Empty(t, snapshot.Annotations)
+}
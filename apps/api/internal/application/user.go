@@ -2,8 +2,10 @@ package application
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
@@ -12,21 +14,26 @@ import (
 	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
 	"github.com/jeheskielSunloy77/libra-link/internal/domain"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type UserService interface {
 	ResourceService[domain.User, *applicationdto.StoreUserInput, *applicationdto.UpdateUserInput]
+	// CheckAccess fails when the user no longer exists or is banned or suspended.
+	CheckAccess(ctx context.Context, userID uuid.UUID) error
 }
 
 type userService struct {
 	ResourceService[domain.User, *applicationdto.StoreUserInput, *applicationdto.UpdateUserInput]
-	repo port.UserRepository
+	repo        port.UserRepository
+	accessCache *AccountAccessCache
 }
 
-func NewUserService(repo port.UserRepository) UserService {
+func NewUserService(repo port.UserRepository, accessCache *AccountAccessCache) UserService {
 	return &userService{
 		ResourceService: NewResourceService[domain.User, *applicationdto.StoreUserInput, *applicationdto.UpdateUserInput]("user", repo),
 		repo:            repo,
+		accessCache:     accessCache,
 	}
 }
 
@@ -108,6 +115,42 @@ func (s *userService) Update(ctx context.Context, id uuid.UUID, dto *application
 	return updatedUser, nil
 }
 
+func (s *userService) Destroy(ctx context.Context, id uuid.UUID) error {
+	if err := s.ResourceService.Destroy(ctx, id); err != nil {
+		return err
+	}
+	s.accessCache.evict(ctx, id)
+	return nil
+}
+
+func (s *userService) Kill(ctx context.Context, id uuid.UUID) error {
+	if err := s.ResourceService.Kill(ctx, id); err != nil {
+		return err
+	}
+	s.accessCache.evict(ctx, id)
+	return nil
+}
+
+// CheckAccess runs on every authenticated request, so the user's ban and
+// suspension state is served from the access cache when it is there. A timed
+// suspension is still checked against the current time.
+func (s *userService) CheckAccess(ctx context.Context, userID uuid.UUID) error {
+	now := time.Now().UTC()
+	if access, ok := s.accessCache.get(ctx, userID); ok {
+		return checkAccountAccess(access.user(), now)
+	}
+
+	user, err := s.repo.GetByID(ctx, userID, nil)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errs.NewUnauthorizedError("Unauthorized", false)
+	}
+	if err != nil {
+		return sqlerr.HandleError(err)
+	}
+	s.accessCache.set(ctx, user)
+	return checkAccountAccess(user, now)
+}
+
 func normalizeEmail(email string) string {
 	return strings.ToLower(strings.TrimSpace(email))
 }
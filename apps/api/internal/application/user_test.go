@@ -4,6 +4,7 @@ import (
 	"context"
 	"net/http"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
@@ -22,6 +23,18 @@ func newUserServiceWithRepo(repo repository.UserRepository) UserService {
 	}
 }
 
+type testUserRepo struct {
+	*repository.MockResourceRepository[domain.User]
+}
+
+func newTestUserRepo() *testUserRepo {
+	return &testUserRepo{MockResourceRepository: repository.NewMockResourceRepository[domain.User](false)}
+}
+
+func (r *testUserRepo) GetForUpdate(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	return r.GetByID(ctx, id, nil)
+}
+
 // trackingRepo wraps a UserRepository and records whether GetByID was called.
 type trackingRepo struct {
 	repository.UserRepository
@@ -43,7 +56,7 @@ func ptrString(v string) *string {
 func TestUserServiceStore_HashesPassword(t *testing.T) {
 	ctx := context.Background()
 
-	repo := repository.NewMockResourceRepository[domain.User](false)
+	repo := newTestUserRepo()
 
 	svc := newUserServiceWithRepo(repo)
 
@@ -65,7 +78,7 @@ func TestUserServiceUpdate_NormalizesAndHashes(t *testing.T) {
 	id := uuid.New()
 	existing := &domain.User{ID: id, Email: "old@example.com", Username: "old"}
 
-	repo := repository.NewMockResourceRepository[domain.User](false)
+	repo := newTestUserRepo()
 	// pre-populate existing user
 	require.NoError(t, repo.Store(ctx, existing))
 
@@ -91,7 +104,7 @@ func TestUserServiceUpdate_EmptyUpdates(t *testing.T) {
 	id := uuid.New()
 	existing := &domain.User{ID: id, Email: "old@example.com", Username: "old"}
 
-	repo := repository.NewMockResourceRepository[domain.User](false)
+	repo := newTestUserRepo()
 	require.NoError(t, repo.Store(ctx, existing))
 
 	svc := newUserServiceWithRepo(repo)
@@ -111,7 +124,7 @@ func TestUserServiceUpdate_PasswordTooShort(t *testing.T) {
 	getCalled := false
 
 	// wrap underlying mock to track whether GetByID is called
-	base := repository.NewMockResourceRepository[domain.User](false)
+	base := newTestUserRepo()
 	trepo := &trackingRepo{UserRepository: base, called: &getCalled}
 
 	svc := newUserServiceWithRepo(trepo)
@@ -126,3 +139,36 @@ func TestUserServiceUpdate_PasswordTooShort(t *testing.T) {
 	require.ErrorAs(t, err, &httpErr)
 	require.Equal(t, http.StatusBadRequest, httpErr.Status)
 }
+
+// Ensures CheckAccess lets users in once their suspension ends.
+func TestUserServiceCheckAccess(t *testing.T) {
+	ctx := context.Background()
+	repo := newTestUserRepo()
+	svc := newUserServiceWithRepo(repo)
+
+	now := time.Now().UTC()
+	past, future := now.Add(-time.Hour), now.Add(time.Hour)
+	users := map[string]*domain.User{
+		"active":           {ID: uuid.New()},
+		"suspension_ended": {ID: uuid.New(), SuspendedAt: &past, SuspendedUntil: &past},
+		"suspended":        {ID: uuid.New(), SuspendedAt: &past, SuspendedUntil: &future},
+		"suspended_no_end": {ID: uuid.New(), SuspendedAt: &past},
+		"banned":           {ID: uuid.New(), BannedAt: &past},
+	}
+	for _, user := range users {
+		require.NoError(t, repo.Store(ctx, user))
+	}
+
+	require.NoError(t, svc.CheckAccess(ctx, users["active"].ID))
+	require.NoError(t, svc.CheckAccess(ctx, users["suspension_ended"].ID))
+
+	for _, name := range []string{"suspended", "suspended_no_end", "banned"} {
+		var httpErr *errs.ErrorResponse
+		require.ErrorAs(t, svc.CheckAccess(ctx, users[name].ID), &httpErr, name)
+		require.Equal(t, http.StatusForbidden, httpErr.Status, name)
+	}
+
+	var httpErr *errs.ErrorResponse
+	require.ErrorAs(t, svc.CheckAccess(ctx, uuid.New()), &httpErr)
+	require.Equal(t, http.StatusUnauthorized, httpErr.Status)
+}
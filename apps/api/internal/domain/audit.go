@@ -0,0 +1,25 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditEvent records an administrative action: who took it, what it did and
+// what it was applied to. Events are append-only. ActorUserID is nil for actions
+// the system took on its own, and TargetID for targets without an id, such as
+// authorization policy rules, which Metadata describes.
+type AuditEvent struct {
+	ID          uuid.UUID       `json:"id" gorm:"type:uuid;primaryKey"`
+	ActorUserID *uuid.UUID      `json:"actorUserId,omitempty" gorm:"type:uuid;index"`
+	Action      AuditAction     `json:"action" gorm:"type:audit_action;not null"`
+	TargetType  AuditTargetType `json:"targetType" gorm:"type:audit_target_type;not null"`
+	TargetID    *uuid.UUID      `json:"targetId,omitempty" gorm:"type:uuid"`
+	Metadata    map[string]any  `json:"metadata,omitempty" gorm:"type:jsonb;serializer:json"`
+	CreatedAt   time.Time       `json:"createdAt"`
+}
+
+func (m AuditEvent) GetID() uuid.UUID {
+	return m.ID
+}
@@ -1,9 +1,22 @@
 package domain
 
-import "github.com/golang-jwt/jwt/v5"
+import (
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
 
 type AuthClaims struct {
 	jwt.RegisteredClaims
 	Email   string `json:"email,omitempty"`
 	IsAdmin bool   `json:"is_admin"`
 }
+
+// BorrowLeaseClaims describe an offline-verifiable grant to read a borrowed ebook.
+// The subject is the borrower and the lease expires at the latest with the
+// borrow's due date.
+type BorrowLeaseClaims struct {
+	jwt.RegisteredClaims
+	BorrowID uuid.UUID `json:"borrow_id"`
+	ShareID  uuid.UUID `json:"share_id"`
+	EbookID  uuid.UUID `json:"ebook_id"`
+}
@@ -9,6 +9,7 @@ import (
 type AuthSession struct {
 	ID               uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
 	UserID           uuid.UUID  `json:"userId" gorm:"type:uuid;not null;index"`
+	DeviceID         *uuid.UUID `json:"deviceId,omitempty" gorm:"type:uuid;index"`
 	RefreshTokenHash string     `json:"-" gorm:"not null;uniqueIndex"`
 	UserAgent        *string    `json:"userAgent,omitempty"`
 	IPAddress        *string    `json:"ipAddress,omitempty"`
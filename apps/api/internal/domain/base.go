@@ -5,3 +5,10 @@ import "github.com/google/uuid"
 type BaseModel interface {
 	GetID() uuid.UUID
 }
+
+// VersionedModel is a model whose RowVersion is bumped on every change, so
+// clients can tell whether the row changed since they last read it.
+type VersionedModel interface {
+	BaseModel
+	GetRowVersion() int64
+}
@@ -38,6 +38,8 @@ type Borrow struct {
 	ExpiredAt           *time.Time   `json:"expiredAt,omitempty"`
 	Status              BorrowStatus `json:"status" gorm:"type:borrow_status;not null"`
 	LegalAcknowledgedAt time.Time    `json:"legalAcknowledgedAt" gorm:"not null"`
+	// FlaggedAt is set while the share is taken down pending review.
+	FlaggedAt *time.Time `json:"flaggedAt,omitempty"`
 }
 
 func (m Borrow) GetID() uuid.UUID {
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Device is one installation of a client app signed in as a user. ClientID is
+// the stable id the client generates on first run; the sync fields are the
+// newest position in the user's event stream the device has acknowledged.
+type Device struct {
+	ID                  uuid.UUID  `json:"id" gorm:"type:uuid;primaryKey"`
+	UserID              uuid.UUID  `json:"userId" gorm:"type:uuid;not null;index"`
+	ClientID            string     `json:"clientId" gorm:"not null"`
+	Name                string     `json:"name" gorm:"not null"`
+	LastSeenAt          time.Time  `json:"lastSeenAt" gorm:"not null"`
+	LastSyncedAt        *time.Time `json:"lastSyncedAt,omitempty"`
+	SyncServerTimestamp *time.Time `json:"syncServerTimestamp,omitempty"`
+	SyncEventID         *uuid.UUID `json:"syncEventId,omitempty" gorm:"type:uuid"`
+	RevokedAt           *time.Time `json:"revokedAt,omitempty"`
+	CreatedAt           time.Time  `json:"createdAt"`
+	UpdatedAt           time.Time  `json:"updatedAt"`
+	// Current marks the device that made the request.
+	Current bool `json:"current" gorm:"-"`
+}
+
+func (m Device) GetID() uuid.UUID {
+	return m.ID
+}
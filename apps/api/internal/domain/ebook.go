@@ -23,6 +23,8 @@ type Ebook struct {
 	FileSizeBytes  int64       `json:"fileSizeBytes" gorm:"not null"`
 	ChecksumSHA256 string      `json:"checksumSha256" gorm:"type:varchar(64);not null"`
 	ImportedAt     time.Time   `json:"importedAt" gorm:"not null"`
+	Authors        []Author    `json:"authors,omitempty" gorm:"many2many:ebook_authors"`
+	Tags           []Tag       `json:"tags,omitempty" gorm:"many2many:ebook_tags"`
 }
 
 func (m Ebook) GetID() uuid.UUID {
@@ -68,8 +70,8 @@ type EbookTag struct {
 type EbookGoogleMetadata struct {
 	EbookID       uuid.UUID      `json:"ebookId" gorm:"type:uuid;primaryKey"`
 	GoogleBooksID string         `json:"googleBooksId" gorm:"not null"`
-	ISBN10        *string        `json:"isbn10,omitempty" gorm:"type:varchar(10)"`
-	ISBN13        *string        `json:"isbn13,omitempty" gorm:"type:varchar(13)"`
+	ISBN10        *string        `json:"isbn10,omitempty" gorm:"column:isbn_10;type:varchar(10)"`
+	ISBN13        *string        `json:"isbn13,omitempty" gorm:"column:isbn_13;type:varchar(13)"`
 	Publisher     *string        `json:"publisher,omitempty"`
 	PublishedDate *string        `json:"publishedDate,omitempty"`
 	PageCount     *int           `json:"pageCount,omitempty"`
@@ -85,3 +87,59 @@ type EbookGoogleMetadata struct {
 func (m EbookGoogleMetadata) GetID() uuid.UUID {
 	return m.EbookID
 }
+
+// EbookMetadataSuggestion is a Google Books volume that may describe an ebook.
+// Enrichment stores the likeliest candidates when none matches well enough to
+// be attached on its own, and the owner accepts one of them or dismisses them.
+type EbookMetadataSuggestion struct {
+	ID            uuid.UUID `json:"id" gorm:"type:uuid;primaryKey"`
+	EbookID       uuid.UUID `json:"ebookId" gorm:"type:uuid;not null"`
+	GoogleBooksID string    `json:"googleBooksId" gorm:"not null"`
+	Title         string    `json:"title" gorm:"not null"`
+	Authors       []string  `json:"authors" gorm:"type:jsonb;serializer:json"`
+	PublishedDate *string   `json:"publishedDate,omitempty"`
+	ISBN13        *string   `json:"isbn13,omitempty" gorm:"column:isbn_13;type:varchar(13)"`
+	ThumbnailURL  *string   `json:"thumbnailUrl,omitempty"`
+	// Score is how closely the volume matches the ebook, from 0 to 1.
+	Score     float64   `json:"score" gorm:"not null"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+func (m EbookMetadataSuggestion) GetID() uuid.UUID {
+	return m.ID
+}
+
+// EbookContentIndex records the indexing of an ebook's text. ChecksumSHA256 is
+// the checksum of the file the passages were extracted from, so they are stale
+// once it differs from the ebook's. Error says why a failed file has no text.
+type EbookContentIndex struct {
+	EbookID        uuid.UUID               `json:"ebookId" gorm:"type:uuid;primaryKey"`
+	ChecksumSHA256 string                  `json:"checksumSha256" gorm:"type:char(64);not null"`
+	Status         EbookContentIndexStatus `json:"status" gorm:"type:ebook_content_index_status;not null"`
+	PassageCount   int                     `json:"passageCount" gorm:"not null"`
+	Error          *string                 `json:"error,omitempty"`
+	IndexedAt      time.Time               `json:"indexedAt" gorm:"not null"`
+}
+
+func (EbookContentIndex) TableName() string {
+	return "ebook_content_indexes"
+}
+
+// EbookPassage is a passage of an ebook's text. Location is the reader's
+// location token of where it starts.
+type EbookPassage struct {
+	EbookID  uuid.UUID `json:"ebookId" gorm:"type:uuid;primaryKey"`
+	Position int       `json:"position" gorm:"primaryKey"`
+	Location string    `json:"location" gorm:"not null"`
+	Content  string    `json:"content" gorm:"not null"`
+}
+
+// EbookPassageHit is a passage of an ebook that matches a search inside it.
+// Snippet is the matched part of the passage, highlighted like a SearchHit's.
+type EbookPassageHit struct {
+	Position int    `json:"position"`
+	Location string `json:"location"`
+	Snippet  string `json:"snippet"`
+	// Rank is how well the passage matches, from 0 to 1.
+	Rank float64 `json:"rank"`
+}
@@ -90,3 +90,35 @@ const (
 	SyncOperationUpsert SyncOperation = "upsert"
 	SyncOperationDelete SyncOperation = "delete"
 )
+
+type AuditAction string
+
+const (
+	AuditActionReportResolved             AuditAction = "report_resolved"
+	AuditActionReportRejected             AuditAction = "report_rejected"
+	AuditActionShareTakenDown             AuditAction = "share_taken_down"
+	AuditActionShareReinstated            AuditAction = "share_reinstated"
+	AuditActionBorrowRevoked              AuditAction = "borrow_revoked"
+	AuditActionAuthorizationPolicyAdded   AuditAction = "authorization_policy_added"
+	AuditActionAuthorizationPolicyRemoved AuditAction = "authorization_policy_removed"
+	AuditActionUserSuspended              AuditAction = "user_suspended"
+	AuditActionUserBanned                 AuditAction = "user_banned"
+	AuditActionUserReinstated             AuditAction = "user_reinstated"
+)
+
+type AuditTargetType string
+
+const (
+	AuditTargetTypeShareReport         AuditTargetType = "share_report"
+	AuditTargetTypeShare               AuditTargetType = "share"
+	AuditTargetTypeBorrow              AuditTargetType = "borrow"
+	AuditTargetTypeAuthorizationPolicy AuditTargetType = "authorization_policy"
+	AuditTargetTypeUser                AuditTargetType = "user"
+)
+
+type EbookContentIndexStatus string
+
+const (
+	EbookContentIndexStatusIndexed EbookContentIndexStatus = "indexed"
+	EbookContentIndexStatusFailed  EbookContentIndexStatus = "failed"
+)
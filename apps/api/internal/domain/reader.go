@@ -23,6 +23,10 @@ func (m UserPreferences) GetID() uuid.UUID {
 	return m.UserID
 }
 
+func (m UserPreferences) GetRowVersion() int64 {
+	return m.RowVersion
+}
+
 func (UserPreferences) TableName() string {
 	return "user_preferences"
 }
@@ -42,6 +46,10 @@ func (m UserReaderState) GetID() uuid.UUID {
 	return m.UserID
 }
 
+func (m UserReaderState) GetRowVersion() int64 {
+	return m.RowVersion
+}
+
 func (UserReaderState) TableName() string {
 	return "user_reader_state"
 }
@@ -64,6 +72,10 @@ func (m ReadingProgress) GetID() uuid.UUID {
 	return m.ID
 }
 
+func (m ReadingProgress) GetRowVersion() int64 {
+	return m.RowVersion
+}
+
 type Bookmark struct {
 	ID         uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey"`
 	CreatedAt  time.Time      `json:"createdAt"`
@@ -80,6 +92,10 @@ func (m Bookmark) GetID() uuid.UUID {
 	return m.ID
 }
 
+func (m Bookmark) GetRowVersion() int64 {
+	return m.RowVersion
+}
+
 type Annotation struct {
 	ID            uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey"`
 	CreatedAt     time.Time      `json:"createdAt"`
@@ -99,6 +115,10 @@ func (m Annotation) GetID() uuid.UUID {
 	return m.ID
 }
 
+func (m Annotation) GetRowVersion() int64 {
+	return m.RowVersion
+}
+
 type SyncEvent struct {
 	ID              uuid.UUID      `json:"id" gorm:"type:uuid;primaryKey"`
 	UserID          uuid.UUID      `json:"userId" gorm:"type:uuid;not null;index"`
@@ -0,0 +1,33 @@
+package domain
+
+import "github.com/google/uuid"
+
+type SearchScope string
+
+const (
+	// SearchScopeLibrary searches the ebooks of the user.
+	SearchScopeLibrary SearchScope = "library"
+	// SearchScopeCommunity searches the public shares of every user.
+	SearchScopeCommunity SearchScope = "community"
+)
+
+// SearchHighlightStart and SearchHighlightStop enclose the matched words in the
+// headline and snippet of a SearchHit.
+const (
+	SearchHighlightStart = "<mark>"
+	SearchHighlightStop  = "</mark>"
+)
+
+// SearchHit is an ebook, or a share in the community scope, that matches a
+// full-text search. Headline is its title and Snippet the passages of its
+// authors, description, tags and metadata that matched, both highlighted.
+type SearchHit struct {
+	Scope    SearchScope `json:"scope"`
+	EbookID  uuid.UUID   `json:"ebookId"`
+	ShareID  *uuid.UUID  `json:"shareId,omitempty"`
+	Title    string      `json:"title"`
+	Headline string      `json:"headline"`
+	Snippet  string      `json:"snippet"`
+	// Rank is how well the row matches, from 0 to 1.
+	Rank float64 `json:"rank"`
+}
@@ -21,8 +21,25 @@ type User struct {
 	EmailVerifiedAt *time.Time `json:"emailVerifiedAt,omitempty"`
 	LastLoginAt     *time.Time `json:"lastLoginAt,omitempty"`
 	IsAdmin         bool       `json:"isAdmin" gorm:"not null;default:false"`
+
+	// A suspension without SuspendedUntil lasts until an admin lifts it.
+	SuspendedAt      *time.Time `json:"suspendedAt,omitempty"`
+	SuspendedUntil   *time.Time `json:"suspendedUntil,omitempty"`
+	SuspensionReason *string    `json:"suspensionReason,omitempty"`
+	BannedAt         *time.Time `json:"bannedAt,omitempty"`
+	BanReason        *string    `json:"banReason,omitempty"`
 }
 
 func (m User) GetID() uuid.UUID {
 	return m.ID
 }
+
+// IsBanned reports whether the user is permanently banned.
+func (m User) IsBanned() bool {
+	return m.BannedAt != nil
+}
+
+// IsSuspended reports whether a suspension of the user is in effect at now.
+func (m User) IsSuspended(now time.Time) bool {
+	return m.SuspendedAt != nil && (m.SuspendedUntil == nil || now.Before(*m.SuspendedUntil))
+}
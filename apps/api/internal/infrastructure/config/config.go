@@ -1,12 +1,17 @@
 package config
 
 import (
+	"crypto/hkdf"
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
 	"github.com/go-playground/validator/v10"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
 	_ "github.com/joho/godotenv/autoload"
 	"github.com/knadh/koanf/providers/env"
 	"github.com/knadh/koanf/v2"
@@ -23,8 +28,15 @@ type Config struct {
 	SMTP          SMTPConfig           `koanf:"smtp" validate:"required"`
 	Observability *ObservabilityConfig `koanf:"observability"`
 	Seeder        SeederConfig         `koanf:"seeder" validate:"required"`
+	Jobs          JobsConfig           `koanf:"jobs"`
+	Moderation    ModerationConfig     `koanf:"moderation"`
+	GoogleBooks   GoogleBooksConfig    `koanf:"google_books"`
 }
 
+// StaticDir is the directory served publicly under /static. Local file storage
+// must not live inside it, or uploads become readable without a signed URL.
+const StaticDir = "static"
+
 type Env string
 
 const (
@@ -82,13 +94,16 @@ type FileStorageConfig struct {
 	MaxUploadSizeMB  int                    `koanf:"max_upload_size_mb"`
 	AllowedMimeTypes []string               `koanf:"allowed_mime_types"`
 	PathPrefix       string                 `koanf:"path_prefix"`
+	SignedURLTTL     time.Duration          `koanf:"signed_url_ttl"`
 	Local            FileStorageLocalConfig `koanf:"local"`
 	S3               FileStorageS3Config    `koanf:"s3"`
 }
 
 type FileStorageLocalConfig struct {
-	BaseDir    string `koanf:"base_dir"`
-	PublicPath string `koanf:"public_path"`
+	BaseDir       string `koanf:"base_dir"`
+	PublicPath    string `koanf:"public_path"`
+	SignedPath    string `koanf:"signed_path"`
+	SigningSecret string `koanf:"signing_secret"`
 }
 
 type FileStorageS3Config struct {
@@ -101,6 +116,54 @@ type FileStorageS3Config struct {
 	ForcePathStyle  bool   `koanf:"force_path_style"`
 }
 
+type JobsConfig struct {
+	BorrowExpiryInterval   time.Duration `koanf:"borrow_expiry_interval"`
+	SyncCompactionInterval time.Duration `koanf:"sync_compaction_interval"`
+	SyncEventRetention     time.Duration `koanf:"sync_event_retention"`
+}
+
+type ModerationConfig struct {
+	TakedownThresholds TakedownThresholdsConfig `koanf:"takedown_thresholds"`
+}
+
+// TakedownThresholdsConfig is how many distinct users must have an open report
+// of a reason against a share before it is disabled pending review. A threshold
+// of 0 turns automatic takedown off for its reason.
+type TakedownThresholdsConfig struct {
+	Copyright int `koanf:"copyright"`
+	Abuse     int `koanf:"abuse"`
+	Spam      int `koanf:"spam"`
+	Other     int `koanf:"other"`
+}
+
+// ForReason returns the threshold of a report reason, or 0 for unknown reasons.
+func (c TakedownThresholdsConfig) ForReason(reason domain.ReportReason) int {
+	switch reason {
+	case domain.ReportReasonCopyright:
+		return c.Copyright
+	case domain.ReportReasonAbuse:
+		return c.Abuse
+	case domain.ReportReasonSpam:
+		return c.Spam
+	case domain.ReportReasonOther:
+		return c.Other
+	default:
+		return 0
+	}
+}
+
+// GoogleBooksConfig configures lookups against the Google Books API. Failed
+// requests are retried up to MaxRetries times, waiting RetryBackoff and then
+// doubling it before each retry.
+type GoogleBooksConfig struct {
+	BaseURL      string        `koanf:"base_url"`
+	APIKey       string        `koanf:"api_key"`
+	Timeout      time.Duration `koanf:"timeout"`
+	CacheTTL     time.Duration `koanf:"cache_ttl"`
+	MaxRetries   int           `koanf:"max_retries"`
+	RetryBackoff time.Duration `koanf:"retry_backoff"`
+}
+
 type IntegrationConfig struct {
 	SMTP SMTPConfig `koanf:"smtp" validate:"required"`
 }
@@ -136,6 +199,8 @@ type AuthConfig struct {
 	RefreshCookieName        string         `koanf:"refresh_cookie_name" validate:"required"`
 	CookieDomain             string         `koanf:"cookie_domain"`
 	CookieSameSite           CookieSameSite `koanf:"cookie_same_site" validate:"required,oneof=lax strict none"`
+	BorrowLeaseSecret        string         `koanf:"borrow_lease_secret"`
+	BorrowLeaseTTL           time.Duration  `koanf:"borrow_lease_ttl"`
 }
 
 func LoadConfig() (*Config, error) {
@@ -168,6 +233,11 @@ func LoadConfig() (*Config, error) {
 		logger.Fatal().Err(err).Msg("file storage config validation failed")
 	}
 
+	setAuthDefaults(mainConfig)
+	setJobsDefaults(mainConfig)
+	setModerationDefaults(mainConfig, k)
+	setGoogleBooksDefaults(mainConfig)
+
 	// Set default observability config if not provided
 	if mainConfig.Observability == nil {
 		mainConfig.Observability = DefaultObservabilityConfig()
@@ -201,6 +271,66 @@ func LoadConfig() (*Config, error) {
 	return mainConfig, nil
 }
 
+func setAuthDefaults(cfg *Config) {
+	if strings.TrimSpace(cfg.Auth.BorrowLeaseSecret) == "" {
+		cfg.Auth.BorrowLeaseSecret = cfg.Auth.SecretKey
+	}
+	if cfg.Auth.BorrowLeaseTTL <= 0 {
+		cfg.Auth.BorrowLeaseTTL = time.Hour
+	}
+}
+
+func setJobsDefaults(cfg *Config) {
+	if cfg.Jobs.BorrowExpiryInterval <= 0 {
+		cfg.Jobs.BorrowExpiryInterval = time.Minute
+	}
+	if cfg.Jobs.SyncCompactionInterval <= 0 {
+		cfg.Jobs.SyncCompactionInterval = time.Hour
+	}
+	if cfg.Jobs.SyncEventRetention <= 0 {
+		cfg.Jobs.SyncEventRetention = 90 * 24 * time.Hour
+	}
+}
+
+// setModerationDefaults only fills in thresholds that were not configured, so
+// setting one to 0 keeps automatic takedown off for its reason.
+func setModerationDefaults(cfg *Config, k *koanf.Koanf) {
+	thresholds := &cfg.Moderation.TakedownThresholds
+	for _, threshold := range []struct {
+		key      string
+		value    *int
+		fallback int
+	}{
+		{"copyright", &thresholds.Copyright, 3},
+		{"abuse", &thresholds.Abuse, 5},
+		{"spam", &thresholds.Spam, 10},
+		{"other", &thresholds.Other, 10},
+	} {
+		if !k.Exists("moderation.takedown_thresholds." + threshold.key) {
+			*threshold.value = threshold.fallback
+		}
+	}
+}
+
+func setGoogleBooksDefaults(cfg *Config) {
+	googleBooks := &cfg.GoogleBooks
+	if strings.TrimSpace(googleBooks.BaseURL) == "" {
+		googleBooks.BaseURL = "https://www.googleapis.com/books/v1"
+	}
+	if googleBooks.Timeout <= 0 {
+		googleBooks.Timeout = 5 * time.Second
+	}
+	if googleBooks.CacheTTL <= 0 {
+		googleBooks.CacheTTL = 24 * time.Hour
+	}
+	if googleBooks.MaxRetries <= 0 {
+		googleBooks.MaxRetries = 2
+	}
+	if googleBooks.RetryBackoff <= 0 {
+		googleBooks.RetryBackoff = 200 * time.Millisecond
+	}
+}
+
 func validateFileStorageConfig(cfg *Config) error {
 	if cfg == nil {
 		return nil
@@ -222,13 +352,34 @@ func validateFileStorageConfig(cfg *Config) error {
 		return fmt.Errorf("file storage allowed_mime_types cannot be empty")
 	}
 
+	if cfg.FileStorage.SignedURLTTL <= 0 {
+		cfg.FileStorage.SignedURLTTL = 15 * time.Minute
+	}
+
 	if cfg.FileStorage.Provider == "local" {
 		if strings.TrimSpace(cfg.FileStorage.Local.BaseDir) == "" {
 			return fmt.Errorf("file storage local.base_dir is required")
 		}
+		overlaps, err := pathsOverlap(cfg.FileStorage.Local.BaseDir, StaticDir)
+		if err != nil {
+			return fmt.Errorf("file storage local.base_dir: %w", err)
+		}
+		if overlaps {
+			return fmt.Errorf("file storage local.base_dir must not overlap the public %q directory", StaticDir)
+		}
 		if strings.TrimSpace(cfg.FileStorage.Local.PublicPath) == "" {
 			return fmt.Errorf("file storage local.public_path is required")
 		}
+		if strings.TrimSpace(cfg.FileStorage.Local.SignedPath) == "" {
+			cfg.FileStorage.Local.SignedPath = "/files"
+		}
+		if strings.TrimSpace(cfg.FileStorage.Local.SigningSecret) == "" {
+			secret, err := deriveSecret(cfg.Auth.SecretKey, "libra-link file storage signed urls")
+			if err != nil {
+				return fmt.Errorf("file storage local.signing_secret: %w", err)
+			}
+			cfg.FileStorage.Local.SigningSecret = secret
+		}
 	}
 
 	if cfg.FileStorage.Provider == "s3" {
@@ -242,3 +393,38 @@ func validateFileStorageConfig(cfg *Config) error {
 
 	return nil
 }
+
+// pathsOverlap reports whether one of the two directories is, or lies inside,
+// the other.
+func pathsOverlap(a, b string) (bool, error) {
+	absA, err := filepath.Abs(a)
+	if err != nil {
+		return false, err
+	}
+	absB, err := filepath.Abs(b)
+	if err != nil {
+		return false, err
+	}
+	return within(absA, absB) || within(absB, absA), nil
+}
+
+func within(dir, parent string) bool {
+	rel, err := filepath.Rel(parent, dir)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// deriveSecret derives a key for one purpose from the auth secret key, so that a
+// key leaked from one use neither reveals the auth secret nor works for another.
+func deriveSecret(secret, label string) (string, error) {
+	if strings.TrimSpace(secret) == "" {
+		return "", fmt.Errorf("auth.secret_key is required to derive a key")
+	}
+	key, err := hkdf.Key(sha256.New, []byte(secret), nil, label, 32)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(key), nil
+}
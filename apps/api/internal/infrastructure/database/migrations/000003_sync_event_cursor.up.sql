@@ -0,0 +1,3 @@
+CREATE INDEX IF NOT EXISTS idx_sync_events_user_server_timestamp_id_asc ON sync_events (user_id, server_timestamp ASC, id ASC);
+
+DROP INDEX IF EXISTS idx_sync_events_user_server_timestamp_asc;
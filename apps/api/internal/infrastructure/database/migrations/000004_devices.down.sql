@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_auth_sessions_device_id;
+ALTER TABLE auth_sessions DROP COLUMN IF EXISTS device_id;
+
+DROP INDEX IF EXISTS idx_devices_user_last_seen_at_desc;
+DROP INDEX IF EXISTS uq_devices_user_client_id;
+DROP TABLE IF EXISTS devices;
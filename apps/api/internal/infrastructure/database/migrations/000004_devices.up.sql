@@ -0,0 +1,19 @@
+CREATE TABLE IF NOT EXISTS devices (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    client_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    last_seen_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_synced_at TIMESTAMPTZ,
+    sync_server_timestamp TIMESTAMPTZ,
+    sync_event_id UUID,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS uq_devices_user_client_id ON devices (user_id, client_id);
+CREATE INDEX IF NOT EXISTS idx_devices_user_last_seen_at_desc ON devices (user_id, last_seen_at DESC);
+
+ALTER TABLE auth_sessions ADD COLUMN IF NOT EXISTS device_id UUID REFERENCES devices(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_auth_sessions_device_id ON auth_sessions (device_id);
@@ -0,0 +1,6 @@
+DELETE FROM casbin_rule
+WHERE ptype = 'p' AND (v0, v1, v2, v3) IN (
+    ('true', 'keyMatch(r.obj.Route, "/api/v1/*")', 'true', 'allow'),
+    ('true', 'keyMatch(r.obj.Route, "/api/v1/admin/*")', 'true', 'deny'),
+    ('true', 'regexMatch(r.obj.Route, "^/api/v1/users/?(:id(/kill|/restore)?)?$")', 'true', 'deny')
+);
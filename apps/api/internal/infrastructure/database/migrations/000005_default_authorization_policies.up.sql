@@ -0,0 +1,15 @@
+-- Default authorization policy, version 1. Admins bypass the policy; every other
+-- user may call any API route except the admin and user management ones.
+-- Rules may be changed at runtime, so later versions add or remove single rules
+-- instead of replacing the set.
+INSERT INTO casbin_rule (ptype, v0, v1, v2, v3, v4, v5)
+SELECT 'p', rule.v0, rule.v1, rule.v2, rule.v3, '', ''
+FROM (VALUES
+    ('true', 'keyMatch(r.obj.Route, "/api/v1/*")', 'true', 'allow'),
+    ('true', 'keyMatch(r.obj.Route, "/api/v1/admin/*")', 'true', 'deny'),
+    ('true', 'regexMatch(r.obj.Route, "^/api/v1/users/?(:id(/kill|/restore)?)?$")', 'true', 'deny')
+) AS rule (v0, v1, v2, v3)
+WHERE NOT EXISTS (
+    SELECT 1 FROM casbin_rule
+    WHERE ptype = 'p' AND v0 = rule.v0 AND v1 = rule.v1 AND v2 = rule.v2 AND v3 = rule.v3
+);
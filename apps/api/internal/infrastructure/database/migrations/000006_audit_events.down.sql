@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS trg_audit_events_append_only ON audit_events;
+DROP FUNCTION IF EXISTS prevent_audit_event_change;
+DROP INDEX IF EXISTS idx_audit_events_target_created_at;
+DROP INDEX IF EXISTS idx_audit_events_actor_created_at;
+DROP INDEX IF EXISTS idx_audit_events_created_at_desc;
+DROP TABLE IF EXISTS audit_events;
+DROP TYPE IF EXISTS audit_target_type;
+DROP TYPE IF EXISTS audit_action;
@@ -0,0 +1,45 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'audit_action') THEN
+        CREATE TYPE audit_action AS ENUM (
+            'report_resolved',
+            'report_rejected',
+            'share_taken_down',
+            'borrow_revoked',
+            'authorization_policy_added',
+            'authorization_policy_removed'
+        );
+    END IF;
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'audit_target_type') THEN
+        CREATE TYPE audit_target_type AS ENUM ('share_report', 'share', 'borrow', 'authorization_policy');
+    END IF;
+END
+$$;
+
+-- Actors are not referenced, so the trail outlives the users it mentions.
+CREATE TABLE IF NOT EXISTS audit_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    actor_user_id UUID NOT NULL,
+    action audit_action NOT NULL,
+    target_type audit_target_type NOT NULL,
+    target_id UUID,
+    metadata JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_audit_events_created_at_desc ON audit_events (created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_actor_created_at ON audit_events (actor_user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_audit_events_target_created_at ON audit_events (target_type, target_id, created_at DESC);
+
+CREATE OR REPLACE FUNCTION prevent_audit_event_change()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit events are append-only' USING ERRCODE = '23514';
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_audit_events_append_only ON audit_events;
+CREATE TRIGGER trg_audit_events_append_only
+BEFORE UPDATE OR DELETE ON audit_events
+FOR EACH ROW
+EXECUTE FUNCTION prevent_audit_event_change();
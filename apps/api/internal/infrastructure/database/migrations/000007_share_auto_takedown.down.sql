@@ -0,0 +1,10 @@
+DROP INDEX IF EXISTS idx_share_reports_share_reason_pending;
+
+ALTER TABLE borrows DROP COLUMN IF EXISTS flagged_at;
+
+ALTER TABLE audit_events DISABLE TRIGGER trg_audit_events_append_only;
+DELETE FROM audit_events WHERE actor_user_id IS NULL;
+ALTER TABLE audit_events ENABLE TRIGGER trg_audit_events_append_only;
+ALTER TABLE audit_events ALTER COLUMN actor_user_id SET NOT NULL;
+
+-- Postgres cannot drop an enum value, so 'share_reinstated' stays in audit_action.
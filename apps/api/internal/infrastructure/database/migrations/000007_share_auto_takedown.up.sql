@@ -0,0 +1,8 @@
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'share_reinstated' AFTER 'share_taken_down';
+
+-- Automatic takedowns are taken by the system, not a user.
+ALTER TABLE audit_events ALTER COLUMN actor_user_id DROP NOT NULL;
+
+ALTER TABLE borrows ADD COLUMN IF NOT EXISTS flagged_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_share_reports_share_reason_pending ON share_reports (share_id, reason) WHERE status IN ('open', 'in_review');
@@ -0,0 +1,9 @@
+ALTER TABLE users
+    DROP COLUMN IF EXISTS ban_reason,
+    DROP COLUMN IF EXISTS banned_at,
+    DROP COLUMN IF EXISTS suspension_reason,
+    DROP COLUMN IF EXISTS suspended_until,
+    DROP COLUMN IF EXISTS suspended_at;
+
+-- Postgres cannot drop an enum value, so the user actions and the 'user' target
+-- type stay in audit_action and audit_target_type.
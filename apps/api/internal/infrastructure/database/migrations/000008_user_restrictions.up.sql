@@ -0,0 +1,11 @@
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'user_suspended';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'user_banned';
+ALTER TYPE audit_action ADD VALUE IF NOT EXISTS 'user_reinstated';
+ALTER TYPE audit_target_type ADD VALUE IF NOT EXISTS 'user';
+
+ALTER TABLE users
+    ADD COLUMN IF NOT EXISTS suspended_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS suspended_until TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS suspension_reason TEXT,
+    ADD COLUMN IF NOT EXISTS banned_at TIMESTAMPTZ,
+    ADD COLUMN IF NOT EXISTS ban_reason TEXT;
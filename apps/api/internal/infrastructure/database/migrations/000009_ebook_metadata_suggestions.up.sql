@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS ebook_metadata_suggestions (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    ebook_id UUID NOT NULL REFERENCES ebooks(id) ON DELETE CASCADE,
+    google_books_id TEXT NOT NULL,
+    title TEXT NOT NULL,
+    authors JSONB,
+    published_date TEXT,
+    isbn_13 VARCHAR(13),
+    thumbnail_url TEXT,
+    score DOUBLE PRECISION NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT uq_ebook_metadata_suggestions_ebook_volume UNIQUE (ebook_id, google_books_id)
+);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_tags_name_active;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_tags_name_active ON tags (name) WHERE deleted_at IS NULL;
+
+DROP INDEX IF EXISTS idx_authors_name_active;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_authors_name_active ON authors (name) WHERE deleted_at IS NULL;
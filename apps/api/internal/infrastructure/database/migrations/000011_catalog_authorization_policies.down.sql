@@ -0,0 +1,4 @@
+DELETE FROM casbin_rule
+WHERE ptype = 'p' AND (v0, v1, v2, v3) IN (
+    ('true', 'regexMatch(r.obj.Route, "^/api/v1/(authors|tags)/:id(/kill|/restore)?$")', 'r.act != "GET"', 'deny')
+);
@@ -0,0 +1,12 @@
+-- Default authorization policy, version 2. Authors and tags are shared by every
+-- user, so only admins may rename, delete or restore them; users may still list
+-- and create them.
+INSERT INTO casbin_rule (ptype, v0, v1, v2, v3, v4, v5)
+SELECT 'p', rule.v0, rule.v1, rule.v2, rule.v3, '', ''
+FROM (VALUES
+    ('true', 'regexMatch(r.obj.Route, "^/api/v1/(authors|tags)/:id(/kill|/restore)?$")', 'r.act != "GET"', 'deny')
+) AS rule (v0, v1, v2, v3)
+WHERE NOT EXISTS (
+    SELECT 1 FROM casbin_rule
+    WHERE ptype = 'p' AND v0 = rule.v0 AND v1 = rule.v1 AND v2 = rule.v2 AND v3 = rule.v3
+);
@@ -0,0 +1,25 @@
+DROP TRIGGER IF EXISTS trg_tags_search_vector ON tags;
+DROP FUNCTION IF EXISTS tags_search_vector_update;
+DROP TRIGGER IF EXISTS trg_authors_search_vector ON authors;
+DROP FUNCTION IF EXISTS authors_search_vector_update;
+DROP TRIGGER IF EXISTS trg_ebook_google_metadata_search_vector ON ebook_google_metadata;
+DROP TRIGGER IF EXISTS trg_ebook_tags_search_vector ON ebook_tags;
+DROP TRIGGER IF EXISTS trg_ebook_authors_search_vector ON ebook_authors;
+DROP FUNCTION IF EXISTS ebook_relations_search_vector_update;
+DROP TRIGGER IF EXISTS trg_shares_search_vector ON shares;
+DROP FUNCTION IF EXISTS shares_search_vector_update;
+DROP TRIGGER IF EXISTS trg_ebooks_language_share_search_vector ON ebooks;
+DROP FUNCTION IF EXISTS ebooks_language_share_search_vector_update;
+DROP TRIGGER IF EXISTS trg_ebooks_search_vector ON ebooks;
+DROP FUNCTION IF EXISTS ebooks_search_vector_update;
+
+DROP FUNCTION IF EXISTS refresh_ebook_search_vector;
+DROP FUNCTION IF EXISTS share_search_document;
+DROP FUNCTION IF EXISTS ebook_search_document;
+DROP FUNCTION IF EXISTS ebook_metadata_terms;
+DROP FUNCTION IF EXISTS ebook_tag_names;
+DROP FUNCTION IF EXISTS ebook_author_names;
+DROP FUNCTION IF EXISTS search_config;
+
+ALTER TABLE shares DROP COLUMN IF EXISTS search_vector;
+ALTER TABLE ebooks DROP COLUMN IF EXISTS search_vector;
@@ -0,0 +1,216 @@
+-- Full-text search over ebooks and community shares. Each row is indexed with
+-- the text search configuration of its ebook's language, and queries are parsed
+-- with the same configuration row by row, so stemming matches the book's
+-- language. The vectors are kept up to date by triggers, since ebooks are
+-- indexed along with their authors, tags and Google Books metadata.
+
+ALTER TABLE ebooks ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+ALTER TABLE shares ADD COLUMN IF NOT EXISTS search_vector TSVECTOR;
+
+CREATE OR REPLACE FUNCTION search_config(p_language_code TEXT)
+RETURNS regconfig AS $$
+    SELECT (CASE lower(split_part(replace(coalesce(p_language_code, ''), '_', '-'), '-', 1))
+        WHEN 'ar' THEN 'arabic'
+        WHEN 'da' THEN 'danish'
+        WHEN 'de' THEN 'german'
+        WHEN 'el' THEN 'greek'
+        WHEN 'en' THEN 'english'
+        WHEN 'es' THEN 'spanish'
+        WHEN 'fi' THEN 'finnish'
+        WHEN 'fr' THEN 'french'
+        WHEN 'ga' THEN 'irish'
+        WHEN 'hu' THEN 'hungarian'
+        WHEN 'id' THEN 'indonesian'
+        WHEN 'it' THEN 'italian'
+        WHEN 'lt' THEN 'lithuanian'
+        WHEN 'nb' THEN 'norwegian'
+        WHEN 'ne' THEN 'nepali'
+        WHEN 'nl' THEN 'dutch'
+        WHEN 'nn' THEN 'norwegian'
+        WHEN 'no' THEN 'norwegian'
+        WHEN 'pt' THEN 'portuguese'
+        WHEN 'ro' THEN 'romanian'
+        WHEN 'ru' THEN 'russian'
+        WHEN 'sr' THEN 'serbian'
+        WHEN 'sv' THEN 'swedish'
+        WHEN 'ta' THEN 'tamil'
+        WHEN 'tr' THEN 'turkish'
+        ELSE 'simple'
+    END)::regconfig;
+$$ LANGUAGE sql IMMUTABLE;
+
+CREATE OR REPLACE FUNCTION ebook_author_names(p_ebook_id UUID)
+RETURNS TEXT AS $$
+    SELECT string_agg(a.name, ', ' ORDER BY a.name)
+    FROM ebook_authors ea
+    JOIN authors a ON a.id = ea.author_id
+    WHERE ea.ebook_id = p_ebook_id
+      AND a.deleted_at IS NULL;
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION ebook_tag_names(p_ebook_id UUID)
+RETURNS TEXT AS $$
+    SELECT string_agg(t.name, ', ' ORDER BY t.name)
+    FROM ebook_tags et
+    JOIN tags t ON t.id = et.tag_id
+    WHERE et.ebook_id = p_ebook_id
+      AND t.deleted_at IS NULL;
+$$ LANGUAGE sql STABLE;
+
+-- ebook_metadata_terms returns the categories and publisher of the Google Books
+-- metadata attached to the ebook.
+CREATE OR REPLACE FUNCTION ebook_metadata_terms(p_ebook_id UUID)
+RETURNS TEXT AS $$
+    SELECT nullif(concat_ws(', ',
+        (
+            SELECT string_agg(category, ', ')
+            FROM jsonb_array_elements_text(CASE WHEN jsonb_typeof(m.categories) = 'array' THEN m.categories ELSE '[]'::jsonb END) AS category
+        ),
+        m.publisher
+    ), '')
+    FROM ebook_google_metadata m
+    WHERE m.ebook_id = p_ebook_id
+      AND m.deleted_at IS NULL;
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION ebook_search_document(p_ebook_id UUID, p_title TEXT, p_description TEXT, p_language_code TEXT)
+RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector(cfg, coalesce(p_title, '')), 'A')
+        || setweight(to_tsvector(cfg, coalesce(ebook_author_names(p_ebook_id), '')), 'B')
+        || setweight(to_tsvector(cfg, concat_ws(' ', ebook_tag_names(p_ebook_id), ebook_metadata_terms(p_ebook_id))), 'C')
+        || setweight(to_tsvector(cfg, coalesce(p_description, '')), 'D')
+    FROM search_config(p_language_code) AS cfg;
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION share_search_document(p_ebook_id UUID, p_title_override TEXT, p_description TEXT)
+RETURNS tsvector AS $$
+    SELECT setweight(to_tsvector(cfg, coalesce(p_title_override, '')), 'A')
+        || setweight(to_tsvector(cfg, coalesce(p_description, '')), 'D')
+    FROM ebooks e
+    CROSS JOIN LATERAL search_config(e.language_code) AS cfg
+    WHERE e.id = p_ebook_id;
+$$ LANGUAGE sql STABLE;
+
+CREATE OR REPLACE FUNCTION refresh_ebook_search_vector(p_ebook_id UUID)
+RETURNS VOID AS $$
+    UPDATE ebooks
+    SET search_vector = ebook_search_document(id, title, description, language_code)
+    WHERE id = p_ebook_id;
+$$ LANGUAGE sql;
+
+CREATE OR REPLACE FUNCTION ebooks_search_vector_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector := ebook_search_document(NEW.id, NEW.title, NEW.description, NEW.language_code);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_ebooks_search_vector ON ebooks;
+CREATE TRIGGER trg_ebooks_search_vector
+BEFORE INSERT OR UPDATE OF title, description, language_code ON ebooks
+FOR EACH ROW
+EXECUTE FUNCTION ebooks_search_vector_update();
+
+-- Shares are indexed with the configuration of their ebook's language, so they
+-- follow it when it changes.
+CREATE OR REPLACE FUNCTION ebooks_language_share_search_vector_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE shares
+    SET search_vector = share_search_document(ebook_id, title_override, description)
+    WHERE ebook_id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_ebooks_language_share_search_vector ON ebooks;
+CREATE TRIGGER trg_ebooks_language_share_search_vector
+AFTER UPDATE OF language_code ON ebooks
+FOR EACH ROW
+WHEN (OLD.language_code IS DISTINCT FROM NEW.language_code)
+EXECUTE FUNCTION ebooks_language_share_search_vector_update();
+
+CREATE OR REPLACE FUNCTION shares_search_vector_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector := share_search_document(NEW.ebook_id, NEW.title_override, NEW.description);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_shares_search_vector ON shares;
+CREATE TRIGGER trg_shares_search_vector
+BEFORE INSERT OR UPDATE OF ebook_id, title_override, description ON shares
+FOR EACH ROW
+EXECUTE FUNCTION shares_search_vector_update();
+
+-- ebook_relations_search_vector_update reindexes an ebook when its author or
+-- tag links or its Google Books metadata change.
+CREATE OR REPLACE FUNCTION ebook_relations_search_vector_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP <> 'INSERT' THEN
+        PERFORM refresh_ebook_search_vector(OLD.ebook_id);
+    END IF;
+    IF TG_OP = 'INSERT' OR (TG_OP = 'UPDATE' AND NEW.ebook_id IS DISTINCT FROM OLD.ebook_id) THEN
+        PERFORM refresh_ebook_search_vector(NEW.ebook_id);
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_ebook_authors_search_vector ON ebook_authors;
+CREATE TRIGGER trg_ebook_authors_search_vector
+AFTER INSERT OR UPDATE OR DELETE ON ebook_authors
+FOR EACH ROW
+EXECUTE FUNCTION ebook_relations_search_vector_update();
+
+DROP TRIGGER IF EXISTS trg_ebook_tags_search_vector ON ebook_tags;
+CREATE TRIGGER trg_ebook_tags_search_vector
+AFTER INSERT OR UPDATE OR DELETE ON ebook_tags
+FOR EACH ROW
+EXECUTE FUNCTION ebook_relations_search_vector_update();
+
+DROP TRIGGER IF EXISTS trg_ebook_google_metadata_search_vector ON ebook_google_metadata;
+CREATE TRIGGER trg_ebook_google_metadata_search_vector
+AFTER INSERT OR UPDATE OR DELETE ON ebook_google_metadata
+FOR EACH ROW
+EXECUTE FUNCTION ebook_relations_search_vector_update();
+
+-- Authors and tags are shared between ebooks, so renaming or deleting one
+-- reindexes every ebook it is linked to.
+CREATE OR REPLACE FUNCTION authors_search_vector_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM refresh_ebook_search_vector(ea.ebook_id)
+    FROM ebook_authors ea
+    WHERE ea.author_id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_authors_search_vector ON authors;
+CREATE TRIGGER trg_authors_search_vector
+AFTER UPDATE OF name, deleted_at ON authors
+FOR EACH ROW
+EXECUTE FUNCTION authors_search_vector_update();
+
+CREATE OR REPLACE FUNCTION tags_search_vector_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    PERFORM refresh_ebook_search_vector(et.ebook_id)
+    FROM ebook_tags et
+    WHERE et.tag_id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_tags_search_vector ON tags;
+CREATE TRIGGER trg_tags_search_vector
+AFTER UPDATE OF name, deleted_at ON tags
+FOR EACH ROW
+EXECUTE FUNCTION tags_search_vector_update();
+
+UPDATE ebooks SET search_vector = ebook_search_document(id, title, description, language_code);
+UPDATE shares SET search_vector = share_search_document(ebook_id, title_override, description);
@@ -0,0 +1,8 @@
+DROP TRIGGER IF EXISTS trg_ebooks_language_passage_search_vector ON ebooks;
+DROP FUNCTION IF EXISTS ebooks_language_passage_search_vector_update;
+DROP TRIGGER IF EXISTS trg_ebook_passages_search_vector ON ebook_passages;
+DROP FUNCTION IF EXISTS ebook_passages_search_vector_update;
+DROP INDEX IF EXISTS idx_ebook_passages_search_vector;
+DROP TABLE IF EXISTS ebook_passages;
+DROP TABLE IF EXISTS ebook_content_indexes;
+DROP TYPE IF EXISTS ebook_content_index_status;
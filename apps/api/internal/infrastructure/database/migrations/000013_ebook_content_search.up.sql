@@ -0,0 +1,68 @@
+DO $$
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'ebook_content_index_status') THEN
+        CREATE TYPE ebook_content_index_status AS ENUM ('indexed', 'failed');
+    END IF;
+END
+$$;
+
+-- The file an ebook's passages were extracted from, identified by its checksum,
+-- so a changed file is indexed again. Files without extractable text are
+-- recorded as failed, with the reason.
+CREATE TABLE IF NOT EXISTS ebook_content_indexes (
+    ebook_id UUID PRIMARY KEY REFERENCES ebooks(id) ON DELETE CASCADE,
+    checksum_sha256 CHAR(64) NOT NULL,
+    status ebook_content_index_status NOT NULL,
+    passage_count INTEGER NOT NULL DEFAULT 0,
+    error TEXT,
+    indexed_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+-- Passages of an ebook's text, in reading order. Location is the reader's
+-- location token of the passage's first line.
+CREATE TABLE IF NOT EXISTS ebook_passages (
+    ebook_id UUID NOT NULL REFERENCES ebooks(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    location TEXT NOT NULL,
+    content TEXT NOT NULL,
+    search_vector TSVECTOR NOT NULL,
+    PRIMARY KEY (ebook_id, position)
+);
+
+CREATE INDEX IF NOT EXISTS idx_ebook_passages_search_vector ON ebook_passages USING GIN (search_vector);
+
+CREATE OR REPLACE FUNCTION ebook_passages_search_vector_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    NEW.search_vector := to_tsvector(
+        search_config((SELECT language_code FROM ebooks WHERE id = NEW.ebook_id)),
+        NEW.content
+    );
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_ebook_passages_search_vector ON ebook_passages;
+CREATE TRIGGER trg_ebook_passages_search_vector
+BEFORE INSERT OR UPDATE OF content ON ebook_passages
+FOR EACH ROW
+EXECUTE FUNCTION ebook_passages_search_vector_update();
+
+-- Like shares, passages are indexed with the configuration of their ebook's
+-- language.
+CREATE OR REPLACE FUNCTION ebooks_language_passage_search_vector_update()
+RETURNS TRIGGER AS $$
+BEGIN
+    UPDATE ebook_passages
+    SET search_vector = to_tsvector(search_config(NEW.language_code), content)
+    WHERE ebook_id = NEW.id;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_ebooks_language_passage_search_vector ON ebooks;
+CREATE TRIGGER trg_ebooks_language_passage_search_vector
+AFTER UPDATE OF language_code ON ebooks
+FOR EACH ROW
+WHEN (OLD.language_code IS DISTINCT FROM NEW.language_code)
+EXECUTE FUNCTION ebooks_language_passage_search_vector_update();
@@ -0,0 +1,3 @@
+DROP FUNCTION IF EXISTS search_queries;
+DROP INDEX IF EXISTS idx_shares_search_vector;
+DROP INDEX IF EXISTS idx_ebooks_search_vector;
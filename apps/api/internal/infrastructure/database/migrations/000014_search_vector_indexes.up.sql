@@ -0,0 +1,15 @@
+CREATE INDEX IF NOT EXISTS idx_ebooks_search_vector ON ebooks USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_shares_search_vector ON shares USING GIN (search_vector);
+
+-- Queries are parsed with each row's own configuration, which no index can
+-- serve. search_queries parses the text once with every installed
+-- configuration instead, so a row matching under its own configuration also
+-- matches one of these, and the GIN indexes can narrow the rows down before
+-- the per-row match is checked.
+CREATE OR REPLACE FUNCTION search_queries(p_text TEXT)
+RETURNS tsquery[] AS $$
+    SELECT ARRAY(
+        SELECT DISTINCT websearch_to_tsquery(c.oid::regconfig, p_text)
+        FROM pg_ts_config AS c
+    );
+$$ LANGUAGE sql STABLE;
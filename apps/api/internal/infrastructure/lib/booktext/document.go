@@ -0,0 +1,134 @@
+// Package booktext extracts the text of ebook files line by line, laid out the
+// way the TUI reader lays them out, so that a position in the text can be given
+// as one of the reader's location tokens.
+package booktext
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxPassageRunes bounds how many lines are joined into a passage. A single
+// line longer than it still makes one passage.
+const maxPassageRunes = 1000
+
+var (
+	ErrUnsupportedFormat = errors.New("unsupported document format")
+	ErrNoText            = errors.New("no extractable text")
+)
+
+// Document is the text of an ebook file as the reader displays it.
+type Document struct {
+	// Format is "epub", "pdf" or "txt".
+	Format string
+	Lines  []Line
+}
+
+// Line is a line of a Document with its anchor in the file. Page is the 1-based
+// page of a PDF and Spine the spine index of an EPUB, and they are -1 when the
+// format has none. Offset is the index of the line within its page, spine item
+// or text file; offset 0 of a page or spine item is the reader's heading line.
+type Line struct {
+	Text   string
+	Page   int
+	Spine  int
+	Offset int
+}
+
+// Passage is a run of lines searched and returned as one. Line is the index of
+// its first line, whose location it has.
+type Passage struct {
+	Line     int
+	Location string
+	Text     string
+}
+
+// Extract reads the file in r, which is size bytes long, in the given format.
+func Extract(format string, r io.ReaderAt, size int64) (*Document, error) {
+	switch strings.ToLower(strings.TrimSpace(format)) {
+	case "epub":
+		return extractEPUB(r, size)
+	case "pdf":
+		return extractPDF(r, size)
+	case "txt":
+		return extractTXT(io.NewSectionReader(r, 0, size))
+	default:
+		return nil, fmt.Errorf("%w %q", ErrUnsupportedFormat, format)
+	}
+}
+
+// Location is the reader's location token of the line, as encoded by the TUI's
+// reader.EncodeLocation.
+func (d *Document) Location(line int) string {
+	if len(d.Lines) == 0 {
+		return "fmt=txt;line=0"
+	}
+	line = max(0, min(line, len(d.Lines)-1))
+
+	anchor := d.Lines[line]
+	switch d.Format {
+	case "pdf":
+		return fmt.Sprintf("fmt=pdf;page=%d;line=%d", max(anchor.Page, 1), line)
+	case "epub":
+		return fmt.Sprintf("fmt=epub;spine=%d;offset=%d;line=%d", anchor.Spine, anchor.Offset, line)
+	default:
+		return fmt.Sprintf("fmt=txt;line=%d", line)
+	}
+}
+
+// Passages splits the document into the passages it is searched by. Passages
+// end at blank lines and at page and spine item boundaries, the lines of an
+// EPUB are paragraphs of their own, and the reader's heading lines are left out.
+func (d *Document) Passages() []Passage {
+	passages := []Passage{}
+	var (
+		start int
+		text  strings.Builder
+		runes int
+	)
+	flush := func() {
+		if text.Len() > 0 {
+			passages = append(passages, Passage{Line: start, Location: d.Location(start), Text: text.String()})
+		}
+		text.Reset()
+		runes = 0
+	}
+
+	for i, line := range d.Lines {
+		content := cleanText(line.Text)
+		if content == "" || (d.Format != "txt" && line.Offset == 0) {
+			flush()
+			continue
+		}
+
+		if text.Len() > 0 {
+			first := d.Lines[start]
+			length := utf8.RuneCountInString(content)
+			if d.Format == "epub" || line.Page != first.Page || line.Spine != first.Spine || runes+1+length > maxPassageRunes {
+				flush()
+			}
+		}
+
+		if text.Len() == 0 {
+			start = i
+		} else {
+			text.WriteByte(' ')
+			runes++
+		}
+		text.WriteString(content)
+		runes += utf8.RuneCountInString(content)
+	}
+	flush()
+	return passages
+}
+
+// cleanText trims the line and makes it storable as Postgres text, which must
+// be valid UTF-8 without NUL characters.
+func cleanText(value string) string {
+	value = strings.ToValidUTF8(value, "\uFFFD")
+	value = strings.ReplaceAll(value, "\x00", "")
+	return strings.TrimSpace(value)
+}
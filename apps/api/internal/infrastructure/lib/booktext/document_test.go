@@ -0,0 +1,119 @@
+package booktext
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// The fixtures are the TUI reader's, and the lines and location tokens below
+// are the ones its reader.Load and reader.EncodeLocation give for them, so a
+// passage's location opens the reader at that passage.
+func TestExtract_MatchesReaderLocations(t *testing.T) {
+	tests := []struct {
+		file      string
+		format    string
+		lines     []string
+		locations []string
+	}{
+		{
+			file:      "sample.txt",
+			format:    "txt",
+			lines:     []string{"Alpha", "Beta", "Gamma"},
+			locations: []string{"fmt=txt;line=0", "fmt=txt;line=1", "fmt=txt;line=2"},
+		},
+		{
+			file:   "sample.pdf",
+			format: "pdf",
+			lines:  []string{"--- Page 1 ---", "Page one text", "--- Page 2 ---", "Page two text"},
+			locations: []string{
+				"fmt=pdf;page=1;line=0",
+				"fmt=pdf;page=1;line=1",
+				"fmt=pdf;page=2;line=2",
+				"fmt=pdf;page=2;line=3",
+			},
+		},
+		{
+			file:   "sample.epub",
+			format: "epub",
+			lines:  []string{"=== Chapter: Intro ===", "Intro", "Hello chapter one.", "=== Chapter: Deep Dive ===", "Second chapter body."},
+			locations: []string{
+				"fmt=epub;spine=0;offset=0;line=0",
+				"fmt=epub;spine=0;offset=1;line=1",
+				"fmt=epub;spine=0;offset=2;line=2",
+				"fmt=epub;spine=1;offset=0;line=3",
+				"fmt=epub;spine=1;offset=1;line=4",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.file, func(t *testing.T) {
+			doc := extractFixture(t, tt.file, tt.format)
+
+			lines := make([]string, 0, len(doc.Lines))
+			locations := make([]string, 0, len(doc.Lines))
+			for i, line := range doc.Lines {
+				lines = append(lines, line.Text)
+				locations = append(locations, doc.Location(i))
+			}
+			require.Equal(t, tt.lines, lines)
+			require.Equal(t, tt.locations, locations)
+
+			for _, passage := range doc.Passages() {
+				require.Equal(t, tt.locations[passage.Line], passage.Location)
+			}
+		})
+	}
+}
+
+func TestDocumentPassages(t *testing.T) {
+	require.Equal(t, []Passage{
+		{Line: 0, Location: "fmt=txt;line=0", Text: "Alpha Beta Gamma"},
+	}, extractFixture(t, "sample.txt", "txt").Passages())
+
+	require.Equal(t, []Passage{
+		{Line: 1, Location: "fmt=pdf;page=1;line=1", Text: "Page one text"},
+		{Line: 3, Location: "fmt=pdf;page=2;line=3", Text: "Page two text"},
+	}, extractFixture(t, "sample.pdf", "pdf").Passages())
+
+	require.Equal(t, []Passage{
+		{Line: 1, Location: "fmt=epub;spine=0;offset=1;line=1", Text: "Intro"},
+		{Line: 2, Location: "fmt=epub;spine=0;offset=2;line=2", Text: "Hello chapter one."},
+		{Line: 4, Location: "fmt=epub;spine=1;offset=1;line=4", Text: "Second chapter body."},
+	}, extractFixture(t, "sample.epub", "epub").Passages())
+}
+
+func TestExtract_ScannedPDFHasNoText(t *testing.T) {
+	file, size := openFixture(t, "sample_scanned_like.pdf")
+
+	_, err := Extract("pdf", file, size)
+	require.ErrorIs(t, err, ErrNoText)
+}
+
+func TestExtract_UnsupportedFormat(t *testing.T) {
+	_, err := Extract("mobi", nil, 0)
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func extractFixture(t *testing.T, name, format string) *Document {
+	t.Helper()
+
+	file, size := openFixture(t, name)
+	doc, err := Extract(format, file, size)
+	require.NoError(t, err)
+	return doc
+}
+
+func openFixture(t *testing.T, name string) (*os.File, int64) {
+	t.Helper()
+
+	file, err := os.Open(filepath.Join("testdata", name))
+	require.NoError(t, err)
+	t.Cleanup(func() { file.Close() })
+	info, err := file.Stat()
+	require.NoError(t, err)
+	return file, info.Size()
+}
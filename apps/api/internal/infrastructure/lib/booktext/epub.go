@@ -0,0 +1,392 @@
+package booktext
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/epub"
+	"golang.org/x/net/html"
+)
+
+type epubContainer struct {
+	Rootfiles []struct {
+		FullPath string `xml:"full-path,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+type opfDocument struct {
+	Manifest []opfManifestItem `xml:"manifest>item"`
+	Spine    struct {
+		TOC      string `xml:"toc,attr"`
+		Itemrefs []struct {
+			IDRef string `xml:"idref,attr"`
+		} `xml:"itemref"`
+	} `xml:"spine"`
+}
+
+type opfManifestItem struct {
+	ID         string `xml:"id,attr"`
+	Href       string `xml:"href,attr"`
+	MediaType  string `xml:"media-type,attr"`
+	Properties string `xml:"properties,attr"`
+}
+
+type ncxDocument struct {
+	NavMap struct {
+		NavPoints []ncxNavPoint `xml:"navPoint"`
+	} `xml:"navMap"`
+}
+
+type ncxNavPoint struct {
+	Label struct {
+		Text string `xml:"text"`
+	} `xml:"navLabel"`
+	Content struct {
+		Src string `xml:"src,attr"`
+	} `xml:"content"`
+	Children []ncxNavPoint `xml:"navPoint"`
+}
+
+// extractEPUB lays the spine items of the EPUB out as the reader does: a
+// chapter heading line, titled from the table of contents, followed by a line
+// per block of text.
+func extractEPUB(r io.ReaderAt, size int64) (*Document, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", epub.ErrInvalidEPUB, err)
+	}
+
+	files := make(map[string]*zip.File, len(archive.File))
+	for _, file := range archive.File {
+		files[path.Clean(file.Name)] = file
+	}
+	read := func(name string) ([]byte, bool, error) {
+		file, ok := files[name]
+		if !ok {
+			return nil, false, nil
+		}
+		body, err := readZipFile(file)
+		if err != nil {
+			return nil, true, fmt.Errorf("%w: %s: %v", epub.ErrInvalidEPUB, name, err)
+		}
+		return body, true, nil
+	}
+
+	containerBytes, ok, err := read("META-INF/container.xml")
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: missing META-INF/container.xml", epub.ErrInvalidEPUB)
+	}
+	var container epubContainer
+	if err := xml.Unmarshal(containerBytes, &container); err != nil {
+		return nil, fmt.Errorf("%w: container.xml: %v", epub.ErrInvalidEPUB, err)
+	}
+	if len(container.Rootfiles) == 0 || strings.TrimSpace(container.Rootfiles[0].FullPath) == "" {
+		return nil, fmt.Errorf("%w: missing rootfile path", epub.ErrInvalidEPUB)
+	}
+
+	opfPath := path.Clean(container.Rootfiles[0].FullPath)
+	opfBytes, ok, err := read(opfPath)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: missing package document %q", epub.ErrInvalidEPUB, opfPath)
+	}
+	var opf opfDocument
+	if err := xml.Unmarshal(opfBytes, &opf); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", epub.ErrInvalidEPUB, opfPath, err)
+	}
+	if len(opf.Manifest) == 0 || len(opf.Spine.Itemrefs) == 0 {
+		return nil, fmt.Errorf("%w: empty manifest or spine", epub.ErrInvalidEPUB)
+	}
+
+	manifestByID := make(map[string]opfManifestItem, len(opf.Manifest))
+	for _, item := range opf.Manifest {
+		manifestByID[item.ID] = item
+	}
+
+	// EPUB 3 navigation documents take precedence over an EPUB 2 NCX
+	tocTitles := map[string]string{}
+	opfDir := path.Dir(opfPath)
+	if tocItem, found := manifestByID[opf.Spine.TOC]; found && opf.Spine.TOC != "" {
+		tocPath := path.Clean(path.Join(opfDir, tocItem.Href))
+		if tocBytes, ok, err := read(tocPath); err == nil && ok {
+			for href, title := range parseNCXTitles(tocBytes, path.Dir(tocPath)) {
+				tocTitles[href] = title
+			}
+		}
+	}
+	for _, item := range opf.Manifest {
+		if !strings.Contains(item.Properties, "nav") {
+			continue
+		}
+		navPath := path.Clean(path.Join(opfDir, item.Href))
+		if navBytes, ok, err := read(navPath); err == nil && ok {
+			for href, title := range parseHTMLNavTitles(navBytes, path.Dir(navPath)) {
+				tocTitles[href] = title
+			}
+		}
+	}
+
+	doc := &Document{Format: "epub"}
+	for spineIdx, ref := range opf.Spine.Itemrefs {
+		item, ok := manifestByID[ref.IDRef]
+		if !ok || !strings.Contains(item.MediaType, "html") {
+			continue
+		}
+
+		spinePath := path.Clean(path.Join(opfDir, item.Href))
+		body, ok, err := read(spinePath)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			continue
+		}
+
+		extracted, err := extractHTMLText(body)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", epub.ErrInvalidEPUB, spinePath, err)
+		}
+
+		heading := strings.TrimSpace(tocTitles[normalizeHref(spinePath)])
+		if heading == "" {
+			heading = strings.TrimSpace(extracted.Heading)
+		}
+		if heading == "" {
+			heading = fallbackChapterTitle(spinePath, spineIdx)
+		}
+		doc.Lines = append(doc.Lines, Line{Text: fmt.Sprintf("=== Chapter: %s ===", heading), Page: -1, Spine: spineIdx, Offset: 0})
+		for i, line := range extracted.Lines {
+			doc.Lines = append(doc.Lines, Line{Text: line, Page: -1, Spine: spineIdx, Offset: i + 1})
+		}
+	}
+
+	if len(doc.Lines) == 0 {
+		return nil, fmt.Errorf("%w: no readable chapters", epub.ErrInvalidEPUB)
+	}
+	return doc, nil
+}
+
+func readZipFile(file *zip.File) ([]byte, error) {
+	rc, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+func fallbackChapterTitle(spinePath string, spineIdx int) string {
+	base := strings.TrimSpace(strings.TrimSuffix(path.Base(spinePath), path.Ext(spinePath)))
+	if base != "" {
+		return strings.ReplaceAll(base, "_", " ")
+	}
+	return fmt.Sprintf("Section %d", spineIdx+1)
+}
+
+func parseNCXTitles(data []byte, baseDir string) map[string]string {
+	out := map[string]string{}
+	var ncx ncxDocument
+	if err := xml.Unmarshal(data, &ncx); err != nil {
+		return out
+	}
+
+	var walk func(points []ncxNavPoint)
+	walk = func(points []ncxNavPoint) {
+		for _, p := range points {
+			src := strings.TrimSpace(p.Content.Src)
+			label := strings.TrimSpace(p.Label.Text)
+			if src != "" && label != "" {
+				full := normalizeHref(path.Clean(path.Join(baseDir, src)))
+				if _, exists := out[full]; !exists {
+					out[full] = label
+				}
+			}
+			walk(p.Children)
+		}
+	}
+	walk(ncx.NavMap.NavPoints)
+	return out
+}
+
+func parseHTMLNavTitles(data []byte, baseDir string) map[string]string {
+	out := map[string]string{}
+	node, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return out
+	}
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode && n.Data == "a" {
+			href := ""
+			for _, attr := range n.Attr {
+				if attr.Key == "href" {
+					href = strings.TrimSpace(attr.Val)
+					break
+				}
+			}
+			if title := strings.TrimSpace(joinNodeText(n)); href != "" && title != "" {
+				full := normalizeHref(path.Clean(path.Join(baseDir, href)))
+				if _, exists := out[full]; !exists {
+					out[full] = title
+				}
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(node)
+	return out
+}
+
+type htmlText struct {
+	Lines   []string
+	Heading string
+}
+
+// extractHTMLText returns a line per block element of the body, with its
+// whitespace collapsed, and the text of its first heading. A body without text
+// is a single empty line.
+func extractHTMLText(data []byte) (*htmlText, error) {
+	doc, err := html.Parse(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+
+	body := findNode(doc, "body")
+	if body == nil {
+		body = doc
+	}
+
+	out := &htmlText{}
+	tokens := []string{}
+	flush := func() {
+		if line := strings.Join(strings.Fields(strings.Join(tokens, " ")), " "); line != "" {
+			out.Lines = append(out.Lines, line)
+		}
+		tokens = tokens[:0]
+	}
+
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			if text := strings.Join(strings.Fields(n.Data), " "); text != "" {
+				tokens = append(tokens, text)
+			}
+			return
+		}
+		if n.Type != html.ElementNode {
+			for child := n.FirstChild; child != nil; child = child.NextSibling {
+				walk(child)
+			}
+			return
+		}
+
+		tag := strings.ToLower(n.Data)
+		switch {
+		case isSkippableHTMLTag(tag):
+			return
+		case tag == "br":
+			flush()
+			return
+		}
+
+		block := isBlockHTMLTag(tag)
+		if block {
+			flush()
+		}
+		if out.Heading == "" && isHeadingHTMLTag(tag) {
+			out.Heading = strings.Join(strings.Fields(joinNodeText(n)), " ")
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+		if block {
+			flush()
+		}
+	}
+
+	walk(body)
+	flush()
+	if len(out.Lines) == 0 {
+		out.Lines = []string{""}
+	}
+	return out, nil
+}
+
+func findNode(root *html.Node, tag string) *html.Node {
+	if root.Type == html.ElementNode && root.Data == tag {
+		return root
+	}
+	for child := root.FirstChild; child != nil; child = child.NextSibling {
+		if found := findNode(child, tag); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+func joinNodeText(node *html.Node) string {
+	parts := []string{}
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			if text := strings.TrimSpace(n.Data); text != "" {
+				parts = append(parts, text)
+			}
+		}
+		for child := n.FirstChild; child != nil; child = child.NextSibling {
+			walk(child)
+		}
+	}
+	walk(node)
+	return strings.Join(parts, " ")
+}
+
+func normalizeHref(href string) string {
+	trimmed := strings.TrimSpace(href)
+	if idx := strings.Index(trimmed, "#"); idx >= 0 {
+		trimmed = trimmed[:idx]
+	}
+	return path.Clean(trimmed)
+}
+
+func isSkippableHTMLTag(tag string) bool {
+	switch tag {
+	case "script", "style", "noscript", "svg", "math":
+		return true
+	default:
+		return false
+	}
+}
+
+func isHeadingHTMLTag(tag string) bool {
+	switch tag {
+	case "h1", "h2", "h3", "h4", "h5", "h6":
+		return true
+	default:
+		return false
+	}
+}
+
+func isBlockHTMLTag(tag string) bool {
+	switch tag {
+	case "p", "div", "section", "article", "header", "footer", "main", "aside",
+		"ul", "ol", "li", "blockquote", "pre", "table", "thead", "tbody", "tr", "td", "th",
+		"h1", "h2", "h3", "h4", "h5", "h6", "nav":
+		return true
+	default:
+		return false
+	}
+}
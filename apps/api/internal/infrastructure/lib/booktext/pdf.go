@@ -0,0 +1,82 @@
+package booktext
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// extractPDF lays the pages of the PDF out as the reader does: a page marker
+// line followed by the lines of the page's text. PDFs without text, such as
+// scans, fail with ErrNoText.
+func extractPDF(r io.ReaderAt, size int64) (doc *Document, err error) {
+	// the parser panics on some malformed files rather than failing
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			doc, err = nil, fmt.Errorf("invalid pdf: %v", recovered)
+		}
+	}()
+
+	reader, err := pdf.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pdf: %w", err)
+	}
+
+	doc = &Document{Format: "pdf"}
+	hasText := false
+	for page := 1; page <= reader.NumPage(); page++ {
+		pdfPage := reader.Page(page)
+		if pdfPage.V.IsNull() {
+			continue
+		}
+		content, err := pdfPage.GetPlainText(nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid pdf: page %d: %w", page, err)
+		}
+
+		doc.Lines = append(doc.Lines, Line{Text: fmt.Sprintf("--- Page %d ---", page), Page: page, Spine: -1, Offset: 0})
+		for i, line := range splitNormalizedLines(content) {
+			doc.Lines = append(doc.Lines, Line{Text: line, Page: page, Spine: -1, Offset: i + 1})
+			if line != "" {
+				hasText = true
+			}
+		}
+	}
+
+	if !hasText {
+		return nil, fmt.Errorf("pdf: %w", ErrNoText)
+	}
+	return doc, nil
+}
+
+// splitNormalizedLines splits text into lines with their whitespace collapsed,
+// keeping at most one blank line between paragraphs. Text without any is a
+// single empty line.
+func splitNormalizedLines(content string) []string {
+	normalized := strings.ReplaceAll(content, "\r\n", "\n")
+	normalized = strings.ReplaceAll(normalized, "\r", "\n")
+
+	rawLines := strings.Split(normalized, "\n")
+	out := make([]string, 0, len(rawLines))
+	prevBlank := false
+	for _, line := range rawLines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			if !prevBlank && len(out) > 0 {
+				out = append(out, "")
+				prevBlank = true
+			}
+			continue
+		}
+
+		out = append(out, strings.Join(strings.Fields(trimmed), " "))
+		prevBlank = false
+	}
+
+	if len(out) == 0 {
+		return []string{""}
+	}
+	return out
+}
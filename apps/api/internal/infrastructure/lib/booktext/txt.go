@@ -0,0 +1,24 @@
+package booktext
+
+import (
+	"bufio"
+	"io"
+)
+
+// extractTXT makes each line of the text a line of the document, as the reader
+// does. An empty file is a single empty line.
+func extractTXT(r io.Reader) (*Document, error) {
+	doc := &Document{Format: "txt"}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		doc.Lines = append(doc.Lines, Line{Text: scanner.Text(), Page: -1, Spine: -1, Offset: len(doc.Lines)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(doc.Lines) == 0 {
+		doc.Lines = append(doc.Lines, Line{Page: -1, Spine: -1, Offset: 0})
+	}
+	return doc, nil
+}
@@ -29,3 +29,19 @@ func (c *Client) SendEmailVerification(to, username, code string, expiresInMinut
 		data,
 	)
 }
+
+func (c *Client) SendAccountRestricted(to, username, title, reason, details string) error {
+	data := map[string]string{
+		"Username": username,
+		"Title":    title,
+		"Reason":   reason,
+		"Details":  details,
+	}
+
+	return c.SendEmail(
+		to,
+		title,
+		TemplateAccountRestricted,
+		data,
+	)
+}
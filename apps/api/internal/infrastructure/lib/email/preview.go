@@ -9,4 +9,10 @@ var PreviewData = map[string]map[string]string{
 		"VerificationCode": "123456",
 		"ExpiresInMinutes": "30",
 	},
+	"account_restricted": {
+		"Username": "John",
+		"Title":    "Your account has been suspended",
+		"Reason":   "Sharing copyrighted books",
+		"Details":  "The suspension ends on Oct 20, 2026 at 14:00 UTC.",
+	},
 }
@@ -5,4 +5,5 @@ type Template string
 const (
 	TemplateWelcome           Template = "welcome"
 	TemplateEmailVerification Template = "email-verification"
+	TemplateAccountRestricted Template = "account-restricted"
 )
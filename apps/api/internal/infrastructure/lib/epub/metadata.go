@@ -0,0 +1,113 @@
+package epub
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+)
+
+// maxXMLBytes bounds how much of the container and package documents is read,
+// real ones are a few kilobytes.
+const maxXMLBytes = 1 << 20
+
+var ErrInvalidEPUB = errors.New("invalid epub")
+
+// Metadata is the Dublin Core metadata of an EPUB's package document.
+type Metadata struct {
+	Title       string
+	Creators    []string
+	Language    string
+	Identifiers []Identifier
+}
+
+type Identifier struct {
+	// Scheme is the opf:scheme attribute, e.g. "ISBN", when the package sets one.
+	Scheme string
+	Value  string
+}
+
+type container struct {
+	Rootfiles []struct {
+		FullPath  string `xml:"full-path,attr"`
+		MediaType string `xml:"media-type,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+type packageDocument struct {
+	Metadata struct {
+		Titles      []string `xml:"title"`
+		Creators    []string `xml:"creator"`
+		Languages   []string `xml:"language"`
+		Identifiers []struct {
+			Scheme string `xml:"scheme,attr"`
+			Value  string `xml:",chardata"`
+		} `xml:"identifier"`
+	} `xml:"metadata"`
+}
+
+// ReadMetadata reads the metadata of the EPUB in r, which is size bytes long.
+func ReadMetadata(r io.ReaderAt, size int64) (*Metadata, error) {
+	archive, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidEPUB, err)
+	}
+
+	var c container
+	if err := decodeFile(archive, "META-INF/container.xml", &c); err != nil {
+		return nil, err
+	}
+
+	packagePath := ""
+	for _, rootfile := range c.Rootfiles {
+		if rootfile.MediaType == "application/oebps-package+xml" || packagePath == "" {
+			packagePath = rootfile.FullPath
+		}
+	}
+	if packagePath == "" {
+		return nil, fmt.Errorf("%w: container.xml lists no package document", ErrInvalidEPUB)
+	}
+
+	var pkg packageDocument
+	if err := decodeFile(archive, path.Clean(packagePath), &pkg); err != nil {
+		return nil, err
+	}
+
+	metadata := &Metadata{}
+	for _, title := range pkg.Metadata.Titles {
+		if title = strings.TrimSpace(title); title != "" {
+			metadata.Title = title
+			break
+		}
+	}
+	for _, creator := range pkg.Metadata.Creators {
+		if creator = strings.TrimSpace(creator); creator != "" {
+			metadata.Creators = append(metadata.Creators, creator)
+		}
+	}
+	if len(pkg.Metadata.Languages) > 0 {
+		metadata.Language = strings.TrimSpace(pkg.Metadata.Languages[0])
+	}
+	for _, identifier := range pkg.Metadata.Identifiers {
+		if value := strings.TrimSpace(identifier.Value); value != "" {
+			metadata.Identifiers = append(metadata.Identifiers, Identifier{Scheme: strings.TrimSpace(identifier.Scheme), Value: value})
+		}
+	}
+	return metadata, nil
+}
+
+func decodeFile(archive *zip.Reader, name string, dest any) error {
+	file, err := archive.Open(name)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidEPUB, name, err)
+	}
+	defer file.Close()
+
+	if err := xml.NewDecoder(io.LimitReader(file, maxXMLBytes)).Decode(dest); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidEPUB, name, err)
+	}
+	return nil
+}
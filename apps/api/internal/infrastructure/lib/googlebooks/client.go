@@ -0,0 +1,251 @@
+package googlebooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/config"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/cache"
+	"github.com/rs/zerolog"
+)
+
+const (
+	cacheKeyPrefix = "googlebooks:"
+	// maxResponseBytes bounds how much of an upstream response is read, volumes
+	// are a few kilobytes and a search page a few dozen.
+	maxResponseBytes = 2 << 20
+)
+
+var (
+	// ErrUnavailable is returned when Google Books could not be reached, kept
+	// failing, or is rate limiting the API.
+	ErrUnavailable = errors.New("google books is unavailable")
+	// ErrVolumeNotFound is returned when Google Books has no volume with the id.
+	ErrVolumeNotFound = errors.New("google books volume not found")
+)
+
+// Client queries the Google Books API. Successful responses are cached, failed
+// requests are retried with exponential backoff, and once Google Books rate
+// limits the API every request fails fast until the limit is expected to lift.
+type Client struct {
+	httpClient *http.Client
+	cache      cache.Cache
+	cfg        *config.GoogleBooksConfig
+	logger     *zerolog.Logger
+
+	mu           sync.Mutex
+	blockedUntil time.Time
+}
+
+// NewClient creates a Google Books client. httpClient and cacheClient are
+// optional; without a cache every lookup goes to Google Books.
+func NewClient(cfg *config.GoogleBooksConfig, httpClient *http.Client, cacheClient cache.Cache, logger *zerolog.Logger) *Client {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: cfg.Timeout}
+	}
+	return &Client{httpClient: httpClient, cache: cacheClient, cfg: cfg, logger: logger}
+}
+
+// Search returns the volumes matching query, which uses the Google Books query
+// syntax, e.g. "dune frank herbert" or "isbn:9780441172719".
+func (c *Client) Search(ctx context.Context, query string, limit int) ([]Volume, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	params.Set("maxResults", strconv.Itoa(limit))
+	params.Set("printType", "books")
+
+	body, err := c.get(ctx, "/volumes", params)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Items []rawVolume `json:"items"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("%w: invalid search response: %v", ErrUnavailable, err)
+	}
+
+	volumes := make([]Volume, 0, len(result.Items))
+	for _, item := range result.Items {
+		volumes = append(volumes, item.normalize(nil))
+	}
+	return volumes, nil
+}
+
+// GetVolume returns the volume with the Google Books id volumeID.
+func (c *Client) GetVolume(ctx context.Context, volumeID string) (*Volume, error) {
+	body, err := c.get(ctx, "/volumes/"+url.PathEscape(volumeID), url.Values{})
+	if err != nil {
+		return nil, err
+	}
+
+	var item rawVolume
+	if err := json.Unmarshal(body, &item); err != nil {
+		return nil, fmt.Errorf("%w: invalid volume response: %v", ErrUnavailable, err)
+	}
+	var raw map[string]any
+	if err := json.Unmarshal(body, &raw); err != nil {
+		return nil, fmt.Errorf("%w: invalid volume response: %v", ErrUnavailable, err)
+	}
+
+	volume := item.normalize(raw)
+	return &volume, nil
+}
+
+// get returns the body of a successful response to path, from the cache when
+// it holds one.
+func (c *Client) get(ctx context.Context, path string, params url.Values) ([]byte, error) {
+	cacheKey := cacheKeyPrefix + path + "?" + params.Encode()
+	if c.cache != nil {
+		if body, err := c.cache.Get(ctx, cacheKey); err == nil {
+			return body, nil
+		}
+	}
+
+	if c.cfg.APIKey != "" {
+		params.Set("key", c.cfg.APIKey)
+	}
+	endpoint := strings.TrimRight(c.cfg.BaseURL, "/") + path + "?" + params.Encode()
+
+	body, err := c.fetch(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+
+	if c.cache != nil {
+		_ = c.cache.Set(ctx, cacheKey, body, c.cfg.CacheTTL)
+	}
+	return body, nil
+}
+
+// fetch requests endpoint, retrying network errors, rate limiting and server
+// errors with exponential backoff. A Retry-After longer than the backoff budget
+// is not waited out; it blocks further requests until it has passed instead.
+func (c *Client) fetch(ctx context.Context, endpoint string) ([]byte, error) {
+	if until := c.rateLimitedUntil(); !until.IsZero() {
+		return nil, fmt.Errorf("%w: rate limited until %s", ErrUnavailable, until.Format(time.RFC3339))
+	}
+
+	var lastErr error
+	for attempt := 0; ; attempt++ {
+		body, retryAfter, retryable, err := c.do(ctx, endpoint)
+		if err == nil || errors.Is(err, ErrVolumeNotFound) {
+			return body, err
+		}
+		lastErr = err
+		if !retryable {
+			break
+		}
+
+		delay := max(c.cfg.RetryBackoff<<attempt, retryAfter)
+		if attempt >= c.cfg.MaxRetries || delay > c.cfg.RetryBackoff<<c.cfg.MaxRetries {
+			if retryAfter > 0 {
+				c.blockFor(retryAfter)
+			}
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("%w: %v", ErrUnavailable, ctx.Err())
+		case <-time.After(delay):
+		}
+	}
+
+	if c.logger != nil {
+		c.logger.Warn().Err(lastErr).Str("endpoint", redactKey(endpoint)).Msg("google books request failed")
+	}
+	return nil, fmt.Errorf("%w: %v", ErrUnavailable, lastErr)
+}
+
+// do sends a single request. retryAfter is set when Google Books rate limited
+// it, and retryable when a later attempt may succeed.
+func (c *Client) do(ctx context.Context, endpoint string) (body []byte, retryAfter time.Duration, retryable bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, 0, false, err
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, ctx.Err() == nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err = io.ReadAll(io.LimitReader(resp.Body, maxResponseBytes))
+	if err != nil {
+		return nil, 0, true, err
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		return body, 0, false, nil
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, 0, false, ErrVolumeNotFound
+	case resp.StatusCode == http.StatusTooManyRequests:
+		retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		if retryAfter <= 0 {
+			retryAfter = c.cfg.RetryBackoff
+		}
+		return nil, retryAfter, true, fmt.Errorf("rate limited with status %d", resp.StatusCode)
+	default:
+		return nil, 0, resp.StatusCode >= http.StatusInternalServerError, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+}
+
+func (c *Client) rateLimitedUntil() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if time.Now().Before(c.blockedUntil) {
+		return c.blockedUntil
+	}
+	return time.Time{}
+}
+
+func (c *Client) blockFor(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if until := time.Now().Add(d); until.After(c.blockedUntil) {
+		c.blockedUntil = until
+	}
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+func redactKey(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return endpoint
+	}
+	query := u.Query()
+	if query.Has("key") {
+		query.Set("key", "REDACTED")
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
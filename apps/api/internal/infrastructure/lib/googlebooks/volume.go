@@ -0,0 +1,102 @@
+package googlebooks
+
+import "strings"
+
+// Volume is a Google Books volume normalized to the fields the API uses.
+type Volume struct {
+	ID            string   `json:"id"`
+	Title         string   `json:"title"`
+	Subtitle      *string  `json:"subtitle,omitempty"`
+	Authors       []string `json:"authors"`
+	Description   *string  `json:"description,omitempty"`
+	Publisher     *string  `json:"publisher,omitempty"`
+	PublishedDate *string  `json:"publishedDate,omitempty"`
+	PageCount     *int     `json:"pageCount,omitempty"`
+	Categories    []string `json:"categories"`
+	LanguageCode  *string  `json:"languageCode,omitempty"`
+	ISBN10        *string  `json:"isbn10,omitempty"`
+	ISBN13        *string  `json:"isbn13,omitempty"`
+	ThumbnailURL  *string  `json:"thumbnailUrl,omitempty"`
+	InfoLink      *string  `json:"infoLink,omitempty"`
+	// Raw is the volume as returned by Google Books. It is only set by GetVolume.
+	Raw map[string]any `json:"-"`
+}
+
+type rawVolume struct {
+	ID         string `json:"id"`
+	VolumeInfo struct {
+		Title               string   `json:"title"`
+		Subtitle            string   `json:"subtitle"`
+		Authors             []string `json:"authors"`
+		Description         string   `json:"description"`
+		Publisher           string   `json:"publisher"`
+		PublishedDate       string   `json:"publishedDate"`
+		PageCount           int      `json:"pageCount"`
+		Categories          []string `json:"categories"`
+		Language            string   `json:"language"`
+		InfoLink            string   `json:"infoLink"`
+		IndustryIdentifiers []struct {
+			Type       string `json:"type"`
+			Identifier string `json:"identifier"`
+		} `json:"industryIdentifiers"`
+		ImageLinks struct {
+			SmallThumbnail string `json:"smallThumbnail"`
+			Thumbnail      string `json:"thumbnail"`
+		} `json:"imageLinks"`
+	} `json:"volumeInfo"`
+}
+
+func (v rawVolume) normalize(raw map[string]any) Volume {
+	info := v.VolumeInfo
+	volume := Volume{
+		ID:            v.ID,
+		Title:         strings.TrimSpace(info.Title),
+		Subtitle:      optional(info.Subtitle),
+		Authors:       info.Authors,
+		Description:   optional(info.Description),
+		Publisher:     optional(info.Publisher),
+		PublishedDate: optional(info.PublishedDate),
+		Categories:    info.Categories,
+		LanguageCode:  optional(info.Language),
+		InfoLink:      optional(info.InfoLink),
+		Raw:           raw,
+	}
+	if volume.Authors == nil {
+		volume.Authors = []string{}
+	}
+	if volume.Categories == nil {
+		volume.Categories = []string{}
+	}
+	if info.PageCount > 0 {
+		volume.PageCount = &info.PageCount
+	}
+
+	for _, id := range info.IndustryIdentifiers {
+		switch id.Type {
+		case "ISBN_10":
+			volume.ISBN10 = optional(id.Identifier)
+		case "ISBN_13":
+			volume.ISBN13 = optional(id.Identifier)
+		}
+	}
+
+	thumbnail := info.ImageLinks.Thumbnail
+	if thumbnail == "" {
+		thumbnail = info.ImageLinks.SmallThumbnail
+	}
+	// Google Books hands out http image links that are also served over https.
+	if rest, ok := strings.CutPrefix(thumbnail, "http://"); ok {
+		thumbnail = "https://" + rest
+	}
+	volume.ThumbnailURL = optional(thumbnail)
+
+	return volume
+}
+
+func optional(value string) *string {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil
+	}
+	return &value
+}
@@ -0,0 +1,47 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskBorrowExpirySweep = "borrow:expiry_sweep"
+)
+
+// BorrowExpirer moves active borrows whose due date has passed to expired.
+type BorrowExpirer interface {
+	ExpireOverdueBorrows(ctx context.Context, now time.Time) (int, error)
+}
+
+func NewBorrowExpirySweepTask(interval time.Duration) *asynq.Task {
+	return asynq.NewTask(TaskBorrowExpirySweep, nil,
+		asynq.MaxRetry(3),
+		asynq.Queue("default"),
+		asynq.Timeout(time.Minute),
+		asynq.Unique(interval))
+}
+
+// RegisterBorrowExpiryHandler wires the periodic borrow expiry sweep to the given expirer.
+func (j *JobService) RegisterBorrowExpiryHandler(expirer BorrowExpirer) {
+	j.mux.HandleFunc(TaskBorrowExpirySweep, func(ctx context.Context, t *asynq.Task) error {
+		expired, err := expirer.ExpireOverdueBorrows(ctx, time.Now().UTC())
+		if err != nil {
+			j.logger.Error().
+				Str("type", "borrow_expiry_sweep").
+				Err(err).
+				Msg("Failed to expire overdue borrows")
+			return err
+		}
+
+		if expired > 0 {
+			j.logger.Info().
+				Str("type", "borrow_expiry_sweep").
+				Int("expired", expired).
+				Msg("Expired overdue borrows")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,123 @@
+package job
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskEbookEnrichment   = "ebook:enrichment"
+	TaskEbookContentIndex = "ebook:content_index"
+)
+
+// EbookEnricher looks an ebook up on Google Books, attaching the metadata of a
+// confident match or storing the likeliest candidates as suggestions.
+type EbookEnricher interface {
+	EnrichMetadata(ctx context.Context, ebookID uuid.UUID) (attached bool, suggested int, err error)
+}
+
+// EbookContentIndexer extracts the text of an ebook's stored file and indexes
+// its passages for searching inside the book.
+type EbookContentIndexer interface {
+	IndexContent(ctx context.Context, ebookID uuid.UUID) (passages int, err error)
+}
+
+type EbookEnrichmentPayload struct {
+	EbookID uuid.UUID `json:"ebook_id"`
+}
+
+type EbookContentIndexPayload struct {
+	EbookID uuid.UUID `json:"ebook_id"`
+}
+
+// NewEbookEnrichmentTask enqueues the enrichment of an ebook. Tasks are unique
+// per ebook while pending, so enqueueing again before it ran is a no-op, and
+// failures are retried with asynq's exponential backoff.
+func NewEbookEnrichmentTask(ebookID uuid.UUID) (*asynq.Task, error) {
+	payloadBytes, err := json.Marshal(EbookEnrichmentPayload{EbookID: ebookID})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TaskEbookEnrichment, payloadBytes,
+		asynq.MaxRetry(8),
+		asynq.Queue("low"),
+		asynq.Timeout(2*time.Minute),
+		asynq.Unique(time.Hour)), nil
+}
+
+// RegisterEbookEnrichmentHandler wires ebook enrichment tasks to the given enricher.
+func (j *JobService) RegisterEbookEnrichmentHandler(enricher EbookEnricher) {
+	j.mux.HandleFunc(TaskEbookEnrichment, func(ctx context.Context, t *asynq.Task) error {
+		var p EbookEnrichmentPayload
+		if err := json.Unmarshal(t.Payload(), &p); err != nil {
+			return fmt.Errorf("failed to unmarshal ebook enrichment payload: %w: %w", err, asynq.SkipRetry)
+		}
+
+		attached, suggested, err := enricher.EnrichMetadata(ctx, p.EbookID)
+		if err != nil {
+			j.logger.Error().
+				Str("type", "ebook_enrichment").
+				Str("ebook_id", p.EbookID.String()).
+				Err(err).
+				Msg("Failed to enrich ebook metadata")
+			return err
+		}
+
+		j.logger.Info().
+			Str("type", "ebook_enrichment").
+			Str("ebook_id", p.EbookID.String()).
+			Bool("attached", attached).
+			Int("suggested", suggested).
+			Msg("Enriched ebook metadata")
+		return nil
+	})
+}
+
+// NewEbookContentIndexTask enqueues the indexing of an ebook's text. Like
+// enrichment, tasks are unique per ebook while pending; the task indexes the
+// file stored when it runs.
+func NewEbookContentIndexTask(ebookID uuid.UUID) (*asynq.Task, error) {
+	payloadBytes, err := json.Marshal(EbookContentIndexPayload{EbookID: ebookID})
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TaskEbookContentIndex, payloadBytes,
+		asynq.MaxRetry(5),
+		asynq.Queue("low"),
+		asynq.Timeout(10*time.Minute),
+		asynq.Unique(time.Hour)), nil
+}
+
+// RegisterEbookContentIndexHandler wires ebook content index tasks to the given indexer.
+func (j *JobService) RegisterEbookContentIndexHandler(indexer EbookContentIndexer) {
+	j.mux.HandleFunc(TaskEbookContentIndex, func(ctx context.Context, t *asynq.Task) error {
+		var p EbookContentIndexPayload
+		if err := json.Unmarshal(t.Payload(), &p); err != nil {
+			return fmt.Errorf("failed to unmarshal ebook content index payload: %w: %w", err, asynq.SkipRetry)
+		}
+
+		passages, err := indexer.IndexContent(ctx, p.EbookID)
+		if err != nil {
+			j.logger.Error().
+				Str("type", "ebook_content_index").
+				Str("ebook_id", p.EbookID.String()).
+				Err(err).
+				Msg("Failed to index ebook content")
+			return err
+		}
+
+		j.logger.Info().
+			Str("type", "ebook_content_index").
+			Str("ebook_id", p.EbookID.String()).
+			Int("passages", passages).
+			Msg("Indexed ebook content")
+		return nil
+	})
+}
@@ -8,7 +8,8 @@ import (
 )
 
 const (
-	TaskEmailVerification = "email:verification"
+	TaskEmailVerification      = "email:verification"
+	TaskEmailAccountRestricted = "email:account_restricted"
 )
 
 type EmailVerificationPayload struct {
@@ -29,3 +30,25 @@ func NewEmailVerificationTask(payload EmailVerificationPayload) (*asynq.Task, er
 		asynq.Queue("default"),
 		asynq.Timeout(30*time.Second)), nil
 }
+
+// AccountRestrictedPayload notifies a user of a suspension or ban. Title and
+// Details are rendered by the sender, so the worker only fills the template.
+type AccountRestrictedPayload struct {
+	To       string `json:"to"`
+	Username string `json:"username"`
+	Title    string `json:"title"`
+	Reason   string `json:"reason"`
+	Details  string `json:"details"`
+}
+
+func NewAccountRestrictedTask(payload AccountRestrictedPayload) (*asynq.Task, error) {
+	payloadBytes, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return asynq.NewTask(TaskEmailAccountRestricted, payloadBytes,
+		asynq.MaxRetry(3),
+		asynq.Queue("default"),
+		asynq.Timeout(30*time.Second)), nil
+}
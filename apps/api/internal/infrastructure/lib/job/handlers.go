@@ -49,3 +49,31 @@ func (j *JobService) handleEmailVerificationTask(ctx context.Context, t *asynq.T
 		Msg("Successfully sent email verification email")
 	return nil
 }
+
+func (j *JobService) handleAccountRestrictedTask(ctx context.Context, t *asynq.Task) error {
+	var p AccountRestrictedPayload
+	if err := json.Unmarshal(t.Payload(), &p); err != nil {
+		return fmt.Errorf("failed to unmarshal account restricted payload: %w", err)
+	}
+
+	j.logger.Info().
+		Str("type", "account_restricted").
+		Str("to", p.To).
+		Msg("Processing account restricted email task")
+
+	err := emailClient.SendAccountRestricted(p.To, p.Username, p.Title, p.Reason, p.Details)
+	if err != nil {
+		j.logger.Error().
+			Str("type", "account_restricted").
+			Str("to", p.To).
+			Err(err).
+			Msg("Failed to send account restricted email")
+		return err
+	}
+
+	j.logger.Info().
+		Str("type", "account_restricted").
+		Str("to", p.To).
+		Msg("Successfully sent account restricted email")
+	return nil
+}
@@ -1,6 +1,9 @@
 package job
 
 import (
+	"fmt"
+	"time"
+
 	"github.com/hibiken/asynq"
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/config"
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/storage"
@@ -9,11 +12,14 @@ import (
 )
 
 type JobService struct {
-	Client  *asynq.Client
-	server  *asynq.Server
-	logger  *zerolog.Logger
-	db      *gorm.DB
-	storage storage.Storage
+	Client    *asynq.Client
+	server    *asynq.Server
+	scheduler *asynq.Scheduler
+	mux       *asynq.ServeMux
+	logger    *zerolog.Logger
+	cfg       *config.Config
+	db        *gorm.DB
+	storage   storage.Storage
 }
 
 func NewJobService(logger *zerolog.Logger, cfg *config.Config, db *gorm.DB, storageProvider storage.Storage) *JobService {
@@ -35,22 +41,40 @@ func NewJobService(logger *zerolog.Logger, cfg *config.Config, db *gorm.DB, stor
 		},
 	)
 
+	scheduler := asynq.NewScheduler(
+		asynq.RedisClientOpt{Addr: redisAddr},
+		&asynq.SchedulerOpts{Location: time.UTC},
+	)
+
 	return &JobService{
-		Client:  client,
-		server:  server,
-		logger:  logger,
-		db:      db,
-		storage: storageProvider,
+		Client:    client,
+		server:    server,
+		scheduler: scheduler,
+		mux:       asynq.NewServeMux(),
+		logger:    logger,
+		cfg:       cfg,
+		db:        db,
+		storage:   storageProvider,
 	}
 }
 
 func (j *JobService) Start() error {
 	// Register task handlers
-	mux := asynq.NewServeMux()
-	mux.HandleFunc(TaskEmailVerification, j.handleEmailVerificationTask)
+	j.mux.HandleFunc(TaskEmailVerification, j.handleEmailVerificationTask)
+	j.mux.HandleFunc(TaskEmailAccountRestricted, j.handleAccountRestrictedTask)
+
+	// Register periodic tasks; every API instance runs a scheduler, the tasks are
+	// enqueued as unique so only one copy per interval is processed.
+	if err := j.registerPeriodicTasks(); err != nil {
+		return err
+	}
 
 	j.logger.Info().Msg("Starting background job server")
-	if err := j.server.Start(mux); err != nil {
+	if err := j.server.Start(j.mux); err != nil {
+		return err
+	}
+
+	if err := j.scheduler.Start(); err != nil {
 		return err
 	}
 
@@ -59,6 +83,24 @@ func (j *JobService) Start() error {
 
 func (j *JobService) Stop() {
 	j.logger.Info().Msg("Stopping background job server")
+	j.scheduler.Shutdown()
 	j.server.Shutdown()
 	j.Client.Close()
 }
+
+func (j *JobService) registerPeriodicTasks() error {
+	interval := j.cfg.Jobs.BorrowExpiryInterval
+	if _, err := j.scheduler.Register(everySpec(interval), NewBorrowExpirySweepTask(interval)); err != nil {
+		return fmt.Errorf("failed to register borrow expiry sweep: %w", err)
+	}
+
+	interval = j.cfg.Jobs.SyncCompactionInterval
+	if _, err := j.scheduler.Register(everySpec(interval), NewSyncCompactionTask(interval)); err != nil {
+		return fmt.Errorf("failed to register sync compaction: %w", err)
+	}
+	return nil
+}
+
+func everySpec(interval time.Duration) string {
+	return "@every " + interval.String()
+}
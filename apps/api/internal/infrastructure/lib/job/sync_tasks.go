@@ -0,0 +1,49 @@
+package job
+
+import (
+	"context"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+const (
+	TaskSyncCompaction = "sync:compaction"
+)
+
+// SyncCompactor collapses superseded sync events and deletes expired ones.
+type SyncCompactor interface {
+	CompactEvents(ctx context.Context, expireBefore time.Time) (collapsed int, expired int, err error)
+}
+
+func NewSyncCompactionTask(interval time.Duration) *asynq.Task {
+	return asynq.NewTask(TaskSyncCompaction, nil,
+		asynq.MaxRetry(3),
+		asynq.Queue("low"),
+		asynq.Timeout(30*time.Minute),
+		asynq.Unique(interval))
+}
+
+// RegisterSyncCompactionHandler wires the periodic sync event compaction to the given compactor.
+func (j *JobService) RegisterSyncCompactionHandler(compactor SyncCompactor) {
+	j.mux.HandleFunc(TaskSyncCompaction, func(ctx context.Context, t *asynq.Task) error {
+		expireBefore := time.Now().UTC().Add(-j.cfg.Jobs.SyncEventRetention)
+		collapsed, expired, err := compactor.CompactEvents(ctx, expireBefore)
+		if err != nil {
+			j.logger.Error().
+				Str("type", "sync_compaction").
+				Err(err).
+				Msg("Failed to compact sync events")
+			return err
+		}
+
+		if collapsed > 0 || expired > 0 {
+			j.logger.Info().
+				Str("type", "sync_compaction").
+				Int("collapsed", collapsed).
+				Int("expired", expired).
+				Msg("Compacted sync events")
+		}
+		return nil
+	})
+}
@@ -0,0 +1,90 @@
+package pubsub
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/redis/go-redis/v9"
+	"github.com/rs/zerolog"
+)
+
+const (
+	syncChannelPrefix = "sync:events:"
+	// subscriberBuffer absorbs short bursts, such as a batch push, while the
+	// subscriber is still writing earlier events to its connection.
+	subscriberBuffer = 64
+)
+
+// SyncNotifier publishes sync events on a per-user Redis channel so every API
+// instance can forward them to the streams it holds open.
+type SyncNotifier struct {
+	client *redis.Client
+	logger *zerolog.Logger
+}
+
+func NewSyncNotifier(client *redis.Client, logger *zerolog.Logger) *SyncNotifier {
+	return &SyncNotifier{client: client, logger: logger}
+}
+
+func (n *SyncNotifier) Publish(ctx context.Context, event *domain.SyncEvent) {
+	data, err := json.Marshal(event)
+	if err == nil {
+		err = n.client.Publish(ctx, syncChannel(event.UserID), data).Err()
+	}
+	if err != nil && n.logger != nil {
+		n.logger.Warn().Err(err).Str("sync_event_id", event.ID.String()).Msg("failed to publish sync event")
+	}
+}
+
+func (n *SyncNotifier) Subscribe(ctx context.Context, userID uuid.UUID) (<-chan domain.SyncEvent, error) {
+	sub := n.client.Subscribe(ctx, syncChannel(userID))
+	// Wait for the subscription to be confirmed so no event published after
+	// Subscribe returns is missed.
+	if _, err := sub.Receive(ctx); err != nil {
+		_ = sub.Close()
+		if n.logger != nil {
+			n.logger.Error().Err(err).Str("user_id", userID.String()).Msg("failed to subscribe to sync events")
+		}
+		return nil, err
+	}
+
+	events := make(chan domain.SyncEvent, subscriberBuffer)
+	go func() {
+		defer close(events)
+		defer sub.Close()
+
+		messages := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+
+				var event domain.SyncEvent
+				if err := json.Unmarshal([]byte(msg.Payload), &event); err != nil {
+					if n.logger != nil {
+						n.logger.Warn().Err(err).Str("channel", msg.Channel).Msg("dropping malformed sync event message")
+					}
+					continue
+				}
+
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+func syncChannel(userID uuid.UUID) string {
+	return syncChannelPrefix + userID.String()
+}
@@ -2,22 +2,32 @@ package storage
 
 import (
 	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"io"
+	"mime"
+	"net/url"
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 )
 
 type LocalStorage struct {
-	baseDir    string
-	publicPath string
+	baseDir       string
+	publicPath    string
+	signedPath    string
+	signingSecret []byte
 }
 
-func NewLocalStorage(baseDir, publicPath string) *LocalStorage {
+func NewLocalStorage(baseDir, publicPath, signedPath, signingSecret string) *LocalStorage {
 	base := strings.TrimSpace(baseDir)
 	if base == "" {
-		base = "static"
+		base = "storage"
 	}
 
 	public := strings.TrimSpace(publicPath)
@@ -27,12 +37,24 @@ func NewLocalStorage(baseDir, publicPath string) *LocalStorage {
 
 	public = strings.TrimRight(public, "/")
 
+	signed := strings.TrimRight(strings.TrimSpace(signedPath), "/")
+	if signed == "" {
+		signed = "/files"
+	}
+
 	return &LocalStorage{
-		baseDir:    base,
-		publicPath: public,
+		baseDir:       base,
+		publicPath:    public,
+		signedPath:    signed,
+		signingSecret: []byte(signingSecret),
 	}
 }
 
+// SignedPath is the route prefix under which signed local URLs are served.
+func (s *LocalStorage) SignedPath() string {
+	return s.signedPath
+}
+
 func (s *LocalStorage) Save(ctx context.Context, key string, reader io.Reader, size int64, contentType string) (*Object, error) {
 	_ = ctx
 
@@ -57,6 +79,113 @@ func (s *LocalStorage) Save(ctx context.Context, key string, reader io.Reader, s
 	return &Object{Path: cleanKey, URL: url, Size: size}, nil
 }
 
+func (s *LocalStorage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return s.OpenRange(ctx, key, 0, -1)
+}
+
+func (s *LocalStorage) OpenRange(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	_ = ctx
+
+	cleanKey := strings.TrimLeft(path.Clean("/"+key), "/")
+	fullPath := filepath.Join(s.baseDir, filepath.FromSlash(cleanKey))
+	file, err := os.Open(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+
+	if offset > 0 {
+		if _, err := file.Seek(offset, io.SeekStart); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+
+	if length < 0 {
+		return file, nil
+	}
+
+	return &limitedReadCloser{Reader: io.LimitReader(file, length), Closer: file}, nil
+}
+
+func (s *LocalStorage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	_ = ctx
+
+	cleanKey := strings.TrimLeft(path.Clean("/"+key), "/")
+	fullPath := filepath.Join(s.baseDir, filepath.FromSlash(cleanKey))
+	info, err := os.Stat(fullPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, ErrObjectNotFound
+	}
+
+	contentType := mime.TypeByExtension(path.Ext(cleanKey))
+	if contentType == "" {
+		contentType = "application/octet-stream"
+	}
+
+	return &ObjectInfo{
+		Key:         cleanKey,
+		Size:        info.Size(),
+		ContentType: contentType,
+		ModifiedAt:  info.ModTime().UTC(),
+	}, nil
+}
+
+func (s *LocalStorage) SignedURL(ctx context.Context, key string, expiresIn time.Duration) (string, error) {
+	_ = ctx
+
+	if len(s.signingSecret) == 0 {
+		return "", errors.New("local storage signing secret is not configured")
+	}
+
+	cleanKey := strings.TrimLeft(path.Clean("/"+key), "/")
+	expires := strconv.FormatInt(time.Now().Add(expiresIn).Unix(), 10)
+
+	query := url.Values{}
+	query.Set("expires", expires)
+	query.Set("signature", hex.EncodeToString(s.sign(cleanKey, expires)))
+
+	return s.signedPath + "/" + (&url.URL{Path: cleanKey}).EscapedPath() + "?" + query.Encode(), nil
+}
+
+func (s *LocalStorage) VerifySignedURL(key string, expires string, signature string) error {
+	if len(s.signingSecret) == 0 {
+		return ErrInvalidSignature
+	}
+
+	cleanKey := strings.TrimLeft(path.Clean("/"+key), "/")
+	provided, err := hex.DecodeString(signature)
+	if err != nil || !hmac.Equal(s.sign(cleanKey, expires), provided) {
+		return ErrInvalidSignature
+	}
+
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if time.Now().Unix() > expiresAt {
+		return ErrSignatureExpired
+	}
+
+	return nil
+}
+
+func (s *LocalStorage) sign(key string, expires string) []byte {
+	mac := hmac.New(sha256.New, s.signingSecret)
+	mac.Write([]byte(key))
+	mac.Write([]byte{'\n'})
+	mac.Write([]byte(expires))
+	return mac.Sum(nil)
+}
+
 func (s *LocalStorage) Delete(ctx context.Context, key string) error {
 	_ = ctx
 
@@ -67,3 +196,8 @@ func (s *LocalStorage) Delete(ctx context.Context, key string) error {
 	}
 	return nil
 }
+
+type limitedReadCloser struct {
+	io.Reader
+	io.Closer
+}
@@ -2,15 +2,18 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"path"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	awsconfig "github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/config"
 )
 
@@ -86,6 +89,71 @@ func (s *S3Storage) Save(ctx context.Context, key string, reader io.Reader, size
 	return &Object{Path: cleanKey, URL: url, Size: size}, nil
 }
 
+func (s *S3Storage) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return s.OpenRange(ctx, key, 0, -1)
+}
+
+func (s *S3Storage) OpenRange(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	cleanKey := strings.TrimLeft(path.Clean("/"+key), "/")
+	input := &s3.GetObjectInput{
+		Bucket: &s.bucket,
+		Key:    &cleanKey,
+	}
+	switch {
+	case length > 0:
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	case length < 0 && offset > 0:
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-", offset))
+	case length == 0:
+		return io.NopCloser(strings.NewReader("")), nil
+	}
+
+	out, err := s.client.GetObject(ctx, input)
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	return out.Body, nil
+}
+
+func (s *S3Storage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	cleanKey := strings.TrimLeft(path.Clean("/"+key), "/")
+	out, err := s.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: &s.bucket,
+		Key:    &cleanKey,
+	})
+	if err != nil {
+		if isS3NotFound(err) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+
+	info := &ObjectInfo{
+		Key:         cleanKey,
+		Size:        out.ContentLength,
+		ContentType: aws.ToString(out.ContentType),
+	}
+	if out.LastModified != nil {
+		info.ModifiedAt = out.LastModified.UTC()
+	}
+	return info, nil
+}
+
+func (s *S3Storage) SignedURL(ctx context.Context, key string, expiresIn time.Duration) (string, error) {
+	cleanKey := strings.TrimLeft(path.Clean("/"+key), "/")
+	presigned, err := s3.NewPresignClient(s.client).PresignGetObject(ctx, &s3.GetObjectInput{
+		Bucket: &s.bucket,
+		Key:    &cleanKey,
+	}, s3.WithPresignExpires(expiresIn))
+	if err != nil {
+		return "", err
+	}
+	return presigned.URL, nil
+}
+
 func (s *S3Storage) Delete(ctx context.Context, key string) error {
 	cleanKey := strings.TrimLeft(path.Clean("/"+key), "/")
 	_, err := s.client.DeleteObject(ctx, &s3.DeleteObjectInput{
@@ -94,3 +162,9 @@ func (s *S3Storage) Delete(ctx context.Context, key string) error {
 	})
 	return err
 }
+
+func isS3NotFound(err error) bool {
+	var noSuchKey *types.NoSuchKey
+	var notFound *types.NotFound
+	return errors.As(err, &noSuchKey) || errors.As(err, &notFound)
+}
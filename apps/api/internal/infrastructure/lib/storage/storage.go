@@ -2,27 +2,54 @@ package storage
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
+	"time"
 
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/config"
 )
 
+var (
+	ErrObjectNotFound   = errors.New("storage object not found")
+	ErrInvalidSignature = errors.New("invalid storage url signature")
+	ErrSignatureExpired = errors.New("storage url signature expired")
+)
+
 type Object struct {
 	Path string
 	URL  string
 	Size int64
 }
 
+type ObjectInfo struct {
+	Key         string
+	Size        int64
+	ContentType string
+	ModifiedAt  time.Time
+}
+
 type Storage interface {
 	Save(ctx context.Context, key string, reader io.Reader, size int64, contentType string) (*Object, error)
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+	// OpenRange reads length bytes starting at offset. A negative length reads to the end of the object.
+	OpenRange(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error)
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// SignedURL returns a URL that grants read access to the object until it expires.
+	SignedURL(ctx context.Context, key string, expiresIn time.Duration) (string, error)
 	Delete(ctx context.Context, key string) error
 }
 
+// SignedURLVerifier is implemented by backends whose signed URLs are served by this API
+// rather than by the storage provider itself.
+type SignedURLVerifier interface {
+	VerifySignedURL(key string, expires string, signature string) error
+}
+
 func NewStorage(cfg config.FileStorageConfig) (Storage, error) {
 	switch cfg.Provider {
 	case "local":
-		return NewLocalStorage(cfg.Local.BaseDir, cfg.Local.PublicPath), nil
+		return NewLocalStorage(cfg.Local.BaseDir, cfg.Local.PublicPath, cfg.Local.SignedPath, cfg.Local.SigningSecret), nil
 	case "s3":
 		return NewS3Storage(cfg.S3)
 	default:
@@ -0,0 +1,81 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"gorm.io/gorm"
+)
+
+type AuditEventRepository = port.AuditEventRepository
+
+type auditEventRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditEventRepository(db *gorm.DB) AuditEventRepository {
+	return &auditEventRepository{db: db}
+}
+
+func (r *auditEventRepository) Store(ctx context.Context, event *domain.AuditEvent) error {
+	if event.ID == uuid.Nil {
+		event.ID = uuid.New()
+	}
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *auditEventRepository) List(ctx context.Context, filter port.AuditEventFilter) ([]domain.AuditEvent, int64, error) {
+	query := r.db.WithContext(ctx).Model(&domain.AuditEvent{})
+	if filter.ActorUserID != nil {
+		query = query.Where("actor_user_id = ?", *filter.ActorUserID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != nil {
+		query = query.Where("target_id = ?", *filter.TargetID)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64 = -1
+	if !filter.SkipCount {
+		if err := query.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+			return nil, 0, err
+		}
+	}
+
+	offset := filter.Offset
+	if filter.After != nil {
+		var createdAt time.Time
+		if err := json.Unmarshal(filter.After.Value, &createdAt); err != nil {
+			return nil, 0, port.ErrInvalidCursor
+		}
+		query = query.Where("(created_at, id) < (?, ?)", createdAt, filter.After.ID)
+		offset = 0
+	}
+
+	events := []domain.AuditEvent{}
+	err := query.
+		Order("created_at DESC").
+		Order("id DESC").
+		Limit(filter.Limit).
+		Offset(offset).
+		Find(&events).
+		Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return events, total, nil
+}
@@ -54,3 +54,13 @@ func (r *authSessionRepository) RevokeByUserID(ctx context.Context, userID uuid.
 		}).
 		Error
 }
+
+func (r *authSessionRepository) RevokeByDeviceID(ctx context.Context, deviceID uuid.UUID, revokedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.AuthSession{}).
+		Where("device_id = ? AND revoked_at IS NULL", deviceID).
+		Updates(map[string]any{
+			"revoked_at": revokedAt,
+		}).
+		Error
+}
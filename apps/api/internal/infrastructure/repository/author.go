@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/config"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/cache"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type AuthorRepository = port.AuthorRepository
+
+type authorRepository struct {
+	ResourceRepository[domain.Author]
+	db *gorm.DB
+}
+
+func NewAuthorRepository(cfg *config.Config, db *gorm.DB, cacheClient cache.Cache) AuthorRepository {
+	return &authorRepository{
+		ResourceRepository: NewResourceRepository[domain.Author](cfg, db, cacheClient),
+		db:                 db,
+	}
+}
+
+func (r *authorRepository) GetByName(ctx context.Context, name string) (*domain.Author, error) {
+	return getByName[domain.Author](r.db.WithContext(ctx), name)
+}
+
+func (r *authorRepository) FindOrCreateByNames(ctx context.Context, names []string) ([]domain.Author, error) {
+	authors := make([]domain.Author, len(names))
+	for i, name := range names {
+		authors[i] = domain.Author{ID: uuid.New(), Name: name}
+	}
+	return findOrCreateByNames(r.db.WithContext(ctx), names, authors)
+}
+
+func (r *authorRepository) ReplaceEbookAuthors(ctx context.Context, ebookID uuid.UUID, authorIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("ebook_id = ?", ebookID).Delete(&domain.EbookAuthor{}).Error; err != nil {
+			return err
+		}
+		if len(authorIDs) == 0 {
+			return nil
+		}
+
+		rows := make([]domain.EbookAuthor, len(authorIDs))
+		for i, authorID := range authorIDs {
+			rows[i] = domain.EbookAuthor{EbookID: ebookID, AuthorID: authorID}
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// getByName finds the active row of T with the name, ignoring case, which is
+// how the unique name indexes of authors and tags compare names.
+func getByName[T any](db *gorm.DB, name string) (*T, error) {
+	var row T
+	if err := db.Where("LOWER(name) = LOWER(?)", name).First(&row).Error; err != nil {
+		return nil, err
+	}
+	return &row, nil
+}
+
+// findOrCreateByNames inserts the rows whose name is not taken yet and returns
+// the active rows named names, ignoring case, ordered by name. Names taken by a
+// concurrent insert are skipped by the unique name index rather than failing.
+func findOrCreateByNames[T any](db *gorm.DB, names []string, rows []T) ([]T, error) {
+	found := []T{}
+	if len(names) == 0 {
+		return found, nil
+	}
+
+	if err := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	lowered := make([]string, len(names))
+	for i, name := range names {
+		lowered[i] = strings.ToLower(name)
+	}
+	if err := db.Where("LOWER(name) IN ?", lowered).Order("name ASC").Find(&found).Error; err != nil {
+		return nil, err
+	}
+	return found, nil
+}
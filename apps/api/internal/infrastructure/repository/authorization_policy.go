@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+
+	gormadapter "github.com/casbin/gorm-adapter/v3"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"gorm.io/gorm"
+)
+
+type AuthorizationPolicyRepository = port.AuthorizationPolicyRepository
+
+// authorizationPolicyRepository writes the casbin_rule rows the authorization
+// enforcer loads, so rule changes can share a transaction with other writes.
+type authorizationPolicyRepository struct {
+	db *gorm.DB
+}
+
+func NewAuthorizationPolicyRepository(db *gorm.DB) AuthorizationPolicyRepository {
+	return &authorizationPolicyRepository{db: db}
+}
+
+func (r *authorizationPolicyRepository) Add(ctx context.Context, rule []string) (bool, error) {
+	line := casbinPolicyLine(rule)
+
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&gormadapter.CasbinRule{}).
+		Where(&line, "ptype", "v0", "v1", "v2", "v3", "v4", "v5").
+		Count(&count).
+		Error
+	if err != nil || count > 0 {
+		return false, err
+	}
+
+	if err := r.db.WithContext(ctx).Create(&line).Error; err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *authorizationPolicyRepository) Remove(ctx context.Context, rule []string) (bool, error) {
+	line := casbinPolicyLine(rule)
+	result := r.db.WithContext(ctx).
+		Where(&line, "ptype", "v0", "v1", "v2", "v3", "v4", "v5").
+		Delete(&gormadapter.CasbinRule{})
+	return result.RowsAffected > 0, result.Error
+}
+
+func casbinPolicyLine(rule []string) gormadapter.CasbinRule {
+	values := make([]string, 6)
+	copy(values, rule)
+	return gormadapter.CasbinRule{
+		Ptype: "p",
+		V0:    values[0],
+		V1:    values[1],
+		V2:    values[2],
+		V3:    values[3],
+		V4:    values[4],
+		V5:    values[5],
+	}
+}
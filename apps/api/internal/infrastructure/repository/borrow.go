@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
@@ -46,3 +47,111 @@ func (r *borrowRepository) GetActiveByShareAndBorrower(ctx context.Context, shar
 	}
 	return &borrow, nil
 }
+
+// ExpireOverdue marks up to limit active borrows that are past due as expired and
+// returns them. Rows already claimed by a concurrent sweep are skipped, so several
+// workers can run this at the same time without double-processing a borrow.
+func (r *borrowRepository) ExpireOverdue(ctx context.Context, now time.Time, limit int) ([]domain.Borrow, error) {
+	var expired []domain.Borrow
+	err := r.db.WithContext(ctx).
+		Raw(`
+			UPDATE borrows
+			SET status = @expired, expired_at = @now, updated_at = @now
+			WHERE id IN (
+				SELECT id FROM borrows
+				WHERE status = @active AND due_at <= @now
+				ORDER BY due_at
+				LIMIT @limit
+				FOR UPDATE SKIP LOCKED
+			)
+			AND status = @active
+			RETURNING *`,
+			map[string]any{
+				"expired": domain.BorrowStatusExpired,
+				"active":  domain.BorrowStatusActive,
+				"now":     now,
+				"limit":   limit,
+			}).
+		Scan(&expired).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range expired {
+		r.EvictCache(ctx, expired[i].ID)
+	}
+	return expired, nil
+}
+
+func (r *borrowRepository) RevokeActiveByShare(ctx context.Context, shareID uuid.UUID, now time.Time) ([]domain.Borrow, error) {
+	var revoked []domain.Borrow
+	err := r.db.WithContext(ctx).
+		Raw(`
+			UPDATE borrows
+			SET status = @revoked, updated_at = @now
+			WHERE share_id = @share_id AND status = @active
+			RETURNING *`,
+			map[string]any{
+				"revoked":  domain.BorrowStatusRevoked,
+				"active":   domain.BorrowStatusActive,
+				"share_id": shareID,
+				"now":      now,
+			}).
+		Scan(&revoked).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range revoked {
+		r.EvictCache(ctx, revoked[i].ID)
+	}
+	return revoked, nil
+}
+
+func (r *borrowRepository) FlagActiveByShare(ctx context.Context, shareID uuid.UUID, now time.Time) ([]domain.Borrow, error) {
+	var flagged []domain.Borrow
+	err := r.db.WithContext(ctx).
+		Raw(`
+			UPDATE borrows
+			SET flagged_at = @now, updated_at = @now
+			WHERE share_id = @share_id AND status = @active AND flagged_at IS NULL
+			RETURNING *`,
+			map[string]any{
+				"active":   domain.BorrowStatusActive,
+				"share_id": shareID,
+				"now":      now,
+			}).
+		Scan(&flagged).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range flagged {
+		r.EvictCache(ctx, flagged[i].ID)
+	}
+	return flagged, nil
+}
+
+func (r *borrowRepository) UnflagByShare(ctx context.Context, shareID uuid.UUID) ([]domain.Borrow, error) {
+	var unflagged []domain.Borrow
+	err := r.db.WithContext(ctx).
+		Raw(`
+			UPDATE borrows
+			SET flagged_at = NULL, updated_at = NOW()
+			WHERE share_id = ? AND flagged_at IS NOT NULL
+			RETURNING *`,
+			shareID).
+		Scan(&unflagged).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range unflagged {
+		r.EvictCache(ctx, unflagged[i].ID)
+	}
+	return unflagged, nil
+}
@@ -0,0 +1,153 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DeviceRepository = port.DeviceRepository
+
+type deviceRepository struct {
+	db *gorm.DB
+}
+
+func NewDeviceRepository(db *gorm.DB) DeviceRepository {
+	return &deviceRepository{db: db}
+}
+
+func (r *deviceRepository) Upsert(ctx context.Context, device *domain.Device) error {
+	now := time.Now().UTC()
+	if device.ID == uuid.Nil {
+		device.ID = uuid.New()
+	}
+	device.LastSeenAt = now
+	device.CreatedAt = now
+	device.UpdatedAt = now
+
+	// The name is only taken on insert so a rename is not undone by the next
+	// login, and a revoked device stays revoked.
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "user_id"}, {Name: "client_id"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"last_seen_at": now,
+				"updated_at":   now,
+			}),
+		}).
+		Create(device).
+		Error
+	if err != nil {
+		return err
+	}
+
+	// On conflict the existing row keeps its id, so read it back.
+	var stored domain.Device
+	err = r.db.WithContext(ctx).
+		Where("user_id = ? AND client_id = ?", device.UserID, device.ClientID).
+		First(&stored).
+		Error
+	if err != nil {
+		return err
+	}
+	*device = stored
+	return nil
+}
+
+func (r *deviceRepository) ListByUserID(ctx context.Context, userID uuid.UUID) ([]domain.Device, error) {
+	var devices []domain.Device
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Order("last_seen_at DESC").
+		Find(&devices).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return devices, nil
+}
+
+func (r *deviceRepository) GetByUserAndID(ctx context.Context, userID uuid.UUID, id uuid.UUID) (*domain.Device, error) {
+	var device domain.Device
+	if err := r.db.WithContext(ctx).First(&device, "id = ? AND user_id = ?", id, userID).Error; err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+func (r *deviceRepository) GetByUserAndClientID(ctx context.Context, userID uuid.UUID, clientID string) (*domain.Device, error) {
+	var device domain.Device
+	if err := r.db.WithContext(ctx).First(&device, "user_id = ? AND client_id = ?", userID, clientID).Error; err != nil {
+		return nil, err
+	}
+	return &device, nil
+}
+
+func (r *deviceRepository) Rename(ctx context.Context, id uuid.UUID, name string) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.Device{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"name":       name,
+			"updated_at": time.Now().UTC(),
+		}).
+		Error
+}
+
+func (r *deviceRepository) Revoke(ctx context.Context, id uuid.UUID, revokedAt time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&domain.Device{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Updates(map[string]any{
+			"revoked_at": revokedAt,
+			"updated_at": revokedAt,
+		}).
+		Error
+}
+
+func (r *deviceRepository) RecordSync(ctx context.Context, userID uuid.UUID, clientID string, position *port.SyncEventCursor, syncedAt time.Time) error {
+	updates := map[string]any{
+		"last_seen_at":   syncedAt,
+		"last_synced_at": syncedAt,
+		"updated_at":     syncedAt,
+	}
+	if position != nil {
+		// A position built from a since timestamp ends on the max UUID and names
+		// no event.
+		var eventID *uuid.UUID
+		if position.ID != uuid.Max {
+			eventID = &position.ID
+		}
+
+		// The checkpoint only moves forward, so replaying an old page is harmless.
+		advances := "sync_server_timestamp IS NULL OR sync_server_timestamp < ?"
+		updates["sync_server_timestamp"] = gorm.Expr("CASE WHEN "+advances+" THEN ? ELSE sync_server_timestamp END", position.ServerTimestamp, position.ServerTimestamp)
+		updates["sync_event_id"] = gorm.Expr("CASE WHEN "+advances+" THEN ? ELSE sync_event_id END", position.ServerTimestamp, eventID)
+	}
+
+	return r.db.WithContext(ctx).
+		Model(&domain.Device{}).
+		Where("user_id = ? AND client_id = ? AND revoked_at IS NULL", userID, clientID).
+		Updates(updates).
+		Error
+}
+
+func (r *deviceRepository) GetSyncHorizon(ctx context.Context, userID uuid.UUID, activeSince time.Time) (*time.Time, error) {
+	// A device that has not pulled yet holds the horizon at the epoch.
+	var horizon *time.Time
+	err := r.db.WithContext(ctx).
+		Model(&domain.Device{}).
+		Select("MIN(COALESCE(sync_server_timestamp, 'epoch'::timestamptz))").
+		Where("user_id = ? AND revoked_at IS NULL AND last_seen_at >= ?", userID, activeSince).
+		Scan(&horizon).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return horizon, nil
+}
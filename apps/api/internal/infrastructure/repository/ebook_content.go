@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EbookContentRepository = port.EbookContentRepository
+
+// passageBatchSize keeps the inserts of a long book under Postgres' limit of
+// bind parameters.
+const passageBatchSize = 1000
+
+type ebookContentRepository struct {
+	db *gorm.DB
+}
+
+func NewEbookContentRepository(db *gorm.DB) EbookContentRepository {
+	return &ebookContentRepository{db: db}
+}
+
+func (r *ebookContentRepository) GetIndexByEbookID(ctx context.Context, ebookID uuid.UUID) (*domain.EbookContentIndex, error) {
+	var index domain.EbookContentIndex
+	if err := r.db.WithContext(ctx).Where("ebook_id = ?", ebookID).First(&index).Error; err != nil {
+		return nil, err
+	}
+	return &index, nil
+}
+
+func (r *ebookContentRepository) ReplaceIndex(ctx context.Context, index *domain.EbookContentIndex, passages []domain.EbookPassage) error {
+	if index == nil {
+		return errors.New("index is required")
+	}
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("ebook_id = ?", index.EbookID).Delete(&domain.EbookPassage{}).Error; err != nil {
+			return err
+		}
+
+		if len(passages) > 0 {
+			for i := range passages {
+				passages[i].EbookID = index.EbookID
+			}
+			if err := tx.CreateInBatches(&passages, passageBatchSize).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "ebook_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"checksum_sha256", "status", "passage_count", "error", "indexed_at"}),
+		}).
+			Create(index).
+			Error
+	})
+}
+
+func (r *ebookContentRepository) SearchPassages(ctx context.Context, ebookID uuid.UUID, query port.SearchQuery) ([]domain.EbookPassageHit, int64, error) {
+	matches := r.db.WithContext(ctx).
+		Table("ebook_passages AS p").
+		Joins("JOIN ebooks AS e ON e.id = p.ebook_id").
+		Joins("CROSS JOIN LATERAL websearch_to_tsquery(search_config(e.language_code), ?) AS q(query)", query.Text).
+		Where("p.ebook_id = ?", ebookID).
+		Where("p.search_vector @@ q.query")
+
+	var total int64
+	if err := matches.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	page := matches.
+		Select(`
+			p.position,
+			p.location,
+			p.content,
+			search_config(e.language_code) AS cfg,
+			q.query,
+			ts_rank_cd(p.search_vector, q.query, 32) AS rank`).
+		Order("p.position").
+		Limit(query.Limit).
+		Offset(query.Offset)
+
+	// as in searchRepository.search, only the rows of the page are highlighted
+	hits := []domain.EbookPassageHit{}
+	err := r.db.WithContext(ctx).
+		Table("(?) AS m", page).
+		Select(`
+			m.position,
+			m.location,
+			ts_headline(m.cfg, m.content, m.query, ?) AS snippet,
+			m.rank`, searchSnippetOptions).
+		Order("m.position").
+		Scan(&hits).
+		Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return hits, total, nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"gorm.io/gorm"
+)
+
+type EbookMetadataSuggestionRepository = port.EbookMetadataSuggestionRepository
+
+type ebookMetadataSuggestionRepository struct {
+	db *gorm.DB
+}
+
+func NewEbookMetadataSuggestionRepository(db *gorm.DB) EbookMetadataSuggestionRepository {
+	return &ebookMetadataSuggestionRepository{db: db}
+}
+
+func (r *ebookMetadataSuggestionRepository) ListByEbookID(ctx context.Context, ebookID uuid.UUID) ([]domain.EbookMetadataSuggestion, error) {
+	suggestions := []domain.EbookMetadataSuggestion{}
+	err := r.db.WithContext(ctx).
+		Where("ebook_id = ?", ebookID).
+		Order("score DESC").
+		Order("id ASC").
+		Find(&suggestions).
+		Error
+	return suggestions, err
+}
+
+func (r *ebookMetadataSuggestionRepository) GetByID(ctx context.Context, id uuid.UUID) (*domain.EbookMetadataSuggestion, error) {
+	var suggestion domain.EbookMetadataSuggestion
+	if err := r.db.WithContext(ctx).First(&suggestion, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &suggestion, nil
+}
+
+func (r *ebookMetadataSuggestionRepository) ReplaceByEbookID(ctx context.Context, ebookID uuid.UUID, suggestions []domain.EbookMetadataSuggestion) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("ebook_id = ?", ebookID).Delete(&domain.EbookMetadataSuggestion{}).Error; err != nil {
+			return err
+		}
+		if len(suggestions) == 0 {
+			return nil
+		}
+
+		for i := range suggestions {
+			suggestions[i].EbookID = ebookID
+			if suggestions[i].ID == uuid.Nil {
+				suggestions[i].ID = uuid.New()
+			}
+		}
+		return tx.Create(&suggestions).Error
+	})
+}
+
+func (r *ebookMetadataSuggestionRepository) DeleteByEbookID(ctx context.Context, ebookID uuid.UUID) error {
+	return r.db.WithContext(ctx).
+		Where("ebook_id = ?", ebookID).
+		Delete(&domain.EbookMetadataSuggestion{}).
+		Error
+}
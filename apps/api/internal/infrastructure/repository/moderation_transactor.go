@@ -0,0 +1,34 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/config"
+	"gorm.io/gorm"
+)
+
+type ModerationTransactor = port.ModerationTransactor
+
+type moderationTransactor struct {
+	cfg *config.Config
+	db  *gorm.DB
+}
+
+func NewModerationTransactor(cfg *config.Config, db *gorm.DB) ModerationTransactor {
+	return &moderationTransactor{cfg: cfg, db: db}
+}
+
+func (t *moderationTransactor) WithinTransaction(ctx context.Context, fn func(repos *port.ModerationRepositories) error) error {
+	return t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return fn(&port.ModerationRepositories{
+			User:                NewUserRepository(t.cfg, tx, nil),
+			AuthSession:         NewAuthSessionRepository(tx),
+			Share:               NewShareRepository(t.cfg, tx, nil),
+			Borrow:              NewBorrowRepository(t.cfg, tx, nil),
+			ShareReport:         NewShareReportRepository(t.cfg, tx, nil),
+			AuthorizationPolicy: NewAuthorizationPolicyRepository(tx),
+			AuditEvent:          NewAuditEventRepository(tx),
+		})
+	})
+}
@@ -2,6 +2,9 @@ package repository
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"maps"
 	"reflect"
 	"strings"
 
@@ -12,6 +15,7 @@ import (
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/cache"
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/utils"
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
 type ResourceRepository[T domain.BaseModel] = port.ResourceRepository[T]
@@ -96,6 +100,35 @@ func (r *resourceRepository[T]) Update(ctx context.Context, entity T, updates ..
 	return &entity, nil
 }
 
+func (r *resourceRepository[T]) UpdateVersioned(ctx context.Context, entity T, updates map[string]any, ifVersion *int64) (*T, error) {
+	if _, ok := any(entity).(domain.VersionedModel); !ok {
+		return r.Update(ctx, entity, updates)
+	}
+
+	assignments := maps.Clone(updates)
+	assignments["row_version"] = gorm.Expr("row_version + 1")
+	query := r.db.WithContext(ctx).Model(&entity)
+	if ifVersion != nil {
+		query = query.Where("row_version = ?", *ifVersion)
+	}
+	result := query.Updates(assignments)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	r.EvictCache(ctx, entity.GetID())
+	if result.RowsAffected == 0 {
+		return nil, port.ErrStaleRowVersion
+	}
+
+	// The new version is only known to the database, so read the row back.
+	var updated T
+	if err := r.db.WithContext(ctx).First(&updated, entity.GetID()).Error; err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
 func (r *resourceRepository[T]) Destroy(ctx context.Context, id uuid.UUID) error {
 	if err := r.db.WithContext(ctx).Delete(new(T), id).Error; err != nil {
 		return err
@@ -116,6 +149,32 @@ func (r *resourceRepository[T]) Kill(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
+func (r *resourceRepository[T]) DestroyVersioned(ctx context.Context, id uuid.UUID, ifVersion *int64) error {
+	return r.deleteVersioned(ctx, r.db.WithContext(ctx), id, ifVersion)
+}
+
+func (r *resourceRepository[T]) KillVersioned(ctx context.Context, id uuid.UUID, ifVersion *int64) error {
+	return r.deleteVersioned(ctx, r.db.WithContext(ctx).Unscoped(), id, ifVersion)
+}
+
+// deleteVersioned deletes the row id with query, in the same statement as the
+// check that it is still at ifVersion, when one is given.
+func (r *resourceRepository[T]) deleteVersioned(ctx context.Context, query *gorm.DB, id uuid.UUID, ifVersion *int64) error {
+	if ifVersion != nil {
+		query = query.Where("row_version = ?", *ifVersion)
+	}
+	result := query.Delete(new(T), id)
+	if result.Error != nil {
+		return result.Error
+	}
+
+	r.EvictCache(ctx, id)
+	if ifVersion != nil && result.RowsAffected == 0 {
+		return port.ErrStaleRowVersion
+	}
+	return nil
+}
+
 func (r *resourceRepository[T]) Restore(ctx context.Context, id uuid.UUID) (*T, error) {
 	if err := r.db.WithContext(ctx).
 		Unscoped().
@@ -136,29 +195,112 @@ func (r *resourceRepository[T]) GetMany(ctx context.Context, opts GetManyOptions
 
 	var (
 		entities []T
-		total    int64
+		total    int64 = -1
 	)
 
-	countQuery := r.db.WithContext(ctx).Model(new(T))
-	countQuery = applyJoins(countQuery, opts.Joins)
-	countQuery = applyFilters(countQuery, opts.Filters)
-	countQuery = applyWheres(countQuery, opts.Wheres)
-	if err := countQuery.Count(&total).Error; err != nil {
+	orderColumn, idColumn, orderField, err := r.orderColumns(opts.OrderBy, opts.After != nil)
+	if err != nil {
 		return nil, 0, err
 	}
 
-	listQuery := r.db.WithContext(ctx).Model(new(T))
+	if !opts.SkipCount {
+		countQuery := r.scoped(ctx, opts).Model(new(T))
+		countQuery = applyJoins(countQuery, opts.Joins)
+		countQuery = applyFilters(countQuery, opts.Filters)
+		countQuery = applyWheres(countQuery, opts.Wheres)
+		if err := countQuery.Count(&total).Error; err != nil {
+			return nil, 0, err
+		}
+	}
+
+	listQuery := r.scoped(ctx, opts).Model(new(T))
 	listQuery = applyJoins(listQuery, opts.Joins)
 	listQuery = applyFilters(listQuery, opts.Filters)
 	listQuery = applyWheres(listQuery, opts.Wheres)
 	listQuery = applyPreloads(listQuery, opts.Preloads)
-	if err := listQuery.Limit(opts.Limit).Offset(opts.Offset).Order(opts.OrderBy + " " + opts.OrderDirection).Find(&entities).Error; err != nil {
+	if opts.After != nil {
+		value, err := cursorValue(orderField, opts.After.Value)
+		if err != nil {
+			return nil, 0, err
+		}
+		comparison := "<"
+		if opts.OrderDirection == "asc" {
+			comparison = ">"
+		}
+		if orderColumn == idColumn {
+			listQuery = listQuery.Where(idColumn+" "+comparison+" ?", opts.After.ID)
+		} else {
+			listQuery = listQuery.Where("("+orderColumn+", "+idColumn+") "+comparison+" (?, ?)", value, opts.After.ID)
+		}
+	}
+	order := orderColumn + " " + opts.OrderDirection
+	if orderColumn != idColumn {
+		order += ", " + idColumn + " " + opts.OrderDirection
+	}
+	if err := listQuery.Limit(opts.Limit).Offset(opts.Offset).Order(order).Find(&entities).Error; err != nil {
 		return nil, 0, err
 	}
 
 	return entities, total, nil
 }
 
+// orderColumns resolves orderBy, a column or field name of T, and the primary
+// key that breaks ties between equal values to table-qualified columns. A
+// keyset listing compares row values, which is never true for a null, so it
+// cannot be ordered by a nullable column.
+func (r *resourceRepository[T]) orderColumns(orderBy string, keyset bool) (string, string, *schema.Field, error) {
+	stmt := &gorm.Statement{DB: r.db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return "", "", nil, err
+	}
+
+	field := stmt.Schema.LookUpField(strings.TrimSpace(orderBy))
+	if field == nil || field.DBName == "" {
+		return "", "", nil, port.ErrInvalidOrderBy
+	}
+	if keyset && nullable(field) {
+		return "", "", nil, port.ErrNullableOrderBy
+	}
+	primaryKey := stmt.Schema.PrioritizedPrimaryField
+	if primaryKey == nil {
+		return "", "", nil, port.ErrInvalidOrderBy
+	}
+
+	table := stmt.Schema.Table
+	return table + "." + field.DBName, table + "." + primaryKey.DBName, field, nil
+}
+
+// cursorValue decodes a cursor's JSON value into the Go type of the order
+// field, so it is bound as that column's type.
+func cursorValue(field *schema.Field, raw json.RawMessage) (any, error) {
+	value := reflect.New(field.FieldType)
+	if err := json.Unmarshal(raw, value.Interface()); err != nil {
+		return nil, port.ErrInvalidCursor
+	}
+	return value.Elem().Interface(), nil
+}
+
+// nullable reports whether the field's column may hold null, going by its Go
+// type: pointers and sql.Null style structs such as gorm.DeletedAt.
+func nullable(field *schema.Field) bool {
+	if field.PrimaryKey || field.NotNull {
+		return false
+	}
+	if field.FieldType.Kind() == reflect.Pointer {
+		return true
+	}
+	_, scanner := reflect.New(field.FieldType).Interface().(sql.Scanner)
+	return scanner && field.FieldType.Kind() == reflect.Struct
+}
+
+func (r *resourceRepository[T]) scoped(ctx context.Context, opts GetManyOptions) *gorm.DB {
+	db := r.db.WithContext(ctx)
+	if opts.WithDeleted {
+		return db.Unscoped()
+	}
+	return db
+}
+
 func applyFilters(db *gorm.DB, filters map[string]any) *gorm.DB {
 	if len(filters) > 0 {
 		return db.Where(filters)
@@ -8,6 +8,7 @@ import (
 	"unicode"
 
 	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
 	"github.com/jeheskielSunloy77/libra-link/internal/domain"
 	"gorm.io/gorm"
 )
@@ -64,6 +65,11 @@ func (m *MockResourceRepository[T]) GetMany(ctx context.Context, opts GetManyOpt
 	for _, v := range m.data {
 		list = append(list, v)
 	}
+	if opts.WithDeleted {
+		for _, v := range m.deleted {
+			list = append(list, v)
+		}
+	}
 	return list, int64(len(list)), nil
 }
 
@@ -81,6 +87,28 @@ func (m *MockResourceRepository[T]) Update(ctx context.Context, entity T, update
 	return &entity, nil
 }
 
+func (m *MockResourceRepository[T]) UpdateVersioned(ctx context.Context, entity T, updates map[string]any, ifVersion *int64) (*T, error) {
+	if _, ok := any(entity).(domain.VersionedModel); !ok {
+		return m.Update(ctx, entity, updates)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stored, ok := m.data[entity.GetID()]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	version := any(stored).(domain.VersionedModel).GetRowVersion()
+	if ifVersion != nil && *ifVersion != version {
+		return nil, port.ErrStaleRowVersion
+	}
+
+	applyUpdates(&entity, updates)
+	applyUpdates(&entity, map[string]any{"row_version": version + 1})
+	m.data[entity.GetID()] = entity
+	return &entity, nil
+}
+
 func (m *MockResourceRepository[T]) Destroy(ctx context.Context, id uuid.UUID) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -101,6 +129,40 @@ func (m *MockResourceRepository[T]) Kill(ctx context.Context, id uuid.UUID) erro
 	return nil
 }
 
+func (m *MockResourceRepository[T]) DestroyVersioned(ctx context.Context, id uuid.UUID, ifVersion *int64) error {
+	if ifVersion != nil {
+		m.mu.RLock()
+		stored, ok := m.data[id]
+		m.mu.RUnlock()
+		if !ok || !atRowVersion(stored, *ifVersion) {
+			return port.ErrStaleRowVersion
+		}
+	}
+	return m.Destroy(ctx, id)
+}
+
+func (m *MockResourceRepository[T]) KillVersioned(ctx context.Context, id uuid.UUID, ifVersion *int64) error {
+	if ifVersion != nil {
+		m.mu.RLock()
+		stored, ok := m.data[id]
+		if !ok {
+			stored, ok = m.deleted[id]
+		}
+		m.mu.RUnlock()
+		if !ok || !atRowVersion(stored, *ifVersion) {
+			return port.ErrStaleRowVersion
+		}
+	}
+	return m.Kill(ctx, id)
+}
+
+// atRowVersion reports whether entity is at version, which models without a
+// RowVersion never are.
+func atRowVersion[T any](entity T, version int64) bool {
+	versioned, ok := any(entity).(domain.VersionedModel)
+	return ok && versioned.GetRowVersion() == version
+}
+
 func (m *MockResourceRepository[T]) Restore(ctx context.Context, id uuid.UUID) (*T, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"gorm.io/gorm"
+)
+
+type SearchRepository = port.SearchRepository
+
+// Options of ts_headline. Titles are short, so they are highlighted whole, while
+// snippets keep the two best passages.
+const (
+	searchHeadlineOptions = `StartSel="` + domain.SearchHighlightStart + `", StopSel="` + domain.SearchHighlightStop + `", HighlightAll=true`
+	searchSnippetOptions  = `StartSel="` + domain.SearchHighlightStart + `", StopSel="` + domain.SearchHighlightStop + `", MaxFragments=2, MaxWords=30, MinWords=10, FragmentDelimiter=" … "`
+)
+
+type searchRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository(db *gorm.DB) SearchRepository {
+	return &searchRepository{db: db}
+}
+
+// Each search first narrows the rows with search_queries, which the GIN
+// indexes on search_vector can serve, then matches them with the query parsed
+// in their own ebook's language.
+func (r *searchRepository) SearchLibrary(ctx context.Context, userID uuid.UUID, query port.SearchQuery) ([]domain.SearchHit, int64, error) {
+	matches := r.db.WithContext(ctx).
+		Table("ebooks AS e").
+		Joins("CROSS JOIN LATERAL websearch_to_tsquery(search_config(e.language_code), ?) AS q(query)", query.Text).
+		Where("e.owner_user_id = ? AND e.deleted_at IS NULL", userID).
+		Where("e.search_vector @@ ANY(search_queries(?))", query.Text).
+		Where("e.search_vector @@ q.query")
+
+	hits, total, err := r.search(ctx, matches, query, `
+		e.id,
+		e.id AS ebook_id,
+		NULL::uuid AS share_id,
+		e.title,
+		concat_ws(' · ', ebook_author_names(e.id), e.description, ebook_tag_names(e.id), ebook_metadata_terms(e.id)) AS snippet_text,
+		search_config(e.language_code) AS cfg,
+		q.query,
+		ts_rank_cd(e.search_vector, q.query, 32) AS rank`)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range hits {
+		hits[i].Scope = domain.SearchScopeLibrary
+	}
+	return hits, total, nil
+}
+
+func (r *searchRepository) SearchCommunity(ctx context.Context, query port.SearchQuery) ([]domain.SearchHit, int64, error) {
+	matches := r.db.WithContext(ctx).
+		Table("shares AS s").
+		Joins("JOIN ebooks AS e ON e.id = s.ebook_id AND e.deleted_at IS NULL").
+		Joins("CROSS JOIN LATERAL websearch_to_tsquery(search_config(e.language_code), ?) AS q(query)", query.Text).
+		Where("s.deleted_at IS NULL AND s.status = ? AND s.visibility = ?", domain.ShareStatusActive, domain.ShareVisibilityPublic).
+		Where(`s.id IN (
+			SELECT id FROM shares WHERE search_vector @@ ANY(search_queries(?))
+			UNION
+			SELECT sh.id FROM shares AS sh JOIN ebooks AS eb ON eb.id = sh.ebook_id WHERE eb.search_vector @@ ANY(search_queries(?))
+		)`, query.Text, query.Text).
+		Where("s.search_vector @@ q.query OR e.search_vector @@ q.query")
+
+	// A share with its own title still matches its ebook's, so the snippet shows
+	// the ebook's title in that case.
+	hits, total, err := r.search(ctx, matches, query, `
+		s.id,
+		e.id AS ebook_id,
+		s.id AS share_id,
+		coalesce(s.title_override, e.title) AS title,
+		concat_ws(' · ', CASE WHEN s.title_override IS NOT NULL THEN e.title END, ebook_author_names(e.id), s.description, e.description, ebook_tag_names(e.id), ebook_metadata_terms(e.id)) AS snippet_text,
+		search_config(e.language_code) AS cfg,
+		q.query,
+		ts_rank_cd(s.search_vector || e.search_vector, q.query, 32) AS rank`)
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range hits {
+		hits[i].Scope = domain.SearchScopeCommunity
+	}
+	return hits, total, nil
+}
+
+// search counts the rows of matches and returns the requested page of them,
+// selected as columns. It highlights only the rows of the page, as ts_headline
+// is expensive.
+func (r *searchRepository) search(ctx context.Context, matches *gorm.DB, query port.SearchQuery, columns string) ([]domain.SearchHit, int64, error) {
+	var total int64
+	if err := matches.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	page := matches.
+		Select(columns).
+		Order("rank DESC").
+		Order("id").
+		Limit(query.Limit).
+		Offset(query.Offset)
+
+	hits := []domain.SearchHit{}
+	err := r.db.WithContext(ctx).
+		Table("(?) AS m", page).
+		Select(`
+			m.ebook_id,
+			m.share_id,
+			m.title,
+			ts_headline(m.cfg, m.title, m.query, ?) AS headline,
+			coalesce(ts_headline(m.cfg, m.snippet_text, m.query, ?), '') AS snippet,
+			m.rank`, searchHeadlineOptions, searchSnippetOptions).
+		Order("m.rank DESC").
+		Order("m.id").
+		Scan(&hits).
+		Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return hits, total, nil
+}
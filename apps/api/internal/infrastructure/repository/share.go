@@ -1,21 +1,64 @@
 package repository
 
 import (
+	"context"
+
+	"github.com/google/uuid"
 	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
 	"github.com/jeheskielSunloy77/libra-link/internal/domain"
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/config"
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/cache"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ShareRepository = port.ShareRepository
 
 type shareRepository struct {
 	ResourceRepository[domain.Share]
+	db *gorm.DB
 }
 
 func NewShareRepository(cfg *config.Config, db *gorm.DB, cacheClient cache.Cache) ShareRepository {
 	return &shareRepository{
 		ResourceRepository: NewResourceRepository[domain.Share](cfg, db, cacheClient),
+		db:                 db,
+	}
+}
+
+func (r *shareRepository) GetForUpdate(ctx context.Context, id uuid.UUID) (*domain.Share, error) {
+	var share domain.Share
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&share, "id = ?", id).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &share, nil
+}
+
+func (r *shareRepository) DisableActiveByOwner(ctx context.Context, ownerID uuid.UUID) ([]domain.Share, error) {
+	var disabled []domain.Share
+	err := r.db.WithContext(ctx).
+		Raw(`
+			UPDATE shares
+			SET status = @disabled, updated_at = NOW()
+			WHERE owner_user_id = @owner_id AND status = @active AND deleted_at IS NULL
+			RETURNING *`,
+			map[string]any{
+				"disabled": domain.ShareStatusDisabled,
+				"active":   domain.ShareStatusActive,
+				"owner_id": ownerID,
+			}).
+		Scan(&disabled).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range disabled {
+		r.EvictCache(ctx, disabled[i].ID)
 	}
+	return disabled, nil
 }
@@ -1,21 +1,80 @@
 package repository
 
 import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
 	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
 	"github.com/jeheskielSunloy77/libra-link/internal/domain"
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/config"
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/cache"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ShareReportRepository = port.ShareReportRepository
 
 type shareReportRepository struct {
 	ResourceRepository[domain.ShareReport]
+	db *gorm.DB
 }
 
 func NewShareReportRepository(cfg *config.Config, db *gorm.DB, cacheClient cache.Cache) ShareReportRepository {
 	return &shareReportRepository{
 		ResourceRepository: NewResourceRepository[domain.ShareReport](cfg, db, cacheClient),
+		db:                 db,
+	}
+}
+
+func (r *shareReportRepository) GetForUpdate(ctx context.Context, id uuid.UUID) (*domain.ShareReport, error) {
+	var report domain.ShareReport
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&report, "id = ?", id).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &report, nil
+}
+
+func (r *shareReportRepository) CountPendingReporters(ctx context.Context, shareID uuid.UUID, reason domain.ReportReason) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&domain.ShareReport{}).
+		Where("share_id = ? AND reason = ? AND status IN ?", shareID, reason, []domain.ReportStatus{domain.ReportStatusOpen, domain.ReportStatusInReview}).
+		Distinct("reporter_user_id").
+		Count(&count).
+		Error
+	return count, err
+}
+
+func (r *shareReportRepository) ClosePendingByShare(ctx context.Context, shareID uuid.UUID, status domain.ReportStatus, moderatorID uuid.UUID, note string, now time.Time) ([]domain.ShareReport, error) {
+	var closed []domain.ShareReport
+	err := r.db.WithContext(ctx).
+		Raw(`
+			UPDATE share_reports
+			SET status = @status, reviewed_by_user_id = @moderator_id, reviewed_at = @now, resolution_note = @note, updated_at = @now
+			WHERE share_id = @share_id AND status IN (@open, @in_review)
+			RETURNING *`,
+			map[string]any{
+				"status":       status,
+				"open":         domain.ReportStatusOpen,
+				"in_review":    domain.ReportStatusInReview,
+				"moderator_id": moderatorID,
+				"note":         note,
+				"share_id":     shareID,
+				"now":          now,
+			}).
+		Scan(&closed).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range closed {
+		r.EvictCache(ctx, closed[i].ID)
 	}
+	return closed, nil
 }
@@ -35,19 +35,103 @@ func (r *syncEventRepository) GetByUserAndIdempotencyKey(ctx context.Context, us
 	return &event, nil
 }
 
-func (r *syncEventRepository) ListSince(ctx context.Context, userID uuid.UUID, since *time.Time, limit int) ([]domain.SyncEvent, error) {
+func (r *syncEventRepository) ListAfter(ctx context.Context, userID uuid.UUID, after *port.SyncEventCursor, limit int) ([]domain.SyncEvent, error) {
 	if limit <= 0 {
 		limit = 100
 	}
 
 	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
-	if since != nil {
-		query = query.Where("server_timestamp > ?", *since)
+	if after != nil {
+		query = query.Where("(server_timestamp, id) > (?, ?)", after.ServerTimestamp, after.ID)
 	}
 
 	var events []domain.SyncEvent
-	if err := query.Order("server_timestamp asc").Limit(limit).Find(&events).Error; err != nil {
+	if err := query.Order("server_timestamp asc, id asc").Limit(limit).Find(&events).Error; err != nil {
 		return nil, err
 	}
 	return events, nil
 }
+
+func (r *syncEventRepository) GetLatestByUserID(ctx context.Context, userID uuid.UUID) (*domain.SyncEvent, error) {
+	var event domain.SyncEvent
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("server_timestamp desc, id desc").First(&event).Error
+	if err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+func (r *syncEventRepository) ListCompactableUsers(ctx context.Context, afterUserID uuid.UUID, limit int) ([]uuid.UUID, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+
+	var userIDs []uuid.UUID
+	err := r.db.WithContext(ctx).
+		Model(&domain.SyncEvent{}).
+		Where("user_id > ?", afterUserID).
+		Group("user_id, entity_type, entity_id").
+		Having("COUNT(*) > 1").
+		Distinct().
+		Order("user_id asc").
+		Limit(limit).
+		Pluck("user_id", &userIDs).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return userIDs, nil
+}
+
+func (r *syncEventRepository) ListSupersededUntil(ctx context.Context, userID uuid.UUID, until time.Time) ([]domain.SyncEvent, error) {
+	superseded := r.db.
+		Model(&domain.SyncEvent{}).
+		Select("entity_type, entity_id").
+		Where("user_id = ? AND server_timestamp <= ?", userID, until).
+		Group("entity_type, entity_id").
+		Having("COUNT(*) > 1")
+
+	var events []domain.SyncEvent
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND server_timestamp <= ?", userID, until).
+		Where("(entity_type, entity_id) IN (?)", superseded).
+		Order("entity_type asc, entity_id asc, server_timestamp asc, id asc").
+		Find(&events).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *syncEventRepository) Collapse(ctx context.Context, keep *domain.SyncEvent, supersededIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.SyncEvent{ID: keep.ID}).Select("payload").Updates(keep).Error; err != nil {
+			return err
+		}
+		if len(supersededIDs) == 0 {
+			return nil
+		}
+		return tx.Where("id IN ?", supersededIDs).Delete(&domain.SyncEvent{}).Error
+	})
+}
+
+func (r *syncEventRepository) DeleteBefore(ctx context.Context, before time.Time, limit int) (int64, error) {
+	if limit <= 0 {
+		limit = 1000
+	}
+
+	// The event a user checkpoint points at is kept so the checkpoint stays valid.
+	checkpointed := r.db.
+		Model(&domain.SyncCheckpoint{}).
+		Select("last_event_id").
+		Where("last_event_id IS NOT NULL")
+	expired := r.db.
+		Model(&domain.SyncEvent{}).
+		Select("id").
+		Where("server_timestamp < ? AND id NOT IN (?)", before, checkpointed).
+		Limit(limit)
+
+	result := r.db.WithContext(ctx).Where("id IN (?)", expired).Delete(&domain.SyncEvent{})
+	return result.RowsAffected, result.Error
+}
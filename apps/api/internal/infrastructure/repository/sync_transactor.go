@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/config"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/cache"
+	"gorm.io/gorm"
+)
+
+type SyncTransactor = port.SyncTransactor
+
+type syncTransactor struct {
+	cfg             *config.Config
+	db              *gorm.DB
+	readingProgress ReadingProgressRepository
+	bookmark        BookmarkRepository
+	annotation      AnnotationRepository
+}
+
+func NewSyncTransactor(cfg *config.Config, db *gorm.DB, cacheClient cache.Cache) SyncTransactor {
+	return &syncTransactor{
+		cfg:             cfg,
+		db:              db,
+		readingProgress: NewReadingProgressRepository(cfg, db, cacheClient),
+		bookmark:        NewBookmarkRepository(cfg, db, cacheClient),
+		annotation:      NewAnnotationRepository(cfg, db, cacheClient),
+	}
+}
+
+func (t *syncTransactor) WithinTransaction(ctx context.Context, userID uuid.UUID, entityID uuid.UUID, fn func(repos *port.SyncRepositories) error) error {
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// One sync stream per user: concurrent pushes from several devices queue here
+		// until the previous event has been applied and committed.
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(hashtext(?))", "sync:"+userID.String()).Error; err != nil {
+			return err
+		}
+
+		// Repositories inside the transaction skip the cache so rolled back writes
+		// never leak into it; stale entries are evicted after commit instead.
+		return fn(&port.SyncRepositories{
+			SyncEvent:       NewSyncEventRepository(t.cfg, tx, nil),
+			SyncCheckpoint:  NewSyncCheckpointRepository(tx),
+			ReadingProgress: NewReadingProgressRepository(t.cfg, tx, nil),
+			Bookmark:        NewBookmarkRepository(t.cfg, tx, nil),
+			Annotation:      NewAnnotationRepository(t.cfg, tx, nil),
+			UserPreferences: NewUserPreferencesRepository(tx),
+			UserReaderState: NewUserReaderStateRepository(tx),
+			Ebook:           NewEbookRepository(t.cfg, tx, nil),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	t.readingProgress.EvictCache(ctx, entityID)
+	t.bookmark.EvictCache(ctx, entityID)
+	t.annotation.EvictCache(ctx, entityID)
+	return nil
+}
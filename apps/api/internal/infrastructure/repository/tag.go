@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/config"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/cache"
+	"gorm.io/gorm"
+)
+
+type TagRepository = port.TagRepository
+
+type tagRepository struct {
+	ResourceRepository[domain.Tag]
+	db *gorm.DB
+}
+
+func NewTagRepository(cfg *config.Config, db *gorm.DB, cacheClient cache.Cache) TagRepository {
+	return &tagRepository{
+		ResourceRepository: NewResourceRepository[domain.Tag](cfg, db, cacheClient),
+		db:                 db,
+	}
+}
+
+func (r *tagRepository) GetByName(ctx context.Context, name string) (*domain.Tag, error) {
+	return getByName[domain.Tag](r.db.WithContext(ctx), name)
+}
+
+func (r *tagRepository) FindOrCreateByNames(ctx context.Context, names []string) ([]domain.Tag, error) {
+	tags := make([]domain.Tag, len(names))
+	for i, name := range names {
+		tags[i] = domain.Tag{ID: uuid.New(), Name: name}
+	}
+	return findOrCreateByNames(r.db.WithContext(ctx), names, tags)
+}
+
+func (r *tagRepository) ReplaceEbookTags(ctx context.Context, ebookID uuid.UUID, tagIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("ebook_id = ?", ebookID).Delete(&domain.EbookTag{}).Error; err != nil {
+			return err
+		}
+		if len(tagIDs) == 0 {
+			return nil
+		}
+
+		rows := make([]domain.EbookTag, len(tagIDs))
+		for i, tagID := range tagIDs {
+			rows[i] = domain.EbookTag{EbookID: ebookID, TagID: tagID}
+		}
+		return tx.Create(&rows).Error
+	})
+}
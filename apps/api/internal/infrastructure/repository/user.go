@@ -1,21 +1,39 @@
 package repository
 
 import (
+	"context"
+
+	"github.com/google/uuid"
 	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
 	"github.com/jeheskielSunloy77/libra-link/internal/domain"
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/config"
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/cache"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type UserRepository = port.UserRepository
 
 type userRepository struct {
 	ResourceRepository[domain.User]
+	db *gorm.DB
 }
 
 func NewUserRepository(cfg *config.Config, db *gorm.DB, cacheClient cache.Cache) UserRepository {
 	return &userRepository{
 		ResourceRepository: NewResourceRepository[domain.User](cfg, db, cacheClient),
+		db:                 db,
+	}
+}
+
+func (r *userRepository) GetForUpdate(ctx context.Context, id uuid.UUID) (*domain.User, error) {
+	var user domain.User
+	err := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&user, "id = ?", id).
+		Error
+	if err != nil {
+		return nil, err
 	}
+	return &user, nil
 }
@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
 	"github.com/jeheskielSunloy77/libra-link/internal/domain"
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/config"
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/cache"
@@ -51,6 +52,24 @@ func TestUserRepository_ResourceLifecycle(t *testing.T) {
 		require.Equal(t, int64(2), total)
 		require.Len(t, list, 1)
 
+		createdAt, err := json.Marshal(list[0].CreatedAt)
+		require.NoError(t, err)
+		after := &port.ListCursor{Value: createdAt, ID: list[0].ID}
+		next, total, err := repo.GetMany(ctx, GetManyOptions{Limit: 1, OrderBy: "created_at", OrderDirection: "asc", After: after, SkipCount: true})
+		require.NoError(t, err)
+		require.Equal(t, int64(-1), total)
+		require.Len(t, next, 1)
+		require.NotEqual(t, list[0].ID, next[0].ID)
+
+		_, _, err = repo.GetMany(ctx, GetManyOptions{OrderBy: "created_at; DROP TABLE users"})
+		require.ErrorIs(t, err, port.ErrInvalidOrderBy)
+
+		_, _, err = repo.GetMany(ctx, GetManyOptions{OrderBy: "last_login_at", After: after})
+		require.ErrorIs(t, err, port.ErrNullableOrderBy)
+
+		_, _, err = repo.GetMany(ctx, GetManyOptions{OrderBy: "created_at", After: &port.ListCursor{Value: json.RawMessage(`"not a time"`), ID: list[0].ID}})
+		require.ErrorIs(t, err, port.ErrInvalidCursor)
+
 		require.NoError(t, repo.Destroy(ctx, user1.ID))
 		_, err = repo.GetByID(ctx, user1.ID, nil)
 		require.Error(t, err)
@@ -29,26 +29,49 @@ func (r *userPreferencesRepository) GetByUserID(ctx context.Context, userID uuid
 	return &prefs, nil
 }
 
-func (r *userPreferencesRepository) Upsert(ctx context.Context, prefs *domain.UserPreferences) error {
+func (r *userPreferencesRepository) Upsert(ctx context.Context, prefs *domain.UserPreferences, ifVersion *int64) error {
 	now := time.Now().UTC()
 	if prefs.CreatedAt.IsZero() {
 		prefs.CreatedAt = now
 	}
 	prefs.UpdatedAt = now
 
-	return r.db.WithContext(ctx).
+	assignments := clause.AssignmentColumns([]string{
+		"reading_mode",
+		"zen_restore_on_open",
+		"theme_mode",
+		"theme_overrides",
+		"typography_profile",
+		"updated_at",
+	})
+	assignments = append(assignments, clause.Assignment{
+		Column: clause.Column{Name: "row_version"},
+		Value:  gorm.Expr("user_preferences.row_version + 1"),
+	})
+
+	result := r.db.WithContext(ctx).
 		Clauses(clause.OnConflict{
-			Columns: []clause.Column{{Name: "user_id"}},
-			DoUpdates: clause.AssignmentColumns([]string{
-				"reading_mode",
-				"zen_restore_on_open",
-				"theme_mode",
-				"theme_overrides",
-				"typography_profile",
-				"row_version",
-				"updated_at",
-			}),
+			Columns:   []clause.Column{{Name: "user_id"}},
+			DoUpdates: assignments,
+			Where:     versionPredicate("user_preferences", ifVersion),
 		}).
-		Create(prefs).
-		Error
+		Create(prefs)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return port.ErrStaleRowVersion
+	}
+	return nil
+}
+
+// versionPredicate limits an upsert's update of table to rows still at
+// ifVersion, or leaves it unconditional when ifVersion is nil.
+func versionPredicate(table string, ifVersion *int64) clause.Where {
+	if ifVersion == nil {
+		return clause.Where{}
+	}
+	return clause.Where{Exprs: []clause.Expression{
+		clause.Eq{Column: clause.Column{Table: table, Name: "row_version"}, Value: *ifVersion},
+	}}
 }
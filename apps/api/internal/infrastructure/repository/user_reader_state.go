@@ -29,25 +29,32 @@ func (r *userReaderStateRepository) GetByUserID(ctx context.Context, userID uuid
 	return &state, nil
 }
 
-func (r *userReaderStateRepository) Upsert(ctx context.Context, state *domain.UserReaderState) error {
+func (r *userReaderStateRepository) Upsert(ctx context.Context, state *domain.UserReaderState, ifVersion *int64) error {
 	now := time.Now().UTC()
 	if state.CreatedAt.IsZero() {
 		state.CreatedAt = now
 	}
 	state.UpdatedAt = now
 
-	return r.db.WithContext(ctx).
+	result := r.db.WithContext(ctx).
 		Clauses(clause.OnConflict{
 			Columns: []clause.Column{{Name: "user_id"}},
 			DoUpdates: clause.Assignments(map[string]any{
 				"current_ebook_id": state.CurrentEbookID,
 				"current_location": state.CurrentLocation,
 				"reading_mode":     state.ReadingMode,
-				"row_version":      state.RowVersion,
+				"row_version":      gorm.Expr("user_reader_state.row_version + 1"),
 				"last_opened_at":   state.LastOpenedAt,
 				"updated_at":       now,
 			}),
+			Where: versionPredicate("user_reader_state", ifVersion),
 		}).
-		Create(state).
-		Error
+		Create(state)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return port.ErrStaleRowVersion
+	}
+	return nil
 }
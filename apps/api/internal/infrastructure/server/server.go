@@ -61,11 +61,6 @@ func NewServer(cfg *config.Config, logger *zerolog.Logger, loggerService *logger
 	jobService := job.NewJobService(logger, cfg, db.DB, storageProvider)
 	jobService.InitHandlers(cfg, logger)
 
-	// Start job server
-	if err := jobService.Start(); err != nil {
-		return nil, err
-	}
-
 	server := &Server{
 		Config:        cfg,
 		Logger:        logger,
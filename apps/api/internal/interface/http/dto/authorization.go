@@ -0,0 +1,14 @@
+package dto
+
+import "github.com/go-playground/validator/v10"
+
+type AuthorizationPolicyRequest struct {
+	Subject string `json:"subject" validate:"required,max=255"`
+	Object  string `json:"object" validate:"required,max=255"`
+	Action  string `json:"action" validate:"required,max=255"`
+	Effect  string `json:"effect" validate:"required,oneof=allow deny"`
+}
+
+func (d *AuthorizationPolicyRequest) Validate() error {
+	return validator.New().Struct(d)
+}
@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"github.com/go-playground/validator/v10"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+)
+
+type StoreAuthorRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=255"`
+}
+
+func (d *StoreAuthorRequest) Validate() error {
+	return validator.New().Struct(d)
+}
+
+func (d *StoreAuthorRequest) ToUsecase() *applicationdto.StoreAuthorInput {
+	return &applicationdto.StoreAuthorInput{Name: d.Name}
+}
+
+type UpdateAuthorRequest struct {
+	Name *string `json:"name" validate:"omitempty,min=1,max=255"`
+}
+
+func (d *UpdateAuthorRequest) Validate() error {
+	return validator.New().Struct(d)
+}
+
+func (d *UpdateAuthorRequest) ToUsecase() *applicationdto.UpdateAuthorInput {
+	return &applicationdto.UpdateAuthorInput{Name: d.Name}
+}
+
+type StoreTagRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=64"`
+}
+
+func (d *StoreTagRequest) Validate() error {
+	return validator.New().Struct(d)
+}
+
+func (d *StoreTagRequest) ToUsecase() *applicationdto.StoreTagInput {
+	return &applicationdto.StoreTagInput{Name: d.Name}
+}
+
+type UpdateTagRequest struct {
+	Name *string `json:"name" validate:"omitempty,min=1,max=64"`
+}
+
+func (d *UpdateTagRequest) Validate() error {
+	return validator.New().Struct(d)
+}
+
+func (d *UpdateTagRequest) ToUsecase() *applicationdto.UpdateTagInput {
+	return &applicationdto.UpdateTagInput{Name: d.Name}
+}
+
+type SetEbookAuthorsRequest struct {
+	Authors []string `json:"authors" validate:"max=50,dive,required,min=1,max=255"`
+}
+
+func (d *SetEbookAuthorsRequest) Validate() error {
+	return validator.New().Struct(d)
+}
+
+func (d *SetEbookAuthorsRequest) ToUsecase() *applicationdto.SetEbookNamesInput {
+	return &applicationdto.SetEbookNamesInput{Names: d.Authors}
+}
+
+type SetEbookTagsRequest struct {
+	Tags []string `json:"tags" validate:"max=50,dive,required,min=1,max=64"`
+}
+
+func (d *SetEbookTagsRequest) Validate() error {
+	return validator.New().Struct(d)
+}
+
+func (d *SetEbookTagsRequest) ToUsecase() *applicationdto.SetEbookNamesInput {
+	return &applicationdto.SetEbookNamesInput{Names: d.Tags}
+}
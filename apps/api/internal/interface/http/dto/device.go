@@ -0,0 +1,11 @@
+package dto
+
+import "github.com/go-playground/validator/v10"
+
+type RenameDeviceRequest struct {
+	Name string `json:"name" validate:"required,max=100"`
+}
+
+func (d *RenameDeviceRequest) Validate() error {
+	return validator.New().Struct(d)
+}
@@ -0,0 +1,58 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+)
+
+type ResolveShareReportRequest struct {
+	Note        string             `json:"note" validate:"required,max=2000"`
+	ShareStatus domain.ShareStatus `json:"shareStatus" validate:"omitempty,oneof=disabled removed"`
+}
+
+func (d *ResolveShareReportRequest) Validate() error {
+	return validator.New().Struct(d)
+}
+
+type RejectShareReportRequest struct {
+	Note string `json:"note" validate:"required,max=2000"`
+}
+
+func (d *RejectShareReportRequest) Validate() error {
+	return validator.New().Struct(d)
+}
+
+type ReinstateShareRequest struct {
+	Note string `json:"note" validate:"required,max=2000"`
+}
+
+func (d *ReinstateShareRequest) Validate() error {
+	return validator.New().Struct(d)
+}
+
+type SuspendUserRequest struct {
+	Reason string     `json:"reason" validate:"required,max=2000"`
+	Until  *time.Time `json:"until"`
+}
+
+func (d *SuspendUserRequest) Validate() error {
+	return validator.New().Struct(d)
+}
+
+type BanUserRequest struct {
+	Reason string `json:"reason" validate:"required,max=2000"`
+}
+
+func (d *BanUserRequest) Validate() error {
+	return validator.New().Struct(d)
+}
+
+type ReinstateUserRequest struct {
+	Note string `json:"note" validate:"required,max=2000"`
+}
+
+func (d *ReinstateUserRequest) Validate() error {
+	return validator.New().Struct(d)
+}
@@ -12,7 +12,6 @@ type StoreShareRequest struct {
 	TitleOverride        *string                `json:"titleOverride" validate:"omitempty,max=255"`
 	Description          *string                `json:"description"`
 	Visibility           domain.ShareVisibility `json:"visibility" validate:"omitempty,oneof=public unlisted"`
-	Status               domain.ShareStatus     `json:"status" validate:"omitempty,oneof=active disabled removed"`
 	BorrowDurationHours  int                    `json:"borrowDurationHours" validate:"required,gt=0"`
 	MaxConcurrentBorrows int                    `json:"maxConcurrentBorrows" validate:"omitempty,gte=1"`
 }
@@ -32,7 +31,6 @@ func (d *StoreShareRequest) ToUsecase() *applicationdto.StoreShareInput {
 		TitleOverride:        d.TitleOverride,
 		Description:          d.Description,
 		Visibility:           d.Visibility,
-		Status:               d.Status,
 		BorrowDurationHours:  d.BorrowDurationHours,
 		MaxConcurrentBorrows: maxConcurrent,
 	}
@@ -42,7 +40,6 @@ type UpdateShareRequest struct {
 	TitleOverride        *string                 `json:"titleOverride" validate:"omitempty,max=255"`
 	Description          *string                 `json:"description"`
 	Visibility           *domain.ShareVisibility `json:"visibility" validate:"omitempty,oneof=public unlisted"`
-	Status               *domain.ShareStatus     `json:"status" validate:"omitempty,oneof=active disabled removed"`
 	BorrowDurationHours  *int                    `json:"borrowDurationHours" validate:"omitempty,gt=0"`
 	MaxConcurrentBorrows *int                    `json:"maxConcurrentBorrows" validate:"omitempty,gte=1"`
 }
@@ -56,7 +53,6 @@ func (d *UpdateShareRequest) ToUsecase() *applicationdto.UpdateShareInput {
 		TitleOverride:        d.TitleOverride,
 		Description:          d.Description,
 		Visibility:           d.Visibility,
-		Status:               d.Status,
 		BorrowDurationHours:  d.BorrowDurationHours,
 		MaxConcurrentBorrows: d.MaxConcurrentBorrows,
 	}
@@ -40,6 +40,22 @@ func (d *StoreSyncEventRequest) ToUsecase() *applicationdto.StoreSyncEventInput
 	}
 }
 
+type StoreSyncEventsRequest struct {
+	Events []StoreSyncEventRequest `json:"events" validate:"required,min=1,max=100,dive"`
+}
+
+func (d *StoreSyncEventsRequest) Validate() error {
+	return validator.New().Struct(d)
+}
+
+func (d *StoreSyncEventsRequest) ToUsecase() []*applicationdto.StoreSyncEventInput {
+	inputs := make([]*applicationdto.StoreSyncEventInput, 0, len(d.Events))
+	for i := range d.Events {
+		inputs = append(inputs, d.Events[i].ToUsecase())
+	}
+	return inputs
+}
+
 func parseSinceQuery(raw string) (*time.Time, error) {
 	if raw == "" {
 		return nil, nil
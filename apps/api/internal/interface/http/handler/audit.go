@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	"github.com/jeheskielSunloy77/libra-link/internal/application"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	httpdto "github.com/jeheskielSunloy77/libra-link/internal/interface/http/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/interface/http/response"
+)
+
+var (
+	auditActions = []domain.AuditAction{
+		domain.AuditActionReportResolved,
+		domain.AuditActionReportRejected,
+		domain.AuditActionShareTakenDown,
+		domain.AuditActionShareReinstated,
+		domain.AuditActionBorrowRevoked,
+		domain.AuditActionAuthorizationPolicyAdded,
+		domain.AuditActionAuthorizationPolicyRemoved,
+		domain.AuditActionUserSuspended,
+		domain.AuditActionUserBanned,
+		domain.AuditActionUserReinstated,
+	}
+	auditTargetTypes = []domain.AuditTargetType{
+		domain.AuditTargetTypeShareReport,
+		domain.AuditTargetTypeShare,
+		domain.AuditTargetTypeBorrow,
+		domain.AuditTargetTypeAuthorizationPolicy,
+		domain.AuditTargetTypeUser,
+	}
+)
+
+type AuditHandler struct {
+	Handler
+	service application.AuditService
+}
+
+func NewAuditHandler(h Handler, service application.AuditService) *AuditHandler {
+	return &AuditHandler{Handler: h, service: service}
+}
+
+func (h *AuditHandler) ListEvents() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (response.PaginatedResponse[domain.AuditEvent], error) {
+		input, err := parseListAuditEventsQuery(c)
+		if err != nil {
+			return response.PaginatedResponse[domain.AuditEvent]{}, err
+		}
+		options, err := fixedOrderListOptions(c, "created_at", "desc")
+		if err != nil {
+			return response.PaginatedResponse[domain.AuditEvent]{}, err
+		}
+
+		return listPage("Successfully fetched audit events!", options, func(options port.GetManyOptions) ([]domain.AuditEvent, int64, error) {
+			input.Limit, input.Offset = options.Limit, options.Offset
+			input.After, input.SkipCount = options.After, options.SkipCount
+			return h.service.ListEvents(c.UserContext(), input)
+		})
+	}, http.StatusOK, &httpdto.Empty{})
+}
+
+func parseListAuditEventsQuery(c *fiber.Ctx) (*applicationdto.ListAuditEventsInput, error) {
+	input := &applicationdto.ListAuditEventsInput{
+		Action:     domain.AuditAction(c.Query("action")),
+		TargetType: domain.AuditTargetType(c.Query("targetType")),
+	}
+	if input.Action != "" && !slices.Contains(auditActions, input.Action) {
+		return nil, errs.NewBadRequestError("invalid action value", true, nil, nil)
+	}
+	if input.TargetType != "" && !slices.Contains(auditTargetTypes, input.TargetType) {
+		return nil, errs.NewBadRequestError("invalid targetType value", true, nil, nil)
+	}
+
+	var err error
+	if input.ActorUserID, err = parseUUIDQuery(c, "actorId"); err != nil {
+		return nil, err
+	}
+	if input.TargetID, err = parseUUIDQuery(c, "targetId"); err != nil {
+		return nil, err
+	}
+	if input.From, err = parseTimeQuery(c, "from"); err != nil {
+		return nil, err
+	}
+	if input.To, err = parseTimeQuery(c, "to"); err != nil {
+		return nil, err
+	}
+	return input, nil
+}
+
+func parseUUIDQuery(c *fiber.Ctx, key string) (*uuid.UUID, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, errs.NewBadRequestError("invalid "+key+" value; expected UUID", true, nil, nil)
+	}
+	return &id, nil
+}
+
+func parseTimeQuery(c *fiber.Ctx, key string) (*time.Time, error) {
+	raw := c.Query(key)
+	if raw == "" {
+		return nil, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, errs.NewBadRequestError("invalid "+key+" value; expected RFC3339 datetime", true, nil, nil)
+	}
+	utc := parsed.UTC()
+	return &utc, nil
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/stretchr/testify/require"
+)
+
+type stubAuditService struct {
+	input *applicationdto.ListAuditEventsInput
+}
+
+func (s *stubAuditService) ListEvents(ctx context.Context, input *applicationdto.ListAuditEventsInput) ([]domain.AuditEvent, int64, error) {
+	s.input = input
+	return []domain.AuditEvent{}, 0, nil
+}
+
+// Ensures ListEvents turns the query string into the audit filter.
+func TestAuditHandlerListEvents_ParsesFilter(t *testing.T) {
+	srv := newTestServer()
+	app := newTestApp(srv)
+
+	service := &stubAuditService{}
+	h := NewAuditHandler(NewHandler(srv), service)
+	app.Get("/admin/audit-events", h.ListEvents())
+
+	actorID := uuid.New()
+	req, err := http.NewRequest(http.MethodGet, "/admin/audit-events?actorId="+actorID.String()+"&action=share_taken_down&targetType=share&from=2026-01-02T03:04:05%2B07:00&limit=5", nil)
+	require.NoError(t, err)
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	require.NotNil(t, service.input)
+	require.Equal(t, &actorID, service.input.ActorUserID)
+	require.Equal(t, domain.AuditActionShareTakenDown, service.input.Action)
+	require.Equal(t, domain.AuditTargetTypeShare, service.input.TargetType)
+	require.Nil(t, service.input.TargetID)
+	require.NotNil(t, service.input.From)
+	require.True(t, time.Date(2026, 1, 1, 20, 4, 5, 0, time.UTC).Equal(*service.input.From))
+	require.Nil(t, service.input.To)
+	require.Equal(t, 5, service.input.Limit)
+}
+
+// Ensures ListEvents rejects unknown actions before querying.
+func TestAuditHandlerListEvents_InvalidAction(t *testing.T) {
+	srv := newTestServer()
+	app := newTestApp(srv)
+
+	service := &stubAuditService{}
+	h := NewAuditHandler(NewHandler(srv), service)
+	app.Get("/admin/audit-events", h.ListEvents())
+
+	req, err := http.NewRequest(http.MethodGet, "/admin/audit-events?action=user_deleted", nil)
+	require.NoError(t, err)
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.Nil(t, service.input)
+}
+
+// Ensures ListEvents continues from a cursor and rejects cursors of another order.
+func TestAuditHandlerListEvents_Cursor(t *testing.T) {
+	srv := newTestServer()
+	app := newTestApp(srv)
+
+	service := &stubAuditService{}
+	h := NewAuditHandler(NewHandler(srv), service)
+	app.Get("/admin/audit-events", h.ListEvents())
+
+	after := domain.AuditEvent{ID: uuid.New(), CreatedAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)}
+	cursor, err := encodeListCursor(port.GetManyOptions{OrderBy: "created_at", OrderDirection: "desc"}, after)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodGet, "/admin/audit-events?cursor="+cursor+"&limit=5", nil)
+	require.NoError(t, err)
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, after.ID, service.input.After.ID)
+	require.JSONEq(t, `"2026-01-02T03:04:05Z"`, string(service.input.After.Value))
+	require.Equal(t, 6, service.input.Limit)
+
+	service.input = nil
+	cursor, err = encodeListCursor(port.GetManyOptions{OrderBy: "action", OrderDirection: "asc"}, after)
+	require.NoError(t, err)
+	req, err = http.NewRequest(http.MethodGet, "/admin/audit-events?cursor="+cursor, nil)
+	require.NoError(t, err)
+
+	resp, err = app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	require.Nil(t, service.input)
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeheskielSunloy77/libra-link/internal/application"
+	httpdto "github.com/jeheskielSunloy77/libra-link/internal/interface/http/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/interface/http/response"
+)
+
+type AuthorizationPolicyService interface {
+	ListPolicies(ctx context.Context) ([]application.AuthorizationPolicy, error)
+	AddPolicy(ctx context.Context, policy application.AuthorizationPolicy) (*application.AuthorizationPolicy, error)
+	RemovePolicy(ctx context.Context, policy application.AuthorizationPolicy) error
+}
+
+type AuthorizationHandler struct {
+	Handler
+	service AuthorizationPolicyService
+}
+
+func NewAuthorizationHandler(h Handler, service AuthorizationPolicyService) *AuthorizationHandler {
+	return &AuthorizationHandler{Handler: h, service: service}
+}
+
+func (h *AuthorizationHandler) ListPolicies() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (*response.Response[[]application.AuthorizationPolicy], error) {
+		policies, err := h.service.ListPolicies(c.UserContext())
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[[]application.AuthorizationPolicy]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Successfully fetched authorization policies!",
+			Data:    &policies,
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.Empty{})
+}
+
+func (h *AuthorizationHandler) AddPolicy() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, req *httpdto.AuthorizationPolicyRequest) (*response.Response[application.AuthorizationPolicy], error) {
+		policy, err := h.service.AddPolicy(c.UserContext(), toAuthorizationPolicy(req))
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[application.AuthorizationPolicy]{
+			Status:  http.StatusCreated,
+			Success: true,
+			Message: "Authorization policy added successfully!",
+			Data:    policy,
+		}
+		return &resp, nil
+	}, http.StatusCreated, &httpdto.AuthorizationPolicyRequest{})
+}
+
+func (h *AuthorizationHandler) RemovePolicy() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, req *httpdto.AuthorizationPolicyRequest) (*response.Response[any], error) {
+		if err := h.service.RemovePolicy(c.UserContext(), toAuthorizationPolicy(req)); err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[any]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Authorization policy removed successfully!",
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.AuthorizationPolicyRequest{})
+}
+
+func toAuthorizationPolicy(req *httpdto.AuthorizationPolicyRequest) application.AuthorizationPolicy {
+	return application.AuthorizationPolicy{
+		Subject: req.Subject,
+		Object:  req.Object,
+		Action:  req.Action,
+		Effect:  req.Effect,
+	}
+}
@@ -1,6 +1,7 @@
 package handler
 
 import (
+	"net/http"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -40,6 +41,9 @@ type JSONResponseHandler struct {
 }
 
 func (h JSONResponseHandler) Handle(c *fiber.Ctx, result any) error {
+	if notModified(c) {
+		return c.SendStatus(http.StatusNotModified)
+	}
 	return c.Status(h.status).JSON(result)
 }
 
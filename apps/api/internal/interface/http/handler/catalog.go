@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"github.com/jeheskielSunloy77/libra-link/internal/application"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	httpdto "github.com/jeheskielSunloy77/libra-link/internal/interface/http/dto"
+)
+
+// catalogFilters applies to authors and tags alike.
+var catalogFilters = port.FilterSpec{
+	"name":      {Column: "name", Type: port.FilterTypeString},
+	"createdAt": {Column: "created_at", Type: port.FilterTypeTime},
+	"updatedAt": {Column: "updated_at", Type: port.FilterTypeTime},
+}
+
+type AuthorHandler struct {
+	*ResourceHandler[domain.Author, *applicationdto.StoreAuthorInput, *applicationdto.UpdateAuthorInput, *httpdto.StoreAuthorRequest, *httpdto.UpdateAuthorRequest]
+}
+
+func NewAuthorHandler(h Handler, service application.AuthorService) *AuthorHandler {
+	return &AuthorHandler{
+		ResourceHandler: NewResourceHandler[domain.Author, *applicationdto.StoreAuthorInput, *applicationdto.UpdateAuthorInput, *httpdto.StoreAuthorRequest, *httpdto.UpdateAuthorRequest]("author", h, service).WithFilters(catalogFilters),
+	}
+}
+
+type TagHandler struct {
+	*ResourceHandler[domain.Tag, *applicationdto.StoreTagInput, *applicationdto.UpdateTagInput, *httpdto.StoreTagRequest, *httpdto.UpdateTagRequest]
+}
+
+func NewTagHandler(h Handler, service application.TagService) *TagHandler {
+	return &TagHandler{
+		ResourceHandler: NewResourceHandler[domain.Tag, *applicationdto.StoreTagInput, *applicationdto.UpdateTagInput, *httpdto.StoreTagRequest, *httpdto.UpdateTagRequest]("tag", h, service).WithFilters(catalogFilters),
+	}
+}
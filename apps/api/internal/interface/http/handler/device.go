@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeheskielSunloy77/libra-link/internal/application"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	httpdto "github.com/jeheskielSunloy77/libra-link/internal/interface/http/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/interface/http/response"
+	httputils "github.com/jeheskielSunloy77/libra-link/internal/interface/http/utils"
+)
+
+type DeviceHandler struct {
+	Handler
+	service application.DeviceService
+}
+
+func NewDeviceHandler(h Handler, service application.DeviceService) *DeviceHandler {
+	return &DeviceHandler{Handler: h, service: service}
+}
+
+func (h *DeviceHandler) List() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (*response.Response[[]domain.Device], error) {
+		userID, err := parseAuthenticatedUserID(c)
+		if err != nil {
+			return nil, err
+		}
+
+		devices, err := h.service.List(c.UserContext(), userID)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[[]domain.Device]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Successfully fetched devices!",
+			Data:    &devices,
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.Empty{})
+}
+
+func (h *DeviceHandler) Rename() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, req *httpdto.RenameDeviceRequest) (*response.Response[domain.Device], error) {
+		deviceID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return nil, err
+		}
+		userID, err := parseAuthenticatedUserID(c)
+		if err != nil {
+			return nil, err
+		}
+
+		device, err := h.service.Rename(c.UserContext(), userID, deviceID, req.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[domain.Device]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Device renamed successfully!",
+			Data:    device,
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.RenameDeviceRequest{})
+}
+
+func (h *DeviceHandler) Revoke() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (*response.Response[any], error) {
+		deviceID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return nil, err
+		}
+		userID, err := parseAuthenticatedUserID(c)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := h.service.Revoke(c.UserContext(), userID, deviceID); err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[any]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Device revoked successfully!",
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.Empty{})
+}
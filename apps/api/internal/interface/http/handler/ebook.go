@@ -1,17 +1,33 @@
 package handler
 
 import (
+	"bytes"
 	"net/http"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
 	"github.com/jeheskielSunloy77/libra-link/internal/application"
 	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
 	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/googlebooks"
 	httpdto "github.com/jeheskielSunloy77/libra-link/internal/interface/http/dto"
 	"github.com/jeheskielSunloy77/libra-link/internal/interface/http/response"
 	httputils "github.com/jeheskielSunloy77/libra-link/internal/interface/http/utils"
 )
 
+var ebookFilters = port.FilterSpec{
+	"title":         {Column: "title", Type: port.FilterTypeString},
+	"format":        {Column: "format", Type: port.FilterTypeString, Values: []string{string(domain.EbookFormatEPUB), string(domain.EbookFormatPDF), string(domain.EbookFormatTXT)}},
+	"languageCode":  {Column: "language_code", Type: port.FilterTypeString, Nullable: true},
+	"fileSizeBytes": {Column: "file_size_bytes", Type: port.FilterTypeInt},
+	"importedAt":    {Column: "imported_at", Type: port.FilterTypeTime},
+	"createdAt":     {Column: "created_at", Type: port.FilterTypeTime},
+	"updatedAt":     {Column: "updated_at", Type: port.FilterTypeTime},
+}
+
 type EbookHandler struct {
 	*ResourceHandler[domain.Ebook, *applicationdto.StoreEbookInput, *applicationdto.UpdateEbookInput, *httpdto.StoreEbookRequest, *httpdto.UpdateEbookRequest]
 	service application.EbookService
@@ -19,7 +35,7 @@ type EbookHandler struct {
 
 func NewEbookHandler(h Handler, service application.EbookService) *EbookHandler {
 	return &EbookHandler{
-		ResourceHandler: NewResourceHandler[domain.Ebook, *applicationdto.StoreEbookInput, *applicationdto.UpdateEbookInput, *httpdto.StoreEbookRequest, *httpdto.UpdateEbookRequest]("ebook", h, service),
+		ResourceHandler: NewResourceHandler[domain.Ebook, *applicationdto.StoreEbookInput, *applicationdto.UpdateEbookInput, *httpdto.StoreEbookRequest, *httpdto.UpdateEbookRequest]("ebook", h, service).WithFilters(ebookFilters),
 		service:         service,
 	}
 }
@@ -49,6 +65,84 @@ func (h *EbookHandler) Store() fiber.Handler {
 	}, http.StatusCreated, &httpdto.StoreEbookRequest{})
 }
 
+// GetMany lists ebooks like every resource, and can also narrow the listing to
+// authors and tags with the authorId, author, tagId and tag query parameters,
+// each a comma-separated list.
+func (h *EbookHandler) GetMany() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (response.PaginatedResponse[domain.Ebook], error) {
+		options, err := h.getManyOptions(c)
+		if err != nil {
+			return response.PaginatedResponse[domain.Ebook]{}, err
+		}
+
+		filter := application.EbookCatalogFilter{
+			Authors: parseQueryList(c.Query("author")),
+			Tags:    parseQueryList(c.Query("tag")),
+		}
+		if filter.AuthorIDs, err = parseUUIDListQuery(c, "authorId"); err != nil {
+			return response.PaginatedResponse[domain.Ebook]{}, err
+		}
+		if filter.TagIDs, err = parseUUIDListQuery(c, "tagId"); err != nil {
+			return response.PaginatedResponse[domain.Ebook]{}, err
+		}
+		options.Wheres = append(options.Wheres, filter.Wheres()...)
+
+		return listPage("Successfully fetched ebooks!", options, func(options port.GetManyOptions) ([]domain.Ebook, int64, error) {
+			return h.service.GetMany(c.UserContext(), options)
+		})
+	}, http.StatusOK, &httpdto.Empty{})
+}
+
+func (h *EbookHandler) SetAuthors() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, req *httpdto.SetEbookAuthorsRequest) (*response.Response[domain.Ebook], error) {
+		ebookID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return nil, err
+		}
+
+		input := req.ToUsecase()
+		input.EbookID = ebookID
+
+		ebook, err := h.service.SetAuthors(c.UserContext(), input)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[domain.Ebook]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Successfully set ebook authors!",
+			Data:    ebook,
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.SetEbookAuthorsRequest{})
+}
+
+func (h *EbookHandler) SetTags() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, req *httpdto.SetEbookTagsRequest) (*response.Response[domain.Ebook], error) {
+		ebookID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return nil, err
+		}
+
+		input := req.ToUsecase()
+		input.EbookID = ebookID
+
+		ebook, err := h.service.SetTags(c.UserContext(), input)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[domain.Ebook]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Successfully set ebook tags!",
+			Data:    ebook,
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.SetEbookTagsRequest{})
+}
+
 func (h *EbookHandler) AttachMetadata() fiber.Handler {
 	return Handle(h.Handler, func(c *fiber.Ctx, req *httpdto.AttachGoogleMetadataRequest) (*response.Response[domain.EbookGoogleMetadata], error) {
 		ebookID, err := httputils.ParseUUIDParam(c.Params("id"))
@@ -74,6 +168,115 @@ func (h *EbookHandler) AttachMetadata() fiber.Handler {
 	}, http.StatusOK, &httpdto.AttachGoogleMetadataRequest{})
 }
 
+func (h *EbookHandler) SearchGoogleBooks() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (*response.Response[[]googlebooks.Volume], error) {
+		limit := httputils.ParseQueryInt(c.Query("limit"), application.MaxGoogleBooksResults, 10)
+
+		volumes, err := h.service.SearchGoogleBooks(c.UserContext(), c.Query("q"), c.Query("isbn"), limit)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[[]googlebooks.Volume]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Successfully searched Google Books!",
+			Data:    &volumes,
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.Empty{})
+}
+
+func (h *EbookHandler) AttachGoogleVolume() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (*response.Response[domain.EbookGoogleMetadata], error) {
+		ebookID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return nil, err
+		}
+
+		metadata, err := h.service.AttachGoogleVolume(c.UserContext(), ebookID, c.Params("volumeId"))
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[domain.EbookGoogleMetadata]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Successfully attached Google Books metadata!",
+			Data:    metadata,
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.Empty{})
+}
+
+func (h *EbookHandler) ListMetadataSuggestions() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (*response.Response[[]domain.EbookMetadataSuggestion], error) {
+		ebookID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return nil, err
+		}
+
+		suggestions, err := h.service.ListMetadataSuggestions(c.UserContext(), ebookID)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[[]domain.EbookMetadataSuggestion]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Successfully fetched metadata suggestions!",
+			Data:    &suggestions,
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.Empty{})
+}
+
+func (h *EbookHandler) AcceptMetadataSuggestion() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (*response.Response[domain.EbookGoogleMetadata], error) {
+		ebookID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return nil, err
+		}
+		suggestionID, err := httputils.ParseUUIDParam(c.Params("suggestionId"))
+		if err != nil {
+			return nil, err
+		}
+
+		metadata, err := h.service.AcceptMetadataSuggestion(c.UserContext(), ebookID, suggestionID)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[domain.EbookGoogleMetadata]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Successfully accepted metadata suggestion!",
+			Data:    metadata,
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.Empty{})
+}
+
+func (h *EbookHandler) DismissMetadataSuggestions() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (*response.Response[any], error) {
+		ebookID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := h.service.DismissMetadataSuggestions(c.UserContext(), ebookID); err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[any]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Successfully dismissed metadata suggestions!",
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.Empty{})
+}
+
 func (h *EbookHandler) DetachMetadata() fiber.Handler {
 	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (*response.Response[any], error) {
 		ebookID, err := httputils.ParseUUIDParam(c.Params("id"))
@@ -93,3 +296,164 @@ func (h *EbookHandler) DetachMetadata() fiber.Handler {
 		return &resp, nil
 	}, http.StatusOK, &httpdto.Empty{})
 }
+
+func (h *EbookHandler) UploadFile() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, err := parseAuthenticatedUserID(c)
+		if err != nil {
+			return err
+		}
+
+		ebookID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return err
+		}
+
+		input := &applicationdto.UploadEbookFileInput{
+			EbookID:     ebookID,
+			UserID:      userID,
+			ContentType: c.Get(fiber.HeaderContentType),
+		}
+
+		if strings.HasPrefix(input.ContentType, fiber.MIMEMultipartForm) {
+			fileHeader, err := c.FormFile("file")
+			if err != nil {
+				return errs.NewBadRequestError("file is required", true, []errs.FieldError{{Field: "file", Error: "is required"}}, nil)
+			}
+
+			file, err := fileHeader.Open()
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			input.Reader = file
+			input.Size = fileHeader.Size
+			input.ContentType = fileHeader.Header.Get(fiber.HeaderContentType)
+		} else {
+			// request bodies are streamed, so large files are never buffered in memory
+			if stream := c.Context().RequestBodyStream(); stream != nil {
+				input.Reader = stream
+			} else {
+				input.Reader = bytes.NewReader(c.Body())
+			}
+			if contentLength := c.Request().Header.ContentLength(); contentLength > 0 {
+				input.Size = int64(contentLength)
+			}
+		}
+
+		ebook, err := h.service.UploadFile(c.UserContext(), input)
+		if err != nil {
+			return err
+		}
+
+		return c.Status(http.StatusOK).JSON(response.Response[domain.Ebook]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Successfully uploaded ebook file!",
+			Data:    ebook,
+		})
+	}
+}
+
+func (h *EbookHandler) DownloadFile() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, err := parseAuthenticatedUserID(c)
+		if err != nil {
+			return err
+		}
+
+		ebookID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return err
+		}
+
+		file, err := h.service.OpenFile(c.UserContext(), ebookID, userID)
+		if err != nil {
+			return err
+		}
+
+		c.Attachment(file.Ebook.Title + "." + string(file.Ebook.Format))
+		c.Set(fiber.HeaderContentType, file.ContentType)
+		return c.Status(http.StatusOK).SendStream(file.Body, int(file.Size))
+	}
+}
+
+func (h *EbookHandler) GetFileURL() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (*response.Response[application.EbookFileURL], error) {
+		userID, err := parseAuthenticatedUserID(c)
+		if err != nil {
+			return nil, err
+		}
+
+		ebookID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return nil, err
+		}
+
+		fileURL, err := h.service.FileURL(c.UserContext(), ebookID, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[application.EbookFileURL]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Successfully created ebook file url!",
+			Data:    fileURL,
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.Empty{})
+}
+
+func (h *EbookHandler) SearchContent() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (response.PaginatedResponse[domain.EbookPassageHit], error) {
+		userID, err := parseAuthenticatedUserID(c)
+		if err != nil {
+			return response.PaginatedResponse[domain.EbookPassageHit]{}, err
+		}
+
+		ebookID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return response.PaginatedResponse[domain.EbookPassageHit]{}, err
+		}
+
+		input := &applicationdto.SearchEbookContentInput{
+			EbookID: ebookID,
+			UserID:  userID,
+			Query:   c.Query("q"),
+			Limit:   httputils.ParseQueryInt(c.Query("limit"), 100, 20),
+			Offset:  httputils.ParseQueryInt(c.Query("offset")),
+		}
+		hits, total, err := h.service.SearchContent(c.UserContext(), input)
+		if err != nil {
+			return response.PaginatedResponse[domain.EbookPassageHit]{}, err
+		}
+
+		return response.NewPaginatedResponse("Successfully searched ebook!", hits, total, input.Limit, input.Offset), nil
+	}, http.StatusOK, &httpdto.Empty{})
+}
+
+// parseQueryList splits a comma-separated query value, dropping empty entries.
+func parseQueryList(raw string) []string {
+	var values []string
+	for _, value := range strings.Split(raw, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+func parseUUIDListQuery(c *fiber.Ctx, key string) ([]uuid.UUID, error) {
+	values := parseQueryList(c.Query(key))
+	ids := make([]uuid.UUID, 0, len(values))
+	for _, value := range values {
+		id, err := uuid.Parse(value)
+		if err != nil {
+			return nil, errs.NewBadRequestError("invalid "+key+" value; expected comma-separated UUIDs", true, nil, nil)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
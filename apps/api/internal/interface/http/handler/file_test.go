@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/lib/storage"
+	"github.com/stretchr/testify/require"
+)
+
+func newSignedFileTestApp(t *testing.T) (*storage.LocalStorage, func(req *http.Request) *http.Response) {
+	t.Helper()
+
+	srv := newTestServer()
+	app := newTestApp(srv)
+
+	local := storage.NewLocalStorage(t.TempDir(), "/static", "/files", "test-secret")
+	_, err := local.Save(context.Background(), "ebooks/book.txt", strings.NewReader("hello, signed world"), 19, "text/plain")
+	require.NoError(t, err)
+
+	h := NewFileHandler(NewHandler(srv), local)
+	app.Get(h.SignedPath()+"/*", h.ServeSigned())
+
+	return local, func(req *http.Request) *http.Response {
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+}
+
+// Ensures a valid signed URL serves the object and honors byte ranges.
+func TestFileHandlerServeSigned_ServesRange(t *testing.T) {
+	local, do := newSignedFileTestApp(t)
+
+	url, err := local.SignedURL(context.Background(), "ebooks/book.txt", time.Minute)
+	require.NoError(t, err)
+
+	resp := do(httptestRequest(t, url, ""))
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "hello, signed world", string(body))
+
+	resp = do(httptestRequest(t, url, "bytes=7-12"))
+	require.Equal(t, http.StatusPartialContent, resp.StatusCode)
+	require.Equal(t, "bytes 7-12/19", resp.Header.Get("Content-Range"))
+	body, err = io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Equal(t, "signed", string(body))
+}
+
+// Ensures tampered or expired signatures are rejected.
+func TestFileHandlerServeSigned_RejectsInvalidSignature(t *testing.T) {
+	local, do := newSignedFileTestApp(t)
+
+	url, err := local.SignedURL(context.Background(), "ebooks/book.txt", time.Minute)
+	require.NoError(t, err)
+
+	tampered := strings.Replace(url, "book.txt", "other.txt", 1)
+	resp := do(httptestRequest(t, tampered, ""))
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+	expired, err := local.SignedURL(context.Background(), "ebooks/book.txt", -time.Minute)
+	require.NoError(t, err)
+	resp = do(httptestRequest(t, expired, ""))
+	require.Equal(t, http.StatusForbidden, resp.StatusCode)
+}
+
+func httptestRequest(t *testing.T, url string, byteRange string) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	require.NoError(t, err)
+	if byteRange != "" {
+		req.Header.Set("Range", byteRange)
+	}
+	return req
+}
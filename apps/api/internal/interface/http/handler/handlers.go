@@ -10,12 +10,20 @@ type Handlers struct {
 	Auth            *AuthHandler
 	User            *UserHandler
 	Ebook           *EbookHandler
+	Author          *AuthorHandler
+	Tag             *TagHandler
 	Share           *ShareHandler
 	ReadingProgress *ReadingProgressHandler
 	Bookmark        *BookmarkHandler
 	Annotation      *AnnotationHandler
 	ReaderSettings  *ReaderSettingsHandler
 	Sync            *SyncHandler
+	Device          *DeviceHandler
+	Authorization   *AuthorizationHandler
+	Moderation      *ModerationHandler
+	Audit           *AuditHandler
+	Search          *SearchHandler
+	File            *FileHandler
 	OpenAPI         *OpenAPIHandler
 }
 
@@ -27,12 +35,20 @@ func NewHandlers(s *server.Server, services *application.Services) *Handlers {
 		Auth:            NewAuthHandler(h, services.Auth),
 		User:            NewUserHandler(h, services.User),
 		Ebook:           NewEbookHandler(h, services.Ebook),
+		Author:          NewAuthorHandler(h, services.Author),
+		Tag:             NewTagHandler(h, services.Tag),
 		Share:           NewShareHandler(h, services.Share),
 		ReadingProgress: NewReadingProgressHandler(h, services.ReadingProgress),
 		Bookmark:        NewBookmarkHandler(h, services.Bookmark),
 		Annotation:      NewAnnotationHandler(h, services.Annotation),
 		ReaderSettings:  NewReaderSettingsHandler(h, services.UserPreferences, services.UserReaderState),
-		Sync:            NewSyncHandler(h, services.Sync),
+		Sync:            NewSyncHandler(h, services.Sync, services.User),
+		Device:          NewDeviceHandler(h, services.Device),
+		Authorization:   NewAuthorizationHandler(h, services.Authorization),
+		Moderation:      NewModerationHandler(h, services.Moderation),
+		Audit:           NewAuditHandler(h, services.Audit),
+		Search:          NewSearchHandler(h, services.Search),
+		File:            NewFileHandler(h, s.Storage),
 		OpenAPI:         NewOpenAPIHandler(h),
 	}
 }
@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	"github.com/jeheskielSunloy77/libra-link/internal/application"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	httpdto "github.com/jeheskielSunloy77/libra-link/internal/interface/http/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/interface/http/response"
+	httputils "github.com/jeheskielSunloy77/libra-link/internal/interface/http/utils"
+)
+
+var (
+	reportStatuses = []domain.ReportStatus{domain.ReportStatusOpen, domain.ReportStatusInReview, domain.ReportStatusResolved, domain.ReportStatusRejected}
+	reportReasons  = []domain.ReportReason{domain.ReportReasonCopyright, domain.ReportReasonAbuse, domain.ReportReasonSpam, domain.ReportReasonOther}
+)
+
+type ModerationHandler struct {
+	Handler
+	service application.ModerationService
+}
+
+func NewModerationHandler(h Handler, service application.ModerationService) *ModerationHandler {
+	return &ModerationHandler{Handler: h, service: service}
+}
+
+func (h *ModerationHandler) ListReports() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (response.PaginatedResponse[domain.ShareReport], error) {
+		input := &applicationdto.ListShareReportsInput{
+			Reason: domain.ReportReason(c.Query("reason")),
+		}
+		if input.Reason != "" && !slices.Contains(reportReasons, input.Reason) {
+			return response.PaginatedResponse[domain.ShareReport]{}, errs.NewBadRequestError("invalid reason; expected copyright, abuse, spam or other", true, nil, nil)
+		}
+		if rawStatus := c.Query("status"); rawStatus != "" {
+			for _, part := range strings.Split(rawStatus, ",") {
+				status := domain.ReportStatus(strings.TrimSpace(part))
+				if !slices.Contains(reportStatuses, status) {
+					return response.PaginatedResponse[domain.ShareReport]{}, errs.NewBadRequestError("invalid status; expected open, in_review, resolved or rejected", true, nil, nil)
+				}
+				input.Statuses = append(input.Statuses, status)
+			}
+		}
+
+		options, err := fixedOrderListOptions(c, "created_at", "asc")
+		if err != nil {
+			return response.PaginatedResponse[domain.ShareReport]{}, err
+		}
+
+		return listPage("Successfully fetched share reports!", options, func(options port.GetManyOptions) ([]domain.ShareReport, int64, error) {
+			input.Limit, input.Offset = options.Limit, options.Offset
+			input.After, input.SkipCount = options.After, options.SkipCount
+			return h.service.ListReports(c.UserContext(), input)
+		})
+	}, http.StatusOK, &httpdto.Empty{})
+}
+
+func (h *ModerationHandler) ClaimReport() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (*response.Response[domain.ShareReport], error) {
+		reportID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return nil, err
+		}
+		moderatorID, err := parseAuthenticatedUserID(c)
+		if err != nil {
+			return nil, err
+		}
+
+		report, err := h.service.ClaimReport(c.UserContext(), reportID, moderatorID)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[domain.ShareReport]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Report claimed successfully!",
+			Data:    report,
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.Empty{})
+}
+
+func (h *ModerationHandler) ResolveReport() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, req *httpdto.ResolveShareReportRequest) (*response.Response[application.ShareReportResolution], error) {
+		reportID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return nil, err
+		}
+		moderatorID, err := parseAuthenticatedUserID(c)
+		if err != nil {
+			return nil, err
+		}
+
+		resolution, err := h.service.ResolveReport(c.UserContext(), &applicationdto.ResolveShareReportInput{
+			ReportID:    reportID,
+			ModeratorID: moderatorID,
+			Note:        req.Note,
+			ShareStatus: req.ShareStatus,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[application.ShareReportResolution]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Report resolved successfully!",
+			Data:    resolution,
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.ResolveShareReportRequest{})
+}
+
+func (h *ModerationHandler) RejectReport() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, req *httpdto.RejectShareReportRequest) (*response.Response[domain.ShareReport], error) {
+		reportID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return nil, err
+		}
+		moderatorID, err := parseAuthenticatedUserID(c)
+		if err != nil {
+			return nil, err
+		}
+
+		report, err := h.service.RejectReport(c.UserContext(), &applicationdto.RejectShareReportInput{
+			ReportID:    reportID,
+			ModeratorID: moderatorID,
+			Note:        req.Note,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[domain.ShareReport]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Report rejected successfully!",
+			Data:    report,
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.RejectShareReportRequest{})
+}
+
+func (h *ModerationHandler) ReinstateShare() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, req *httpdto.ReinstateShareRequest) (*response.Response[application.ShareReinstatement], error) {
+		shareID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return nil, err
+		}
+		moderatorID, err := parseAuthenticatedUserID(c)
+		if err != nil {
+			return nil, err
+		}
+
+		reinstatement, err := h.service.ReinstateShare(c.UserContext(), &applicationdto.ReinstateShareInput{
+			ShareID:     shareID,
+			ModeratorID: moderatorID,
+			Note:        req.Note,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[application.ShareReinstatement]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Share reinstated successfully!",
+			Data:    reinstatement,
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.ReinstateShareRequest{})
+}
+
+func (h *ModerationHandler) SuspendUser() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, req *httpdto.SuspendUserRequest) (*response.Response[application.UserRestriction], error) {
+		userID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return nil, err
+		}
+		moderatorID, err := parseAuthenticatedUserID(c)
+		if err != nil {
+			return nil, err
+		}
+
+		restriction, err := h.service.SuspendUser(c.UserContext(), &applicationdto.SuspendUserInput{
+			UserID:      userID,
+			ModeratorID: moderatorID,
+			Reason:      req.Reason,
+			Until:       req.Until,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[application.UserRestriction]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "User suspended successfully!",
+			Data:    restriction,
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.SuspendUserRequest{})
+}
+
+func (h *ModerationHandler) BanUser() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, req *httpdto.BanUserRequest) (*response.Response[application.UserRestriction], error) {
+		userID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return nil, err
+		}
+		moderatorID, err := parseAuthenticatedUserID(c)
+		if err != nil {
+			return nil, err
+		}
+
+		restriction, err := h.service.BanUser(c.UserContext(), &applicationdto.BanUserInput{
+			UserID:      userID,
+			ModeratorID: moderatorID,
+			Reason:      req.Reason,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[application.UserRestriction]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "User banned successfully!",
+			Data:    restriction,
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.BanUserRequest{})
+}
+
+func (h *ModerationHandler) ReinstateUser() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, req *httpdto.ReinstateUserRequest) (*response.Response[domain.User], error) {
+		userID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return nil, err
+		}
+		moderatorID, err := parseAuthenticatedUserID(c)
+		if err != nil {
+			return nil, err
+		}
+
+		user, err := h.service.ReinstateUser(c.UserContext(), &applicationdto.ReinstateUserInput{
+			UserID:      userID,
+			ModeratorID: moderatorID,
+			Note:        req.Note,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[domain.User]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "User reinstated successfully!",
+			Data:    user,
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.ReinstateUserRequest{})
+}
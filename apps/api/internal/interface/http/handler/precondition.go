@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeheskielSunloy77/libra-link/internal/application"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+)
+
+// setETag exposes the RowVersion of entity as the response's ETag, for models
+// that have one.
+func setETag(c *fiber.Ctx, entity any) {
+	if versioned, ok := entity.(domain.VersionedModel); ok {
+		c.Set(fiber.HeaderETag, versionETag(versioned.GetRowVersion()))
+	}
+}
+
+func versionETag(version int64) string {
+	return `"` + strconv.FormatInt(version, 10) + `"`
+}
+
+// conditionalContext returns the request's context, carrying the versions of
+// its If-Match header when it has one. Weak and unknown tags never match, and
+// "*" leaves the request unconditional as the row must exist anyway.
+func conditionalContext(c *fiber.Ctx) context.Context {
+	ctx := c.UserContext()
+	header := strings.TrimSpace(c.Get(fiber.HeaderIfMatch))
+	if header == "" || header == "*" {
+		return ctx
+	}
+
+	versions := []int64{}
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if len(tag) < 2 || !strings.HasPrefix(tag, `"`) || !strings.HasSuffix(tag, `"`) {
+			continue
+		}
+		version, err := strconv.ParseInt(tag[1:len(tag)-1], 10, 64)
+		if err != nil {
+			continue
+		}
+		versions = append(versions, version)
+	}
+	return application.WithIfMatch(ctx, versions)
+}
+
+// notModified reports whether a GET or HEAD request already holds the ETag set
+// on its response, going by its If-None-Match header.
+func notModified(c *fiber.Ctx) bool {
+	if c.Method() != http.MethodGet && c.Method() != http.MethodHead {
+		return false
+	}
+	etag := string(c.Response().Header.Peek(fiber.HeaderETag))
+	header := c.Get(fiber.HeaderIfNoneMatch)
+	if etag == "" || header == "" {
+		return false
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || strings.TrimPrefix(tag, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/application"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/repository"
+	httpdto "github.com/jeheskielSunloy77/libra-link/internal/interface/http/dto"
+	"github.com/stretchr/testify/require"
+)
+
+func newBookmarkTestApp(t *testing.T, bookmark *domain.Bookmark) func(req *http.Request) *http.Response {
+	t.Helper()
+	srv := newTestServer()
+	app := newTestApp(srv)
+
+	repo := repository.NewMockResourceRepository[domain.Bookmark](false)
+	require.NoError(t, repo.Store(context.Background(), bookmark))
+	service := application.NewResourceService[domain.Bookmark, *applicationdto.StoreBookmarkInput, *applicationdto.UpdateBookmarkInput]("bookmark", repo)
+
+	h := NewResourceHandler[domain.Bookmark, *applicationdto.StoreBookmarkInput, *applicationdto.UpdateBookmarkInput, *httpdto.StoreBookmarkRequest, *httpdto.UpdateBookmarkRequest]("bookmark", NewHandler(srv), service)
+	app.Get("/bookmarks/:id", h.GetByID())
+	app.Patch("/bookmarks/:id", h.Update())
+	app.Delete("/bookmarks/:id", h.Destroy())
+
+	return func(req *http.Request) *http.Response {
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+		return resp
+	}
+}
+
+// Ensures GetByID exposes the row version as an ETag and answers 304 when the client holds it.
+func TestResourceHandlerGetByID_ETag(t *testing.T) {
+	bookmark := &domain.Bookmark{ID: uuid.New(), Location: "loc-1", RowVersion: 3}
+	do := newBookmarkTestApp(t, bookmark)
+
+	req, err := http.NewRequest(http.MethodGet, "/bookmarks/"+bookmark.ID.String(), nil)
+	require.NoError(t, err)
+	resp := do(req)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, `"3"`, resp.Header.Get("ETag"))
+
+	req.Header.Set("If-None-Match", `"2", "3"`)
+	resp = do(req)
+	require.Equal(t, http.StatusNotModified, resp.StatusCode)
+
+	req.Header.Set("If-None-Match", `"2"`)
+	resp = do(req)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+}
+
+// Ensures PATCH and DELETE honor If-Match and answer 412 on a stale version.
+func TestResourceHandlerUpdate_IfMatch(t *testing.T) {
+	bookmark := &domain.Bookmark{ID: uuid.New(), Location: "loc-1", RowVersion: 1}
+	do := newBookmarkTestApp(t, bookmark)
+	path := "/bookmarks/" + bookmark.ID.String()
+
+	patch := func(ifMatch string) *http.Response {
+		req, err := http.NewRequest(http.MethodPatch, path, bytes.NewReader(mustJSON(t, map[string]any{"label": "intro"})))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("If-Match", ifMatch)
+		return do(req)
+	}
+
+	resp := patch(`"1"`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, `"2"`, resp.Header.Get("ETag"))
+
+	resp = patch(`"1"`)
+	require.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+
+	req, err := http.NewRequest(http.MethodDelete, path, nil)
+	require.NoError(t, err)
+	req.Header.Set("If-Match", `W/"2"`)
+	resp = do(req)
+	require.Equal(t, http.StatusPreconditionFailed, resp.StatusCode)
+
+	req.Header.Set("If-Match", `"2"`)
+	resp = do(req)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+}
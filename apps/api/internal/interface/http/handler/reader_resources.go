@@ -6,11 +6,35 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/jeheskielSunloy77/libra-link/internal/application"
 	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
 	"github.com/jeheskielSunloy77/libra-link/internal/domain"
 	httpdto "github.com/jeheskielSunloy77/libra-link/internal/interface/http/dto"
 	"github.com/jeheskielSunloy77/libra-link/internal/interface/http/response"
 )
 
+var (
+	readingProgressFilters = port.FilterSpec{
+		"ebookId":         {Column: "ebook_id", Type: port.FilterTypeUUID},
+		"readingMode":     {Column: "reading_mode", Type: port.FilterTypeString, Values: []string{string(domain.ReadingModeNormal), string(domain.ReadingModeZen)}},
+		"progressPercent": {Column: "progress_percent", Type: port.FilterTypeNumber, Nullable: true},
+		"lastReadAt":      {Column: "last_read_at", Type: port.FilterTypeTime, Nullable: true},
+		"createdAt":       {Column: "created_at", Type: port.FilterTypeTime},
+		"updatedAt":       {Column: "updated_at", Type: port.FilterTypeTime},
+	}
+	bookmarkFilters = port.FilterSpec{
+		"ebookId":   {Column: "ebook_id", Type: port.FilterTypeUUID},
+		"label":     {Column: "label", Type: port.FilterTypeString, Nullable: true},
+		"createdAt": {Column: "created_at", Type: port.FilterTypeTime},
+		"updatedAt": {Column: "updated_at", Type: port.FilterTypeTime},
+	}
+	annotationFilters = port.FilterSpec{
+		"ebookId":   {Column: "ebook_id", Type: port.FilterTypeUUID},
+		"color":     {Column: "color", Type: port.FilterTypeString, Nullable: true},
+		"createdAt": {Column: "created_at", Type: port.FilterTypeTime},
+		"updatedAt": {Column: "updated_at", Type: port.FilterTypeTime},
+	}
+)
+
 type ReadingProgressHandler struct {
 	*ResourceHandler[domain.ReadingProgress, *applicationdto.StoreReadingProgressInput, *applicationdto.UpdateReadingProgressInput, *httpdto.StoreReadingProgressRequest, *httpdto.UpdateReadingProgressRequest]
 	service application.ReadingProgressService
@@ -18,7 +42,7 @@ type ReadingProgressHandler struct {
 
 func NewReadingProgressHandler(h Handler, service application.ReadingProgressService) *ReadingProgressHandler {
 	return &ReadingProgressHandler{
-		ResourceHandler: NewResourceHandler[domain.ReadingProgress, *applicationdto.StoreReadingProgressInput, *applicationdto.UpdateReadingProgressInput, *httpdto.StoreReadingProgressRequest, *httpdto.UpdateReadingProgressRequest]("reading_progress", h, service),
+		ResourceHandler: NewResourceHandler[domain.ReadingProgress, *applicationdto.StoreReadingProgressInput, *applicationdto.UpdateReadingProgressInput, *httpdto.StoreReadingProgressRequest, *httpdto.UpdateReadingProgressRequest]("reading_progress", h, service).WithFilters(readingProgressFilters),
 		service:         service,
 	}
 }
@@ -55,7 +79,7 @@ type BookmarkHandler struct {
 
 func NewBookmarkHandler(h Handler, service application.BookmarkService) *BookmarkHandler {
 	return &BookmarkHandler{
-		ResourceHandler: NewResourceHandler[domain.Bookmark, *applicationdto.StoreBookmarkInput, *applicationdto.UpdateBookmarkInput, *httpdto.StoreBookmarkRequest, *httpdto.UpdateBookmarkRequest]("bookmark", h, service),
+		ResourceHandler: NewResourceHandler[domain.Bookmark, *applicationdto.StoreBookmarkInput, *applicationdto.UpdateBookmarkInput, *httpdto.StoreBookmarkRequest, *httpdto.UpdateBookmarkRequest]("bookmark", h, service).WithFilters(bookmarkFilters),
 		service:         service,
 	}
 }
@@ -92,7 +116,7 @@ type AnnotationHandler struct {
 
 func NewAnnotationHandler(h Handler, service application.AnnotationService) *AnnotationHandler {
 	return &AnnotationHandler{
-		ResourceHandler: NewResourceHandler[domain.Annotation, *applicationdto.StoreAnnotationInput, *applicationdto.UpdateAnnotationInput, *httpdto.StoreAnnotationRequest, *httpdto.UpdateAnnotationRequest]("annotation", h, service),
+		ResourceHandler: NewResourceHandler[domain.Annotation, *applicationdto.StoreAnnotationInput, *applicationdto.UpdateAnnotationInput, *httpdto.StoreAnnotationRequest, *httpdto.UpdateAnnotationRequest]("annotation", h, service).WithFilters(annotationFilters),
 		service:         service,
 	}
 }
@@ -35,6 +35,7 @@ func (h *ReaderSettingsHandler) GetPreferences() fiber.Handler {
 		if err != nil {
 			return nil, err
 		}
+		setETag(c, prefs)
 
 		resp := response.Response[domain.UserPreferences]{
 			Status:  http.StatusOK,
@@ -53,10 +54,11 @@ func (h *ReaderSettingsHandler) PatchPreferences() fiber.Handler {
 			return nil, err
 		}
 
-		updated, err := h.preferencesService.Patch(c.UserContext(), userID, req.ToUsecase())
+		updated, err := h.preferencesService.Patch(conditionalContext(c), userID, req.ToUsecase())
 		if err != nil {
 			return nil, err
 		}
+		setETag(c, updated)
 
 		resp := response.Response[domain.UserPreferences]{
 			Status:  http.StatusOK,
@@ -79,6 +81,7 @@ func (h *ReaderSettingsHandler) GetReaderState() fiber.Handler {
 		if err != nil {
 			return nil, err
 		}
+		setETag(c, state)
 
 		resp := response.Response[domain.UserReaderState]{
 			Status:  http.StatusOK,
@@ -102,10 +105,11 @@ func (h *ReaderSettingsHandler) PatchReaderState() fiber.Handler {
 			return nil, err
 		}
 
-		updated, err := h.readerStateService.Patch(c.UserContext(), userID, input)
+		updated, err := h.readerStateService.Patch(conditionalContext(c), userID, input)
 		if err != nil {
 			return nil, err
 		}
+		setETag(c, updated)
 
 		resp := response.Response[domain.UserReaderState]{
 			Status:  http.StatusOK,
@@ -1,9 +1,18 @@
 package handler
 
 import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"reflect"
+	"strings"
+	"sync"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
 	"github.com/jeheskielSunloy77/libra-link/internal/application"
 	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
 	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
@@ -11,12 +20,14 @@ import (
 	httpdto "github.com/jeheskielSunloy77/libra-link/internal/interface/http/dto"
 	"github.com/jeheskielSunloy77/libra-link/internal/interface/http/response"
 	httputils "github.com/jeheskielSunloy77/libra-link/internal/interface/http/utils"
+	"gorm.io/gorm/schema"
 )
 
 type ResourceHandler[T domain.BaseModel, S applicationdto.StoreDTO[T], U applicationdto.UpdateDTO[T], TS httpdto.StoreDTO[S], TU httpdto.UpdateDTO[U]] struct {
 	Handler
 	resourceName string
 	service      application.ResourceService[T, S, U]
+	filters      port.FilterSpec
 }
 
 func NewResourceHandler[T domain.BaseModel, S applicationdto.StoreDTO[T], U applicationdto.UpdateDTO[T], TS httpdto.StoreDTO[S], TU httpdto.UpdateDTO[U]](resourceName string, base Handler, service application.ResourceService[T, S, U]) *ResourceHandler[T, S, U, TS, TU] {
@@ -27,6 +38,12 @@ func NewResourceHandler[T domain.BaseModel, S applicationdto.StoreDTO[T], U appl
 	}
 }
 
+// WithFilters allows GetMany to be filtered by the fields in spec.
+func (h *ResourceHandler[T, S, U, TS, TU]) WithFilters(spec port.FilterSpec) *ResourceHandler[T, S, U, TS, TU] {
+	h.filters = spec
+	return h
+}
+
 func (h *ResourceHandler[T, S, U, TS, TU]) Update() fiber.Handler {
 	return Handle(h.Handler, func(c *fiber.Ctx, dto TU) (*T, error) {
 		id, err := httputils.ParseUUIDParam(c.Params("id"))
@@ -34,7 +51,12 @@ func (h *ResourceHandler[T, S, U, TS, TU]) Update() fiber.Handler {
 			return nil, err
 		}
 
-		return h.service.Update(c.UserContext(), id, dto.ToUsecase())
+		entity, err := h.service.Update(conditionalContext(c), id, dto.ToUsecase())
+		if err != nil {
+			return nil, err
+		}
+		setETag(c, entity)
+		return entity, nil
 	}, http.StatusOK, httpdto.NewDTO[TU]())
 }
 
@@ -46,20 +68,25 @@ func (h *ResourceHandler[T, S, U, TS, TU]) GetByID() fiber.Handler {
 		}
 
 		preloads := port.ParsePreloads(c.Query("preloads"))
-		return h.service.GetByID(c.UserContext(), id, preloads)
+		entity, err := h.service.GetByID(c.UserContext(), id, preloads)
+		if err != nil {
+			return nil, err
+		}
+		setETag(c, entity)
+		return entity, nil
 	}, http.StatusOK, &httpdto.Empty{})
 }
 
 func (h *ResourceHandler[T, S, U, TS, TU]) GetMany() fiber.Handler {
 	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (response.PaginatedResponse[T], error) {
-		options := getManyOptionsFromRequest(c)
-		entities, total, err := h.service.GetMany(c.UserContext(), options)
+		options, err := h.getManyOptions(c)
 		if err != nil {
 			return response.PaginatedResponse[T]{}, err
 		}
 
-		resp := response.NewPaginatedResponse("Successfully fetched "+h.resourceName+"s!", entities, total, options.Limit, options.Offset)
-		return resp, nil
+		return listPage("Successfully fetched "+h.resourceName+"s!", options, func(options port.GetManyOptions) ([]T, int64, error) {
+			return h.service.GetMany(c.UserContext(), options)
+		})
 	}, http.StatusOK, &httpdto.Empty{})
 }
 
@@ -70,7 +97,7 @@ func (h *ResourceHandler[T, S, U, TS, TU]) Destroy() fiber.Handler {
 			return nil, err
 		}
 
-		err = h.service.Destroy(c.UserContext(), id)
+		err = h.service.Destroy(conditionalContext(c), id)
 		if err != nil {
 			return nil, err
 		}
@@ -92,7 +119,7 @@ func (h *ResourceHandler[T, S, U, TS, TU]) Kill() fiber.Handler {
 			return nil, err
 		}
 
-		err = h.service.Kill(c.UserContext(), id)
+		err = h.service.Kill(conditionalContext(c), id)
 		if err != nil {
 			return nil, err
 		}
@@ -119,6 +146,7 @@ func (h *ResourceHandler[T, S, U, TS, TU]) Restore() fiber.Handler {
 		if err != nil {
 			return nil, err
 		}
+		setETag(c, entity)
 
 		resp := response.Response[T]{
 			Status:  http.StatusOK,
@@ -149,6 +177,26 @@ func (h *ResourceHandler[T, S, U, TS, TU]) Store() fiber.Handler {
 	}, http.StatusCreated, httpdto.NewDTO[TS]())
 }
 
+// getManyOptions reads the listing options of a request, including its filters
+// and cursor.
+func (h *ResourceHandler[T, S, U, TS, TU]) getManyOptions(c *fiber.Ctx) (port.GetManyOptions, error) {
+	options := getManyOptionsFromRequest(c)
+	if err := applyListCursor(c, &options); err != nil {
+		return port.GetManyOptions{}, err
+	}
+
+	wheres, err := h.filters.Wheres(parseFilterQuery(c))
+	if err != nil {
+		var filterErr *port.FilterError
+		if errors.As(err, &filterErr) {
+			return port.GetManyOptions{}, errs.NewBadRequestError("invalid filter", true, []errs.FieldError{{Field: "filter[" + filterErr.Field + "]", Error: filterErr.Message}}, nil)
+		}
+		return port.GetManyOptions{}, err
+	}
+	options.Wheres = append(options.Wheres, wheres...)
+	return options, nil
+}
+
 func getManyOptionsFromRequest(c *fiber.Ctx) port.GetManyOptions {
 	opts := port.GetManyOptions{
 		Limit:          httputils.ParseQueryInt(c.Query("limit")),
@@ -156,7 +204,157 @@ func getManyOptionsFromRequest(c *fiber.Ctx) port.GetManyOptions {
 		Preloads:       port.ParsePreloads(c.Query("preloads")),
 		OrderBy:        c.Query("orderBy"),
 		OrderDirection: c.Query("orderDirection"),
+		SkipCount:      !c.QueryBool("count", true),
 	}
 	opts.Normalize()
 	return opts
 }
+
+// listPage lists a page of entities. When the listing is not counted or
+// continues a cursor, one row beyond the limit is fetched to tell whether
+// another page follows. A page with more after it carries the cursor of its
+// last item.
+func listPage[T domain.BaseModel](message string, options port.GetManyOptions, list func(port.GetManyOptions) ([]T, int64, error)) (response.PaginatedResponse[T], error) {
+	lookahead := options.SkipCount || options.After != nil
+	query := options
+	if lookahead {
+		query.Limit++
+	}
+
+	entities, total, err := list(query)
+	if err != nil {
+		return response.PaginatedResponse[T]{}, err
+	}
+
+	hasMore := len(entities) > options.Limit
+	if hasMore {
+		entities = entities[:options.Limit]
+	}
+
+	resp := response.NewPaginatedResponse(message, entities, total, options.Limit, options.Offset)
+	if lookahead {
+		resp.HasMore = hasMore
+	}
+	if resp.HasMore && len(entities) > 0 {
+		if resp.NextCursor, err = encodeListCursor(options, entities[len(entities)-1]); err != nil {
+			return response.PaginatedResponse[T]{}, err
+		}
+	}
+	return resp, nil
+}
+
+// listCursor is the decoded form of a listing cursor: the listing's order,
+// which the next page must keep, and the position of the last row handed out.
+type listCursor struct {
+	OrderBy        string          `json:"o"`
+	OrderDirection string          `json:"d"`
+	Value          json.RawMessage `json:"v"`
+	ID             uuid.UUID       `json:"id"`
+}
+
+// listCursorSchemas caches the models parsed by encodeListCursor.
+var listCursorSchemas sync.Map
+
+// encodeListCursor makes the opaque cursor continuing a listing after entity.
+// It carries the entity's orderBy value, resolved against the model as the
+// repository resolves it, so the next page is found without reading the row
+// again.
+func encodeListCursor(options port.GetManyOptions, entity domain.BaseModel) (string, error) {
+	model, err := schema.Parse(entity, &listCursorSchemas, schema.NamingStrategy{})
+	if err != nil {
+		return "", err
+	}
+	field := model.LookUpField(options.OrderBy)
+	if field == nil {
+		return "", errs.NewBadRequestError("invalid orderBy", true, []errs.FieldError{{Field: "orderBy", Error: "is not a sortable column"}}, nil)
+	}
+	value, _ := field.ValueOf(context.Background(), reflect.Indirect(reflect.ValueOf(entity)))
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := json.Marshal(listCursor{OrderBy: options.OrderBy, OrderDirection: options.OrderDirection, Value: encoded, ID: entity.GetID()})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+// applyListCursor continues the listing after the cursor query parameter, when
+// one is given. The cursor sets the order, so orderBy and orderDirection may be
+// omitted, but they must match it when given.
+func applyListCursor(c *fiber.Ctx, options *port.GetManyOptions) error {
+	cursor := c.Query("cursor")
+	if cursor == "" {
+		return nil
+	}
+
+	invalid := errs.NewBadRequestError("invalid cursor", true, nil, nil)
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return invalid
+	}
+	var decoded listCursor
+	if err := json.Unmarshal(raw, &decoded); err != nil || decoded.OrderBy == "" || len(decoded.Value) == 0 || decoded.ID == uuid.Nil {
+		return invalid
+	}
+
+	if (c.Query("orderBy") != "" && c.Query("orderBy") != decoded.OrderBy) ||
+		(c.Query("orderDirection") != "" && options.OrderDirection != decoded.OrderDirection) {
+		return errs.NewBadRequestError("cursor does not match orderBy and orderDirection", true, nil, nil)
+	}
+
+	options.OrderBy = decoded.OrderBy
+	options.OrderDirection = decoded.OrderDirection
+	options.After = &port.ListCursor{Value: decoded.Value, ID: decoded.ID}
+	options.Normalize()
+	return nil
+}
+
+// fixedOrderListOptions reads the paging options and cursor of a listing whose
+// order is fixed, rejecting cursors made for any other order.
+func fixedOrderListOptions(c *fiber.Ctx, orderBy, orderDirection string) (port.GetManyOptions, error) {
+	options := port.GetManyOptions{
+		Limit:          httputils.ParseQueryInt(c.Query("limit"), 100, 20),
+		Offset:         httputils.ParseQueryInt(c.Query("offset")),
+		OrderBy:        orderBy,
+		OrderDirection: orderDirection,
+		SkipCount:      !c.QueryBool("count", true),
+	}
+	options.Normalize()
+	if err := applyListCursor(c, &options); err != nil {
+		return port.GetManyOptions{}, err
+	}
+	if options.OrderBy != orderBy || options.OrderDirection != orderDirection {
+		return port.GetManyOptions{}, errs.NewBadRequestError("invalid cursor", true, nil, nil)
+	}
+	return options, nil
+}
+
+// parseFilterQuery reads the filter[field]=value and filter[field][op]=value
+// query parameters of a request. A field may be given more than once.
+func parseFilterQuery(c *fiber.Ctx) []port.Filter {
+	var filters []port.Filter
+	c.Context().QueryArgs().VisitAll(func(key, value []byte) {
+		rest, ok := strings.CutPrefix(string(key), "filter[")
+		if !ok {
+			return
+		}
+		field, rest, ok := strings.Cut(rest, "]")
+		if !ok {
+			return
+		}
+
+		filter := port.Filter{Field: field, Value: string(value)}
+		if rest != "" {
+			operator, ok := strings.CutPrefix(rest, "[")
+			if !ok || !strings.HasSuffix(operator, "]") {
+				return
+			}
+			filter.Operator = port.FilterOperator(strings.TrimSuffix(operator, "]"))
+		}
+		filters = append(filters, filter)
+	})
+	return filters
+}
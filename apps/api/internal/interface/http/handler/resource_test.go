@@ -5,7 +5,9 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/url"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/jeheskielSunloy77/libra-link/internal/application"
@@ -111,3 +113,137 @@ func TestResourceHandlerGetMany_Paginates(t *testing.T) {
 	require.Equal(t, 5, got.Total)
 	require.Equal(t, 3, got.TotalPages)
 }
+
+// Ensures GetMany hands out a cursor that continues an uncounted listing in the same order.
+func TestResourceHandlerGetMany_CursorPages(t *testing.T) {
+	srv := newTestServer()
+	app := newTestApp(srv)
+
+	users := []domain.User{{ID: uuid.New(), Username: "ada"}, {ID: uuid.New(), Username: "bea"}, {ID: uuid.New(), Username: "cy"}}
+	var captured port.GetManyOptions
+	mockService := application.NewMockResourceService[domain.User, *applicationdto.StoreUserInput, *applicationdto.UpdateUserInput]()
+	mockService.GetManyFn = func(ctx context.Context, opts port.GetManyOptions) ([]domain.User, int64, error) {
+		captured = opts
+		if opts.After == nil {
+			return users, -1, nil
+		}
+		return users[2:], -1, nil
+	}
+
+	h := NewResourceHandler[domain.User, *applicationdto.StoreUserInput, *applicationdto.UpdateUserInput, *httpdto.StoreUserRequest, *httpdto.UpdateUserRequest]("user", NewHandler(srv), mockService)
+	app.Get("/users", h.GetMany())
+
+	getPage := func(query string) (int, response.PaginatedResponse[domain.User]) {
+		req, err := http.NewRequest(http.MethodGet, "/users?"+query, nil)
+		require.NoError(t, err)
+		resp, err := app.Test(req)
+		require.NoError(t, err)
+
+		var got response.PaginatedResponse[domain.User]
+		if resp.StatusCode == http.StatusOK {
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&got))
+		}
+		return resp.StatusCode, got
+	}
+
+	status, first := getPage("limit=2&count=false&orderBy=username&orderDirection=asc")
+	require.Equal(t, http.StatusOK, status)
+	require.True(t, captured.SkipCount)
+	require.Equal(t, 3, captured.Limit)
+	require.Nil(t, captured.After)
+	require.Len(t, first.Data, 2)
+	require.Equal(t, -1, first.Total)
+	require.True(t, first.HasMore)
+	require.NotEmpty(t, first.NextCursor)
+
+	status, second := getPage("limit=2&count=false&cursor=" + first.NextCursor)
+	require.Equal(t, http.StatusOK, status)
+	require.NotNil(t, captured.After)
+	require.Equal(t, users[1].ID, captured.After.ID)
+	require.JSONEq(t, `"bea"`, string(captured.After.Value))
+	require.Equal(t, "username", captured.OrderBy)
+	require.Equal(t, "asc", captured.OrderDirection)
+	require.Equal(t, 0, captured.Offset)
+	require.Len(t, second.Data, 1)
+	require.False(t, second.HasMore)
+	require.Empty(t, second.NextCursor)
+
+	status, _ = getPage("cursor=" + first.NextCursor + "&orderBy=email")
+	require.Equal(t, http.StatusBadRequest, status)
+
+	status, _ = getPage("cursor=not-a-cursor")
+	require.Equal(t, http.StatusBadRequest, status)
+}
+
+// Ensures GetMany translates allowlisted filters into bound where clauses.
+func TestResourceHandlerGetMany_Filters(t *testing.T) {
+	srv := newTestServer()
+	app := newTestApp(srv)
+
+	var captured port.GetManyOptions
+	mockService := application.NewMockResourceService[domain.User, *applicationdto.StoreUserInput, *applicationdto.UpdateUserInput]()
+	mockService.GetManyFn = func(ctx context.Context, opts port.GetManyOptions) ([]domain.User, int64, error) {
+		captured = opts
+		return []domain.User{}, 0, nil
+	}
+
+	h := NewResourceHandler[domain.User, *applicationdto.StoreUserInput, *applicationdto.UpdateUserInput, *httpdto.StoreUserRequest, *httpdto.UpdateUserRequest]("user", NewHandler(srv), mockService).WithFilters(userFilters)
+	app.Get("/users", h.GetMany())
+
+	query := url.Values{}
+	query.Set("filter[isAdmin]", "true")
+	query.Set("filter[username][like]", "50%_off")
+	query.Set("filter[createdAt][gte]", "2026-01-02T03:04:05Z")
+	query.Set("filter[bannedAt][null]", "false")
+	req, err := http.NewRequest(http.MethodGet, "/users?"+query.Encode(), nil)
+	require.NoError(t, err)
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	wheres := map[string][]any{}
+	for _, where := range captured.Wheres {
+		wheres[where.Query] = where.Args
+	}
+	require.Equal(t, map[string][]any{
+		"is_admin = ?":                      {true},
+		`LOWER(username) LIKE ? ESCAPE '\'`: {`%50\%\_off%`},
+		"created_at >= ?":                   {time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)},
+		"banned_at IS NOT NULL":             nil,
+	}, wheres)
+}
+
+// Ensures GetMany rejects filters outside the allowlist without calling the application.
+func TestResourceHandlerGetMany_RejectsInvalidFilters(t *testing.T) {
+	srv := newTestServer()
+	app := newTestApp(srv)
+
+	called := false
+	mockService := application.NewMockResourceService[domain.User, *applicationdto.StoreUserInput, *applicationdto.UpdateUserInput]()
+	mockService.GetManyFn = func(ctx context.Context, opts port.GetManyOptions) ([]domain.User, int64, error) {
+		called = true
+		return nil, 0, nil
+	}
+
+	h := NewResourceHandler[domain.User, *applicationdto.StoreUserInput, *applicationdto.UpdateUserInput, *httpdto.StoreUserRequest, *httpdto.UpdateUserRequest]("user", NewHandler(srv), mockService).WithFilters(userFilters)
+	app.Get("/users", h.GetMany())
+
+	for _, rawQuery := range []string{
+		"filter[password_hash]=x",
+		"filter[email][gt]=a",
+		"filter[isAdmin]=maybe",
+		"filter[createdAt][lt]=yesterday",
+		"filter[username][null]=true",
+	} {
+		t.Run(rawQuery, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodGet, "/users?"+rawQuery, nil)
+			require.NoError(t, err)
+
+			resp, err := app.Test(req)
+			require.NoError(t, err)
+			require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+		})
+	}
+	require.False(t, called)
+}
@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/jeheskielSunloy77/libra-link/internal/application"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	httpdto "github.com/jeheskielSunloy77/libra-link/internal/interface/http/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/interface/http/response"
+	httputils "github.com/jeheskielSunloy77/libra-link/internal/interface/http/utils"
+)
+
+type SearchHandler struct {
+	Handler
+	service application.SearchService
+}
+
+func NewSearchHandler(h Handler, service application.SearchService) *SearchHandler {
+	return &SearchHandler{Handler: h, service: service}
+}
+
+func (h *SearchHandler) Search() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (response.PaginatedResponse[domain.SearchHit], error) {
+		userID, err := parseAuthenticatedUserID(c)
+		if err != nil {
+			return response.PaginatedResponse[domain.SearchHit]{}, err
+		}
+
+		input := &applicationdto.SearchInput{
+			UserID: userID,
+			Query:  c.Query("q"),
+			Scope:  domain.SearchScope(c.Query("scope")),
+			Limit:  httputils.ParseQueryInt(c.Query("limit"), 100, 20),
+			Offset: httputils.ParseQueryInt(c.Query("offset")),
+		}
+		hits, total, err := h.service.Search(c.UserContext(), input)
+		if err != nil {
+			return response.PaginatedResponse[domain.SearchHit]{}, err
+		}
+
+		return response.NewPaginatedResponse("Successfully searched!", hits, total, input.Limit, input.Offset), nil
+	}, http.StatusOK, &httpdto.Empty{})
+}
@@ -6,12 +6,24 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/jeheskielSunloy77/libra-link/internal/application"
 	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
 	"github.com/jeheskielSunloy77/libra-link/internal/domain"
 	httpdto "github.com/jeheskielSunloy77/libra-link/internal/interface/http/dto"
 	"github.com/jeheskielSunloy77/libra-link/internal/interface/http/response"
 	httputils "github.com/jeheskielSunloy77/libra-link/internal/interface/http/utils"
 )
 
+var shareFilters = port.FilterSpec{
+	"ebookId":              {Column: "ebook_id", Type: port.FilterTypeUUID},
+	"ownerUserId":          {Column: "owner_user_id", Type: port.FilterTypeUUID},
+	"visibility":           {Column: "visibility", Type: port.FilterTypeString, Values: []string{string(domain.ShareVisibilityPublic), string(domain.ShareVisibilityUnlisted)}},
+	"status":               {Column: "status", Type: port.FilterTypeString, Values: []string{string(domain.ShareStatusActive), string(domain.ShareStatusDisabled), string(domain.ShareStatusRemoved)}},
+	"borrowDurationHours":  {Column: "borrow_duration_hours", Type: port.FilterTypeInt},
+	"maxConcurrentBorrows": {Column: "max_concurrent_borrows", Type: port.FilterTypeInt},
+	"createdAt":            {Column: "created_at", Type: port.FilterTypeTime},
+	"updatedAt":            {Column: "updated_at", Type: port.FilterTypeTime},
+}
+
 type ShareHandler struct {
 	*ResourceHandler[domain.Share, *applicationdto.StoreShareInput, *applicationdto.UpdateShareInput, *httpdto.StoreShareRequest, *httpdto.UpdateShareRequest]
 	service application.ShareService
@@ -19,7 +31,7 @@ type ShareHandler struct {
 
 func NewShareHandler(h Handler, service application.ShareService) *ShareHandler {
 	return &ShareHandler{
-		ResourceHandler: NewResourceHandler[domain.Share, *applicationdto.StoreShareInput, *applicationdto.UpdateShareInput, *httpdto.StoreShareRequest, *httpdto.UpdateShareRequest]("share", h, service),
+		ResourceHandler: NewResourceHandler[domain.Share, *applicationdto.StoreShareInput, *applicationdto.UpdateShareInput, *httpdto.StoreShareRequest, *httpdto.UpdateShareRequest]("share", h, service).WithFilters(shareFilters),
 		service:         service,
 	}
 }
@@ -108,6 +120,66 @@ func (h *ShareHandler) ReturnBorrow() fiber.Handler {
 	}, http.StatusOK, &httpdto.Empty{})
 }
 
+func (h *ShareHandler) IssueBorrowAccess() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (*response.Response[application.BorrowAccess], error) {
+		borrowID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return nil, err
+		}
+		userID, err := parseAuthenticatedUserID(c)
+		if err != nil {
+			return nil, err
+		}
+
+		access, err := h.service.IssueBorrowAccess(c.UserContext(), borrowID, userID)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[application.BorrowAccess]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Successfully issued borrow access!",
+			Data:    access,
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.Empty{})
+}
+
+func (h *ShareHandler) DownloadBorrowedFile() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		borrowID, err := httputils.ParseUUIDParam(c.Params("id"))
+		if err != nil {
+			return err
+		}
+		userID, err := parseAuthenticatedUserID(c)
+		if err != nil {
+			return err
+		}
+
+		file, err := h.service.OpenBorrowedFile(c.UserContext(), borrowID, userID)
+		if err != nil {
+			return err
+		}
+
+		c.Attachment(file.Ebook.Title + "." + string(file.Ebook.Format))
+		c.Set(fiber.HeaderContentType, file.ContentType)
+		return c.Status(http.StatusOK).SendStream(file.Body, int(file.Size))
+	}
+}
+
+func (h *ShareHandler) GetBorrowLeaseKey() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (*response.Response[application.BorrowLeaseKey], error) {
+		resp := response.Response[application.BorrowLeaseKey]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Successfully fetched borrow lease key!",
+			Data:    h.service.BorrowLeaseKey(),
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.Empty{})
+}
+
 func (h *ShareHandler) UpsertReview() fiber.Handler {
 	return Handle(h.Handler, func(c *fiber.Ctx, req *httpdto.UpsertShareReviewRequest) (*response.Response[domain.ShareReview], error) {
 		shareID, err := httputils.ParseUUIDParam(c.Params("id"))
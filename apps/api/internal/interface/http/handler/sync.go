@@ -1,25 +1,40 @@
 package handler
 
 import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
 	"github.com/jeheskielSunloy77/libra-link/internal/application"
 	"github.com/jeheskielSunloy77/libra-link/internal/domain"
 	httpdto "github.com/jeheskielSunloy77/libra-link/internal/interface/http/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/interface/http/middleware"
 	"github.com/jeheskielSunloy77/libra-link/internal/interface/http/response"
 	httputils "github.com/jeheskielSunloy77/libra-link/internal/interface/http/utils"
 )
 
+// syncStreamHeartbeat keeps idle event streams alive through proxies and lets
+// the server notice disconnected clients and revoked access.
+var syncStreamHeartbeat = 15 * time.Second
+
+// syncStreamReplayPage is how many missed events a reconnecting stream lists at
+// a time.
+const syncStreamReplayPage = 200
+
 type SyncHandler struct {
 	Handler
-	service application.SyncService
+	service  application.SyncService
+	accounts middleware.AccountAccessChecker
 }
 
-func NewSyncHandler(h Handler, service application.SyncService) *SyncHandler {
-	return &SyncHandler{Handler: h, service: service}
+func NewSyncHandler(h Handler, service application.SyncService, accounts middleware.AccountAccessChecker) *SyncHandler {
+	return &SyncHandler{Handler: h, service: service, accounts: accounts}
 }
 
 func (h *SyncHandler) StoreEvent() fiber.Handler {
@@ -47,11 +62,38 @@ func (h *SyncHandler) StoreEvent() fiber.Handler {
 	}, http.StatusCreated, &httpdto.StoreSyncEventRequest{})
 }
 
+func (h *SyncHandler) StoreEvents() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, req *httpdto.StoreSyncEventsRequest) (*response.Response[[]application.SyncEventResult], error) {
+		userID, err := parseAuthenticatedUserID(c)
+		if err != nil {
+			return nil, err
+		}
+
+		inputs := req.ToUsecase()
+		for _, input := range inputs {
+			input.UserID = userID
+		}
+
+		results, err := h.service.StoreEvents(c.UserContext(), inputs)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[[]application.SyncEventResult]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Sync events processed successfully!",
+			Data:    &results,
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.StoreSyncEventsRequest{})
+}
+
 func (h *SyncHandler) ListEvents() fiber.Handler {
-	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (response.PaginatedResponse[domain.SyncEvent], error) {
+	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (response.CursorResponse[domain.SyncEvent], error) {
 		userID, err := parseAuthenticatedUserID(c)
 		if err != nil {
-			return response.PaginatedResponse[domain.SyncEvent]{}, err
+			return response.CursorResponse[domain.SyncEvent]{}, err
 		}
 
 		limit := httputils.ParseQueryInt(c.Query("limit"), 200, 100)
@@ -59,18 +101,186 @@ func (h *SyncHandler) ListEvents() fiber.Handler {
 		if rawSince := c.Query("since"); rawSince != "" {
 			parsed, parseErr := time.Parse(time.RFC3339, rawSince)
 			if parseErr != nil {
-				return response.PaginatedResponse[domain.SyncEvent]{}, errs.NewBadRequestError("invalid since value; expected RFC3339 datetime", true, nil, nil)
+				return response.CursorResponse[domain.SyncEvent]{}, errs.NewBadRequestError("invalid since value; expected RFC3339 datetime", true, nil, nil)
 			}
 			utc := parsed.UTC()
 			since = &utc
 		}
 
-		events, err := h.service.ListEvents(c.UserContext(), userID, since, limit)
+		page, err := h.service.ListEvents(c.UserContext(), userID, since, c.Query("cursor"), limit)
 		if err != nil {
-			return response.PaginatedResponse[domain.SyncEvent]{}, err
+			return response.CursorResponse[domain.SyncEvent]{}, err
 		}
 
-		resp := response.NewPaginatedResponse("Successfully fetched sync events!", events, int64(len(events)), limit, 0)
+		resp := response.NewCursorResponse("Successfully fetched sync events!", page.Events, limit, page.NextCursor, page.HasMore)
 		return resp, nil
 	}, http.StatusOK, &httpdto.Empty{})
 }
+
+func (h *SyncHandler) Snapshot() fiber.Handler {
+	return Handle(h.Handler, func(c *fiber.Ctx, _ *httpdto.Empty) (*response.Response[application.SyncSnapshot], error) {
+		userID, err := parseAuthenticatedUserID(c)
+		if err != nil {
+			return nil, err
+		}
+
+		snapshot, err := h.service.Snapshot(c.UserContext(), userID)
+		if err != nil {
+			return nil, err
+		}
+
+		resp := response.Response[application.SyncSnapshot]{
+			Status:  http.StatusOK,
+			Success: true,
+			Message: "Successfully fetched sync snapshot!",
+			Data:    snapshot,
+		}
+		return &resp, nil
+	}, http.StatusOK, &httpdto.Empty{})
+}
+
+// StreamEvents holds a server-sent events stream open and writes each sync
+// event stored for the current user, from any device, as a "sync" event whose
+// id is the cursor listing the events after it. A client reconnecting with that
+// id as Last-Event-ID first receives the events it missed. The stream closes
+// when the access token it was opened with expires, and at the first heartbeat
+// after the user is banned or suspended or the device is revoked.
+func (h *SyncHandler) StreamEvents() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, err := parseAuthenticatedUserID(c)
+		if err != nil {
+			return err
+		}
+
+		// The stream outlives the request context, which is cancelled as soon as
+		// this handler returns, so it only keeps the device that opened it.
+		ctx := context.Background()
+		if device, ok := application.ClientDeviceFromContext(c.UserContext()); ok {
+			ctx = application.WithClientDevice(ctx, device)
+		}
+		var cancel context.CancelFunc
+		if expiry, ok := middleware.GetTokenExpiry(c); ok {
+			ctx, cancel = context.WithDeadline(ctx, expiry)
+		} else {
+			ctx, cancel = context.WithCancel(ctx)
+		}
+
+		events, err := h.service.SubscribeEvents(ctx, userID)
+		if err != nil {
+			cancel()
+			return err
+		}
+
+		// Events stored while the client was away are listed after subscribing,
+		// so none falls in between. Clients apply events idempotently, so one
+		// that is both listed and published does no harm.
+		var missed []domain.SyncEvent
+		if lastEventID := c.Get("Last-Event-ID"); lastEventID != "" {
+			missed, err = h.listMissedEvents(c.UserContext(), userID, lastEventID)
+			if err != nil {
+				cancel()
+				return err
+			}
+		}
+
+		c.Set(fiber.HeaderContentType, "text/event-stream")
+		c.Set(fiber.HeaderCacheControl, "no-cache")
+		c.Set(fiber.HeaderConnection, "keep-alive")
+		c.Set("X-Accel-Buffering", "no")
+
+		conn := c.Context().Conn()
+		writeTimeout := h.server.Config.Server.WriteTimeout
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			defer cancel()
+
+			write := func(frame []byte) bool {
+				if writeTimeout > 0 {
+					_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+				}
+				if _, err := w.Write(frame); err != nil {
+					return false
+				}
+				return w.Flush() == nil
+			}
+			writeEvent := func(event domain.SyncEvent) bool {
+				data, err := json.Marshal(event)
+				if err != nil {
+					return true
+				}
+				frame := make([]byte, 0, len(data)+128)
+				frame = append(frame, "id: "+application.EncodeSyncEventCursor(event)+"\nevent: sync\ndata: "...)
+				frame = append(frame, data...)
+				frame = append(frame, "\n\n"...)
+				return write(frame)
+			}
+
+			if !write([]byte(": connected\n\n")) {
+				return
+			}
+			for _, event := range missed {
+				if !writeEvent(event) {
+					return
+				}
+			}
+
+			heartbeat := time.NewTicker(syncStreamHeartbeat)
+			defer heartbeat.Stop()
+
+			for {
+				select {
+				case event, ok := <-events:
+					if !ok {
+						return
+					}
+					if !writeEvent(event) {
+						return
+					}
+				case <-heartbeat.C:
+					if h.streamRevoked(ctx, userID) || !write([]byte(": ping\n\n")) {
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		})
+
+		return nil
+	}
+}
+
+// listMissedEvents lists every event of the user after the cursor a client
+// last received on the stream.
+func (h *SyncHandler) listMissedEvents(ctx context.Context, userID uuid.UUID, cursor string) ([]domain.SyncEvent, error) {
+	var missed []domain.SyncEvent
+	for {
+		page, err := h.service.ListEvents(ctx, userID, nil, cursor, syncStreamReplayPage)
+		if err != nil {
+			return nil, err
+		}
+		missed = append(missed, page.Events...)
+		if !page.HasMore {
+			return missed, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// streamRevoked reports whether the user may no longer hold the stream. A
+// failed check that is not the user's doing keeps the stream open.
+func (h *SyncHandler) streamRevoked(ctx context.Context, userID uuid.UUID) bool {
+	checks := []func() error{
+		func() error { return h.service.CheckSubscription(ctx, userID) },
+	}
+	if h.accounts != nil {
+		checks = append(checks, func() error { return h.accounts.CheckAccess(ctx, userID) })
+	}
+
+	for _, check := range checks {
+		var httpErr *errs.ErrorResponse
+		if err := check(); errors.As(err, &httpErr) && httpErr.Status < http.StatusInternalServerError {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,229 @@
+package handler
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	"github.com/jeheskielSunloy77/libra-link/internal/application"
+	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/domain"
+	"github.com/jeheskielSunloy77/libra-link/internal/interface/http/middleware"
+	"github.com/stretchr/testify/require"
+)
+
+type stubSyncService struct {
+	subscribeFn         func(ctx context.Context, userID uuid.UUID) (<-chan domain.SyncEvent, error)
+	listFn              func(ctx context.Context, userID uuid.UUID, since *time.Time, cursor string, limit int) (*application.SyncEventPage, error)
+	checkSubscriptionFn func(ctx context.Context, userID uuid.UUID) error
+}
+
+func (s *stubSyncService) StoreEvent(ctx context.Context, input *applicationdto.StoreSyncEventInput) (*domain.SyncEvent, error) {
+	return nil, nil
+}
+
+func (s *stubSyncService) StoreEvents(ctx context.Context, inputs []*applicationdto.StoreSyncEventInput) ([]application.SyncEventResult, error) {
+	return nil, nil
+}
+
+func (s *stubSyncService) ListEvents(ctx context.Context, userID uuid.UUID, since *time.Time, cursor string, limit int) (*application.SyncEventPage, error) {
+	if s.listFn != nil {
+		return s.listFn(ctx, userID, since, cursor, limit)
+	}
+	return &application.SyncEventPage{}, nil
+}
+
+func (s *stubSyncService) CheckSubscription(ctx context.Context, userID uuid.UUID) error {
+	if s.checkSubscriptionFn != nil {
+		return s.checkSubscriptionFn(ctx, userID)
+	}
+	return nil
+}
+
+func (s *stubSyncService) Snapshot(ctx context.Context, userID uuid.UUID) (*application.SyncSnapshot, error) {
+	return &application.SyncSnapshot{}, nil
+}
+
+func (s *stubSyncService) CompactEvents(ctx context.Context, expireBefore time.Time) (int, int, error) {
+	return 0, 0, nil
+}
+
+func (s *stubSyncService) SubscribeEvents(ctx context.Context, userID uuid.UUID) (<-chan domain.SyncEvent, error) {
+	return s.subscribeFn(ctx, userID)
+}
+
+// Ensures StreamEvents writes each published event as a server-sent event.
+func TestSyncHandlerStreamEvents_WritesEvents(t *testing.T) {
+	srv := newTestServer()
+	app := newTestApp(srv)
+
+	userID := uuid.New()
+	event := domain.SyncEvent{
+		ID:         uuid.New(),
+		UserID:     userID,
+		EntityType: domain.SyncEntityTypeBookmark,
+		EntityID:   uuid.New(),
+		Operation:  domain.SyncOperationUpsert,
+	}
+	service := &stubSyncService{
+		subscribeFn: func(ctx context.Context, id uuid.UUID) (<-chan domain.SyncEvent, error) {
+			require.Equal(t, userID, id)
+			events := make(chan domain.SyncEvent, 1)
+			events <- event
+			close(events)
+			return events, nil
+		},
+	}
+
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(middleware.UserIDKey, userID.String())
+		return c.Next()
+	})
+
+	h := NewSyncHandler(NewHandler(srv), service, nil)
+	app.Get("/sync/events/stream", h.StreamEvents())
+
+	req, err := http.NewRequest(http.MethodGet, "/sync/events/stream", nil)
+	require.NoError(t, err)
+
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get(fiber.HeaderContentType))
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(string(body), ": connected\n\n"))
+	require.Contains(t, string(body), "id: "+application.EncodeSyncEventCursor(event)+"\nevent: sync\ndata: {")
+	require.Contains(t, string(body), `"entityId":"`+event.EntityID.String()+`"`)
+}
+
+// Ensures StreamEvents rejects anonymous requests before subscribing.
+func TestSyncHandlerStreamEvents_MissingUserID(t *testing.T) {
+	srv := newTestServer()
+	app := newTestApp(srv)
+
+	called := false
+	service := &stubSyncService{
+		subscribeFn: func(ctx context.Context, id uuid.UUID) (<-chan domain.SyncEvent, error) {
+			called = true
+			return nil, nil
+		},
+	}
+
+	h := NewSyncHandler(NewHandler(srv), service, nil)
+	app.Get("/sync/events/stream", h.StreamEvents())
+
+	req, err := http.NewRequest(http.MethodGet, "/sync/events/stream", nil)
+	require.NoError(t, err)
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+	require.False(t, called)
+}
+
+// Ensures a stream resumed with Last-Event-ID first writes the events missed
+// since that cursor.
+func TestSyncHandlerStreamEvents_ReplaysMissedEvents(t *testing.T) {
+	srv := newTestServer()
+	app := newTestApp(srv)
+
+	userID := uuid.New()
+	missed := domain.SyncEvent{ID: uuid.New(), UserID: userID, ServerTimestamp: time.Now().UTC()}
+	service := &stubSyncService{
+		subscribeFn: func(ctx context.Context, id uuid.UUID) (<-chan domain.SyncEvent, error) {
+			events := make(chan domain.SyncEvent)
+			close(events)
+			return events, nil
+		},
+		listFn: func(ctx context.Context, id uuid.UUID, since *time.Time, cursor string, limit int) (*application.SyncEventPage, error) {
+			require.Equal(t, "last-cursor", cursor)
+			return &application.SyncEventPage{Events: []domain.SyncEvent{missed}, NextCursor: application.EncodeSyncEventCursor(missed)}, nil
+		},
+	}
+
+	app.Use(func(c *fiber.Ctx) error {
+		c.Locals(middleware.UserIDKey, userID.String())
+		return c.Next()
+	})
+
+	h := NewSyncHandler(NewHandler(srv), service, nil)
+	app.Get("/sync/events/stream", h.StreamEvents())
+
+	req, err := http.NewRequest(http.MethodGet, "/sync/events/stream", nil)
+	require.NoError(t, err)
+	req.Header.Set("Last-Event-ID", "last-cursor")
+
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	require.Contains(t, string(body), "id: "+application.EncodeSyncEventCursor(missed)+"\nevent: sync\n")
+}
+
+// Ensures a stream closes once its device is revoked or its access token expires.
+func TestSyncHandlerStreamEvents_ClosesWhenAccessEnds(t *testing.T) {
+	heartbeat := syncStreamHeartbeat
+	syncStreamHeartbeat = 10 * time.Millisecond
+	t.Cleanup(func() { syncStreamHeartbeat = heartbeat })
+
+	tests := []struct {
+		name              string
+		expiry            time.Time
+		checkSubscription func(ctx context.Context, userID uuid.UUID) error
+	}{
+		{
+			name:   "device revoked",
+			expiry: time.Now().Add(time.Hour),
+			checkSubscription: func(ctx context.Context, userID uuid.UUID) error {
+				return errs.NewForbiddenError("this device has been revoked", true)
+			},
+		},
+		{
+			name:   "token expired",
+			expiry: time.Now().Add(50 * time.Millisecond),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newTestServer()
+			app := newTestApp(srv)
+
+			userID := uuid.New()
+			service := &stubSyncService{
+				subscribeFn: func(ctx context.Context, id uuid.UUID) (<-chan domain.SyncEvent, error) {
+					return make(chan domain.SyncEvent), nil
+				},
+				checkSubscriptionFn: tt.checkSubscription,
+			}
+
+			app.Use(func(c *fiber.Ctx) error {
+				c.Locals(middleware.UserIDKey, userID.String())
+				c.Locals(middleware.TokenExpiryKey, tt.expiry)
+				return c.Next()
+			})
+
+			h := NewSyncHandler(NewHandler(srv), service, nil)
+			app.Get("/sync/events/stream", h.StreamEvents())
+
+			req, err := http.NewRequest(http.MethodGet, "/sync/events/stream", nil)
+			require.NoError(t, err)
+
+			resp, err := app.Test(req, 5000)
+			require.NoError(t, err)
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(string(body), ": connected\n\n"))
+		})
+	}
+}
@@ -3,16 +3,29 @@ package handler
 import (
 	"github.com/jeheskielSunloy77/libra-link/internal/application"
 	applicationdto "github.com/jeheskielSunloy77/libra-link/internal/application/dto"
+	"github.com/jeheskielSunloy77/libra-link/internal/application/port"
 	"github.com/jeheskielSunloy77/libra-link/internal/domain"
 	httpdto "github.com/jeheskielSunloy77/libra-link/internal/interface/http/dto"
 )
 
+var userFilters = port.FilterSpec{
+	"email":           {Column: "email", Type: port.FilterTypeString},
+	"username":        {Column: "username", Type: port.FilterTypeString},
+	"isAdmin":         {Column: "is_admin", Type: port.FilterTypeBool},
+	"emailVerifiedAt": {Column: "email_verified_at", Type: port.FilterTypeTime, Nullable: true},
+	"lastLoginAt":     {Column: "last_login_at", Type: port.FilterTypeTime, Nullable: true},
+	"suspendedAt":     {Column: "suspended_at", Type: port.FilterTypeTime, Nullable: true},
+	"bannedAt":        {Column: "banned_at", Type: port.FilterTypeTime, Nullable: true},
+	"createdAt":       {Column: "created_at", Type: port.FilterTypeTime},
+	"updatedAt":       {Column: "updated_at", Type: port.FilterTypeTime},
+}
+
 type UserHandler struct {
 	*ResourceHandler[domain.User, *applicationdto.StoreUserInput, *applicationdto.UpdateUserInput, *httpdto.StoreUserRequest, *httpdto.UpdateUserRequest]
 }
 
 func NewUserHandler(h Handler, service application.UserService) *UserHandler {
 	return &UserHandler{
-		ResourceHandler: NewResourceHandler[domain.User, *applicationdto.StoreUserInput, *applicationdto.UpdateUserInput, *httpdto.StoreUserRequest, *httpdto.UpdateUserRequest]("user", h, service),
+		ResourceHandler: NewResourceHandler[domain.User, *applicationdto.StoreUserInput, *applicationdto.UpdateUserInput, *httpdto.StoreUserRequest, *httpdto.UpdateUserRequest]("user", h, service).WithFilters(userFilters),
 	}
 }
@@ -1,6 +1,7 @@
 package middleware
 
 import (
+	"context"
 	"strings"
 	"time"
 
@@ -8,17 +9,25 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"github.com/jeheskielSunloy77/libra-link/internal/app/errs"
+	"github.com/jeheskielSunloy77/libra-link/internal/application"
 	"github.com/jeheskielSunloy77/libra-link/internal/domain"
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/server"
 )
 
 type AuthMiddleware struct {
 	server           *server.Server
+	accounts         AccountAccessChecker
 	secret           []byte
 	accessCookieName string
 }
 
-func NewAuthMiddleware(s *server.Server) *AuthMiddleware {
+// AccountAccessChecker rejects users who were banned or suspended after their
+// access token was issued.
+type AccountAccessChecker interface {
+	CheckAccess(ctx context.Context, userID uuid.UUID) error
+}
+
+func NewAuthMiddleware(s *server.Server, accounts AccountAccessChecker) *AuthMiddleware {
 	cookieName := s.Config.Auth.AccessCookieName
 	if strings.TrimSpace(cookieName) == "" {
 		cookieName = "access_token"
@@ -26,6 +35,7 @@ func NewAuthMiddleware(s *server.Server) *AuthMiddleware {
 
 	return &AuthMiddleware{
 		server:           s,
+		accounts:         accounts,
 		secret:           []byte(s.Config.Auth.SecretKey),
 		accessCookieName: cookieName,
 	}
@@ -73,13 +83,24 @@ func (auth *AuthMiddleware) RequireAuth() fiber.Handler {
 			return errs.NewUnauthorizedError("Unauthorized", false)
 		}
 
-		if _, err := uuid.Parse(claims.Subject); err != nil {
+		userID, err := uuid.Parse(claims.Subject)
+		if err != nil {
 			return errs.NewUnauthorizedError("Unauthorized", false)
 		}
 
+		if auth.accounts != nil {
+			if err := auth.accounts.CheckAccess(c.UserContext(), userID); err != nil {
+				return err
+			}
+		}
+
 		c.Locals(UserIDKey, claims.Subject)
 		c.Locals(UserEmailKey, claims.Email)
 		c.Locals(UserIsAdminKey, claims.IsAdmin)
+		if claims.ExpiresAt != nil {
+			c.Locals(TokenExpiryKey, claims.ExpiresAt.Time)
+		}
+		c.SetUserContext(application.WithActor(c.UserContext(), application.Actor{UserID: userID, IsAdmin: claims.IsAdmin}))
 
 		auth.server.Logger.Info().
 			Str("function", "RequireAuth").
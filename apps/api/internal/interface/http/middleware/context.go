@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/jeheskielSunloy77/libra-link/internal/application"
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/logger"
 	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/server"
 	"github.com/newrelic/go-agent/v3/newrelic"
@@ -16,9 +17,17 @@ const (
 	UserRoleKey    = "user_role"
 	UserEmailKey   = "user_email"
 	UserIsAdminKey = "user_is_admin"
+	TokenExpiryKey = "token_expiry"
 	LoggerKey      = "logger"
 )
 
+// Clients identify their installation with these headers so sessions and sync
+// positions can be tracked per device.
+const (
+	DeviceIDHeader   = "X-Device-ID"
+	DeviceNameHeader = "X-Device-Name"
+)
+
 type ContextEnhancer struct {
 	server *server.Server
 }
@@ -69,6 +78,9 @@ func (ce *ContextEnhancer) EnhanceContext() fiber.Handler {
 		c.Locals(LoggerKey, &contextLogger)
 
 		ctx := context.WithValue(c.UserContext(), LoggerKey, &contextLogger)
+		if device, ok := application.NewClientDevice(c.Get(DeviceIDHeader), c.Get(DeviceNameHeader)); ok {
+			ctx = application.WithClientDevice(ctx, device)
+		}
 		c.SetUserContext(ctx)
 
 		return c.Next()
@@ -119,6 +131,13 @@ func GetUserIsAdmin(c *fiber.Ctx) bool {
 	return false
 }
 
+// GetTokenExpiry returns when the access token that authenticated the request
+// expires, and false when it has no expiry.
+func GetTokenExpiry(c *fiber.Ctx) (time.Time, bool) {
+	expiry, ok := c.Locals(TokenExpiryKey).(time.Time)
+	return expiry, ok
+}
+
 func GetLogger(c *fiber.Ctx) *zerolog.Logger {
 	if logger, ok := c.Locals(LoggerKey).(*zerolog.Logger); ok {
 		return logger
@@ -112,6 +112,7 @@ func (global *GlobalMiddlewares) GlobalErrorHandler(c *fiber.Ctx, err error) err
 	var message string
 	var fieldErrors []errs.FieldError
 	var action *errs.Action
+	var data any
 
 	switch {
 	case errors.As(err, &httpErr):
@@ -119,6 +120,7 @@ func (global *GlobalMiddlewares) GlobalErrorHandler(c *fiber.Ctx, err error) err
 		message = httpErr.Message
 		fieldErrors = httpErr.Errors
 		action = httpErr.Action
+		data = httpErr.Data
 
 	case errors.As(err, &fiberErr):
 		status = fiberErr.Code
@@ -149,6 +151,7 @@ func (global *GlobalMiddlewares) GlobalErrorHandler(c *fiber.Ctx, err error) err
 		Override: httpErr != nil && httpErr.Override,
 		Errors:   fieldErrors,
 		Action:   action,
+		Data:     data,
 	})
 
 	return nil
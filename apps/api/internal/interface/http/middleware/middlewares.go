@@ -22,14 +22,18 @@ func NewMiddlewares(s *server.Server, services *application.Services) *Middlewar
 		nrApp = s.LoggerService.GetApplication()
 	}
 
-	var authorizer AuthorizationEnforcer
+	var (
+		authorizer AuthorizationEnforcer
+		accounts   AccountAccessChecker
+	)
 	if services != nil {
 		authorizer = services.Authorization
+		accounts = services.User
 	}
 
 	return &Middlewares{
 		Global:          NewGlobalMiddlewares(s),
-		Auth:            NewAuthMiddleware(s),
+		Auth:            NewAuthMiddleware(s, accounts),
 		Authorization:   NewAuthorizationMiddleware(authorizer),
 		ContextEnhancer: NewContextEnhancer(s),
 		Tracing:         NewTracingMiddleware(s, nrApp),
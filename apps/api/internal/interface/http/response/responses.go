@@ -8,20 +8,28 @@ type Response[T any] struct {
 	Data *T `json:"data,omitempty"`
 }
 
+// PaginatedResponse is a page of a listing. Total and TotalPages are -1 when
+// the listing was not counted. NextCursor, when set, continues the listing
+// after the last item regardless of rows inserted since.
 type PaginatedResponse[T any] struct {
 	Message string `json:"message"`
 	Status  int    `json:"status"`
 	Success bool   `json:"success"`
 
-	Data       []T `json:"data"`
-	Page       int `json:"page"`
-	Limit      int `json:"limit"`
-	Total      int `json:"total"`
-	TotalPages int `json:"totalPages"`
+	Data       []T    `json:"data"`
+	Page       int    `json:"page"`
+	Limit      int    `json:"limit"`
+	Total      int    `json:"total"`
+	TotalPages int    `json:"totalPages"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	HasMore    bool   `json:"hasMore"`
 }
 
 func NewPaginatedResponse[T any](message string, entities []T, total int64, limit int, offset int) PaginatedResponse[T] {
 	totalPages := int((total + int64(limit) - 1) / int64(limit))
+	if total < 0 {
+		totalPages = -1
+	}
 	page := offset/limit + 1
 
 	return PaginatedResponse[T]{
@@ -33,5 +41,35 @@ func NewPaginatedResponse[T any](message string, entities []T, total int64, limi
 		Limit:      limit,
 		Total:      int(total),
 		TotalPages: totalPages,
+		HasMore:    total >= 0 && int64(offset+len(entities)) < total,
+	}
+}
+
+// CursorResponse is a page of a keyset-paginated listing. Clients pass NextCursor
+// back to continue after the last item and stop once HasMore is false.
+type CursorResponse[T any] struct {
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+	Success bool   `json:"success"`
+
+	Data       []T    `json:"data"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	HasMore    bool   `json:"hasMore"`
+}
+
+func NewCursorResponse[T any](message string, entities []T, limit int, nextCursor string, hasMore bool) CursorResponse[T] {
+	if entities == nil {
+		entities = []T{}
+	}
+
+	return CursorResponse[T]{
+		Status:     200,
+		Success:    true,
+		Message:    message,
+		Data:       entities,
+		Limit:      limit,
+		NextCursor: nextCursor,
+		HasMore:    hasMore,
 	}
 }
@@ -24,6 +24,8 @@ func NewRouter(s *server.Server, h *handler.Handlers, services *application.Serv
 		ReadTimeout:           s.Config.Server.ReadTimeout,
 		WriteTimeout:          s.Config.Server.WriteTimeout,
 		IdleTimeout:           s.Config.Server.IdleTimeout,
+		BodyLimit:             s.Config.FileStorage.MaxUploadSizeMB * 1024 * 1024,
+		StreamRequestBody:     true,
 		DisableStartupMessage: true,
 	})
 
@@ -59,7 +61,8 @@ func NewRouter(s *server.Server, h *handler.Handlers, services *application.Serv
 		cors.New(cors.Config{
 			AllowOrigins:     strings.Join(s.Config.Server.CORSAllowedOrigins, ","),
 			AllowCredentials: true,
-			AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
+			AllowHeaders:     "Origin, Content-Type, Accept, Authorization, If-Match, If-None-Match, " + middleware.DeviceIDHeader + ", " + middleware.DeviceNameHeader,
+			ExposeHeaders:    "ETag",
 		}),
 		helmet.New(),
 		middleware.RequestID(),
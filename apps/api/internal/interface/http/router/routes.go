@@ -1,7 +1,10 @@
 package router
 
 import (
+	"slices"
+
 	"github.com/gofiber/fiber/v2"
+	"github.com/jeheskielSunloy77/libra-link/internal/infrastructure/config"
 	"github.com/jeheskielSunloy77/libra-link/internal/interface/http/handler"
 	"github.com/jeheskielSunloy77/libra-link/internal/interface/http/middleware"
 )
@@ -13,8 +16,11 @@ func registerRoutes(
 ) {
 	// system routes
 	r.Get("/health", h.Health.GetHealth)
-	r.Static("/static", "static")
+	r.Static("/static", config.StaticDir)
 	r.Get("/api/docs", h.OpenAPI.ServeOpenAPIUI)
+	if signedPath := h.File.SignedPath(); signedPath != "" {
+		r.Get(signedPath+"/*", h.File.ServeSigned())
+	}
 
 	// versioned routes
 	api := r.Group("/api/v1")
@@ -35,31 +41,73 @@ func registerRoutes(
 	authProtected.Post("/resend-verification", h.Auth.ResendVerification())
 	authProtected.Post("/logout-all", h.Auth.LogoutAll())
 
-	// protected routes
+	// protected routes. The authorization check runs on each route rather than on
+	// the group, because group middleware only sees the group prefix as its route
+	// while policies match on route patterns.
 	protected := api.Group("", middlewares.Auth.RequireAuth())
+	authorize := middlewares.Authorization.RequireAuthorization()
+
+	protected.Get("/users/preferences", authorize, h.ReaderSettings.GetPreferences())
+	protected.Patch("/users/preferences", authorize, h.ReaderSettings.PatchPreferences())
+	protected.Get("/users/reader-state", authorize, h.ReaderSettings.GetReaderState())
+	protected.Patch("/users/reader-state", authorize, h.ReaderSettings.PatchReaderState())
+
+	resource(protected, "/users", h.User, authorize)
+	resource(protected, "/ebooks", h.Ebook, authorize)
+	resource(protected, "/shares", h.Share, authorize)
+	resource(protected, "/reading-progress", h.ReadingProgress, authorize)
+	resource(protected, "/bookmarks", h.Bookmark, authorize)
+	resource(protected, "/annotations", h.Annotation, authorize)
+	resource(protected, "/authors", h.Author, authorize)
+	resource(protected, "/tags", h.Tag, authorize)
 
-	protected.Get("/users/preferences", h.ReaderSettings.GetPreferences())
-	protected.Patch("/users/preferences", h.ReaderSettings.PatchPreferences())
-	protected.Get("/users/reader-state", h.ReaderSettings.GetReaderState())
-	protected.Patch("/users/reader-state", h.ReaderSettings.PatchReaderState())
+	protected.Post("/ebooks/:id/metadata", authorize, h.Ebook.AttachMetadata())
+	protected.Delete("/ebooks/:id/metadata", authorize, h.Ebook.DetachMetadata())
+	protected.Post("/ebooks/:id/metadata/google/:volumeId", authorize, h.Ebook.AttachGoogleVolume())
+	protected.Get("/ebooks/:id/metadata/suggestions", authorize, h.Ebook.ListMetadataSuggestions())
+	protected.Post("/ebooks/:id/metadata/suggestions/:suggestionId/accept", authorize, h.Ebook.AcceptMetadataSuggestion())
+	protected.Delete("/ebooks/:id/metadata/suggestions", authorize, h.Ebook.DismissMetadataSuggestions())
+	protected.Get("/metadata/google-books/search", authorize, h.Ebook.SearchGoogleBooks())
+	protected.Get("/search", authorize, h.Search.Search())
+	protected.Put("/ebooks/:id/authors", authorize, h.Ebook.SetAuthors())
+	protected.Put("/ebooks/:id/tags", authorize, h.Ebook.SetTags())
+	protected.Put("/ebooks/:id/file", authorize, h.Ebook.UploadFile())
+	protected.Get("/ebooks/:id/file", authorize, h.Ebook.DownloadFile())
+	protected.Get("/ebooks/:id/file/url", authorize, h.Ebook.GetFileURL())
+	protected.Get("/ebooks/:id/search", authorize, h.Ebook.SearchContent())
 
-	resource(protected, "/users", h.User)
-	resource(protected, "/ebooks", h.Ebook)
-	resource(protected, "/shares", h.Share)
-	resource(protected, "/reading-progress", h.ReadingProgress)
-	resource(protected, "/bookmarks", h.Bookmark)
-	resource(protected, "/annotations", h.Annotation)
+	protected.Post("/shares/:id/borrow", authorize, h.Share.Borrow())
+	protected.Post("/borrows/:id/return", authorize, h.Share.ReturnBorrow())
+	protected.Get("/borrows/lease-key", authorize, h.Share.GetBorrowLeaseKey())
+	protected.Post("/borrows/:id/access", authorize, h.Share.IssueBorrowAccess())
+	protected.Get("/borrows/:id/file", authorize, h.Share.DownloadBorrowedFile())
+	protected.Put("/shares/:id/review", authorize, h.Share.UpsertReview())
+	protected.Post("/shares/:id/report", authorize, h.Share.CreateReport())
 
-	protected.Post("/ebooks/:id/metadata", h.Ebook.AttachMetadata())
-	protected.Delete("/ebooks/:id/metadata", h.Ebook.DetachMetadata())
+	protected.Get("/devices", authorize, h.Device.List())
+	protected.Patch("/devices/:id", authorize, h.Device.Rename())
+	protected.Delete("/devices/:id", authorize, h.Device.Revoke())
 
-	protected.Post("/shares/:id/borrow", h.Share.Borrow())
-	protected.Post("/borrows/:id/return", h.Share.ReturnBorrow())
-	protected.Put("/shares/:id/review", h.Share.UpsertReview())
-	protected.Post("/shares/:id/report", h.Share.CreateReport())
+	protected.Post("/sync/events", authorize, h.Sync.StoreEvent())
+	protected.Post("/sync/events\\:batch", authorize, h.Sync.StoreEvents())
+	protected.Get("/sync/events", authorize, h.Sync.ListEvents())
+	protected.Get("/sync/events/stream", authorize, h.Sync.StreamEvents())
+	protected.Get("/sync/snapshot", authorize, h.Sync.Snapshot())
 
-	protected.Post("/sync/events", h.Sync.StoreEvent())
-	protected.Get("/sync/events", h.Sync.ListEvents())
+	protected.Get("/admin/authorization/policies", authorize, h.Authorization.ListPolicies())
+	protected.Post("/admin/authorization/policies", authorize, h.Authorization.AddPolicy())
+	protected.Delete("/admin/authorization/policies", authorize, h.Authorization.RemovePolicy())
+
+	protected.Get("/admin/share-reports", authorize, h.Moderation.ListReports())
+	protected.Post("/admin/share-reports/:id/claim", authorize, h.Moderation.ClaimReport())
+	protected.Post("/admin/share-reports/:id/resolve", authorize, h.Moderation.ResolveReport())
+	protected.Post("/admin/share-reports/:id/reject", authorize, h.Moderation.RejectReport())
+	protected.Post("/admin/shares/:id/reinstate", authorize, h.Moderation.ReinstateShare())
+	protected.Post("/admin/users/:id/suspend", authorize, h.Moderation.SuspendUser())
+	protected.Post("/admin/users/:id/ban", authorize, h.Moderation.BanUser())
+	protected.Post("/admin/users/:id/reinstate", authorize, h.Moderation.ReinstateUser())
+
+	protected.Get("/admin/audit-events", authorize, h.Audit.ListEvents())
 }
 
 type resourceHandler interface {
@@ -72,13 +120,19 @@ type resourceHandler interface {
 	Update() fiber.Handler
 }
 
-func resource(group fiber.Router, path string, h resourceHandler, authMiddleware ...fiber.Handler) {
-	g := group.Group(path, authMiddleware...)
-	g.Get("/", h.GetMany())
-	g.Get("/:id", h.GetByID())
-	g.Post("/", h.Store())
-	g.Delete("/:id", h.Destroy())
-	g.Delete("/:id/kill", h.Kill())
-	g.Patch("/:id/restore", h.Restore())
-	g.Patch("/:id", h.Update())
+// resource registers the CRUD routes of h under path, each with middleware in
+// front of its handler.
+func resource(group fiber.Router, path string, h resourceHandler, middleware ...fiber.Handler) {
+	g := group.Group(path)
+	route := func(handler fiber.Handler) []fiber.Handler {
+		return append(slices.Clone(middleware), handler)
+	}
+
+	g.Get("/", route(h.GetMany())...)
+	g.Get("/:id", route(h.GetByID())...)
+	g.Post("/", route(h.Store())...)
+	g.Delete("/:id", route(h.Destroy())...)
+	g.Delete("/:id/kill", route(h.Kill())...)
+	g.Patch("/:id/restore", route(h.Restore())...)
+	g.Patch("/:id", route(h.Update())...)
 }
@@ -126,6 +126,65 @@ func TestUsersInvalidIDStillReturnsUUIDValidationError(t *testing.T) {
 	require.Equal(t, "must be a valid uuid", payload.Errors[0].Error)
 }
 
+func TestResourceMiddlewareSeesRoutePattern(t *testing.T) {
+	app := fiber.New()
+	api := app.Group("/api/v1")
+
+	var routes []string
+	recordRoute := func(c *fiber.Ctx) error {
+		routes = append(routes, c.Route().Path)
+		return c.Next()
+	}
+
+	resource(api, "/users", fakeUserResourceHandler{}, recordRoute)
+
+	targets := []struct {
+		method string
+		target string
+	}{
+		{method: http.MethodGet, target: "/api/v1/users"},
+		{method: http.MethodGet, target: "/api/v1/users/9fd6f3d6-39ff-4b8e-9e68-a8bd96d96d2c"},
+		{method: http.MethodDelete, target: "/api/v1/users/9fd6f3d6-39ff-4b8e-9e68-a8bd96d96d2c/kill"},
+	}
+	for _, tc := range targets {
+		resp, err := app.Test(httptest.NewRequest(tc.method, tc.target, nil), -1)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+
+	require.Equal(t, []string{"/api/v1/users/", "/api/v1/users/:id", "/api/v1/users/:id/kill"}, routes)
+}
+
+func TestSyncBatchRouteMatchesLiteralColon(t *testing.T) {
+	app := fiber.New()
+	api := app.Group("/api/v1")
+
+	api.Post("/sync/events", func(c *fiber.Ctx) error { return c.SendString("sync-store") })
+	api.Post("/sync/events\\:batch", func(c *fiber.Ctx) error { return c.SendString("sync-batch") })
+
+	tests := []struct {
+		target   string
+		wantBody string
+	}{
+		{target: "/api/v1/sync/events", wantBody: "sync-store"},
+		{target: "/api/v1/sync/events:batch", wantBody: "sync-batch"},
+	}
+
+	for _, tc := range tests {
+		req := httptest.NewRequest(http.MethodPost, tc.target, strings.NewReader("{}"))
+		req.Header.Set("Content-Type", "application/json")
+		resp, err := app.Test(req, -1)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, tc.wantBody, readBody(t, resp))
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/v1/sync/events:other", strings.NewReader("{}"))
+	resp, err := app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
 func readBody(t *testing.T, resp *http.Response) string {
 	t.Helper()
 	defer resp.Body.Close()
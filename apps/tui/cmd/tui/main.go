@@ -12,7 +12,6 @@ import (
 	"github.com/jeheskielSunloy77/libra-link/apps/tui/internal/session"
 	"github.com/jeheskielSunloy77/libra-link/apps/tui/internal/storage/repo"
 	"github.com/jeheskielSunloy77/libra-link/apps/tui/internal/storage/sqlite"
-	"github.com/jeheskielSunloy77/libra-link/apps/tui/internal/storage/sqlite/sqlcdb"
 	syncer "github.com/jeheskielSunloy77/libra-link/apps/tui/internal/sync"
 )
 
@@ -28,13 +27,13 @@ func main() {
 	}
 	defer db.Close()
 
-	queries := sqlcdb.New(db)
-	repository := repo.New(queries)
+	repository := repo.New(db)
 
 	apiClient, err := api.NewClient(cfg.APIBaseURL, cfg.HTTPTimeout)
 	if err != nil {
 		fatalf("create API client: %v", err)
 	}
+	apiClient.SetDevice(cfg.DeviceID, cfg.DeviceName)
 
 	sessionStore := session.NewStore(cfg.SessionPath)
 	worker := syncer.NewWorker(repository, apiClient, cfg.SyncBatchSize)
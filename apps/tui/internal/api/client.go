@@ -1,12 +1,19 @@
 package api
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/http/cookiejar"
 	"net/url"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -20,6 +27,8 @@ import (
 const (
 	defaultAccessCookieName  = "access_token"
 	defaultRefreshCookieName = "refresh_token"
+	deviceIDHeader           = "X-Device-ID"
+	deviceNameHeader         = "X-Device-Name"
 )
 
 type Client struct {
@@ -31,6 +40,8 @@ type Client struct {
 	userID    string
 	accessCK  string
 	refreshCK string
+	deviceID  string
+	device    string
 	mu        sync.RWMutex
 }
 
@@ -55,13 +66,49 @@ func NewClient(baseURL string, timeout time.Duration) (*Client, error) {
 		return nil, err
 	}
 
-	return &Client{
+	client := &Client{
 		client:    genClient,
 		http:      httpClient,
 		baseURL:   parsedBase,
 		accessCK:  defaultAccessCookieName,
 		refreshCK: defaultRefreshCookieName,
-	}, nil
+	}
+	httpClient.Transport = &deviceTransport{base: http.DefaultTransport, client: client}
+	return client, nil
+}
+
+// SetDevice names this installation on every request so the API can track its
+// sessions and sync position separately from the user's other devices.
+func (c *Client) SetDevice(id, name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.deviceID = id
+	c.device = name
+}
+
+func (c *Client) deviceHeaders() (id, name string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.deviceID, c.device
+}
+
+type deviceTransport struct {
+	base   http.RoundTripper
+	client *Client
+}
+
+func (t *deviceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	id, name := t.client.deviceHeaders()
+	if id == "" {
+		return t.base.RoundTrip(req)
+	}
+
+	req = req.Clone(req.Context())
+	req.Header.Set(deviceIDHeader, id)
+	if name != "" {
+		req.Header.Set(deviceNameHeader, name)
+	}
+	return t.base.RoundTrip(req)
 }
 
 func (c *Client) SetSession(accessToken, refreshToken, userID string) {
@@ -308,49 +355,186 @@ func (c *Client) CreateEbook(ctx context.Context, input CreateEbookInput) (*Eboo
 	return item, nil
 }
 
-func (c *Client) ListShares(ctx context.Context, limit int) ([]Share, error) {
+func (c *Client) UploadEbookFile(ctx context.Context, ebookID string, file io.Reader, size int64) error {
+	if _, err := parseUUID(ebookID); err != nil {
+		return err
+	}
+
+	endpoint := c.baseURL.JoinPath("api", "v1", "ebooks", ebookID, "file")
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint.String(), file)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	if err := c.withBearer()(ctx, req); err != nil {
+		return err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return apiError("upload ebook file", resp.StatusCode, body)
+	}
+	return nil
+}
+
+// ListShares returns a page of up to limit community shares, newest first. It
+// continues after cursor when one is given; the listing is not counted, so
+// callers page until HasMore is false.
+func (c *Client) ListShares(ctx context.Context, cursor string, limit int) (*SharePage, error) {
 	if limit <= 0 {
 		limit = 20
 	}
-	params := &gen.ShareGetManyParams{Limit: &limit}
-	resp, err := c.client.ShareGetManyWithResponse(ctx, params, c.withBearer())
-	if err != nil {
-		return nil, err
+	endpoint := c.baseURL.JoinPath("api", "v1", "shares")
+	query := endpoint.Query()
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("count", "false")
+	if cursor != "" {
+		query.Set("cursor", cursor)
 	}
-	if resp.JSON200 == nil {
-		return nil, apiError("list shares", resp.StatusCode(), resp.Body)
+	endpoint.RawQuery = query.Encode()
+
+	var payload struct {
+		Data []struct {
+			ID            string  `json:"id"`
+			EbookID       string  `json:"ebookId"`
+			OwnerUserID   string  `json:"ownerUserId"`
+			Status        string  `json:"status"`
+			Visibility    string  `json:"visibility"`
+			TitleOverride *string `json:"titleOverride"`
+		} `json:"data"`
+		NextCursor string `json:"nextCursor"`
+		HasMore    bool   `json:"hasMore"`
+	}
+	if err := c.doJSON(ctx, "list shares", http.MethodGet, endpoint, nil, &payload); err != nil {
+		return nil, err
 	}
 
-	items := make([]Share, 0, len(resp.JSON200.Data))
-	for _, row := range resp.JSON200.Data {
+	page := &SharePage{
+		Shares:     make([]Share, 0, len(payload.Data)),
+		NextCursor: payload.NextCursor,
+		HasMore:    payload.HasMore,
+	}
+	for _, row := range payload.Data {
 		entry := Share{
-			ID:          row.Id.String(),
-			EbookID:     row.EbookId.String(),
-			OwnerUserID: row.OwnerUserId.String(),
-			Status:      string(row.Status),
-			Visibility:  string(row.Visibility),
+			ID:          row.ID,
+			EbookID:     row.EbookID,
+			OwnerUserID: row.OwnerUserID,
+			Status:      row.Status,
+			Visibility:  row.Visibility,
 		}
 		if row.TitleOverride != nil {
 			entry.Title = *row.TitleOverride
 		}
-		items = append(items, entry)
+		page.Shares = append(page.Shares, entry)
 	}
-	return items, nil
+	return page, nil
 }
 
-func (c *Client) BorrowShare(ctx context.Context, shareID string) error {
+func (c *Client) BorrowShare(ctx context.Context, shareID string) (*Borrow, error) {
 	uuidVal, err := parseUUID(shareID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	resp, err := c.client.ShareBorrowWithResponse(ctx, uuidVal, gen.ShareBorrowJSONRequestBody{LegalAcknowledged: gen.True}, c.withBearer())
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if resp.StatusCode() != http.StatusCreated {
-		return apiError("borrow share", resp.StatusCode(), resp.Body)
+	if resp.StatusCode() != http.StatusCreated || resp.JSON201 == nil {
+		return nil, apiError("borrow share", resp.StatusCode(), resp.Body)
 	}
-	return nil
+	data := resp.JSON201.Data
+	return &Borrow{
+		ID:      data.Id.String(),
+		ShareID: data.ShareId.String(),
+		DueAt:   data.DueAt,
+		Status:  string(data.Status),
+	}, nil
+}
+
+func (c *Client) IssueBorrowAccess(ctx context.Context, borrowID string) (*BorrowAccess, error) {
+	if _, err := parseUUID(borrowID); err != nil {
+		return nil, err
+	}
+
+	var payload struct {
+		Data struct {
+			BorrowID  string    `json:"borrowId"`
+			EbookID   string    `json:"ebookId"`
+			Lease     string    `json:"lease"`
+			ExpiresAt time.Time `json:"expiresAt"`
+		} `json:"data"`
+	}
+	endpoint := c.baseURL.JoinPath("api", "v1", "borrows", borrowID, "access")
+	if err := c.doJSON(ctx, "issue borrow access", http.MethodPost, endpoint, nil, &payload); err != nil {
+		return nil, err
+	}
+	return &BorrowAccess{
+		BorrowID:  payload.Data.BorrowID,
+		EbookID:   payload.Data.EbookID,
+		Lease:     payload.Data.Lease,
+		ExpiresAt: payload.Data.ExpiresAt,
+	}, nil
+}
+
+// DownloadBorrowedFile writes the file of an actively borrowed ebook to w and
+// returns its content type.
+func (c *Client) DownloadBorrowedFile(ctx context.Context, borrowID string, w io.Writer) (string, error) {
+	if _, err := parseUUID(borrowID); err != nil {
+		return "", err
+	}
+
+	endpoint := c.baseURL.JoinPath("api", "v1", "borrows", borrowID, "file")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return "", err
+	}
+	if err := c.withBearer()(ctx, req); err != nil {
+		return "", err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return "", apiError("download borrowed file", resp.StatusCode, body)
+	}
+	if _, err := io.Copy(w, resp.Body); err != nil {
+		return "", err
+	}
+	return resp.Header.Get("Content-Type"), nil
+}
+
+// GetBorrowLeaseKey fetches the public key that verifies borrow leases offline.
+func (c *Client) GetBorrowLeaseKey(ctx context.Context) (ed25519.PublicKey, error) {
+	var payload struct {
+		Data struct {
+			Algorithm string `json:"algorithm"`
+			PublicKey string `json:"publicKey"`
+		} `json:"data"`
+	}
+	endpoint := c.baseURL.JoinPath("api", "v1", "borrows", "lease-key")
+	if err := c.doJSON(ctx, "get borrow lease key", http.MethodGet, endpoint, nil, &payload); err != nil {
+		return nil, err
+	}
+	if payload.Data.Algorithm != "Ed25519" {
+		return nil, fmt.Errorf("unsupported borrow lease algorithm %q", payload.Data.Algorithm)
+	}
+	key, err := base64.StdEncoding.DecodeString(payload.Data.PublicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("invalid borrow lease key")
+	}
+	return ed25519.PublicKey(key), nil
 }
 
 func (c *Client) UpsertReview(ctx context.Context, shareID string, rating int, review string) error {
@@ -551,6 +735,185 @@ func (c *Client) StoreSyncEvent(ctx context.Context, input SyncEvent) error {
 	return nil
 }
 
+// ListSyncEvents returns a page of up to limit server events, oldest first. It
+// continues after cursor when one is given, otherwise after since; a nil since
+// lists from the beginning.
+func (c *Client) ListSyncEvents(ctx context.Context, since *time.Time, cursor string, limit int) (*SyncEventPage, error) {
+	endpoint := c.baseURL.JoinPath("api", "v1", "sync", "events")
+	query := endpoint.Query()
+	if limit > 0 {
+		query.Set("limit", strconv.Itoa(limit))
+	}
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	} else if since != nil {
+		query.Set("since", since.UTC().Format(time.RFC3339Nano))
+	}
+	endpoint.RawQuery = query.Encode()
+
+	var payload struct {
+		Data       []syncEventJSON `json:"data"`
+		NextCursor string          `json:"nextCursor"`
+		HasMore    bool            `json:"hasMore"`
+	}
+	if err := c.doJSON(ctx, "list sync events", http.MethodGet, endpoint, nil, &payload); err != nil {
+		return nil, err
+	}
+
+	page := &SyncEventPage{
+		Events:     make([]SyncEvent, 0, len(payload.Data)),
+		NextCursor: payload.NextCursor,
+		HasMore:    payload.HasMore,
+	}
+	for _, item := range payload.Data {
+		page.Events = append(page.Events, item.toSyncEvent())
+	}
+	return page, nil
+}
+
+// StreamSyncEvents subscribes to the server-sent events stream of the current
+// user's sync events and calls handle for each event as it arrives. It blocks
+// until ctx is done, the server closes the stream, or handle returns an error.
+func (c *Client) StreamSyncEvents(ctx context.Context, handle func(SyncEvent) error) error {
+	endpoint := c.baseURL.JoinPath("api", "v1", "sync", "events", "stream")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if err := c.withBearer()(ctx, req); err != nil {
+		return err
+	}
+
+	// The stream stays open indefinitely, so the request timeout must not apply.
+	streamClient := *c.http
+	streamClient.Timeout = 0
+	resp, err := streamClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return apiError("stream sync events", resp.StatusCode, body)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	var eventType string
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			// A blank line dispatches the event collected so far.
+			if eventType == "sync" && data.Len() > 0 {
+				var item syncEventJSON
+				if err := json.Unmarshal([]byte(data.String()), &item); err != nil {
+					return fmt.Errorf("decode sync stream event: %w", err)
+				}
+				if err := handle(item.toSyncEvent()); err != nil {
+					return err
+				}
+			}
+			eventType = ""
+			data.Reset()
+		case strings.HasPrefix(line, ":"):
+			// Comment, used by the server for heartbeats.
+		case strings.HasPrefix(line, "event:"):
+			eventType = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			if data.Len() > 0 {
+				data.WriteByte('\n')
+			}
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+type syncEventJSON struct {
+	ID              string         `json:"id"`
+	UserID          string         `json:"userId"`
+	EntityType      string         `json:"entityType"`
+	EntityID        string         `json:"entityId"`
+	Operation       string         `json:"operation"`
+	Payload         map[string]any `json:"payload"`
+	BaseVersion     *int           `json:"baseVersion"`
+	ClientTimestamp time.Time      `json:"clientTimestamp"`
+	ServerTimestamp time.Time      `json:"serverTimestamp"`
+	IdempotencyKey  string         `json:"idempotencyKey"`
+}
+
+func (e syncEventJSON) toSyncEvent() SyncEvent {
+	return SyncEvent{
+		ID:             e.ID,
+		UserID:         e.UserID,
+		EntityType:     e.EntityType,
+		EntityID:       e.EntityID,
+		Operation:      e.Operation,
+		Payload:        e.Payload,
+		BaseVersion:    e.BaseVersion,
+		ClientTS:       e.ClientTimestamp,
+		ServerTS:       e.ServerTimestamp,
+		IdempotencyKey: e.IdempotencyKey,
+	}
+}
+
+// StoreSyncEvents pushes events in a single batch request and returns the
+// server's per-event results in the same order.
+func (c *Client) StoreSyncEvents(ctx context.Context, events []SyncEvent) ([]SyncEventResult, error) {
+	type batchEvent struct {
+		EntityType      string         `json:"entityType"`
+		EntityID        string         `json:"entityId"`
+		Operation       string         `json:"operation"`
+		Payload         map[string]any `json:"payload,omitempty"`
+		BaseVersion     *int           `json:"baseVersion,omitempty"`
+		ClientTimestamp time.Time      `json:"clientTimestamp"`
+		IdempotencyKey  string         `json:"idempotencyKey"`
+	}
+
+	body := struct {
+		Events []batchEvent `json:"events"`
+	}{Events: make([]batchEvent, 0, len(events))}
+	for _, event := range events {
+		if _, err := parseUUID(event.EntityID); err != nil {
+			return nil, err
+		}
+		body.Events = append(body.Events, batchEvent{
+			EntityType:      event.EntityType,
+			EntityID:        event.EntityID,
+			Operation:       event.Operation,
+			Payload:         event.Payload,
+			BaseVersion:     event.BaseVersion,
+			ClientTimestamp: event.ClientTS,
+			IdempotencyKey:  event.IdempotencyKey,
+		})
+	}
+
+	var payload struct {
+		Data []struct {
+			IdempotencyKey string `json:"idempotencyKey"`
+			Status         string `json:"status"`
+			Message        string `json:"message"`
+		} `json:"data"`
+	}
+	endpoint := c.baseURL.JoinPath("api", "v1", "sync", "events:batch")
+	if err := c.doJSON(ctx, "store sync events", http.MethodPost, endpoint, body, &payload); err != nil {
+		return nil, err
+	}
+
+	results := make([]SyncEventResult, 0, len(payload.Data))
+	for _, item := range payload.Data {
+		results = append(results, SyncEventResult(item))
+	}
+	return results, nil
+}
+
 func (c *Client) captureTokens(httpResp *http.Response) {
 	if httpResp == nil {
 		return
@@ -625,6 +988,44 @@ func (c *Client) withRefreshCookie() gen.RequestEditorFn {
 	}
 }
 
+// doJSON sends a body-less authenticated request and decodes a 200 JSON response into out.
+func (c *Client) doJSON(ctx context.Context, operation, method string, endpoint *url.URL, in any, out any) error {
+	var reqBody io.Reader
+	if in != nil {
+		encoded, err := json.Marshal(in)
+		if err != nil {
+			return err
+		}
+		reqBody = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), reqBody)
+	if err != nil {
+		return err
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if err := c.withBearer()(ctx, req); err != nil {
+		return err
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return apiError(operation, resp.StatusCode, body)
+	}
+	return json.Unmarshal(body, out)
+}
+
 func parseUUID(raw string) (openapi_types.UUID, error) {
 	parsed, err := uuid.Parse(raw)
 	if err != nil {
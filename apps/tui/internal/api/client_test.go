@@ -2,9 +2,14 @@ package api
 
 import (
 	"context"
+	"crypto/ed25519"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
@@ -188,3 +193,371 @@ func TestListEbooksMapsChecksumSizeAndLanguage(t *testing.T) {
 		t.Fatalf("unexpected mapped ebook fields: %#v", got)
 	}
 }
+
+func TestUploadEbookFileStreamsBodyWithBearer(t *testing.T) {
+	t.Parallel()
+
+	var gotAuth, gotContentType string
+	var gotBody []byte
+	var gotLength int64
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/ebooks/aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa/file" || r.Method != http.MethodPut {
+			http.NotFound(w, r)
+			return
+		}
+		gotAuth = r.Header.Get("Authorization")
+		gotContentType = r.Header.Get("Content-Type")
+		gotLength = r.ContentLength
+		gotBody, _ = io.ReadAll(r.Body)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"status":200,"success":true,"message":"ok"}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	client.SetSession("access-token", "refresh-token", "user-1")
+
+	content := "hello book"
+	if err := client.UploadEbookFile(context.Background(), "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa", strings.NewReader(content), int64(len(content))); err != nil {
+		t.Fatalf("upload ebook file: %v", err)
+	}
+
+	if gotAuth != "Bearer access-token" {
+		t.Fatalf("unexpected auth header: %q", gotAuth)
+	}
+	if gotContentType != "application/octet-stream" {
+		t.Fatalf("unexpected content type: %q", gotContentType)
+	}
+	if gotLength != int64(len(content)) || string(gotBody) != content {
+		t.Fatalf("unexpected upload body: length=%d body=%q", gotLength, string(gotBody))
+	}
+}
+
+func TestIssueBorrowAccessLeaseVerifiesOffline(t *testing.T) {
+	t.Parallel()
+
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+	lease := signTestLease(t, privateKey, map[string]any{
+		"sub":       "user-1",
+		"exp":       expiresAt.Unix(),
+		"borrow_id": "bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb",
+		"ebook_id":  "eeeeeeee-eeee-eeee-eeee-eeeeeeeeeeee",
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/v1/borrows/bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb/access":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+				"borrowId":  "bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb",
+				"ebookId":   "eeeeeeee-eeee-eeee-eeee-eeeeeeeeeeee",
+				"lease":     lease,
+				"expiresAt": expiresAt.Format(time.RFC3339),
+			}})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/v1/borrows/lease-key":
+			_ = json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{
+				"algorithm": "Ed25519",
+				"publicKey": base64.StdEncoding.EncodeToString(publicKey),
+			}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	client.SetSession("access-token", "refresh-token", "user-1")
+
+	access, err := client.IssueBorrowAccess(context.Background(), "bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb")
+	if err != nil {
+		t.Fatalf("issue borrow access: %v", err)
+	}
+	key, err := client.GetBorrowLeaseKey(context.Background())
+	if err != nil {
+		t.Fatalf("get borrow lease key: %v", err)
+	}
+
+	verified, err := VerifyBorrowLease(access.Lease, key, time.Now())
+	if err != nil {
+		t.Fatalf("verify lease: %v", err)
+	}
+	if verified.EbookID != "eeeeeeee-eeee-eeee-eeee-eeeeeeeeeeee" || !verified.ExpiresAt.Equal(expiresAt) {
+		t.Fatalf("unexpected lease: %+v", verified)
+	}
+
+	if _, err := VerifyBorrowLease(access.Lease, key, expiresAt.Add(time.Second)); !errors.Is(err, ErrBorrowLeaseExpired) {
+		t.Fatalf("expected expired lease, got %v", err)
+	}
+	parts := strings.Split(access.Lease, ".")
+	extended, _ := json.Marshal(map[string]any{"sub": "user-1", "exp": expiresAt.Add(24 * time.Hour).Unix()})
+	tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString(extended) + "." + parts[2]
+	if _, err := VerifyBorrowLease(tampered, key, time.Now()); !errors.Is(err, ErrBorrowLeaseInvalid) {
+		t.Fatalf("expected invalid lease, got %v", err)
+	}
+}
+
+func TestStoreSyncEventsPostsBatchAndMapsResults(t *testing.T) {
+	t.Parallel()
+
+	var gotPath, gotContentType string
+	var gotBody struct {
+		Events []map[string]any `json:"events"`
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.NotFound(w, r)
+			return
+		}
+		gotPath = r.URL.Path
+		gotContentType = r.Header.Get("Content-Type")
+		_ = json.NewDecoder(r.Body).Decode(&gotBody)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"data": []map[string]any{
+			{"idempotencyKey": "key-applied", "status": "applied", "event": map[string]any{"id": "ffffffff-ffff-ffff-ffff-ffffffffffff"}},
+			{"idempotencyKey": "key-conflict", "status": "conflict", "message": "sync event conflicts with the current server state"},
+		}})
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	client.SetSession("access-token", "refresh-token", "user-1")
+
+	baseVersion := 3
+	results, err := client.StoreSyncEvents(context.Background(), []SyncEvent{
+		{EntityType: "bookmark", EntityID: "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa", Operation: "upsert", Payload: map[string]any{"label": "intro"}, ClientTS: time.Now(), IdempotencyKey: "key-applied"},
+		{EntityType: "bookmark", EntityID: "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa", Operation: "delete", BaseVersion: &baseVersion, ClientTS: time.Now(), IdempotencyKey: "key-conflict"},
+	})
+	if err != nil {
+		t.Fatalf("store sync events: %v", err)
+	}
+
+	if gotPath != "/api/v1/sync/events:batch" || gotContentType != "application/json" {
+		t.Fatalf("unexpected request: path=%q content-type=%q", gotPath, gotContentType)
+	}
+	if len(gotBody.Events) != 2 || gotBody.Events[1]["baseVersion"] != float64(3) {
+		t.Fatalf("unexpected batch body: %+v", gotBody.Events)
+	}
+	if len(results) != 2 || results[0].Status != SyncEventApplied || results[1].Status != SyncEventConflict {
+		t.Fatalf("unexpected results: %+v", results)
+	}
+	if results[1].IdempotencyKey != "key-conflict" || results[1].Message == "" {
+		t.Fatalf("unexpected conflict result: %+v", results[1])
+	}
+}
+
+func TestListSharesSendsCursorAndMapsPage(t *testing.T) {
+	t.Parallel()
+
+	var gotQuery url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/shares" || r.Method != http.MethodGet {
+			http.NotFound(w, r)
+			return
+		}
+		gotQuery = r.URL.Query()
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"status": 200,
+			"success": true,
+			"message": "ok",
+			"page": 1,
+			"limit": 1,
+			"total": -1,
+			"totalPages": -1,
+			"nextCursor": "next-page",
+			"hasMore": true,
+			"data": [{
+				"id": "ffffffff-ffff-ffff-ffff-ffffffffffff",
+				"ebookId": "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa",
+				"ownerUserId": "bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb",
+				"status": "active",
+				"visibility": "public",
+				"titleOverride": "Shared Title"
+			}]
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	client.SetSession("access-token", "refresh-token", "user-1")
+
+	page, err := client.ListShares(context.Background(), "this-page", 1)
+	if err != nil {
+		t.Fatalf("list shares: %v", err)
+	}
+
+	if gotQuery.Get("cursor") != "this-page" || gotQuery.Get("limit") != "1" || gotQuery.Get("count") != "false" {
+		t.Fatalf("unexpected query: %v", gotQuery)
+	}
+	if !page.HasMore || page.NextCursor != "next-page" || len(page.Shares) != 1 {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+	share := page.Shares[0]
+	if share.ID != "ffffffff-ffff-ffff-ffff-ffffffffffff" || share.Title != "Shared Title" || share.Visibility != "public" {
+		t.Fatalf("unexpected share: %+v", share)
+	}
+}
+
+func TestListSyncEventsSendsCursorAndMapsPage(t *testing.T) {
+	t.Parallel()
+
+	var gotQuery url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/sync/events" || r.Method != http.MethodGet {
+			http.NotFound(w, r)
+			return
+		}
+		gotQuery = r.URL.Query()
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{
+			"status": 200,
+			"success": true,
+			"message": "ok",
+			"limit": 1,
+			"nextCursor": "next-page",
+			"hasMore": true,
+			"data": [{
+				"id": "ffffffff-ffff-ffff-ffff-ffffffffffff",
+				"userId": "bbbbbbbb-bbbb-bbbb-bbbb-bbbbbbbbbbbb",
+				"entityType": "bookmark",
+				"entityId": "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa",
+				"operation": "upsert",
+				"payload": {"label": "intro"},
+				"baseVersion": 2,
+				"clientTimestamp": "2026-01-02T03:04:05Z",
+				"serverTimestamp": "2026-01-02T03:04:06.5Z",
+				"idempotencyKey": "key-1"
+			}]
+		}`))
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	client.SetSession("access-token", "refresh-token", "user-1")
+
+	since := time.Date(2026, time.January, 1, 0, 0, 0, 0, time.UTC)
+	page, err := client.ListSyncEvents(context.Background(), &since, "this-page", 1)
+	if err != nil {
+		t.Fatalf("list sync events: %v", err)
+	}
+
+	if gotQuery.Get("cursor") != "this-page" || gotQuery.Get("limit") != "1" || gotQuery.Has("since") {
+		t.Fatalf("unexpected query: %v", gotQuery)
+	}
+	if !page.HasMore || page.NextCursor != "next-page" || len(page.Events) != 1 {
+		t.Fatalf("unexpected page: %+v", page)
+	}
+	event := page.Events[0]
+	if event.ID != "ffffffff-ffff-ffff-ffff-ffffffffffff" || event.BaseVersion == nil || *event.BaseVersion != 2 || event.Payload["label"] != "intro" {
+		t.Fatalf("unexpected event: %+v", event)
+	}
+	if !event.ServerTS.Equal(time.Date(2026, time.January, 2, 3, 4, 6, 500_000_000, time.UTC)) {
+		t.Fatalf("unexpected server timestamp: %v", event.ServerTS)
+	}
+}
+
+func TestStreamSyncEventsParsesServerSentEvents(t *testing.T) {
+	t.Parallel()
+
+	var gotAuth, gotAccept string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/v1/sync/events/stream" || r.Method != http.MethodGet {
+			http.NotFound(w, r)
+			return
+		}
+		gotAuth = r.Header.Get("Authorization")
+		gotAccept = r.Header.Get("Accept")
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		_, _ = io.WriteString(w, ": connected\n\n")
+		_, _ = io.WriteString(w, "id: ffffffff-ffff-ffff-ffff-ffffffffffff\nevent: sync\ndata: {\"id\":\"ffffffff-ffff-ffff-ffff-ffffffffffff\",\"entityType\":\"bookmark\",\"entityId\":\"aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa\",\"operation\":\"delete\",\"serverTimestamp\":\"2026-01-02T03:04:05Z\"}\n\n")
+		_, _ = io.WriteString(w, ": ping\n\n")
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	client.SetSession("access-token", "refresh-token", "user-1")
+
+	var got []SyncEvent
+	err = client.StreamSyncEvents(context.Background(), func(event SyncEvent) error {
+		got = append(got, event)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("stream sync events: %v", err)
+	}
+
+	if gotAuth != "Bearer access-token" || gotAccept != "text/event-stream" {
+		t.Fatalf("unexpected headers: authorization=%q accept=%q", gotAuth, gotAccept)
+	}
+	if len(got) != 1 || got[0].EntityID != "aaaaaaaa-aaaa-aaaa-aaaa-aaaaaaaaaaaa" || got[0].Operation != "delete" {
+		t.Fatalf("unexpected events: %+v", got)
+	}
+}
+
+func TestClientSendsDeviceHeaders(t *testing.T) {
+	t.Parallel()
+
+	var gotID, gotName string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotID = r.Header.Get("X-Device-ID")
+		gotName = r.Header.Get("X-Device-Name")
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"status":200,"success":true,"message":"ok","data":{"id":"11111111-1111-1111-1111-111111111111","email":"reader@example.com","username":"reader"}}`)
+	}))
+	defer server.Close()
+
+	client, err := NewClient(server.URL, time.Second)
+	if err != nil {
+		t.Fatalf("new client: %v", err)
+	}
+	client.SetDevice("device-1", "workstation")
+
+	if _, err := client.Login(context.Background(), "reader", "password123"); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	if gotID != "device-1" || gotName != "workstation" {
+		t.Fatalf("unexpected device headers: id=%q name=%q", gotID, gotName)
+	}
+}
+
+func signTestLease(t *testing.T, key ed25519.PrivateKey, claims map[string]any) string {
+	t.Helper()
+
+	header, _ := json.Marshal(map[string]string{"alg": "EdDSA", "typ": "JWT"})
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("marshal claims: %v", err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(key, []byte(signingInput)))
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+)
+
+var (
+	ErrBorrowLeaseInvalid = errors.New("borrow lease is invalid")
+	ErrBorrowLeaseExpired = errors.New("borrow lease has expired")
+)
+
+// BorrowLease is the verified content of a lease issued by IssueBorrowAccess.
+type BorrowLease struct {
+	BorrowID  string
+	ShareID   string
+	EbookID   string
+	UserID    string
+	ExpiresAt time.Time
+}
+
+// VerifyBorrowLease checks a lease's EdDSA signature against the server key and
+// its expiry against now, without contacting the server.
+func VerifyBorrowLease(lease string, key ed25519.PublicKey, now time.Time) (*BorrowLease, error) {
+	parts := strings.Split(lease, ".")
+	if len(parts) != 3 || len(key) != ed25519.PublicKeySize {
+		return nil, ErrBorrowLeaseInvalid
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeLeasePart(parts[0], &header); err != nil || header.Alg != "EdDSA" {
+		return nil, ErrBorrowLeaseInvalid
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !ed25519.Verify(key, []byte(parts[0]+"."+parts[1]), signature) {
+		return nil, ErrBorrowLeaseInvalid
+	}
+
+	var claims struct {
+		Subject   string `json:"sub"`
+		ExpiresAt int64  `json:"exp"`
+		BorrowID  string `json:"borrow_id"`
+		ShareID   string `json:"share_id"`
+		EbookID   string `json:"ebook_id"`
+	}
+	if err := decodeLeasePart(parts[1], &claims); err != nil || claims.ExpiresAt == 0 {
+		return nil, ErrBorrowLeaseInvalid
+	}
+
+	expiresAt := time.Unix(claims.ExpiresAt, 0).UTC()
+	if !now.Before(expiresAt) {
+		return nil, ErrBorrowLeaseExpired
+	}
+
+	return &BorrowLease{
+		BorrowID:  claims.BorrowID,
+		ShareID:   claims.ShareID,
+		EbookID:   claims.EbookID,
+		UserID:    claims.Subject,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+func decodeLeasePart(part string, out any) error {
+	raw, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, out)
+}
@@ -40,6 +40,26 @@ type Share struct {
 	Title       string
 }
 
+type SharePage struct {
+	Shares     []Share
+	NextCursor string
+	HasMore    bool
+}
+
+type Borrow struct {
+	ID      string
+	ShareID string
+	DueAt   time.Time
+	Status  string
+}
+
+type BorrowAccess struct {
+	BorrowID  string
+	EbookID   string
+	Lease     string
+	ExpiresAt time.Time
+}
+
 type Preferences struct {
 	UserID            string
 	ReadingMode       string
@@ -60,13 +80,37 @@ type ReaderState struct {
 }
 
 type SyncEvent struct {
+	ID             string
+	UserID         string
 	EntityType     string
 	EntityID       string
 	Operation      string
 	Payload        map[string]any
 	BaseVersion    *int
 	ClientTS       time.Time
+	ServerTS       time.Time
+	IdempotencyKey string
+}
+
+type SyncEventPage struct {
+	Events     []SyncEvent
+	NextCursor string
+	HasMore    bool
+}
+
+// Sync batch statuses reported per event by the server.
+const (
+	SyncEventApplied    = "applied"
+	SyncEventDuplicate  = "duplicate"
+	SyncEventSuperseded = "superseded"
+	SyncEventConflict   = "conflict"
+	SyncEventRejected   = "rejected"
+)
+
+type SyncEventResult struct {
 	IdempotencyKey string
+	Status         string
+	Message        string
 }
 
 type GoogleDeviceStart struct {
@@ -3,8 +3,10 @@ package app
 import (
 	"context"
 	"crypto/sha256"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -20,6 +22,35 @@ import (
 	"github.com/jeheskielSunloy77/libra-link/apps/tui/internal/storage/repo"
 )
 
+// openBookCmd opens the book in the reader. A borrowed book is only opened
+// while its lease is valid; an expired lease is renewed first.
+func (m *Model) openBookCmd(book repo.EbookCache) tea.Cmd {
+	if m.repo != nil {
+		lease, err := m.repo.GetBorrowLease(context.Background(), book.ID)
+		if err != nil {
+			m.errMsg = err.Error()
+			m.status = "Failed to open book"
+			return nil
+		}
+		if lease != nil {
+			if _, err := api.VerifyBorrowLease(lease.Lease, lease.PublicKey, time.Now()); err != nil {
+				if errors.Is(err, api.ErrBorrowLeaseExpired) {
+					return m.runBlocking("Renewing borrow lease...", m.renewBorrowLeaseCmd(lease.BorrowID, book))
+				}
+				m.errMsg = err.Error()
+				m.status = "Failed to open book"
+				return nil
+			}
+		}
+	}
+
+	if !m.openBook(book) {
+		return nil
+	}
+	m.screen = ScreenReader
+	return m.patchReaderStateCmd()
+}
+
 func (m *Model) openBook(book repo.EbookCache) bool {
 	if m.cfg == nil {
 		m.errMsg = "config is not available"
@@ -220,7 +251,28 @@ func (m *Model) createBookFromPrepared(prepared *addBookPrepared, allowDuplicate
 		_ = os.Remove(destPath)
 		return addBookResultMsg{err: err}
 	}
-	return addBookResultMsg{created: created}
+
+	// The library entry exists at this point, so a failed upload is reported but
+	// does not undo the import; the local copy stays readable on this device.
+	uploadErr := m.uploadBookFile(created.ID, destPath, timeout)
+	return addBookResultMsg{created: created, uploadErr: uploadErr}
+}
+
+func (m *Model) uploadBookFile(ebookID, path string, timeout time.Duration) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	return m.apiClient.UploadEbookFile(ctx, ebookID, file, info.Size())
 }
 
 func computeFileChecksum(path string) (string, int64, error) {
@@ -413,6 +465,14 @@ func (m *Model) loadLocalEbooksCmd(query string) tea.Cmd {
 	}
 }
 
+const (
+	// sharesPageSize is how many community shares are fetched per page.
+	sharesPageSize = 50
+	// sharesPrefetchRows is how close to the last loaded share the selection
+	// gets before the next page is fetched.
+	sharesPrefetchRows = 5
+)
+
 func (m *Model) fetchSharesCmd() tea.Cmd {
 	return func() tea.Msg {
 		if m.repo == nil {
@@ -426,7 +486,7 @@ func (m *Model) fetchSharesCmd() tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), m.cfg.HTTPTimeout)
 		defer cancel()
 
-		remote, err := m.apiClient.ListShares(ctx, 50)
+		page, err := m.apiClient.ListShares(ctx, "", sharesPageSize)
 		if err != nil {
 			local, localErr := m.repo.ListShares(context.Background())
 			if localErr != nil {
@@ -435,13 +495,46 @@ func (m *Model) fetchSharesCmd() tea.Cmd {
 			return sharesMsg{shares: local}
 		}
 
-		_ = m.repo.UpsertSharesFromRemote(context.Background(), remote)
-		local, localErr := m.repo.ListShares(context.Background())
-		if localErr != nil {
-			return sharesMsg{err: localErr}
+		_ = m.repo.UpsertSharesFromRemote(context.Background(), page.Shares)
+		return sharesMsg{shares: shareRows(page.Shares), nextCursor: page.NextCursor, hasMore: page.HasMore}
+	}
+}
+
+// fetchMoreSharesCmd loads the page of community shares after cursor, which
+// the community view appends as it is scrolled to the end.
+func (m *Model) fetchMoreSharesCmd(cursor string) tea.Cmd {
+	return func() tea.Msg {
+		if m.repo == nil || m.apiClient == nil || m.cfg == nil {
+			return sharesMsg{more: true, err: fmt.Errorf("api client is not available")}
 		}
-		return sharesMsg{shares: local}
+
+		ctx, cancel := context.WithTimeout(context.Background(), m.cfg.HTTPTimeout)
+		defer cancel()
+
+		page, err := m.apiClient.ListShares(ctx, cursor, sharesPageSize)
+		if err != nil {
+			return sharesMsg{more: true, err: err}
+		}
+
+		_ = m.repo.UpsertSharesFromRemote(context.Background(), page.Shares)
+		return sharesMsg{shares: shareRows(page.Shares), more: true, nextCursor: page.NextCursor, hasMore: page.HasMore}
+	}
+}
+
+// shareRows maps a page of remote shares to rows of the community view, in
+// the order the server listed them.
+func shareRows(shares []api.Share) []repo.ShareCache {
+	rows := make([]repo.ShareCache, 0, len(shares))
+	for _, share := range shares {
+		rows = append(rows, repo.ShareCache{
+			ID:      share.ID,
+			EbookID: share.EbookID,
+			OwnerID: share.OwnerUserID,
+			Status:  share.Status,
+			Title:   share.Title,
+		})
 	}
+	return rows
 }
 
 func (m *Model) fetchPrefsCmd() tea.Cmd {
@@ -623,6 +716,9 @@ func (m *Model) patchReaderStateCmd() tea.Cmd {
 	if len(m.ebooks) > 0 && m.ebookIndex < len(m.ebooks) {
 		state.CurrentEbookID = m.ebooks[m.ebookIndex].ID
 	}
+	if m.readerState != nil {
+		state.RowVersion = m.readerState.RowVersion
+	}
 
 	if m.repo != nil {
 		_ = m.repo.UpsertReaderState(context.Background(), repo.ReaderStateCache{
@@ -630,7 +726,7 @@ func (m *Model) patchReaderStateCmd() tea.Cmd {
 			CurrentEbookID:  state.CurrentEbookID,
 			CurrentLocation: state.CurrentLocation,
 			ReadingMode:     state.ReadingMode,
-			RowVersion:      1,
+			RowVersion:      int64(max(1, state.RowVersion)),
 			LastOpenedAt:    state.LastOpenedAt,
 			UpdatedAt:       time.Now().UTC(),
 		})
@@ -643,39 +739,166 @@ func (m *Model) patchReaderStateCmd() tea.Cmd {
 		ctx, cancel := context.WithTimeout(context.Background(), m.cfg.HTTPTimeout)
 		defer cancel()
 
-		_, err := m.apiClient.PatchReaderState(ctx, state)
+		patched, err := m.apiClient.PatchReaderState(ctx, state)
 		if err != nil {
 			if m.repo != nil {
 				_ = m.repo.EnqueueOutbox(context.Background(), repo.OutboxEvent{
-					EntityType: "reader_state",
-					EntityID:   state.UserID,
-					Operation:  "upsert",
-					Payload: map[string]any{
-						"currentEbookId":  state.CurrentEbookID,
-						"currentLocation": state.CurrentLocation,
-						"readingMode":     state.ReadingMode,
-						"lastOpenedAt":    state.LastOpenedAt,
-					},
+					EntityType:     "reader_state",
+					EntityID:       state.UserID,
+					Operation:      "upsert",
+					Payload:        readerStatePayload(state),
 					IdempotencyKey: uuid.NewString(),
 				})
 			}
 			return patchResultMsg{source: "reader_state", err: err}
 		}
-		return patchResultMsg{source: "reader_state"}
+		if m.repo != nil {
+			_ = m.repo.UpsertReaderState(context.Background(), repo.ReaderStateCache{
+				UserID:          patched.UserID,
+				CurrentEbookID:  patched.CurrentEbookID,
+				CurrentLocation: patched.CurrentLocation,
+				ReadingMode:     patched.ReadingMode,
+				RowVersion:      int64(patched.RowVersion),
+				LastOpenedAt:    patched.LastOpenedAt,
+				UpdatedAt:       time.Now().UTC(),
+			})
+		}
+		return patchResultMsg{source: "reader_state", state: patched}
 	}
 }
 
-func (m *Model) borrowShareCmd(shareID string) tea.Cmd {
+// readerStatePayload leaves out an unset ebook, which the server would reject as
+// an invalid id rather than treat as "no current ebook".
+func readerStatePayload(state api.ReaderState) map[string]any {
+	payload := map[string]any{
+		"currentLocation": state.CurrentLocation,
+		"readingMode":     state.ReadingMode,
+		"lastOpenedAt":    state.LastOpenedAt,
+	}
+	if state.CurrentEbookID != "" {
+		payload["currentEbookId"] = state.CurrentEbookID
+	}
+	return payload
+}
+
+func (m *Model) borrowShareCmd(share repo.ShareCache) tea.Cmd {
 	return func() tea.Msg {
 		if m.apiClient == nil || m.cfg == nil {
 			return patchResultMsg{source: "borrow", err: fmt.Errorf("api client is not available")}
 		}
 		ctx, cancel := context.WithTimeout(context.Background(), m.cfg.HTTPTimeout)
 		defer cancel()
-		if err := m.apiClient.BorrowShare(ctx, shareID); err != nil {
+		borrow, err := m.apiClient.BorrowShare(ctx, share.ID)
+		if err != nil {
 			return patchResultMsg{source: "borrow", err: err}
 		}
-		return patchResultMsg{source: "borrow"}
+		record, lease, err := m.issueBorrowLease(ctx, borrow.ID)
+		if err != nil {
+			return patchResultMsg{source: "borrow", err: err}
+		}
+		path, format, err := m.downloadBorrowedBook(ctx, borrow.ID, lease.EbookID)
+		if err != nil {
+			return patchResultMsg{source: "borrow", err: err}
+		}
+		if m.repo != nil {
+			book := repo.EbookCache{ID: lease.EbookID, Title: share.Title, Format: format, FilePath: path}
+			if err := m.repo.SaveBorrowedEbook(context.Background(), book, *record); err != nil {
+				return patchResultMsg{source: "borrow", err: err}
+			}
+		}
+		return patchResultMsg{source: "borrow", lease: lease}
+	}
+}
+
+// renewBorrowLeaseCmd replaces the expired lease of a borrowed book and opens
+// the book once the server has granted a new one.
+func (m *Model) renewBorrowLeaseCmd(borrowID string, book repo.EbookCache) tea.Cmd {
+	return func() tea.Msg {
+		if m.apiClient == nil || m.cfg == nil {
+			return patchResultMsg{source: "borrow_renew", err: fmt.Errorf("api client is not available")}
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), m.cfg.HTTPTimeout)
+		defer cancel()
+		record, lease, err := m.issueBorrowLease(ctx, borrowID)
+		if err != nil {
+			return patchResultMsg{source: "borrow_renew", err: err}
+		}
+		if m.repo != nil {
+			if err := m.repo.UpsertBorrowLease(context.Background(), *record); err != nil {
+				return patchResultMsg{source: "borrow_renew", err: err}
+			}
+		}
+		return patchResultMsg{source: "borrow_renew", lease: lease, reopen: &book}
+	}
+}
+
+// issueBorrowLease asks the server for a lease on the borrow and verifies it
+// against the server's lease key.
+func (m *Model) issueBorrowLease(ctx context.Context, borrowID string) (*repo.BorrowLease, *api.BorrowLease, error) {
+	access, err := m.apiClient.IssueBorrowAccess(ctx, borrowID)
+	if err != nil {
+		return nil, nil, err
+	}
+	key, err := m.apiClient.GetBorrowLeaseKey(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	lease, err := api.VerifyBorrowLease(access.Lease, key, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	return &repo.BorrowLease{
+		BorrowID:  lease.BorrowID,
+		EbookID:   lease.EbookID,
+		Lease:     access.Lease,
+		PublicKey: key,
+		ExpiresAt: lease.ExpiresAt,
+	}, lease, nil
+}
+
+// downloadBorrowedBook stores the borrowed ebook's file under the data
+// directory and returns its path and format.
+func (m *Model) downloadBorrowedBook(ctx context.Context, borrowID, ebookID string) (string, string, error) {
+	tmp, err := os.CreateTemp(m.cfg.DataDir, "borrow-*")
+	if err != nil {
+		return "", "", err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	contentType, err := m.apiClient.DownloadBorrowedFile(ctx, borrowID, tmp)
+	if err != nil {
+		return "", "", err
+	}
+	format := borrowedBookFormat(contentType)
+	if format == "" {
+		return "", "", fmt.Errorf("unsupported borrowed file type %q", contentType)
+	}
+	if err := tmp.Sync(); err != nil {
+		return "", "", err
+	}
+
+	path := filepath.Join(m.cfg.DataDir, "borrowed", ebookID+"."+format)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", "", err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", "", err
+	}
+	return path, format, nil
+}
+
+func borrowedBookFormat(contentType string) string {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	switch mediaType {
+	case "application/epub+zip":
+		return "epub"
+	case "application/pdf":
+		return "pdf"
+	case "text/plain":
+		return "txt"
+	default:
+		return ""
 	}
 }
 
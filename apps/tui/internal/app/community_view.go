@@ -23,6 +23,11 @@ func (m *Model) renderCommunity(styles viewStyles) string {
 			title := fallback(share.Title, share.ID)
 			rows = append(rows, rowStyle.Render(fmt.Sprintf("%s%s (%s)", prefix, title, share.Status)))
 		}
+		if m.sharesLoadingMore {
+			rows = append(rows, styles.subtle.Render("Loading more shares..."))
+		} else if m.sharesHasMore {
+			rows = append(rows, styles.subtle.Render("More shares load as you scroll."))
+		}
 	}
 
 	return styles.panel.Render(strings.Join(rows, "\n"))
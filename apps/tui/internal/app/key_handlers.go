@@ -312,6 +312,16 @@ func (m *Model) handleReaderKeys(msg tea.KeyMsg) tea.Cmd {
 	return nil
 }
 
+// loadMoreSharesCmd fetches the next page of community shares once the
+// selection nears the end of the loaded ones.
+func (m *Model) loadMoreSharesCmd() tea.Cmd {
+	if !m.sharesHasMore || m.sharesLoadingMore || m.shareIndex < len(m.shares)-sharesPrefetchRows {
+		return nil
+	}
+	m.sharesLoadingMore = true
+	return m.fetchMoreSharesCmd(m.sharesCursor)
+}
+
 func (m *Model) handleCommunityKeys(msg tea.KeyMsg) tea.Cmd {
 	key := msg.String()
 	focusedID := m.focusedID()
@@ -322,7 +332,7 @@ func (m *Model) handleCommunityKeys(msg tea.KeyMsg) tea.Cmd {
 			m.shareIndex++
 		}
 		m.focusByID("community.share." + strconv.Itoa(m.shareIndex))
-		return nil
+		return m.loadMoreSharesCmd()
 	case "up":
 		if len(m.shares) > 0 && m.shareIndex > 0 {
 			m.shareIndex--
@@ -443,11 +453,7 @@ func (m *Model) activateByID(id string) tea.Cmd {
 		if len(m.ebooks) == 0 || m.ebookIndex < 0 || m.ebookIndex >= len(m.ebooks) {
 			return nil
 		}
-		if !m.openBook(m.ebooks[m.ebookIndex]) {
-			return nil
-		}
-		m.screen = ScreenReader
-		return m.patchReaderStateCmd()
+		return m.openBookCmd(m.ebooks[m.ebookIndex])
 	case "library.search.submit":
 		m.searchQuery = strings.TrimSpace(m.searchInput.Value())
 		m.searchActive = false
@@ -486,7 +492,7 @@ func (m *Model) activateByID(id string) tea.Cmd {
 			return nil
 		}
 		selected := m.shares[m.shareIndex]
-		return m.runBlocking("Borrowing share...", m.borrowShareCmd(selected.ID))
+		return m.runBlocking("Borrowing share...", m.borrowShareCmd(selected))
 	case "settings.action.theme":
 		m.prefs.ThemeMode = nextThemeMode(m.prefs.ThemeMode)
 		return m.patchPrefsCmd()
@@ -521,11 +527,7 @@ func (m *Model) activateByID(id string) tea.Cmd {
 			return nil
 		}
 		m.ebookIndex = idx
-		if !m.openBook(m.ebooks[idx]) {
-			return nil
-		}
-		m.screen = ScreenReader
-		return m.patchReaderStateCmd()
+		return m.openBookCmd(m.ebooks[idx])
 	}
 
 	if strings.HasPrefix(id, "community.share.") {
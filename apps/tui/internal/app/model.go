@@ -69,6 +69,10 @@ type Model struct {
 
 	shares     []repo.ShareCache
 	shareIndex int
+	// sharesCursor continues the community listing while sharesHasMore is set.
+	sharesCursor      string
+	sharesHasMore     bool
+	sharesLoadingMore bool
 
 	document    *reader.Document
 	readerLine  int
@@ -160,6 +164,9 @@ func New(cfg *config.Config, apiClient *api.Client, store *repo.Repository, sess
 	syncCtx, cancel := context.WithCancel(context.Background())
 	if worker != nil && cfg != nil {
 		go worker.Run(syncCtx, cfg.SyncInterval)
+		if cfg.SyncStream {
+			go worker.Subscribe(syncCtx)
+		}
 	}
 
 	m := &Model{
@@ -407,13 +414,23 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.errMsg = ""
 		return m.finalize(nil)
 	case sharesMsg:
-		m.endLoading()
+		if typed.more {
+			m.sharesLoadingMore = false
+		} else {
+			m.endLoading()
+		}
 		if typed.err != nil {
 			m.errMsg = typed.err.Error()
 			m.status = "Failed to load community"
 			return m.finalize(nil)
 		}
-		m.shares = typed.shares
+		if typed.more {
+			m.shares = append(m.shares, typed.shares...)
+		} else {
+			m.shares = typed.shares
+		}
+		m.sharesCursor = typed.nextCursor
+		m.sharesHasMore = typed.hasMore
 		if m.shareIndex >= len(m.shares) {
 			m.shareIndex = len(m.shares) - 1
 		}
@@ -447,12 +464,14 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m.finalize(nil)
 	case patchResultMsg:
 		if typed.err != nil {
-			if typed.source == "borrow" {
+			if typed.source == "borrow" || typed.source == "borrow_renew" {
 				m.endLoading()
 			}
 			m.errMsg = typed.err.Error()
 			if typed.source == "borrow" {
 				m.status = "Borrow failed"
+			} else if typed.source == "borrow_renew" {
+				m.status = "Borrow lease could not be renewed"
 			} else if typed.source == "prefs" {
 				m.status = "Preference update queued for sync"
 			}
@@ -461,10 +480,27 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if typed.source == "borrow" {
 			m.endLoading()
 			m.status = "Borrow request completed"
+			if typed.lease != nil {
+				m.status = "Borrowed, lease valid until " + typed.lease.ExpiresAt.Local().Format("2006-01-02 15:04")
+			}
+			m.errMsg = ""
+			return m.finalize(m.loadLocalEbooksCmd(m.searchQuery))
+		}
+		if typed.source == "borrow_renew" {
+			m.endLoading()
+			m.errMsg = ""
+			if typed.reopen != nil && m.openBook(*typed.reopen) {
+				m.screen = ScreenReader
+				return m.finalize(m.patchReaderStateCmd())
+			}
+			return m.finalize(nil)
 		}
 		if typed.prefs != nil {
 			m.prefs = *typed.prefs
 		}
+		if typed.state != nil {
+			m.readerState = typed.state
+		}
 		if typed.source == "prefs" {
 			m.status = "Updated"
 		}
@@ -491,6 +527,10 @@ func (m *Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.clearAddMode()
 			m.status = "Book added: " + title
 			m.errMsg = ""
+			if typed.uploadErr != nil {
+				m.status = "Book added locally: " + title
+				m.errMsg = "file upload failed: " + typed.uploadErr.Error()
+			}
 			return m.finalize(m.runBlocking("Loading library...", m.fetchEbooksCmd()))
 		}
 		return m.finalize(nil)
@@ -1,6 +1,10 @@
 package app
 
 import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
 	"os"
 	"path/filepath"
 	"testing"
@@ -11,6 +15,7 @@ import (
 	"github.com/jeheskielSunloy77/libra-link/apps/tui/internal/api"
 	"github.com/jeheskielSunloy77/libra-link/apps/tui/internal/config"
 	"github.com/jeheskielSunloy77/libra-link/apps/tui/internal/storage/repo"
+	"github.com/jeheskielSunloy77/libra-link/apps/tui/internal/storage/sqlite"
 )
 
 func TestGlobalCtrlPTogglesPalette(t *testing.T) {
@@ -121,6 +126,34 @@ func TestLibraryRefreshSetsLoading(t *testing.T) {
 	}
 }
 
+func TestCommunityScrollLoadsNextSharesPage(t *testing.T) {
+	m := newModelForTest()
+	m.loggedIn = true
+	m.screen = ScreenCommunity
+	m.shares = []repo.ShareCache{{ID: "share-1"}, {ID: "share-2"}}
+	m.sharesCursor = "next-page"
+	m.sharesHasMore = true
+	m.rebuildFocus()
+
+	updated, cmd := m.Update(tea.KeyMsg{Type: tea.KeyDown})
+	got := updated.(*Model)
+	if cmd == nil || !got.sharesLoadingMore {
+		t.Fatal("expected the next page to be requested")
+	}
+	if got.loading.Active {
+		t.Fatal("expected loading more shares not to block the view")
+	}
+
+	updated, _ = got.Update(sharesMsg{shares: []repo.ShareCache{{ID: "share-3"}}, more: true})
+	got = updated.(*Model)
+	if len(got.shares) != 3 || got.shares[2].ID != "share-3" {
+		t.Fatalf("expected the page to be appended, got %+v", got.shares)
+	}
+	if got.sharesLoadingMore || got.sharesHasMore {
+		t.Fatal("expected the listing to be exhausted")
+	}
+}
+
 func TestCommandPaletteExecutesNavigation(t *testing.T) {
 	m := newModelForTest()
 	m.loggedIn = true
@@ -346,6 +379,71 @@ func TestOpenBookDifferentEbookStartsAtTop(t *testing.T) {
 	}
 }
 
+func TestOpenBookCmdChecksBorrowLease(t *testing.T) {
+	m := newModelForTest()
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "libra.db"))
+	if err != nil {
+		t.Fatalf("open db: %v", err)
+	}
+	defer db.Close()
+	m.repo = repo.New(db)
+
+	source := filepath.Join(t.TempDir(), "borrowed.txt")
+	if err := os.WriteFile(source, []byte("one\ntwo\n"), 0o644); err != nil {
+		t.Fatalf("write fixture: %v", err)
+	}
+	publicKey, privateKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("generate key: %v", err)
+	}
+	book := repo.EbookCache{ID: "book-1", Title: "Borrowed", FilePath: source, Format: "txt"}
+	saveLease := func(expiresAt time.Time) {
+		lease := signLeaseForTest(t, privateKey, map[string]any{
+			"sub":       "user-1",
+			"exp":       expiresAt.Unix(),
+			"borrow_id": "borrow-1",
+			"ebook_id":  book.ID,
+		})
+		if err := m.repo.SaveBorrowedEbook(context.Background(), book, repo.BorrowLease{
+			BorrowID:  "borrow-1",
+			EbookID:   book.ID,
+			Lease:     lease,
+			PublicKey: publicKey,
+			ExpiresAt: expiresAt,
+		}); err != nil {
+			t.Fatalf("save borrowed ebook: %v", err)
+		}
+	}
+
+	saveLease(time.Now().Add(-time.Minute))
+	if cmd := m.openBookCmd(book); cmd == nil {
+		t.Fatal("expected an expired lease to be renewed")
+	}
+	if m.document != nil || m.screen == ScreenReader {
+		t.Fatal("expected borrowed book with expired lease to stay closed")
+	}
+
+	saveLease(time.Now().Add(time.Hour))
+	m.openBookCmd(book)
+	if m.document == nil || m.screen != ScreenReader {
+		t.Fatalf("expected borrowed book with valid lease to open: %s", m.errMsg)
+	}
+}
+
+func signLeaseForTest(t *testing.T, key ed25519.PrivateKey, claims map[string]any) string {
+	t.Helper()
+	header, err := json.Marshal(map[string]string{"alg": "EdDSA", "typ": "JWT"})
+	if err != nil {
+		t.Fatalf("marshal header: %v", err)
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		t.Fatalf("marshal claims: %v", err)
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(ed25519.Sign(key, []byte(signingInput)))
+}
+
 func newModelForTest() *Model {
 	cfg := &config.Config{
 		HTTPTimeout:  time.Second,
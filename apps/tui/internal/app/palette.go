@@ -302,11 +302,7 @@ func (m *Model) executePaletteCommand(id string) tea.Cmd {
 				return nil
 			}
 			m.ebookIndex = idx
-			if !m.openBook(m.ebooks[idx]) {
-				return nil
-			}
-			m.screen = ScreenReader
-			return m.patchReaderStateCmd()
+			return m.openBookCmd(m.ebooks[idx])
 		}
 		return nil
 	}
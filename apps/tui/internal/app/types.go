@@ -101,7 +101,11 @@ type ebooksMsg struct {
 
 type sharesMsg struct {
 	shares []repo.ShareCache
-	err    error
+	// more marks a page that continues the listing rather than replacing it.
+	more       bool
+	nextCursor string
+	hasMore    bool
+	err        error
 }
 
 type prefsMsg struct {
@@ -118,6 +122,10 @@ type patchResultMsg struct {
 	source string
 	err    error
 	prefs  *api.Preferences
+	state  *api.ReaderState
+	lease  *api.BorrowLease
+	// reopen is the borrowed book to open once its lease has been renewed.
+	reopen *repo.EbookCache
 }
 
 type googleStartMsg struct {
@@ -161,6 +169,7 @@ type addBookPrepared struct {
 type addBookResultMsg struct {
 	created   *api.Ebook
 	err       error
+	uploadErr error
 	duplicate bool
 	prepared  *addBookPrepared
 }
@@ -5,7 +5,10 @@ import (
 	"os"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
+
+	"github.com/google/uuid"
 )
 
 const (
@@ -23,6 +26,9 @@ type Config struct {
 	HTTPTimeout   time.Duration
 	SyncInterval  time.Duration
 	SyncBatchSize int
+	SyncStream    bool
+	DeviceID      string
+	DeviceName    string
 }
 
 func Load() (*Config, error) {
@@ -42,6 +48,16 @@ func Load() (*Config, error) {
 	if syncBatchSize < 1 {
 		syncBatchSize = 1
 	}
+	syncStream := boolFromEnv("LIBRA_TUI_SYNC_STREAM", false)
+
+	deviceID, err := loadDeviceID(filepath.Join(dataDir, "device_id"))
+	if err != nil {
+		return nil, err
+	}
+	deviceName := os.Getenv("LIBRA_TUI_DEVICE_NAME")
+	if deviceName == "" {
+		deviceName, _ = os.Hostname()
+	}
 
 	cfg := &Config{
 		APIBaseURL:    apiBaseURL,
@@ -51,6 +67,9 @@ func Load() (*Config, error) {
 		HTTPTimeout:   httpTimeout,
 		SyncInterval:  syncInterval,
 		SyncBatchSize: syncBatchSize,
+		SyncStream:    syncStream,
+		DeviceID:      deviceID,
+		DeviceName:    deviceName,
 	}
 	return cfg, nil
 }
@@ -71,6 +90,25 @@ func resolveDataDir() (string, error) {
 	return filepath.Join(home, ".local", "share", defaultAppDataName), nil
 }
 
+// loadDeviceID reads the id this installation registers with the API, creating
+// it on first run so it stays stable across sessions.
+func loadDeviceID(path string) (string, error) {
+	content, err := os.ReadFile(path)
+	if err == nil {
+		if id := strings.TrimSpace(string(content)); id != "" {
+			return id, nil
+		}
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return "", err
+	}
+
+	id := uuid.NewString()
+	if err := os.WriteFile(path, []byte(id+"\n"), 0o600); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
 func envOrDefault(key, fallback string) string {
 	if raw := os.Getenv(key); raw != "" {
 		return raw
@@ -102,3 +140,15 @@ func intFromEnv(key string, fallback int) int {
 	}
 	return value
 }
+
+func boolFromEnv(key string, fallback bool) bool {
+	raw := os.Getenv(key)
+	if raw == "" {
+		return fallback
+	}
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
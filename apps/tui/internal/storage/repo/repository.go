@@ -2,7 +2,9 @@ package repo
 
 import (
 	"context"
+	"crypto/ed25519"
 	"database/sql"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"time"
@@ -13,7 +15,8 @@ import (
 )
 
 type Repository struct {
-	q *sqlcdb.Queries
+	db *sql.DB
+	q  *sqlcdb.Queries
 }
 
 type SessionState struct {
@@ -70,6 +73,17 @@ type ShareCache struct {
 	UpdatedAt   time.Time
 }
 
+// BorrowLease is the lease that grants reading a borrowed ebook, kept with the
+// key it was verified with so it can be checked again offline.
+type BorrowLease struct {
+	BorrowID  string
+	EbookID   string
+	Lease     string
+	PublicKey ed25519.PublicKey
+	ExpiresAt time.Time
+	UpdatedAt time.Time
+}
+
 type OutboxEvent struct {
 	ID             string
 	EntityType     string
@@ -87,11 +101,13 @@ type OutboxEvent struct {
 type SyncCheckpoint struct {
 	LastServerTimestamp *time.Time
 	LastEventID         string
-	UpdatedAt           time.Time
+	// Cursor is the server's cursor after LastEventID; pulls resume from it.
+	Cursor    string
+	UpdatedAt time.Time
 }
 
-func New(q *sqlcdb.Queries) *Repository {
-	return &Repository{q: q}
+func New(db *sql.DB) *Repository {
+	return &Repository{db: db, q: sqlcdb.New(db)}
 }
 
 func (r *Repository) GetUISettings(ctx context.Context) (*UISettings, error) {
@@ -368,6 +384,69 @@ func (r *Repository) ListShares(ctx context.Context) ([]ShareCache, error) {
 	return result, nil
 }
 
+// SaveBorrowedEbook adds a downloaded borrowed ebook to the library together
+// with the lease that grants reading it.
+func (r *Repository) SaveBorrowedEbook(ctx context.Context, ebook EbookCache, lease BorrowLease) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	txRepo := &Repository{db: r.db, q: r.q.WithTx(tx)}
+	if err := txRepo.q.UpsertEbookCache(ctx, sqlcdb.UpsertEbookCacheParams{
+		ID:         ebook.ID,
+		Title:      ebook.Title,
+		Author:     nullString(ebook.Author),
+		Format:     nullString(ebook.Format),
+		FilePath:   nullString(ebook.FilePath),
+		RowVersion: 1,
+		DeletedAt:  sql.NullString{},
+		UpdatedAt:  time.Now().UTC().Format(time.RFC3339Nano),
+	}); err != nil {
+		return err
+	}
+	if err := txRepo.UpsertBorrowLease(ctx, lease); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) UpsertBorrowLease(ctx context.Context, lease BorrowLease) error {
+	return r.q.UpsertBorrowLease(ctx, sqlcdb.UpsertBorrowLeaseParams{
+		BorrowID:  lease.BorrowID,
+		EbookID:   lease.EbookID,
+		Lease:     lease.Lease,
+		PublicKey: base64.StdEncoding.EncodeToString(lease.PublicKey),
+		ExpiresAt: lease.ExpiresAt.UTC().Format(time.RFC3339Nano),
+		UpdatedAt: time.Now().UTC().Format(time.RFC3339Nano),
+	})
+}
+
+// GetBorrowLease returns the latest lease held for a borrowed ebook, or nil when
+// the ebook is not borrowed.
+func (r *Repository) GetBorrowLease(ctx context.Context, ebookID string) (*BorrowLease, error) {
+	row, err := r.q.GetBorrowLeaseByEbookID(ctx, ebookID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	publicKey, _ := base64.StdEncoding.DecodeString(row.PublicKey)
+	expiresAt, _ := time.Parse(time.RFC3339Nano, row.ExpiresAt)
+	updatedAt, _ := time.Parse(time.RFC3339Nano, row.UpdatedAt)
+	return &BorrowLease{
+		BorrowID:  row.BorrowID,
+		EbookID:   row.EbookID,
+		Lease:     row.Lease,
+		PublicKey: ed25519.PublicKey(publicKey),
+		ExpiresAt: expiresAt,
+		UpdatedAt: updatedAt,
+	}, nil
+}
+
 func (r *Repository) EnqueueOutbox(ctx context.Context, event OutboxEvent) error {
 	now := time.Now().UTC()
 	if event.ID == "" {
@@ -458,6 +537,16 @@ func (r *Repository) MarkOutboxDone(ctx context.Context, eventID string) error {
 	})
 }
 
+// MarkOutboxFailed parks an event the server will never accept, such as a
+// conflict or a rejected payload, so the worker stops retrying it.
+func (r *Repository) MarkOutboxFailed(ctx context.Context, eventID string, lastErr string) error {
+	return r.q.MarkSyncOutboxEventFailed(ctx, sqlcdb.MarkSyncOutboxEventFailedParams{
+		UpdatedAt: time.Now().UTC().Format(time.RFC3339Nano),
+		LastError: nullString(lastErr),
+		ID:        eventID,
+	})
+}
+
 func (r *Repository) MarkOutboxRetry(ctx context.Context, eventID string, nextAttempt time.Time, lastErr string) error {
 	if nextAttempt.IsZero() {
 		nextAttempt = time.Now().UTC().Add(10 * time.Second)
@@ -484,6 +573,9 @@ func (r *Repository) GetSyncCheckpoint(ctx context.Context) (*SyncCheckpoint, er
 	if row.LastEventID.Valid {
 		item.LastEventID = row.LastEventID.String
 	}
+	if row.LastCursor.Valid {
+		item.Cursor = row.LastCursor.String
+	}
 	if row.LastServerTimestamp.Valid {
 		if ts, err := time.Parse(time.RFC3339Nano, row.LastServerTimestamp.String); err == nil {
 			item.LastServerTimestamp = &ts
@@ -496,6 +588,7 @@ func (r *Repository) UpsertSyncCheckpoint(ctx context.Context, checkpoint SyncCh
 	params := sqlcdb.UpsertSyncCheckpointParams{
 		LastServerTimestamp: sql.NullString{},
 		LastEventID:         nullString(checkpoint.LastEventID),
+		LastCursor:          nullString(checkpoint.Cursor),
 		UpdatedAt:           time.Now().UTC().Format(time.RFC3339Nano),
 	}
 	if checkpoint.LastServerTimestamp != nil {
@@ -0,0 +1,300 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"github.com/jeheskielSunloy77/libra-link/apps/tui/internal/api"
+	"github.com/jeheskielSunloy77/libra-link/apps/tui/internal/storage/sqlite/sqlcdb"
+)
+
+// ApplySyncEvents writes events pulled from the server into the local caches and
+// advances the sync checkpoint to the last of them, and to the cursor after
+// them, in the same transaction, so a crash mid-page never skips or
+// half-applies events.
+func (r *Repository) ApplySyncEvents(ctx context.Context, events []api.SyncEvent, cursor string) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	txRepo := &Repository{db: r.db, q: r.q.WithTx(tx)}
+	for _, event := range events {
+		if err := txRepo.applySyncEvent(ctx, event); err != nil {
+			return err
+		}
+	}
+
+	last := events[len(events)-1]
+	if err := txRepo.UpsertSyncCheckpoint(ctx, SyncCheckpoint{
+		LastServerTimestamp: &last.ServerTS,
+		LastEventID:         last.ID,
+		Cursor:              cursor,
+	}); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ApplyLiveSyncEvent writes one event received from the live stream into the
+// local caches. The checkpoint is left alone: events stored before the stream
+// connected may not have been pulled yet, and the next pull replays this one
+// harmlessly.
+func (r *Repository) ApplyLiveSyncEvent(ctx context.Context, event api.SyncEvent) error {
+	return r.applySyncEvent(ctx, event)
+}
+
+func (r *Repository) applySyncEvent(ctx context.Context, event api.SyncEvent) error {
+	switch event.EntityType {
+	case "progress":
+		return r.applyProgressEvent(ctx, event)
+	case "bookmark":
+		return r.applyBookmarkEvent(ctx, event)
+	case "annotation":
+		return r.applyAnnotationEvent(ctx, event)
+	case "preference":
+		return r.applyPreferenceEvent(ctx, event)
+	case "reader_state":
+		return r.applyReaderStateEvent(ctx, event)
+	default:
+		// Entity types this client does not cache yet.
+		return nil
+	}
+}
+
+func (r *Repository) applyProgressEvent(ctx context.Context, event api.SyncEvent) error {
+	updatedAt := event.ServerTS.UTC().Format(time.RFC3339Nano)
+	if event.Operation == "delete" {
+		return r.q.SoftDeleteReadingProgressCache(ctx, sqlcdb.SoftDeleteReadingProgressCacheParams{
+			DeletedAt: nullString(updatedAt),
+			UpdatedAt: updatedAt,
+			ID:        event.EntityID,
+		})
+	}
+
+	row, err := r.q.GetReadingProgressCache(ctx, event.EntityID)
+	if errors.Is(err, sql.ErrNoRows) {
+		row = &sqlcdb.ReadingProgressCache{ID: event.EntityID, UserID: event.UserID, ReadingMode: "normal"}
+	} else if err != nil {
+		return err
+	}
+
+	if value, ok := payloadString(event.Payload, "ebookId"); ok {
+		row.EbookID = value
+	}
+	if value, ok := payloadString(event.Payload, "location"); ok {
+		row.Location = value
+	}
+	if value, ok := payloadFloat(event.Payload, "progressPercent"); ok {
+		row.ProgressPercent = sql.NullFloat64{Float64: value, Valid: true}
+	}
+	if value, ok := payloadString(event.Payload, "readingMode"); ok {
+		row.ReadingMode = value
+	}
+	if row.EbookID == "" || row.Location == "" {
+		return nil
+	}
+
+	return r.q.UpsertReadingProgressCache(ctx, sqlcdb.UpsertReadingProgressCacheParams{
+		ID:              row.ID,
+		UserID:          row.UserID,
+		EbookID:         row.EbookID,
+		Location:        row.Location,
+		ProgressPercent: row.ProgressPercent,
+		ReadingMode:     row.ReadingMode,
+		RowVersion:      syncRowVersion(event),
+		UpdatedAt:       updatedAt,
+	})
+}
+
+func (r *Repository) applyBookmarkEvent(ctx context.Context, event api.SyncEvent) error {
+	updatedAt := event.ServerTS.UTC().Format(time.RFC3339Nano)
+	if event.Operation == "delete" {
+		return r.q.SoftDeleteBookmarkCache(ctx, sqlcdb.SoftDeleteBookmarkCacheParams{
+			DeletedAt: nullString(updatedAt),
+			UpdatedAt: updatedAt,
+			ID:        event.EntityID,
+		})
+	}
+
+	row, err := r.q.GetBookmarkCache(ctx, event.EntityID)
+	if errors.Is(err, sql.ErrNoRows) {
+		row = &sqlcdb.BookmarksCache{ID: event.EntityID, UserID: event.UserID}
+	} else if err != nil {
+		return err
+	}
+
+	if value, ok := payloadString(event.Payload, "ebookId"); ok {
+		row.EbookID = value
+	}
+	if value, ok := payloadString(event.Payload, "location"); ok {
+		row.Location = value
+	}
+	if value, ok := payloadString(event.Payload, "label"); ok {
+		row.Label = nullString(value)
+	}
+	if row.EbookID == "" || row.Location == "" {
+		return nil
+	}
+
+	return r.q.UpsertBookmarkCache(ctx, sqlcdb.UpsertBookmarkCacheParams{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		EbookID:    row.EbookID,
+		Location:   row.Location,
+		Label:      row.Label,
+		RowVersion: syncRowVersion(event),
+		UpdatedAt:  updatedAt,
+	})
+}
+
+func (r *Repository) applyAnnotationEvent(ctx context.Context, event api.SyncEvent) error {
+	updatedAt := event.ServerTS.UTC().Format(time.RFC3339Nano)
+	if event.Operation == "delete" {
+		return r.q.SoftDeleteAnnotationCache(ctx, sqlcdb.SoftDeleteAnnotationCacheParams{
+			DeletedAt: nullString(updatedAt),
+			UpdatedAt: updatedAt,
+			ID:        event.EntityID,
+		})
+	}
+
+	row, err := r.q.GetAnnotationCache(ctx, event.EntityID)
+	if errors.Is(err, sql.ErrNoRows) {
+		row = &sqlcdb.AnnotationsCache{ID: event.EntityID, UserID: event.UserID}
+	} else if err != nil {
+		return err
+	}
+
+	if value, ok := payloadString(event.Payload, "ebookId"); ok {
+		row.EbookID = value
+	}
+	if value, ok := payloadString(event.Payload, "locationStart"); ok {
+		row.LocationStart = value
+	}
+	if value, ok := payloadString(event.Payload, "locationEnd"); ok {
+		row.LocationEnd = value
+	}
+	if value, ok := payloadString(event.Payload, "highlightText"); ok {
+		row.HighlightText = nullString(value)
+	}
+	if value, ok := payloadString(event.Payload, "note"); ok {
+		row.Note = nullString(value)
+	}
+	if value, ok := payloadString(event.Payload, "color"); ok {
+		row.Color = nullString(value)
+	}
+	if row.EbookID == "" || row.LocationStart == "" || row.LocationEnd == "" {
+		return nil
+	}
+
+	return r.q.UpsertAnnotationCache(ctx, sqlcdb.UpsertAnnotationCacheParams{
+		ID:            row.ID,
+		UserID:        row.UserID,
+		EbookID:       row.EbookID,
+		LocationStart: row.LocationStart,
+		LocationEnd:   row.LocationEnd,
+		HighlightText: row.HighlightText,
+		Note:          row.Note,
+		Color:         row.Color,
+		RowVersion:    syncRowVersion(event),
+		UpdatedAt:     updatedAt,
+	})
+}
+
+func (r *Repository) applyPreferenceEvent(ctx context.Context, event api.SyncEvent) error {
+	prefs, err := r.GetPreferences(ctx, event.UserID)
+	if err != nil {
+		return err
+	}
+	if prefs == nil {
+		prefs = &PreferencesCache{
+			UserID:            event.UserID,
+			ReadingMode:       "normal",
+			ZenRestoreOnOpen:  true,
+			ThemeMode:         "dark",
+			ThemeOverrides:    map[string]string{},
+			TypographyProfile: "comfortable",
+		}
+	}
+
+	if value, ok := payloadString(event.Payload, "readingMode"); ok {
+		prefs.ReadingMode = value
+	}
+	if value, ok := event.Payload["zenRestoreOnOpen"].(bool); ok {
+		prefs.ZenRestoreOnOpen = value
+	}
+	if value, ok := payloadString(event.Payload, "themeMode"); ok {
+		prefs.ThemeMode = value
+	}
+	if value, ok := event.Payload["themeOverrides"].(map[string]any); ok {
+		prefs.ThemeOverrides = make(map[string]string, len(value))
+		for key, raw := range value {
+			if text, ok := raw.(string); ok {
+				prefs.ThemeOverrides[key] = text
+			}
+		}
+	}
+	if value, ok := payloadString(event.Payload, "typographyProfile"); ok {
+		prefs.TypographyProfile = value
+	}
+	// Events do not carry the server's row version, so the cached one, taken
+	// from the last preferences response, is kept rather than guessed.
+	prefs.UpdatedAt = event.ServerTS.UTC()
+
+	return r.UpsertPreferences(ctx, *prefs)
+}
+
+func (r *Repository) applyReaderStateEvent(ctx context.Context, event api.SyncEvent) error {
+	state, err := r.GetReaderState(ctx, event.UserID)
+	if err != nil {
+		return err
+	}
+	if state == nil {
+		state = &ReaderStateCache{UserID: event.UserID, ReadingMode: "normal"}
+	}
+
+	if value, ok := payloadString(event.Payload, "currentEbookId"); ok {
+		state.CurrentEbookID = value
+	}
+	if value, ok := payloadString(event.Payload, "currentLocation"); ok {
+		state.CurrentLocation = value
+	}
+	if value, ok := payloadString(event.Payload, "readingMode"); ok {
+		state.ReadingMode = value
+	}
+	if value, ok := payloadString(event.Payload, "lastOpenedAt"); ok {
+		if ts, err := time.Parse(time.RFC3339Nano, value); err == nil {
+			state.LastOpenedAt = &ts
+		}
+	}
+	// The row version stays as last reported by the server, as for preferences.
+	state.UpdatedAt = event.ServerTS.UTC()
+
+	return r.UpsertReaderState(ctx, *state)
+}
+
+// syncRowVersion mirrors the server, which bumps RowVersion by one per applied
+// event and starts new entities at one.
+func syncRowVersion(event api.SyncEvent) int64 {
+	if event.BaseVersion == nil {
+		return 1
+	}
+	return int64(*event.BaseVersion) + 1
+}
+
+func payloadString(payload map[string]any, key string) (string, bool) {
+	value, ok := payload[key].(string)
+	return value, ok
+}
+
+func payloadFloat(payload map[string]any, key string) (float64, bool) {
+	value, ok := payload[key].(float64)
+	return value, ok
+}
@@ -46,8 +46,19 @@ func Migrate(db *sql.DB) error {
 			continue
 		}
 		if _, err := db.Exec(s); err != nil {
+			if isAddColumn(s) && strings.Contains(err.Error(), "duplicate column name") {
+				continue
+			}
 			return fmt.Errorf("sqlite migration failed: %w", err)
 		}
 	}
 	return nil
 }
+
+// isAddColumn reports whether stmt adds a column to an existing table. SQLite
+// has no ADD COLUMN IF NOT EXISTS, so those statements fail on every open after
+// the first and the failure is ignored.
+func isAddColumn(stmt string) bool {
+	upper := strings.ToUpper(stmt)
+	return strings.Contains(upper, "ALTER TABLE") && strings.Contains(upper, "ADD COLUMN")
+}
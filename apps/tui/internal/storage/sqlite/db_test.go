@@ -0,0 +1,17 @@
+package sqlite
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestOpenMigratesExistingDatabase(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tui.db")
+	for i := 0; i < 2; i++ {
+		db, err := Open(path)
+		if err != nil {
+			t.Fatalf("open #%d: %v", i+1, err)
+		}
+		_ = db.Close()
+	}
+}
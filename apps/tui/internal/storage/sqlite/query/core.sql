@@ -117,20 +117,23 @@ INSERT INTO sync_checkpoint (
   id,
   last_server_timestamp,
   last_event_id,
+  last_cursor,
   updated_at
 ) VALUES (
   1,
   sqlc.narg(last_server_timestamp),
   sqlc.narg(last_event_id),
+  sqlc.narg(last_cursor),
   sqlc.arg(updated_at)
 )
 ON CONFLICT(id) DO UPDATE SET
   last_server_timestamp = excluded.last_server_timestamp,
   last_event_id = excluded.last_event_id,
+  last_cursor = excluded.last_cursor,
   updated_at = excluded.updated_at;
 
 -- name: GetSyncCheckpoint :one
-SELECT last_server_timestamp, last_event_id, updated_at
+SELECT last_server_timestamp, last_event_id, last_cursor, updated_at
 FROM sync_checkpoint
 WHERE id = 1;
 
@@ -193,6 +196,13 @@ SET status = 'done',
   last_error = NULL
 WHERE id = sqlc.arg(id);
 
+-- name: MarkSyncOutboxEventFailed :exec
+UPDATE sync_events_outbox
+SET status = 'failed',
+  updated_at = sqlc.arg(updated_at),
+  last_error = sqlc.arg(last_error)
+WHERE id = sqlc.arg(id);
+
 -- name: MarkSyncOutboxEventRetry :exec
 UPDATE sync_events_outbox
 SET attempt_count = attempt_count + 1,
@@ -283,3 +293,168 @@ SELECT id, ebook_id, owner_id, status, title, borrow_until, row_version, deleted
 FROM shares_cache
 WHERE deleted_at IS NULL
 ORDER BY updated_at DESC;
+
+-- name: GetReadingProgressCache :one
+SELECT id, user_id, ebook_id, location, progress_percent, reading_mode, row_version, deleted_at, updated_at
+FROM reading_progress_cache
+WHERE id = sqlc.arg(id)
+LIMIT 1;
+
+-- name: UpsertReadingProgressCache :exec
+INSERT INTO reading_progress_cache (
+  id,
+  user_id,
+  ebook_id,
+  location,
+  progress_percent,
+  reading_mode,
+  row_version,
+  deleted_at,
+  updated_at
+) VALUES (
+  sqlc.arg(id),
+  sqlc.arg(user_id),
+  sqlc.arg(ebook_id),
+  sqlc.arg(location),
+  sqlc.narg(progress_percent),
+  sqlc.arg(reading_mode),
+  sqlc.arg(row_version),
+  sqlc.narg(deleted_at),
+  sqlc.arg(updated_at)
+)
+ON CONFLICT(id) DO UPDATE SET
+  user_id = excluded.user_id,
+  ebook_id = excluded.ebook_id,
+  location = excluded.location,
+  progress_percent = excluded.progress_percent,
+  reading_mode = excluded.reading_mode,
+  row_version = excluded.row_version,
+  deleted_at = excluded.deleted_at,
+  updated_at = excluded.updated_at;
+
+-- name: SoftDeleteReadingProgressCache :exec
+UPDATE reading_progress_cache
+SET deleted_at = sqlc.arg(deleted_at),
+  updated_at = sqlc.arg(updated_at)
+WHERE id = sqlc.arg(id);
+
+-- name: GetBookmarkCache :one
+SELECT id, user_id, ebook_id, location, label, row_version, deleted_at, updated_at
+FROM bookmarks_cache
+WHERE id = sqlc.arg(id)
+LIMIT 1;
+
+-- name: UpsertBookmarkCache :exec
+INSERT INTO bookmarks_cache (
+  id,
+  user_id,
+  ebook_id,
+  location,
+  label,
+  row_version,
+  deleted_at,
+  updated_at
+) VALUES (
+  sqlc.arg(id),
+  sqlc.arg(user_id),
+  sqlc.arg(ebook_id),
+  sqlc.arg(location),
+  sqlc.narg(label),
+  sqlc.arg(row_version),
+  sqlc.narg(deleted_at),
+  sqlc.arg(updated_at)
+)
+ON CONFLICT(id) DO UPDATE SET
+  user_id = excluded.user_id,
+  ebook_id = excluded.ebook_id,
+  location = excluded.location,
+  label = excluded.label,
+  row_version = excluded.row_version,
+  deleted_at = excluded.deleted_at,
+  updated_at = excluded.updated_at;
+
+-- name: SoftDeleteBookmarkCache :exec
+UPDATE bookmarks_cache
+SET deleted_at = sqlc.arg(deleted_at),
+  updated_at = sqlc.arg(updated_at)
+WHERE id = sqlc.arg(id);
+
+-- name: GetAnnotationCache :one
+SELECT id, user_id, ebook_id, location_start, location_end, highlight_text, note, color, row_version, deleted_at, updated_at
+FROM annotations_cache
+WHERE id = sqlc.arg(id)
+LIMIT 1;
+
+-- name: UpsertAnnotationCache :exec
+INSERT INTO annotations_cache (
+  id,
+  user_id,
+  ebook_id,
+  location_start,
+  location_end,
+  highlight_text,
+  note,
+  color,
+  row_version,
+  deleted_at,
+  updated_at
+) VALUES (
+  sqlc.arg(id),
+  sqlc.arg(user_id),
+  sqlc.arg(ebook_id),
+  sqlc.arg(location_start),
+  sqlc.arg(location_end),
+  sqlc.narg(highlight_text),
+  sqlc.narg(note),
+  sqlc.narg(color),
+  sqlc.arg(row_version),
+  sqlc.narg(deleted_at),
+  sqlc.arg(updated_at)
+)
+ON CONFLICT(id) DO UPDATE SET
+  user_id = excluded.user_id,
+  ebook_id = excluded.ebook_id,
+  location_start = excluded.location_start,
+  location_end = excluded.location_end,
+  highlight_text = excluded.highlight_text,
+  note = excluded.note,
+  color = excluded.color,
+  row_version = excluded.row_version,
+  deleted_at = excluded.deleted_at,
+  updated_at = excluded.updated_at;
+
+-- name: SoftDeleteAnnotationCache :exec
+UPDATE annotations_cache
+SET deleted_at = sqlc.arg(deleted_at),
+  updated_at = sqlc.arg(updated_at)
+WHERE id = sqlc.arg(id);
+
+-- name: UpsertBorrowLease :exec
+INSERT INTO borrow_leases (
+  borrow_id,
+  ebook_id,
+  lease,
+  public_key,
+  expires_at,
+  updated_at
+) VALUES (
+  sqlc.arg(borrow_id),
+  sqlc.arg(ebook_id),
+  sqlc.arg(lease),
+  sqlc.arg(public_key),
+  sqlc.arg(expires_at),
+  sqlc.arg(updated_at)
+)
+ON CONFLICT(borrow_id) DO UPDATE SET
+  ebook_id = excluded.ebook_id,
+  lease = excluded.lease,
+  public_key = excluded.public_key,
+  expires_at = excluded.expires_at,
+  updated_at = excluded.updated_at;
+
+-- name: GetBorrowLeaseByEbookID :one
+SELECT borrow_id, ebook_id, lease, public_key, expires_at, updated_at
+FROM borrow_leases
+WHERE ebook_id = sqlc.arg(ebook_id)
+ORDER BY expires_at DESC
+LIMIT 1;
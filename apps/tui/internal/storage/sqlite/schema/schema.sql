@@ -69,6 +69,18 @@ CREATE TABLE IF NOT EXISTS borrows_cache (
   updated_at TEXT NOT NULL
 );
 
+CREATE TABLE IF NOT EXISTS borrow_leases (
+  borrow_id TEXT PRIMARY KEY,
+  ebook_id TEXT NOT NULL,
+  lease TEXT NOT NULL,
+  public_key TEXT NOT NULL,
+  expires_at TEXT NOT NULL,
+  updated_at TEXT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_borrow_leases_ebook_id
+  ON borrow_leases(ebook_id);
+
 CREATE TABLE IF NOT EXISTS share_reviews_cache (
   id TEXT PRIMARY KEY,
   share_id TEXT NOT NULL,
@@ -153,3 +165,6 @@ CREATE TABLE IF NOT EXISTS sync_checkpoint (
   last_event_id TEXT,
   updated_at TEXT NOT NULL
 );
+
+-- Added after sync_checkpoint shipped, so Migrate skips it once the column exists.
+ALTER TABLE sync_checkpoint ADD COLUMN last_cursor TEXT;
@@ -81,6 +81,101 @@ func (q *Queries) EnqueueSyncOutboxEvent(ctx context.Context, arg EnqueueSyncOut
 	return err
 }
 
+const getAnnotationCache = `-- name: GetAnnotationCache :one
+SELECT id, user_id, ebook_id, location_start, location_end, highlight_text, note, color, row_version, deleted_at, updated_at
+FROM annotations_cache
+WHERE id = ?1
+LIMIT 1
+`
+
+func (q *Queries) GetAnnotationCache(ctx context.Context, id string) (*AnnotationsCache, error) {
+	row := q.db.QueryRowContext(ctx, getAnnotationCache, id)
+	var i AnnotationsCache
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.EbookID,
+		&i.LocationStart,
+		&i.LocationEnd,
+		&i.HighlightText,
+		&i.Note,
+		&i.Color,
+		&i.RowVersion,
+		&i.DeletedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const getBookmarkCache = `-- name: GetBookmarkCache :one
+SELECT id, user_id, ebook_id, location, label, row_version, deleted_at, updated_at
+FROM bookmarks_cache
+WHERE id = ?1
+LIMIT 1
+`
+
+func (q *Queries) GetBookmarkCache(ctx context.Context, id string) (*BookmarksCache, error) {
+	row := q.db.QueryRowContext(ctx, getBookmarkCache, id)
+	var i BookmarksCache
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.EbookID,
+		&i.Location,
+		&i.Label,
+		&i.RowVersion,
+		&i.DeletedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const getBorrowLeaseByEbookID = `-- name: GetBorrowLeaseByEbookID :one
+SELECT borrow_id, ebook_id, lease, public_key, expires_at, updated_at
+FROM borrow_leases
+WHERE ebook_id = ?1
+ORDER BY expires_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetBorrowLeaseByEbookID(ctx context.Context, ebookID string) (*BorrowLease, error) {
+	row := q.db.QueryRowContext(ctx, getBorrowLeaseByEbookID, ebookID)
+	var i BorrowLease
+	err := row.Scan(
+		&i.BorrowID,
+		&i.EbookID,
+		&i.Lease,
+		&i.PublicKey,
+		&i.ExpiresAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
+const getReadingProgressCache = `-- name: GetReadingProgressCache :one
+SELECT id, user_id, ebook_id, location, progress_percent, reading_mode, row_version, deleted_at, updated_at
+FROM reading_progress_cache
+WHERE id = ?1
+LIMIT 1
+`
+
+func (q *Queries) GetReadingProgressCache(ctx context.Context, id string) (*ReadingProgressCache, error) {
+	row := q.db.QueryRowContext(ctx, getReadingProgressCache, id)
+	var i ReadingProgressCache
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.EbookID,
+		&i.Location,
+		&i.ProgressPercent,
+		&i.ReadingMode,
+		&i.RowVersion,
+		&i.DeletedAt,
+		&i.UpdatedAt,
+	)
+	return &i, err
+}
+
 const getSessionState = `-- name: GetSessionState :one
 SELECT access_token, refresh_token, user_id, updated_at
 FROM session_state
@@ -107,7 +202,7 @@ func (q *Queries) GetSessionState(ctx context.Context) (*GetSessionStateRow, err
 }
 
 const getSyncCheckpoint = `-- name: GetSyncCheckpoint :one
-SELECT last_server_timestamp, last_event_id, updated_at
+SELECT last_server_timestamp, last_event_id, last_cursor, updated_at
 FROM sync_checkpoint
 WHERE id = 1
 `
@@ -115,13 +210,19 @@ WHERE id = 1
 type GetSyncCheckpointRow struct {
 	LastServerTimestamp sql.NullString `json:"last_server_timestamp"`
 	LastEventID         sql.NullString `json:"last_event_id"`
+	LastCursor          sql.NullString `json:"last_cursor"`
 	UpdatedAt           string         `json:"updated_at"`
 }
 
 func (q *Queries) GetSyncCheckpoint(ctx context.Context) (*GetSyncCheckpointRow, error) {
 	row := q.db.QueryRowContext(ctx, getSyncCheckpoint)
 	var i GetSyncCheckpointRow
-	err := row.Scan(&i.LastServerTimestamp, &i.LastEventID, &i.UpdatedAt)
+	err := row.Scan(
+		&i.LastServerTimestamp,
+		&i.LastEventID,
+		&i.LastCursor,
+		&i.UpdatedAt,
+	)
 	return &i, err
 }
 
@@ -330,6 +431,25 @@ func (q *Queries) ListPendingSyncOutboxEvents(ctx context.Context, arg ListPendi
 	return items, nil
 }
 
+const markSyncOutboxEventFailed = `-- name: MarkSyncOutboxEventFailed :exec
+UPDATE sync_events_outbox
+SET status = 'failed',
+  updated_at = ?1,
+  last_error = ?2
+WHERE id = ?3
+`
+
+type MarkSyncOutboxEventFailedParams struct {
+	UpdatedAt string         `json:"updated_at"`
+	LastError sql.NullString `json:"last_error"`
+	ID        string         `json:"id"`
+}
+
+func (q *Queries) MarkSyncOutboxEventFailed(ctx context.Context, arg MarkSyncOutboxEventFailedParams) error {
+	_, err := q.db.ExecContext(ctx, markSyncOutboxEventFailed, arg.UpdatedAt, arg.LastError, arg.ID)
+	return err
+}
+
 const markSyncOutboxEventRetry = `-- name: MarkSyncOutboxEventRetry :exec
 UPDATE sync_events_outbox
 SET attempt_count = attempt_count + 1,
@@ -417,6 +537,230 @@ func (q *Queries) SearchActiveEbooksCache(ctx context.Context, searchTerm string
 	return items, nil
 }
 
+const softDeleteAnnotationCache = `-- name: SoftDeleteAnnotationCache :exec
+UPDATE annotations_cache
+SET deleted_at = ?1,
+  updated_at = ?2
+WHERE id = ?3
+`
+
+type SoftDeleteAnnotationCacheParams struct {
+	DeletedAt sql.NullString `json:"deleted_at"`
+	UpdatedAt string         `json:"updated_at"`
+	ID        string         `json:"id"`
+}
+
+func (q *Queries) SoftDeleteAnnotationCache(ctx context.Context, arg SoftDeleteAnnotationCacheParams) error {
+	_, err := q.db.ExecContext(ctx, softDeleteAnnotationCache, arg.DeletedAt, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const softDeleteBookmarkCache = `-- name: SoftDeleteBookmarkCache :exec
+UPDATE bookmarks_cache
+SET deleted_at = ?1,
+  updated_at = ?2
+WHERE id = ?3
+`
+
+type SoftDeleteBookmarkCacheParams struct {
+	DeletedAt sql.NullString `json:"deleted_at"`
+	UpdatedAt string         `json:"updated_at"`
+	ID        string         `json:"id"`
+}
+
+func (q *Queries) SoftDeleteBookmarkCache(ctx context.Context, arg SoftDeleteBookmarkCacheParams) error {
+	_, err := q.db.ExecContext(ctx, softDeleteBookmarkCache, arg.DeletedAt, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const softDeleteReadingProgressCache = `-- name: SoftDeleteReadingProgressCache :exec
+UPDATE reading_progress_cache
+SET deleted_at = ?1,
+  updated_at = ?2
+WHERE id = ?3
+`
+
+type SoftDeleteReadingProgressCacheParams struct {
+	DeletedAt sql.NullString `json:"deleted_at"`
+	UpdatedAt string         `json:"updated_at"`
+	ID        string         `json:"id"`
+}
+
+func (q *Queries) SoftDeleteReadingProgressCache(ctx context.Context, arg SoftDeleteReadingProgressCacheParams) error {
+	_, err := q.db.ExecContext(ctx, softDeleteReadingProgressCache, arg.DeletedAt, arg.UpdatedAt, arg.ID)
+	return err
+}
+
+const upsertAnnotationCache = `-- name: UpsertAnnotationCache :exec
+INSERT INTO annotations_cache (
+  id,
+  user_id,
+  ebook_id,
+  location_start,
+  location_end,
+  highlight_text,
+  note,
+  color,
+  row_version,
+  deleted_at,
+  updated_at
+) VALUES (
+  ?1,
+  ?2,
+  ?3,
+  ?4,
+  ?5,
+  ?6,
+  ?7,
+  ?8,
+  ?9,
+  ?10,
+  ?11
+)
+ON CONFLICT(id) DO UPDATE SET
+  user_id = excluded.user_id,
+  ebook_id = excluded.ebook_id,
+  location_start = excluded.location_start,
+  location_end = excluded.location_end,
+  highlight_text = excluded.highlight_text,
+  note = excluded.note,
+  color = excluded.color,
+  row_version = excluded.row_version,
+  deleted_at = excluded.deleted_at,
+  updated_at = excluded.updated_at
+`
+
+type UpsertAnnotationCacheParams struct {
+	ID            string         `json:"id"`
+	UserID        string         `json:"user_id"`
+	EbookID       string         `json:"ebook_id"`
+	LocationStart string         `json:"location_start"`
+	LocationEnd   string         `json:"location_end"`
+	HighlightText sql.NullString `json:"highlight_text"`
+	Note          sql.NullString `json:"note"`
+	Color         sql.NullString `json:"color"`
+	RowVersion    int64          `json:"row_version"`
+	DeletedAt     sql.NullString `json:"deleted_at"`
+	UpdatedAt     string         `json:"updated_at"`
+}
+
+func (q *Queries) UpsertAnnotationCache(ctx context.Context, arg UpsertAnnotationCacheParams) error {
+	_, err := q.db.ExecContext(ctx, upsertAnnotationCache,
+		arg.ID,
+		arg.UserID,
+		arg.EbookID,
+		arg.LocationStart,
+		arg.LocationEnd,
+		arg.HighlightText,
+		arg.Note,
+		arg.Color,
+		arg.RowVersion,
+		arg.DeletedAt,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const upsertBookmarkCache = `-- name: UpsertBookmarkCache :exec
+INSERT INTO bookmarks_cache (
+  id,
+  user_id,
+  ebook_id,
+  location,
+  label,
+  row_version,
+  deleted_at,
+  updated_at
+) VALUES (
+  ?1,
+  ?2,
+  ?3,
+  ?4,
+  ?5,
+  ?6,
+  ?7,
+  ?8
+)
+ON CONFLICT(id) DO UPDATE SET
+  user_id = excluded.user_id,
+  ebook_id = excluded.ebook_id,
+  location = excluded.location,
+  label = excluded.label,
+  row_version = excluded.row_version,
+  deleted_at = excluded.deleted_at,
+  updated_at = excluded.updated_at
+`
+
+type UpsertBookmarkCacheParams struct {
+	ID         string         `json:"id"`
+	UserID     string         `json:"user_id"`
+	EbookID    string         `json:"ebook_id"`
+	Location   string         `json:"location"`
+	Label      sql.NullString `json:"label"`
+	RowVersion int64          `json:"row_version"`
+	DeletedAt  sql.NullString `json:"deleted_at"`
+	UpdatedAt  string         `json:"updated_at"`
+}
+
+func (q *Queries) UpsertBookmarkCache(ctx context.Context, arg UpsertBookmarkCacheParams) error {
+	_, err := q.db.ExecContext(ctx, upsertBookmarkCache,
+		arg.ID,
+		arg.UserID,
+		arg.EbookID,
+		arg.Location,
+		arg.Label,
+		arg.RowVersion,
+		arg.DeletedAt,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
+const upsertBorrowLease = `-- name: UpsertBorrowLease :exec
+INSERT INTO borrow_leases (
+  borrow_id,
+  ebook_id,
+  lease,
+  public_key,
+  expires_at,
+  updated_at
+) VALUES (
+  ?1,
+  ?2,
+  ?3,
+  ?4,
+  ?5,
+  ?6
+)
+ON CONFLICT(borrow_id) DO UPDATE SET
+  ebook_id = excluded.ebook_id,
+  lease = excluded.lease,
+  public_key = excluded.public_key,
+  expires_at = excluded.expires_at,
+  updated_at = excluded.updated_at
+`
+
+type UpsertBorrowLeaseParams struct {
+	BorrowID  string `json:"borrow_id"`
+	EbookID   string `json:"ebook_id"`
+	Lease     string `json:"lease"`
+	PublicKey string `json:"public_key"`
+	ExpiresAt string `json:"expires_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
+func (q *Queries) UpsertBorrowLease(ctx context.Context, arg UpsertBorrowLeaseParams) error {
+	_, err := q.db.ExecContext(ctx, upsertBorrowLease,
+		arg.BorrowID,
+		arg.EbookID,
+		arg.Lease,
+		arg.PublicKey,
+		arg.ExpiresAt,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
 const upsertEbookCache = `-- name: UpsertEbookCache :exec
 INSERT INTO ebooks_cache (
   id,
@@ -472,6 +816,66 @@ func (q *Queries) UpsertEbookCache(ctx context.Context, arg UpsertEbookCachePara
 	return err
 }
 
+const upsertReadingProgressCache = `-- name: UpsertReadingProgressCache :exec
+INSERT INTO reading_progress_cache (
+  id,
+  user_id,
+  ebook_id,
+  location,
+  progress_percent,
+  reading_mode,
+  row_version,
+  deleted_at,
+  updated_at
+) VALUES (
+  ?1,
+  ?2,
+  ?3,
+  ?4,
+  ?5,
+  ?6,
+  ?7,
+  ?8,
+  ?9
+)
+ON CONFLICT(id) DO UPDATE SET
+  user_id = excluded.user_id,
+  ebook_id = excluded.ebook_id,
+  location = excluded.location,
+  progress_percent = excluded.progress_percent,
+  reading_mode = excluded.reading_mode,
+  row_version = excluded.row_version,
+  deleted_at = excluded.deleted_at,
+  updated_at = excluded.updated_at
+`
+
+type UpsertReadingProgressCacheParams struct {
+	ID              string          `json:"id"`
+	UserID          string          `json:"user_id"`
+	EbookID         string          `json:"ebook_id"`
+	Location        string          `json:"location"`
+	ProgressPercent sql.NullFloat64 `json:"progress_percent"`
+	ReadingMode     string          `json:"reading_mode"`
+	RowVersion      int64           `json:"row_version"`
+	DeletedAt       sql.NullString  `json:"deleted_at"`
+	UpdatedAt       string          `json:"updated_at"`
+}
+
+func (q *Queries) UpsertReadingProgressCache(ctx context.Context, arg UpsertReadingProgressCacheParams) error {
+	_, err := q.db.ExecContext(ctx, upsertReadingProgressCache,
+		arg.ID,
+		arg.UserID,
+		arg.EbookID,
+		arg.Location,
+		arg.ProgressPercent,
+		arg.ReadingMode,
+		arg.RowVersion,
+		arg.DeletedAt,
+		arg.UpdatedAt,
+	)
+	return err
+}
+
 const upsertSessionState = `-- name: UpsertSessionState :exec
 INSERT INTO session_state (
   id,
@@ -575,27 +979,36 @@ INSERT INTO sync_checkpoint (
   id,
   last_server_timestamp,
   last_event_id,
+  last_cursor,
   updated_at
 ) VALUES (
   1,
   ?1,
   ?2,
-  ?3
+  ?3,
+  ?4
 )
 ON CONFLICT(id) DO UPDATE SET
   last_server_timestamp = excluded.last_server_timestamp,
   last_event_id = excluded.last_event_id,
+  last_cursor = excluded.last_cursor,
   updated_at = excluded.updated_at
 `
 
 type UpsertSyncCheckpointParams struct {
 	LastServerTimestamp sql.NullString `json:"last_server_timestamp"`
 	LastEventID         sql.NullString `json:"last_event_id"`
+	LastCursor          sql.NullString `json:"last_cursor"`
 	UpdatedAt           string         `json:"updated_at"`
 }
 
 func (q *Queries) UpsertSyncCheckpoint(ctx context.Context, arg UpsertSyncCheckpointParams) error {
-	_, err := q.db.ExecContext(ctx, upsertSyncCheckpoint, arg.LastServerTimestamp, arg.LastEventID, arg.UpdatedAt)
+	_, err := q.db.ExecContext(ctx, upsertSyncCheckpoint,
+		arg.LastServerTimestamp,
+		arg.LastEventID,
+		arg.LastCursor,
+		arg.UpdatedAt,
+	)
 	return err
 }
 
@@ -33,6 +33,15 @@ type BookmarksCache struct {
 	UpdatedAt  string         `json:"updated_at"`
 }
 
+type BorrowLease struct {
+	BorrowID  string `json:"borrow_id"`
+	EbookID   string `json:"ebook_id"`
+	Lease     string `json:"lease"`
+	PublicKey string `json:"public_key"`
+	ExpiresAt string `json:"expires_at"`
+	UpdatedAt string `json:"updated_at"`
+}
+
 type BorrowsCache struct {
 	ID         string         `json:"id"`
 	ShareID    string         `json:"share_id"`
@@ -114,6 +123,7 @@ type SyncCheckpoint struct {
 	LastServerTimestamp sql.NullString `json:"last_server_timestamp"`
 	LastEventID         sql.NullString `json:"last_event_id"`
 	UpdatedAt           string         `json:"updated_at"`
+	LastCursor          sql.NullString `json:"last_cursor"`
 }
 
 type SyncEventsOutbox struct {
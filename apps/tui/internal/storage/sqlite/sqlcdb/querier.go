@@ -11,6 +11,10 @@ import (
 type Querier interface {
 	ClearSessionState(ctx context.Context) error
 	EnqueueSyncOutboxEvent(ctx context.Context, arg EnqueueSyncOutboxEventParams) error
+	GetAnnotationCache(ctx context.Context, id string) (*AnnotationsCache, error)
+	GetBookmarkCache(ctx context.Context, id string) (*BookmarksCache, error)
+	GetBorrowLeaseByEbookID(ctx context.Context, ebookID string) (*BorrowLease, error)
+	GetReadingProgressCache(ctx context.Context, id string) (*ReadingProgressCache, error)
 	GetSessionState(ctx context.Context) (*GetSessionStateRow, error)
 	GetSyncCheckpoint(ctx context.Context) (*GetSyncCheckpointRow, error)
 	GetUISettings(ctx context.Context) (*GetUISettingsRow, error)
@@ -19,10 +23,18 @@ type Querier interface {
 	ListActiveEbooksCache(ctx context.Context) ([]*EbooksCache, error)
 	ListActiveSharesCache(ctx context.Context) ([]*SharesCache, error)
 	ListPendingSyncOutboxEvents(ctx context.Context, arg ListPendingSyncOutboxEventsParams) ([]*SyncEventsOutbox, error)
+	MarkSyncOutboxEventFailed(ctx context.Context, arg MarkSyncOutboxEventFailedParams) error
 	MarkSyncOutboxEventRetry(ctx context.Context, arg MarkSyncOutboxEventRetryParams) error
 	MarkSyncOutboxEventSucceeded(ctx context.Context, arg MarkSyncOutboxEventSucceededParams) error
 	SearchActiveEbooksCache(ctx context.Context, searchTerm string) ([]*EbooksCache, error)
+	SoftDeleteAnnotationCache(ctx context.Context, arg SoftDeleteAnnotationCacheParams) error
+	SoftDeleteBookmarkCache(ctx context.Context, arg SoftDeleteBookmarkCacheParams) error
+	SoftDeleteReadingProgressCache(ctx context.Context, arg SoftDeleteReadingProgressCacheParams) error
+	UpsertAnnotationCache(ctx context.Context, arg UpsertAnnotationCacheParams) error
+	UpsertBookmarkCache(ctx context.Context, arg UpsertBookmarkCacheParams) error
+	UpsertBorrowLease(ctx context.Context, arg UpsertBorrowLeaseParams) error
 	UpsertEbookCache(ctx context.Context, arg UpsertEbookCacheParams) error
+	UpsertReadingProgressCache(ctx context.Context, arg UpsertReadingProgressCacheParams) error
 	UpsertSessionState(ctx context.Context, arg UpsertSessionStateParams) error
 	UpsertShareCache(ctx context.Context, arg UpsertShareCacheParams) error
 	UpsertSyncCheckpoint(ctx context.Context, arg UpsertSyncCheckpointParams) error
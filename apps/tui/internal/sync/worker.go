@@ -9,14 +9,26 @@ import (
 	"github.com/jeheskielSunloy77/libra-link/apps/tui/internal/storage/repo"
 )
 
+const (
+	// maxBatchSize matches the server's limit on events per batch push.
+	maxBatchSize = 100
+	pullPageSize = 100
+)
+
 type API interface {
-	StoreSyncEvent(ctx context.Context, input api.SyncEvent) error
+	StoreSyncEvents(ctx context.Context, events []api.SyncEvent) ([]api.SyncEventResult, error)
+	ListSyncEvents(ctx context.Context, since *time.Time, cursor string, limit int) (*api.SyncEventPage, error)
+	StreamSyncEvents(ctx context.Context, handle func(api.SyncEvent) error) error
 }
 
 type Store interface {
 	ListPendingOutbox(ctx context.Context, limit int) ([]repo.OutboxEvent, error)
 	MarkOutboxDone(ctx context.Context, eventID string) error
+	MarkOutboxFailed(ctx context.Context, eventID string, lastErr string) error
 	MarkOutboxRetry(ctx context.Context, eventID string, nextAttempt time.Time, lastErr string) error
+	GetSyncCheckpoint(ctx context.Context) (*repo.SyncCheckpoint, error)
+	ApplySyncEvents(ctx context.Context, events []api.SyncEvent, cursor string) error
+	ApplyLiveSyncEvent(ctx context.Context, event api.SyncEvent) error
 }
 
 type Worker struct {
@@ -29,6 +41,9 @@ func NewWorker(store Store, apiClient API, batchSize int) *Worker {
 	if batchSize <= 0 {
 		batchSize = 25
 	}
+	if batchSize > maxBatchSize {
+		batchSize = maxBatchSize
+	}
 	return &Worker{store: store, apiClient: apiClient, batchSize: batchSize}
 }
 
@@ -37,34 +52,109 @@ func (w *Worker) FlushOnce(ctx context.Context) error {
 	if err != nil {
 		return err
 	}
+	if len(events) == 0 {
+		return nil
+	}
 
+	batch := make([]api.SyncEvent, 0, len(events))
 	for _, event := range events {
-		baseVersion := intPtrFromInt64(event.BaseVersion)
 		payload := map[string]any{}
 		if event.Payload != nil {
 			payload = event.Payload
 		}
 
-		err := w.apiClient.StoreSyncEvent(ctx, api.SyncEvent{
+		batch = append(batch, api.SyncEvent{
 			EntityType:     event.EntityType,
 			EntityID:       event.EntityID,
 			Operation:      event.Operation,
 			Payload:        payload,
-			BaseVersion:    baseVersion,
+			BaseVersion:    intPtrFromInt64(event.BaseVersion),
 			ClientTS:       event.CreatedAt,
 			IdempotencyKey: event.IdempotencyKey,
 		})
-		if err != nil {
-			delay := backoff(event.AttemptCount + 1)
-			_ = w.store.MarkOutboxRetry(ctx, event.ID, time.Now().UTC().Add(delay), err.Error())
+	}
+
+	results, err := w.apiClient.StoreSyncEvents(ctx, batch)
+	if err != nil {
+		for _, event := range events {
+			w.retry(ctx, event, err.Error())
+		}
+		return nil
+	}
+
+	byKey := make(map[string]api.SyncEventResult, len(results))
+	for _, result := range results {
+		byKey[result.IdempotencyKey] = result
+	}
+
+	for _, event := range events {
+		result, ok := byKey[event.IdempotencyKey]
+		if !ok {
+			w.retry(ctx, event, "event missing from sync batch response")
 			continue
 		}
-		_ = w.store.MarkOutboxDone(ctx, event.ID)
+
+		switch result.Status {
+		case api.SyncEventApplied, api.SyncEventDuplicate:
+			_ = w.store.MarkOutboxDone(ctx, event.ID)
+		case api.SyncEventSuperseded:
+			// A newer write won last-writer-wins, so there is nothing to retry.
+			_ = w.store.MarkOutboxDone(ctx, event.ID)
+		case api.SyncEventConflict, api.SyncEventRejected:
+			// Retrying cannot change the outcome, so the server copy is kept.
+			_ = w.store.MarkOutboxFailed(ctx, event.ID, result.Status+": "+result.Message)
+		default:
+			w.retry(ctx, event, "unexpected sync status "+result.Status)
+		}
 	}
 
 	return nil
 }
 
+// PullOnce pages through server events recorded after the local checkpoint and
+// applies each page to the caches, advancing the checkpoint with it. It resumes
+// from the stored cursor, which orders events sharing a timestamp; checkpoints
+// written before cursors were stored fall back to the timestamp.
+func (w *Worker) PullOnce(ctx context.Context) error {
+	checkpoint, err := w.store.GetSyncCheckpoint(ctx)
+	if err != nil {
+		return err
+	}
+
+	var since *time.Time
+	cursor := ""
+	if checkpoint != nil {
+		since = checkpoint.LastServerTimestamp
+		cursor = checkpoint.Cursor
+	}
+
+	for {
+		page, err := w.apiClient.ListSyncEvents(ctx, since, cursor, pullPageSize)
+		if err != nil {
+			return err
+		}
+		if err := w.store.ApplySyncEvents(ctx, page.Events, page.NextCursor); err != nil {
+			return err
+		}
+		if !page.HasMore || page.NextCursor == "" {
+			return nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// SyncOnce pushes pending local changes before pulling, so the pull already
+// reflects them.
+func (w *Worker) SyncOnce(ctx context.Context) {
+	_ = w.FlushOnce(ctx)
+	_ = w.PullOnce(ctx)
+}
+
+func (w *Worker) retry(ctx context.Context, event repo.OutboxEvent, lastErr string) {
+	delay := backoff(event.AttemptCount + 1)
+	_ = w.store.MarkOutboxRetry(ctx, event.ID, time.Now().UTC().Add(delay), lastErr)
+}
+
 func (w *Worker) Run(ctx context.Context, interval time.Duration) {
 	if interval <= 0 {
 		interval = 10 * time.Second
@@ -73,14 +163,40 @@ func (w *Worker) Run(ctx context.Context, interval time.Duration) {
 	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
-	_ = w.FlushOnce(ctx)
+	w.SyncOnce(ctx)
 
 	for {
 		select {
 		case <-ctx.Done():
 			return
 		case <-ticker.C:
-			_ = w.FlushOnce(ctx)
+			w.SyncOnce(ctx)
+		}
+	}
+}
+
+// Subscribe applies events from the server's live stream as they arrive and
+// reconnects with backoff until ctx is done. It complements Run rather than
+// replacing it: events stored while the stream was down are picked up by the
+// next pull.
+func (w *Worker) Subscribe(ctx context.Context) {
+	var attempt int64
+	for {
+		err := w.apiClient.StreamSyncEvents(ctx, func(event api.SyncEvent) error {
+			attempt = 0
+			return w.store.ApplyLiveSyncEvent(ctx, event)
+		})
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			attempt++
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff(attempt)):
 		}
 	}
 }
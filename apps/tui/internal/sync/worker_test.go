@@ -0,0 +1,200 @@
+package sync
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jeheskielSunloy77/libra-link/apps/tui/internal/api"
+	"github.com/jeheskielSunloy77/libra-link/apps/tui/internal/storage/repo"
+	"github.com/jeheskielSunloy77/libra-link/apps/tui/internal/storage/sqlite"
+)
+
+type fakeSyncAPI struct {
+	events  []api.SyncEvent
+	sinces  []*time.Time
+	cursors []string
+
+	streamed    []api.SyncEvent
+	onStreamEnd func()
+}
+
+func (f *fakeSyncAPI) StoreSyncEvents(ctx context.Context, events []api.SyncEvent) ([]api.SyncEventResult, error) {
+	return nil, nil
+}
+
+// ListSyncEvents uses the index of the next event as its cursor.
+func (f *fakeSyncAPI) ListSyncEvents(ctx context.Context, since *time.Time, cursor string, limit int) (*api.SyncEventPage, error) {
+	f.sinces = append(f.sinces, since)
+	f.cursors = append(f.cursors, cursor)
+
+	start := 0
+	if cursor != "" {
+		if _, err := fmt.Sscanf(cursor, "%d", &start); err != nil {
+			return nil, err
+		}
+	} else if since != nil {
+		for start < len(f.events) && !f.events[start].ServerTS.After(*since) {
+			start++
+		}
+	}
+
+	end := min(start+limit, len(f.events))
+	return &api.SyncEventPage{
+		Events:     f.events[start:end],
+		NextCursor: fmt.Sprintf("%d", end),
+		HasMore:    end < len(f.events),
+	}, nil
+}
+
+func (f *fakeSyncAPI) StreamSyncEvents(ctx context.Context, handle func(api.SyncEvent) error) error {
+	for _, event := range f.streamed {
+		if err := handle(event); err != nil {
+			return err
+		}
+	}
+	if f.onStreamEnd != nil {
+		f.onStreamEnd()
+	}
+	return nil
+}
+
+func TestPullOnceAppliesEventsAndAdvancesCheckpoint(t *testing.T) {
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "tui.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer db.Close()
+	store := repo.New(db)
+
+	start := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	fake := &fakeSyncAPI{}
+	for i := 0; i < pullPageSize; i++ {
+		fake.events = append(fake.events, api.SyncEvent{
+			ID:         fmt.Sprintf("filler-%d", i),
+			UserID:     "user-1",
+			EntityType: "ebook",
+			EntityID:   "ebook-1",
+			Operation:  "upsert",
+			ServerTS:   start.Add(time.Duration(i) * time.Millisecond),
+		})
+	}
+	baseVersion := 1
+	last := start.Add(time.Minute)
+	fake.events = append(fake.events,
+		api.SyncEvent{
+			ID:         "event-create",
+			UserID:     "user-1",
+			EntityType: "bookmark",
+			EntityID:   "bookmark-1",
+			Operation:  "upsert",
+			Payload:    map[string]any{"ebookId": "ebook-1", "location": "chapter-1"},
+			ServerTS:   last.Add(-time.Second),
+		},
+		api.SyncEvent{
+			ID:          "event-reader-state",
+			UserID:      "user-1",
+			EntityType:  "reader_state",
+			EntityID:    "user-1",
+			Operation:   "upsert",
+			Payload:     map[string]any{"currentLocation": "chapter-7", "readingMode": "zen"},
+			BaseVersion: &baseVersion,
+			ServerTS:    last,
+		},
+	)
+
+	worker := NewWorker(store, fake, 25)
+	if err := worker.PullOnce(context.Background()); err != nil {
+		t.Fatalf("pull once: %v", err)
+	}
+
+	if len(fake.cursors) != 2 || fake.sinces[0] != nil || fake.cursors[0] != "" || fake.cursors[1] != fmt.Sprint(pullPageSize) {
+		t.Fatalf("expected two pages starting from the beginning, got sinces=%v cursors=%v", fake.sinces, fake.cursors)
+	}
+
+	checkpoint, err := store.GetSyncCheckpoint(context.Background())
+	if err != nil {
+		t.Fatalf("get checkpoint: %v", err)
+	}
+	if checkpoint == nil || checkpoint.LastEventID != "event-reader-state" || !checkpoint.LastServerTimestamp.Equal(last) || checkpoint.Cursor != fmt.Sprint(len(fake.events)) {
+		t.Fatalf("unexpected checkpoint: %+v", checkpoint)
+	}
+
+	state, err := store.GetReaderState(context.Background(), "user-1")
+	if err != nil {
+		t.Fatalf("get reader state: %v", err)
+	}
+	if state == nil || state.CurrentLocation != "chapter-7" || state.ReadingMode != "zen" {
+		t.Fatalf("unexpected reader state: %+v", state)
+	}
+
+	var location string
+	var rowVersion int64
+	if err := db.QueryRow(`SELECT location, row_version FROM bookmarks_cache WHERE id = ?`, "bookmark-1").Scan(&location, &rowVersion); err != nil {
+		t.Fatalf("read bookmark cache: %v", err)
+	}
+	if location != "chapter-1" || rowVersion != 1 {
+		t.Fatalf("unexpected bookmark cache: location=%q row_version=%d", location, rowVersion)
+	}
+
+	if err := worker.PullOnce(context.Background()); err != nil {
+		t.Fatalf("second pull: %v", err)
+	}
+	if got := fake.cursors[len(fake.cursors)-1]; got != fmt.Sprint(len(fake.events)) {
+		t.Fatalf("expected second pull to resume from the stored cursor, got %q", got)
+	}
+}
+
+func TestSubscribeAppliesLiveEventsWithoutMovingCheckpoint(t *testing.T) {
+	db, err := sqlite.Open(filepath.Join(t.TempDir(), "tui.db"))
+	if err != nil {
+		t.Fatalf("open sqlite: %v", err)
+	}
+	defer db.Close()
+	store := repo.New(db)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	fake := &fakeSyncAPI{
+		streamed: []api.SyncEvent{{
+			ID:         "event-live",
+			UserID:     "user-1",
+			EntityType: "bookmark",
+			EntityID:   "bookmark-live",
+			Operation:  "upsert",
+			Payload:    map[string]any{"ebookId": "ebook-1", "location": "chapter-3", "label": "from phone"},
+			ServerTS:   time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC),
+		}},
+		onStreamEnd: cancel,
+	}
+
+	done := make(chan struct{})
+	go func() {
+		NewWorker(store, fake, 25).Subscribe(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("subscribe did not return after cancel")
+	}
+
+	var label string
+	if err := db.QueryRow(`SELECT label FROM bookmarks_cache WHERE id = ?`, "bookmark-live").Scan(&label); err != nil {
+		t.Fatalf("read bookmark cache: %v", err)
+	}
+	if label != "from phone" {
+		t.Fatalf("unexpected bookmark label: %q", label)
+	}
+
+	checkpoint, err := store.GetSyncCheckpoint(context.Background())
+	if err != nil {
+		t.Fatalf("get checkpoint: %v", err)
+	}
+	if checkpoint != nil {
+		t.Fatalf("expected live events to leave the checkpoint alone, got %+v", checkpoint)
+	}
+}
@@ -0,0 +1,52 @@
+import { Heading, Section, Text } from '@react-email/components'
+import { EmailLayout } from '../components/email-layout.js'
+
+interface AccountRestrictedProps {
+	username: string
+	title: string
+	reason: string
+	details: string
+}
+
+export const AccountRestricted = ({
+	username = '{{.Username}}',
+	title = '{{.Title}}',
+	reason = '{{.Reason}}',
+	details = '{{.Details}}',
+}: AccountRestrictedProps) => {
+	return (
+		<EmailLayout preview={title}>
+			<Heading className='text-2xl font-bold text-gray-800 mt-4'>
+				{title}
+			</Heading>
+
+			<Section>
+				<Text className='text-gray-700 text-base'>Hi {username},</Text>
+				<Text className='text-gray-700 text-base'>
+					An administrator restricted your account for the following reason:
+				</Text>
+			</Section>
+
+			<Section className='my-8'>
+				<Text className='text-gray-800 text-base bg-gray-100 px-6 py-3 rounded-md'>
+					{reason}
+				</Text>
+			</Section>
+
+			<Text className='text-gray-600 text-sm'>{details}</Text>
+			<Text className='text-gray-500 text-xs'>
+				You have been signed out of every device and your shares have been
+				disabled.
+			</Text>
+		</EmailLayout>
+	)
+}
+
+AccountRestricted.PreviewProps = {
+	username: 'John',
+	title: 'Your account has been suspended',
+	reason: 'Sharing copyrighted books',
+	details: 'The suspension ends on Oct 20, 2026 at 14:00 UTC.',
+}
+
+export default AccountRestricted
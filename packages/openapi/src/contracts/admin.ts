@@ -0,0 +1,191 @@
+import {
+	ZAuditEvent,
+	ZAuthorizationPolicy,
+	ZBanUserDTO,
+	ZEmpty,
+	ZListAuditEventsQuery,
+	ZListShareReportsQuery,
+	ZPaginatedResponse,
+	ZReinstateShareDTO,
+	ZReinstateUserDTO,
+	ZRejectShareReportDTO,
+	ZResolveShareReportDTO,
+	ZResponse,
+	ZResponseWithData,
+	ZShareReinstatement,
+	ZShareReport,
+	ZShareReportResolution,
+	ZSuspendUserDTO,
+	ZUser,
+	ZUserRestriction,
+} from '@libra-link/zod'
+import { initContract } from '@ts-rest/core'
+import { z } from 'zod'
+import { failResponses, getSecurityMetadata } from '../utils.js'
+
+const c = initContract()
+
+const idParams = z.object({ id: z.string().uuid() })
+
+export const adminContract = c.router({
+	listAuthorizationPolicies: {
+		summary: 'List authorization policies',
+		description:
+			'List the authorization policy rules. Admins bypass the policy; any other user may call a route when some allow rule matches the request and no deny rule does. Admin only.',
+		method: 'GET',
+		path: '/api/v1/admin/authorization/policies',
+		responses: {
+			200: ZResponseWithData(z.array(ZAuthorizationPolicy)),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	addAuthorizationPolicy: {
+		summary: 'Add authorization policy',
+		description:
+			'Add an authorization policy rule. Subject, object and action are expressions over the request, e.g. `keyMatch(r.obj.Route, "/api/v1/admin/*")`, and are checked to compile before the rule is added. Admin only.',
+		method: 'POST',
+		path: '/api/v1/admin/authorization/policies',
+		body: ZAuthorizationPolicy,
+		responses: {
+			201: ZResponseWithData(ZAuthorizationPolicy),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	removeAuthorizationPolicy: {
+		summary: 'Remove authorization policy',
+		description: 'Remove the authorization policy rule matching the body exactly. Admin only.',
+		method: 'DELETE',
+		path: '/api/v1/admin/authorization/policies',
+		body: ZAuthorizationPolicy,
+		responses: {
+			200: ZResponse,
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	listShareReports: {
+		summary: 'List share reports',
+		description:
+			'List the share report queue, oldest first. `status` takes a comma-separated list of report statuses and defaults to `open,in_review`. Pass `nextCursor` back as `cursor` to continue. Admin only.',
+		method: 'GET',
+		path: '/api/v1/admin/share-reports',
+		query: ZListShareReportsQuery,
+		responses: {
+			200: ZPaginatedResponse(ZShareReport),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	claimShareReport: {
+		summary: 'Claim share report',
+		description:
+			'Put an open report in review by the current moderator, so other moderators leave it alone. Admin only.',
+		method: 'POST',
+		path: '/api/v1/admin/share-reports/:id/claim',
+		pathParams: idParams,
+		body: ZEmpty,
+		responses: {
+			200: ZResponseWithData(ZShareReport),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	resolveShareReport: {
+		summary: 'Resolve share report',
+		description:
+			'Resolve a report with a note. With `shareStatus` the share is disabled or removed, its active borrows are revoked and its other pending reports are resolved in the same transaction. A removed share stays removed. Admin only.',
+		method: 'POST',
+		path: '/api/v1/admin/share-reports/:id/resolve',
+		pathParams: idParams,
+		body: ZResolveShareReportDTO,
+		responses: {
+			200: ZResponseWithData(ZShareReportResolution),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	rejectShareReport: {
+		summary: 'Reject share report',
+		description: 'Reject a report with a note, leaving the share untouched. Admin only.',
+		method: 'POST',
+		path: '/api/v1/admin/share-reports/:id/reject',
+		pathParams: idParams,
+		body: ZRejectShareReportDTO,
+		responses: {
+			200: ZResponseWithData(ZShareReport),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	reinstateShare: {
+		summary: 'Reinstate share',
+		description:
+			'Reactivate a disabled share, clear the flags of its borrows and reject its pending reports with a note. Admin only.',
+		method: 'POST',
+		path: '/api/v1/admin/shares/:id/reinstate',
+		pathParams: idParams,
+		body: ZReinstateShareDTO,
+		responses: {
+			200: ZResponseWithData(ZShareReinstatement),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	suspendUser: {
+		summary: 'Suspend user',
+		description:
+			'Suspend a user until `until`, or until reinstated when it is omitted. The user is signed out of every session, their active shares are disabled and they are notified by email. Admin only.',
+		method: 'POST',
+		path: '/api/v1/admin/users/:id/suspend',
+		pathParams: idParams,
+		body: ZSuspendUserDTO,
+		responses: {
+			200: ZResponseWithData(ZUserRestriction),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	banUser: {
+		summary: 'Ban user',
+		description:
+			'Permanently ban a user. The user is signed out of every session, their active shares are disabled and they are notified by email. Admin only.',
+		method: 'POST',
+		path: '/api/v1/admin/users/:id/ban',
+		pathParams: idParams,
+		body: ZBanUserDTO,
+		responses: {
+			200: ZResponseWithData(ZUserRestriction),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	reinstateUser: {
+		summary: 'Reinstate user',
+		description:
+			'Lift the suspension or ban of a user. Shares disabled with it stay disabled until a moderator reinstates them. Admin only.',
+		method: 'POST',
+		path: '/api/v1/admin/users/:id/reinstate',
+		pathParams: idParams,
+		body: ZReinstateUserDTO,
+		responses: {
+			200: ZResponseWithData(ZUser),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	listAuditEvents: {
+		summary: 'List audit events',
+		description:
+			'List the audit log of administrative actions, newest first, filtered by actor, action, target and a `from` (inclusive) to `to` (exclusive) time range. Pass `nextCursor` back as `cursor` to continue. Admin only.',
+		method: 'GET',
+		path: '/api/v1/admin/audit-events',
+		query: ZListAuditEventsQuery,
+		responses: {
+			200: ZPaginatedResponse(ZAuditEvent),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+})
@@ -31,7 +31,8 @@ export const authContract = c.router({
 	},
 	login: {
 		summary: 'Login',
-		description: 'Login with email/username and password',
+		description:
+			'Login with email/username and password. Suspended and banned accounts are rejected with 403.',
 		path: '/api/v1/auth/login',
 		method: 'POST',
 		body: ZAuthLoginDTO,
@@ -94,7 +95,8 @@ export const authContract = c.router({
 	},
 	refresh: {
 		summary: 'Refresh session',
-		description: 'Refresh access using the refresh cookie',
+		description:
+			'Refresh access using the refresh cookie. Suspended and banned accounts are rejected with 403.',
 		path: '/api/v1/auth/refresh',
 		method: 'POST',
 		body: ZEmpty,
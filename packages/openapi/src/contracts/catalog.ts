@@ -0,0 +1,38 @@
+import {
+	ZAuthor,
+	ZCatalogFilter,
+	ZStoreAuthorDTO,
+	ZStoreTagDTO,
+	ZTag,
+	ZUpdateAuthorDTO,
+	ZUpdateTagDTO,
+} from '@libra-link/zod'
+import { initContract } from '@ts-rest/core'
+import { createResourceContract } from './resource.js'
+
+const c = initContract()
+
+export const catalogContract = c.router({
+	authors: createResourceContract({
+		path: '/api/v1/authors',
+		resource: 'Author',
+		resourcePlural: 'Authors',
+		schemas: {
+			entity: ZAuthor,
+			createDTO: ZStoreAuthorDTO,
+			updateDTO: ZUpdateAuthorDTO,
+			filter: ZCatalogFilter,
+		},
+	}),
+	tags: createResourceContract({
+		path: '/api/v1/tags',
+		resource: 'Tag',
+		resourcePlural: 'Tags',
+		schemas: {
+			entity: ZTag,
+			createDTO: ZStoreTagDTO,
+			updateDTO: ZUpdateTagDTO,
+			filter: ZCatalogFilter,
+		},
+	}),
+})
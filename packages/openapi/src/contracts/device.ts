@@ -0,0 +1,54 @@
+import {
+	ZDevice,
+	ZRenameDeviceDTO,
+	ZResponse,
+	ZResponseWithData,
+} from '@libra-link/zod'
+import { initContract } from '@ts-rest/core'
+import { z } from 'zod'
+import { failResponses, getSecurityMetadata } from '../utils.js'
+
+const c = initContract()
+
+const idParams = z.object({ id: z.string().uuid() })
+
+export const deviceContract = c.router({
+	list: {
+		summary: 'List devices',
+		description:
+			'List the active devices of current user, most recently seen first. Clients identify themselves with the X-Device-ID and X-Device-Name headers; the requesting device is marked as current.',
+		method: 'GET',
+		path: '/api/v1/devices',
+		responses: {
+			200: ZResponseWithData(z.array(ZDevice)),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	rename: {
+		summary: 'Rename device',
+		description: 'Rename an active device of current user.',
+		method: 'PATCH',
+		path: '/api/v1/devices/:id',
+		pathParams: idParams,
+		body: ZRenameDeviceDTO,
+		responses: {
+			200: ZResponseWithData(ZDevice),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	revoke: {
+		summary: 'Revoke device',
+		description:
+			'Revoke a device of current user and every session it holds. The device cannot sign in again.',
+		method: 'DELETE',
+		path: '/api/v1/devices/:id',
+		pathParams: idParams,
+		responses: {
+			200: ZResponse,
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+})
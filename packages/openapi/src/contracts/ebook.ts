@@ -1,11 +1,24 @@
 import {
 	ZAttachGoogleMetadataDTO,
 	ZEbook,
+	ZEbookFileURL,
 	ZEbookGoogleMetadata,
+	ZEbookMetadataSuggestion,
+	ZEbookPassageHit,
+	ZEmpty,
+	ZFile,
+	ZGetManyEbooksQuery,
+	ZGoogleBooksSearchQuery,
+	ZGoogleBooksVolume,
+	ZPaginatedResponse,
 	ZResponse,
 	ZResponseWithData,
+	ZSearchEbookContentQuery,
+	ZSetEbookAuthorsDTO,
+	ZSetEbookTagsDTO,
 	ZStoreEbookDTO,
 	ZUpdateEbookDTO,
+	ZUploadEbookFileDTO,
 } from '@libra-link/zod'
 import { initContract } from '@ts-rest/core'
 import { z } from 'zod'
@@ -27,6 +40,47 @@ export const ebookContract = c.router({
 			updateDTO: ZUpdateEbookDTO,
 		},
 	}),
+	getMany: {
+		summary: 'Get Many Ebooks',
+		description:
+			'Retrieve a paginated list of Ebooks that can be filtered, sorted, and preloaded. `authorId`, `author`, `tagId` and `tag` take comma-separated lists; an ebook matches a list when it has any entry of it, and must match every list given. Names are compared ignoring case. Preload `Authors` and `Tags` to include them.',
+		method: 'GET',
+		path: '/api/v1/ebooks',
+		query: ZGetManyEbooksQuery,
+		responses: {
+			200: ZPaginatedResponse(ZEbook),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	setAuthors: {
+		summary: 'Set ebook authors',
+		description:
+			'Replace the authors of an ebook. Names are matched against existing authors ignoring case, and the ones not found are created. An empty list clears the authors.',
+		method: 'PUT',
+		path: '/api/v1/ebooks/:id/authors',
+		pathParams: idParams,
+		body: ZSetEbookAuthorsDTO,
+		responses: {
+			200: ZResponseWithData(ZEbook),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	setTags: {
+		summary: 'Set ebook tags',
+		description:
+			'Replace the tags of an ebook. Names are matched against existing tags ignoring case, and the ones not found are created. An empty list clears the tags.',
+		method: 'PUT',
+		path: '/api/v1/ebooks/:id/tags',
+		pathParams: idParams,
+		body: ZSetEbookTagsDTO,
+		responses: {
+			200: ZResponseWithData(ZEbook),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
 	attachMetadata: {
 		summary: 'Attach Google metadata to ebook',
 		description: 'Upsert Google Books metadata for an ebook.',
@@ -40,6 +94,72 @@ export const ebookContract = c.router({
 		},
 		metadata: getSecurityMetadata(),
 	},
+	attachGoogleVolume: {
+		summary: 'Attach Google Books volume to ebook',
+		description:
+			'Fetch a volume from Google Books and attach its metadata to an ebook, replacing any attached before. Responds 404 for unknown volumes and 503 while Google Books is unavailable or rate limiting the API.',
+		method: 'POST',
+		path: '/api/v1/ebooks/:id/metadata/google/:volumeId',
+		pathParams: z.object({ id: z.string().uuid(), volumeId: z.string().min(1) }),
+		body: ZEmpty,
+		responses: {
+			200: ZResponseWithData(ZEbookGoogleMetadata),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	listMetadataSuggestions: {
+		summary: 'List ebook metadata suggestions',
+		description:
+			'List the Google Books volumes that may describe an ebook, best match first. New ebooks are looked up in the background; a confident match is attached directly, otherwise the likeliest volumes are suggested here.',
+		method: 'GET',
+		path: '/api/v1/ebooks/:id/metadata/suggestions',
+		pathParams: idParams,
+		responses: {
+			200: ZResponseWithData(z.array(ZEbookMetadataSuggestion)),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	acceptMetadataSuggestion: {
+		summary: 'Accept ebook metadata suggestion',
+		description:
+			'Attach the metadata of a suggested Google Books volume to an ebook and dismiss its other suggestions. Responds 503 while Google Books is unavailable.',
+		method: 'POST',
+		path: '/api/v1/ebooks/:id/metadata/suggestions/:suggestionId/accept',
+		pathParams: z.object({ id: z.string().uuid(), suggestionId: z.string().uuid() }),
+		body: ZEmpty,
+		responses: {
+			200: ZResponseWithData(ZEbookGoogleMetadata),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	dismissMetadataSuggestions: {
+		summary: 'Dismiss ebook metadata suggestions',
+		description: 'Delete every metadata suggestion of an ebook.',
+		method: 'DELETE',
+		path: '/api/v1/ebooks/:id/metadata/suggestions',
+		pathParams: idParams,
+		responses: {
+			200: ZResponse,
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	searchGoogleBooks: {
+		summary: 'Search Google Books',
+		description:
+			'Search Google Books by free text (`q`) or by ISBN (`isbn`); exactly one is required. Results are cached, and the endpoint responds 503 while Google Books is unavailable or rate limiting the API.',
+		method: 'GET',
+		path: '/api/v1/metadata/google-books/search',
+		query: ZGoogleBooksSearchQuery,
+		responses: {
+			200: ZResponseWithData(z.array(ZGoogleBooksVolume)),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
 	removeMetadata: {
 		summary: 'Detach Google metadata from ebook',
 		description: 'Soft-delete current Google Books metadata attachment for an ebook.',
@@ -52,4 +172,61 @@ export const ebookContract = c.router({
 		},
 		metadata: getSecurityMetadata(),
 	},
+	uploadFile: {
+		summary: 'Upload ebook file',
+		description:
+			'Upload the ebook contents as multipart/form-data (`file` field) or as a raw request body. The stream must match the ebook fileSizeBytes and checksumSha256.',
+		method: 'PUT',
+		path: '/api/v1/ebooks/:id/file',
+		pathParams: idParams,
+		contentType: 'multipart/form-data',
+		body: ZUploadEbookFileDTO,
+		responses: {
+			200: ZResponseWithData(ZEbook),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	downloadFile: {
+		summary: 'Download ebook file',
+		description: 'Stream the stored ebook file as an attachment.',
+		method: 'GET',
+		path: '/api/v1/ebooks/:id/file',
+		pathParams: idParams,
+		responses: {
+			200: c.otherResponse({
+				contentType: 'application/octet-stream',
+				body: ZFile,
+			}),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	getFileUrl: {
+		summary: 'Get ebook file url',
+		description:
+			'Create a time-limited signed URL for reading the stored ebook file directly from storage.',
+		method: 'GET',
+		path: '/api/v1/ebooks/:id/file/url',
+		pathParams: idParams,
+		responses: {
+			200: ZResponseWithData(ZEbookFileURL),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	searchContent: {
+		summary: 'Search inside ebook',
+		description:
+			'Full-text search, in reading order, of the text of an ebook of current user, extracted from its uploaded file. `q` is a web search query like the one of `/api/v1/search`. Each passage has the reader location token of where it starts, e.g. `fmt=epub;spine=3;offset=12;line=240`, and a `snippet` with matches wrapped in `<mark>` tags. Returns 409 while the file is being indexed, which the first search of a file uploaded before indexing existed starts, and when the file has no extractable text.',
+		method: 'GET',
+		path: '/api/v1/ebooks/:id/search',
+		pathParams: idParams,
+		query: ZSearchEbookContentQuery,
+		responses: {
+			200: ZPaginatedResponse(ZEbookPassageHit),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
 })
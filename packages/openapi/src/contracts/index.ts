@@ -1,8 +1,12 @@
 import { initContract } from '@ts-rest/core'
+import { adminContract } from './admin.js'
 import { authContract } from './auth.js'
+import { catalogContract } from './catalog.js'
+import { deviceContract } from './device.js'
 import { ebookContract } from './ebook.js'
 import { healthContract } from './health.js'
 import { readerContract } from './reader.js'
+import { searchContract } from './search.js'
 import { shareContract } from './share.js'
 import { syncContract } from './sync.js'
 import { userContract } from './user.js'
@@ -14,7 +18,11 @@ export const apiContract = c.router({
 	auth: authContract,
 	user: userContract,
 	ebook: ebookContract,
+	catalog: catalogContract,
 	share: shareContract,
 	reader: readerContract,
 	sync: syncContract,
+	device: deviceContract,
+	admin: adminContract,
+	search: searchContract,
 })
@@ -1,7 +1,13 @@
 import {
 	ZAnnotation,
+	ZAnnotationFilter,
 	ZBookmark,
+	ZBookmarkFilter,
+	ZIfMatchHeaders,
+	ZIfNoneMatchHeaders,
+	ZPreconditionFailedResponse,
 	ZReadingProgress,
+	ZReadingProgressFilter,
 	ZResponseWithData,
 	ZStoreAnnotationDTO,
 	ZStoreBookmarkDTO,
@@ -29,7 +35,9 @@ export const readerContract = c.router({
 			entity: ZReadingProgress,
 			createDTO: ZStoreReadingProgressDTO,
 			updateDTO: ZUpdateReadingProgressDTO,
+			filter: ZReadingProgressFilter,
 		},
+		versioned: true,
 	}),
 	bookmarks: createResourceContract({
 		path: '/api/v1/bookmarks',
@@ -39,7 +47,9 @@ export const readerContract = c.router({
 			entity: ZBookmark,
 			createDTO: ZStoreBookmarkDTO,
 			updateDTO: ZUpdateBookmarkDTO,
+			filter: ZBookmarkFilter,
 		},
+		versioned: true,
 	}),
 	annotations: createResourceContract({
 		path: '/api/v1/annotations',
@@ -49,15 +59,19 @@ export const readerContract = c.router({
 			entity: ZAnnotation,
 			createDTO: ZStoreAnnotationDTO,
 			updateDTO: ZUpdateAnnotationDTO,
+			filter: ZAnnotationFilter,
 		},
+		versioned: true,
 	}),
 	getUserPreferences: {
 		summary: 'Get user preferences',
 		description: 'Get reading preferences for current user.',
 		method: 'GET',
 		path: '/api/v1/users/preferences',
+		headers: ZIfNoneMatchHeaders,
 		responses: {
 			200: ZResponseWithData(ZUserPreferences),
+			304: c.noBody(),
 			...failResponses,
 		},
 		metadata: getSecurityMetadata(),
@@ -67,9 +81,11 @@ export const readerContract = c.router({
 		description: 'Partially update reading preferences for current user.',
 		method: 'PATCH',
 		path: '/api/v1/users/preferences',
+		headers: ZIfMatchHeaders,
 		body: ZUpdateUserPreferencesDTO,
 		responses: {
 			200: ZResponseWithData(ZUserPreferences),
+			412: ZPreconditionFailedResponse(ZUserPreferences),
 			...failResponses,
 		},
 		metadata: getSecurityMetadata(),
@@ -79,8 +95,10 @@ export const readerContract = c.router({
 		description: 'Get reader session state for current user.',
 		method: 'GET',
 		path: '/api/v1/users/reader-state',
+		headers: ZIfNoneMatchHeaders,
 		responses: {
 			200: ZResponseWithData(ZUserReaderState),
+			304: c.noBody(),
 			...failResponses,
 		},
 		metadata: getSecurityMetadata(),
@@ -90,9 +108,11 @@ export const readerContract = c.router({
 		description: 'Partially update current reader state for current user.',
 		method: 'PATCH',
 		path: '/api/v1/users/reader-state',
+		headers: ZIfMatchHeaders,
 		body: ZUpdateUserReaderStateDTO,
 		responses: {
 			200: ZResponseWithData(ZUserReaderState),
+			412: ZPreconditionFailedResponse(ZUserReaderState),
 			...failResponses,
 		},
 		metadata: getSecurityMetadata(),
@@ -1,6 +1,9 @@
 import {
 	ZGetManyQuery,
+	ZIfMatchHeaders,
+	ZIfNoneMatchHeaders,
 	ZPaginatedResponse,
+	ZPreconditionFailedResponse,
 	ZPreloadsQuery,
 	ZResponse,
 	ZResponseWithData,
@@ -21,7 +24,12 @@ type ResourceContractOptions = {
 		entity: z.ZodTypeAny
 		createDTO: z.ZodTypeAny
 		updateDTO: z.ZodTypeAny
+		// filter lists the fields getMany can be filtered by, see ZFieldFilter.
+		filter?: z.AnyZodObject
 	}
+	// versioned resources expose their rowVersion as an ETag and honor
+	// If-None-Match on getById and If-Match on update, destroy and kill.
+	versioned?: boolean
 	security?: boolean
 	securityType?: SecurityType
 }
@@ -37,10 +45,20 @@ export const createResourceContract = ({
 	resource,
 	resourcePlural,
 	schemas,
+	versioned = false,
 	security = true,
 	securityType = 'bearerOrCookie',
 }: ResourceContractOptions) => {
 	const metadata = getSecurityMetadata({ security, securityType })
+	const readPrecondition = versioned
+		? { headers: ZIfNoneMatchHeaders, responses: { 304: c.noBody() } }
+		: { headers: undefined, responses: {} }
+	const writePrecondition = versioned
+		? {
+				headers: ZIfMatchHeaders,
+				responses: { 412: ZPreconditionFailedResponse(schemas.entity) },
+			}
+		: { headers: undefined, responses: {} }
 
 	return c.router({
 		getMany: {
@@ -48,7 +66,9 @@ export const createResourceContract = ({
 			description: `Retrieve a paginated list of ${resourcePlural} that can be filtered, sorted, and preloaded.`,
 			path,
 			method: 'GET',
-			query: ZGetManyQuery,
+			query: schemas.filter
+				? ZGetManyQuery.extend({ filter: schemas.filter.optional() })
+				: ZGetManyQuery,
 			responses: {
 				200: ZPaginatedResponse(schemas.entity),
 				...failResponses,
@@ -61,9 +81,11 @@ export const createResourceContract = ({
 			path: `${path}/:id`,
 			method: 'GET',
 			pathParams: idParams,
+			headers: readPrecondition.headers,
 			query: ZPreloadsQuery,
 			responses: {
 				200: ZResponseWithData(schemas.entity),
+				...readPrecondition.responses,
 				...failResponses,
 			},
 			metadata,
@@ -86,9 +108,11 @@ export const createResourceContract = ({
 			path: `${path}/:id`,
 			method: 'PATCH',
 			pathParams: idParams,
+			headers: writePrecondition.headers,
 			body: schemas.updateDTO,
 			responses: {
 				200: ZResponseWithData(schemas.entity),
+				...writePrecondition.responses,
 				...failResponses,
 			},
 			metadata,
@@ -99,8 +123,10 @@ export const createResourceContract = ({
 			path: `${path}/:id`,
 			method: 'DELETE',
 			pathParams: idParams,
+			headers: writePrecondition.headers,
 			responses: {
 				200: ZResponse,
+				...writePrecondition.responses,
 				...failResponses,
 			},
 			metadata,
@@ -111,8 +137,10 @@ export const createResourceContract = ({
 			path: `${path}/:id/kill`,
 			method: 'DELETE',
 			pathParams: idParams,
+			headers: writePrecondition.headers,
 			responses: {
 				200: ZResponse,
+				...writePrecondition.responses,
 				...failResponses,
 			},
 			metadata,
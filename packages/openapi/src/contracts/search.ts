@@ -0,0 +1,21 @@
+import { ZPaginatedResponse, ZSearchHit, ZSearchQuery } from '@libra-link/zod'
+import { initContract } from '@ts-rest/core'
+import { failResponses, getSecurityMetadata } from '../utils.js'
+
+const c = initContract()
+
+export const searchContract = c.router({
+	search: {
+		summary: 'Search library or community',
+		description:
+			'Full-text search, best match first, of the ebooks of current user (`scope=library`, the default) by title, authors, tags, description and Google Books categories and publisher, or of the active public shares (`scope=community`) by their own title and description and those of their ebook. `q` is a web search query: it may quote phrases, join words with `or` and exclude them with `-`. Words are stemmed in the language of each ebook. `headline` is the title and `snippet` the best matching passages, with matches wrapped in `<mark>` tags.',
+		method: 'GET',
+		path: '/api/v1/search',
+		query: ZSearchQuery,
+		responses: {
+			200: ZPaginatedResponse(ZSearchHit),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+})
@@ -1,9 +1,13 @@
 import {
 	ZBorrow,
+	ZBorrowAccess,
+	ZBorrowLeaseKey,
 	ZBorrowShareDTO,
 	ZCreateShareReportDTO,
 	ZEmpty,
+	ZFile,
 	ZShare,
+	ZShareFilter,
 	ZShareReport,
 	ZShareReview,
 	ZStoreShareDTO,
@@ -29,6 +33,7 @@ export const shareContract = c.router({
 			entity: ZShare,
 			createDTO: ZStoreShareDTO,
 			updateDTO: ZUpdateShareDTO,
+			filter: ZShareFilter,
 		},
 	}),
 	borrow: {
@@ -57,6 +62,47 @@ export const shareContract = c.router({
 		},
 		metadata: getSecurityMetadata(),
 	},
+	getBorrowLeaseKey: {
+		summary: 'Get borrow lease key',
+		description: 'Get the public key used to verify borrow leases offline.',
+		method: 'GET',
+		path: '/api/v1/borrows/lease-key',
+		responses: {
+			200: ZResponseWithData(ZBorrowLeaseKey),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	issueBorrowAccess: {
+		summary: 'Issue borrow access',
+		description:
+			'Issue a signed lease for reading the borrowed ebook. The lease is short-lived and expires at the latest at the borrow due date; request a new one to renew it.',
+		method: 'POST',
+		path: '/api/v1/borrows/:id/access',
+		pathParams: idParams,
+		body: ZEmpty,
+		responses: {
+			200: ZResponseWithData(ZBorrowAccess),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	downloadBorrowedFile: {
+		summary: 'Download borrowed ebook file',
+		description:
+			'Stream the borrowed ebook file while the borrow is active and not past due.',
+		method: 'GET',
+		path: '/api/v1/borrows/:id/file',
+		pathParams: idParams,
+		responses: {
+			200: c.otherResponse({
+				contentType: 'application/octet-stream',
+				body: ZFile,
+			}),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
 	upsertReview: {
 		summary: 'Upsert share review',
 		description: 'Create or update the current user review for a share.',
@@ -72,7 +118,8 @@ export const shareContract = c.router({
 	},
 	createReport: {
 		summary: 'Create share report',
-		description: 'Report problematic share content.',
+		description:
+			'Report problematic share content. Once enough distinct users report a share for the same reason it is disabled pending review and its active borrows are flagged.',
 		method: 'POST',
 		path: '/api/v1/shares/:id/report',
 		pathParams: idParams,
@@ -1,11 +1,16 @@
 import {
-	ZPaginatedResponse,
+	ZCursorResponse,
 	ZResponseWithData,
 	ZStoreSyncEventDTO,
+	ZStoreSyncEventsDTO,
+	ZSyncConflictResponse,
 	ZSyncEvent,
+	ZSyncEventResult,
 	ZSyncEventsQuery,
+	ZSyncSnapshot,
 } from '@libra-link/zod'
 import { initContract } from '@ts-rest/core'
+import { z } from 'zod'
 import { failResponses, getSecurityMetadata } from '../utils.js'
 
 const c = initContract()
@@ -13,24 +18,67 @@ const c = initContract()
 export const syncContract = c.router({
 	storeEvent: {
 		summary: 'Store sync event',
-		description: 'Submit one offline sync event for current user.',
+		description:
+			'Submit one offline sync event for current user and apply it to the target entity. Returns 409 with the current server entity when the event conflicts. A preference or reader state event older than the stored state loses last-writer-wins and is returned without being applied or recorded.',
 		method: 'POST',
 		path: '/api/v1/sync/events',
 		body: ZStoreSyncEventDTO,
 		responses: {
 			201: ZResponseWithData(ZSyncEvent),
+			409: ZSyncConflictResponse,
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	storeEvents: {
+		summary: 'Store sync events in batch',
+		description:
+			'Submit up to 100 offline sync events in order. Each event is applied in its own transaction and reported as applied, duplicate, superseded, conflict or rejected.',
+		method: 'POST',
+		path: '/api/v1/sync/events:batch',
+		body: ZStoreSyncEventsDTO,
+		responses: {
+			200: ZResponseWithData(z.array(ZSyncEventResult)),
 			...failResponses,
 		},
 		metadata: getSecurityMetadata(),
 	},
 	listEvents: {
 		summary: 'List sync events',
-		description: 'List sync events for current user since optional timestamp.',
+		description:
+			'List sync events for current user, oldest first. Pass nextCursor back as cursor to continue; since is only used when no cursor is given.',
 		method: 'GET',
 		path: '/api/v1/sync/events',
 		query: ZSyncEventsQuery,
 		responses: {
-			200: ZPaginatedResponse(ZSyncEvent),
+			200: ZCursorResponse(ZSyncEvent),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	streamEvents: {
+		summary: 'Stream sync events',
+		description:
+			'Open a server-sent events stream that delivers each sync event stored for current user as a `sync` event whose data is the SyncEvent JSON and whose id is the sync cursor after it. Reconnecting with that id as `Last-Event-ID` first delivers the events missed since. The stream closes when the access token it was opened with expires, and once the account is banned or suspended or the device is revoked. Delivery is best-effort; clients should still list events from their checkpoint after reconnecting.',
+		method: 'GET',
+		path: '/api/v1/sync/events/stream',
+		responses: {
+			200: c.otherResponse({
+				contentType: 'text/event-stream',
+				body: z.string(),
+			}),
+			...failResponses,
+		},
+		metadata: getSecurityMetadata(),
+	},
+	snapshot: {
+		summary: 'Get sync snapshot',
+		description:
+			'Get the current state of every synced entity for current user. A new device stores it and then lists events with the returned cursor instead of replaying the full history.',
+		method: 'GET',
+		path: '/api/v1/sync/snapshot',
+		responses: {
+			200: ZResponseWithData(ZSyncSnapshot),
 			...failResponses,
 		},
 		metadata: getSecurityMetadata(),
@@ -1,4 +1,9 @@
-import { ZStoreUserDTO, ZUpdateUserDTO, ZUser } from '@libra-link/zod'
+import {
+	ZStoreUserDTO,
+	ZUpdateUserDTO,
+	ZUser,
+	ZUserFilter,
+} from '@libra-link/zod'
 
 import { createResourceContract } from './resource.js'
 
@@ -10,5 +15,6 @@ export const userContract = createResourceContract({
 		entity: ZUser,
 		createDTO: ZStoreUserDTO,
 		updateDTO: ZUpdateUserDTO,
+		filter: ZUserFilter,
 	},
 })
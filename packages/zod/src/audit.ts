@@ -0,0 +1,38 @@
+import { z } from 'zod'
+
+export const ZAuditAction = z.enum([
+	'report_resolved',
+	'report_rejected',
+	'share_taken_down',
+	'share_reinstated',
+	'borrow_revoked',
+	'authorization_policy_added',
+	'authorization_policy_removed',
+	'user_suspended',
+	'user_banned',
+	'user_reinstated',
+])
+export const ZAuditTargetType = z.enum(['share_report', 'share', 'borrow', 'authorization_policy', 'user'])
+
+export const ZAuditEvent = z.object({
+	id: z.string().uuid(),
+	actorUserId: z.string().uuid().optional(),
+	action: ZAuditAction,
+	targetType: ZAuditTargetType,
+	targetId: z.string().uuid().optional(),
+	metadata: z.record(z.any()).optional(),
+	createdAt: z.string().datetime(),
+})
+
+export const ZListAuditEventsQuery = z.object({
+	actorId: z.string().uuid().optional(),
+	action: ZAuditAction.optional(),
+	targetType: ZAuditTargetType.optional(),
+	targetId: z.string().uuid().optional(),
+	from: z.string().datetime().optional(),
+	to: z.string().datetime().optional(),
+	limit: z.coerce.number().int().nonnegative().optional(),
+	offset: z.coerce.number().int().nonnegative().optional(),
+	cursor: z.string().optional(),
+	count: z.enum(['true', 'false']).optional(),
+})
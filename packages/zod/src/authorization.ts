@@ -0,0 +1,8 @@
+import { z } from 'zod'
+
+export const ZAuthorizationPolicy = z.object({
+	subject: z.string().min(1).max(255),
+	object: z.string().min(1).max(255),
+	action: z.string().min(1).max(255),
+	effect: z.enum(['allow', 'deny']),
+})
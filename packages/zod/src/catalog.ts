@@ -0,0 +1,44 @@
+import { z } from 'zod'
+import { ZFieldFilter, ZModel, filterOperators } from './utils.js'
+
+export const ZAuthor = z
+	.object({
+		name: z.string(),
+	})
+	.extend(ZModel.shape)
+
+export const ZStoreAuthorDTO = z.object({
+	name: z.string().min(1).max(255),
+})
+
+export const ZUpdateAuthorDTO = z.object({
+	name: z.string().min(1).max(255).optional(),
+})
+
+export const ZCatalogFilter = z.object({
+	name: ZFieldFilter(z.string(), filterOperators.text),
+	createdAt: ZFieldFilter(z.string().datetime(), filterOperators.range),
+	updatedAt: ZFieldFilter(z.string().datetime(), filterOperators.range),
+})
+
+export const ZTag = z
+	.object({
+		name: z.string(),
+	})
+	.extend(ZModel.shape)
+
+export const ZStoreTagDTO = z.object({
+	name: z.string().min(1).max(64),
+})
+
+export const ZUpdateTagDTO = z.object({
+	name: z.string().min(1).max(64).optional(),
+})
+
+export const ZSetEbookAuthorsDTO = z.object({
+	authors: z.array(z.string().min(1).max(255)).max(50),
+})
+
+export const ZSetEbookTagsDTO = z.object({
+	tags: z.array(z.string().min(1).max(64)).max(50),
+})
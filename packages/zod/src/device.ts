@@ -0,0 +1,20 @@
+import { z } from 'zod'
+
+export const ZDevice = z.object({
+	id: z.string().uuid(),
+	userId: z.string().uuid(),
+	clientId: z.string(),
+	name: z.string(),
+	lastSeenAt: z.string().datetime(),
+	lastSyncedAt: z.string().datetime().optional(),
+	syncServerTimestamp: z.string().datetime().optional(),
+	syncEventId: z.string().uuid().optional(),
+	revokedAt: z.string().datetime().optional(),
+	createdAt: z.string().datetime(),
+	updatedAt: z.string().datetime(),
+	current: z.boolean(),
+})
+
+export const ZRenameDeviceDTO = z.object({
+	name: z.string().min(1).max(100),
+})
@@ -1,5 +1,12 @@
 import { z } from 'zod'
-import { ZModel } from './utils.js'
+import { ZAuthor, ZTag } from './catalog.js'
+import {
+	ZFieldFilter,
+	ZFile,
+	ZGetManyQuery,
+	ZModel,
+	filterOperators,
+} from './utils.js'
 
 export const ZEbookFormat = z.enum(['epub', 'pdf', 'txt'])
 
@@ -15,9 +22,29 @@ export const ZEbook =
 			fileSizeBytes: z.number().int(),
 			checksumSha256: z.string(),
 			importedAt: z.string().datetime(),
+			authors: z.array(ZAuthor).optional(),
+			tags: z.array(ZTag).optional(),
 		})
 		.extend(ZModel.shape)
 
+export const ZEbookFilter = z.object({
+	title: ZFieldFilter(z.string(), filterOperators.text),
+	format: ZFieldFilter(ZEbookFormat, filterOperators.exact),
+	languageCode: ZFieldFilter(z.string(), [...filterOperators.text, 'null']),
+	fileSizeBytes: ZFieldFilter(z.coerce.number().int(), filterOperators.range),
+	importedAt: ZFieldFilter(z.string().datetime(), filterOperators.range),
+	createdAt: ZFieldFilter(z.string().datetime(), filterOperators.range),
+	updatedAt: ZFieldFilter(z.string().datetime(), filterOperators.range),
+})
+
+export const ZGetManyEbooksQuery = ZGetManyQuery.extend({
+	authorId: z.string().optional(),
+	author: z.string().optional(),
+	tagId: z.string().optional(),
+	tag: z.string().optional(),
+	filter: ZEbookFilter.optional(),
+})
+
 export const ZStoreEbookDTO = z.object({
 	title: z.string().min(1).max(255),
 	description: z.string().optional(),
@@ -29,6 +56,15 @@ export const ZStoreEbookDTO = z.object({
 	importedAt: z.string().datetime().optional(),
 })
 
+export const ZUploadEbookFileDTO = z.object({
+	file: ZFile,
+})
+
+export const ZEbookFileURL = z.object({
+	url: z.string(),
+	expiresAt: z.string().datetime(),
+})
+
 export const ZUpdateEbookDTO = z.object({
 	title: z.string().min(1).max(255).optional(),
 	description: z.string().optional(),
@@ -52,6 +88,55 @@ export const ZEbookGoogleMetadata = z.object({
 	deletedAt: z.string().datetime().optional(),
 })
 
+export const ZGoogleBooksSearchQuery = z.object({
+	q: z.string().optional(),
+	isbn: z.string().optional(),
+	limit: z.coerce.number().int().nonnegative().max(40).optional(),
+})
+
+export const ZGoogleBooksVolume = z.object({
+	id: z.string(),
+	title: z.string(),
+	subtitle: z.string().optional(),
+	authors: z.array(z.string()),
+	description: z.string().optional(),
+	publisher: z.string().optional(),
+	publishedDate: z.string().optional(),
+	pageCount: z.number().int().optional(),
+	categories: z.array(z.string()),
+	languageCode: z.string().optional(),
+	isbn10: z.string().optional(),
+	isbn13: z.string().optional(),
+	thumbnailUrl: z.string().url().optional(),
+	infoLink: z.string().url().optional(),
+})
+
+export const ZEbookMetadataSuggestion = z.object({
+	id: z.string().uuid(),
+	ebookId: z.string().uuid(),
+	googleBooksId: z.string(),
+	title: z.string(),
+	authors: z.array(z.string()),
+	publishedDate: z.string().optional(),
+	isbn13: z.string().optional(),
+	thumbnailUrl: z.string().url().optional(),
+	score: z.number().min(0).max(1),
+	createdAt: z.string().datetime(),
+})
+
+export const ZEbookPassageHit = z.object({
+	position: z.number().int().nonnegative(),
+	location: z.string(),
+	snippet: z.string(),
+	rank: z.number(),
+})
+
+export const ZSearchEbookContentQuery = z.object({
+	q: z.string().min(1).max(256),
+	limit: z.coerce.number().int().nonnegative().optional(),
+	offset: z.coerce.number().int().nonnegative().optional(),
+})
+
 export const ZAttachGoogleMetadataDTO = z.object({
 	googleBooksId: z.string().min(1),
 	isbn10: z.string().length(10).optional(),
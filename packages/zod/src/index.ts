@@ -1,7 +1,12 @@
+export * from './audit.js'
+export * from './authorization.js'
 export * from './auth.js'
+export * from './catalog.js'
+export * from './device.js'
 export * from './ebook.js'
 export * from './health.js'
 export * from './reader.js'
+export * from './search.js'
 export * from './share.js'
 export * from './sync.js'
 export * from './user.js'
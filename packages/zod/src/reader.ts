@@ -1,5 +1,5 @@
 import { z } from 'zod'
-import { ZModel } from './utils.js'
+import { ZFieldFilter, ZModel, filterOperators } from './utils.js'
 
 export const ZReadingMode = z.enum(['normal', 'zen'])
 export const ZThemeMode = z.enum(['light', 'dark', 'sepia', 'high_contrast'])
@@ -18,6 +18,15 @@ export const ZReadingProgress =
 		})
 		.extend(ZModel.shape)
 
+export const ZReadingProgressFilter = z.object({
+	ebookId: ZFieldFilter(z.string().uuid(), filterOperators.exact),
+	readingMode: ZFieldFilter(ZReadingMode, filterOperators.exact),
+	progressPercent: ZFieldFilter(z.coerce.number(), [...filterOperators.range, 'null']),
+	lastReadAt: ZFieldFilter(z.string().datetime(), [...filterOperators.range, 'null']),
+	createdAt: ZFieldFilter(z.string().datetime(), filterOperators.range),
+	updatedAt: ZFieldFilter(z.string().datetime(), filterOperators.range),
+})
+
 export const ZStoreReadingProgressDTO = z.object({
 	ebookId: z.string().uuid(),
 	location: z.string().min(1),
@@ -44,6 +53,13 @@ export const ZBookmark =
 		})
 		.extend(ZModel.shape)
 
+export const ZBookmarkFilter = z.object({
+	ebookId: ZFieldFilter(z.string().uuid(), filterOperators.exact),
+	label: ZFieldFilter(z.string(), [...filterOperators.text, 'null']),
+	createdAt: ZFieldFilter(z.string().datetime(), filterOperators.range),
+	updatedAt: ZFieldFilter(z.string().datetime(), filterOperators.range),
+})
+
 export const ZStoreBookmarkDTO = z.object({
 	ebookId: z.string().uuid(),
 	location: z.string().min(1),
@@ -68,6 +84,13 @@ export const ZAnnotation =
 		})
 		.extend(ZModel.shape)
 
+export const ZAnnotationFilter = z.object({
+	ebookId: ZFieldFilter(z.string().uuid(), filterOperators.exact),
+	color: ZFieldFilter(z.string(), [...filterOperators.text, 'null']),
+	createdAt: ZFieldFilter(z.string().datetime(), filterOperators.range),
+	updatedAt: ZFieldFilter(z.string().datetime(), filterOperators.range),
+})
+
 export const ZStoreAnnotationDTO = z.object({
 	ebookId: z.string().uuid(),
 	locationStart: z.string().min(1),
@@ -0,0 +1,20 @@
+import { z } from 'zod'
+
+export const ZSearchScope = z.enum(['library', 'community'])
+
+export const ZSearchHit = z.object({
+	scope: ZSearchScope,
+	ebookId: z.string().uuid(),
+	shareId: z.string().uuid().optional(),
+	title: z.string(),
+	headline: z.string(),
+	snippet: z.string(),
+	rank: z.number(),
+})
+
+export const ZSearchQuery = z.object({
+	q: z.string().min(1).max(256),
+	scope: ZSearchScope.optional(),
+	limit: z.coerce.number().int().nonnegative().optional(),
+	offset: z.coerce.number().int().nonnegative().optional(),
+})
@@ -1,5 +1,5 @@
 import { z } from 'zod'
-import { ZModel } from './utils.js'
+import { ZFieldFilter, ZModel, filterOperators } from './utils.js'
 
 export const ZShareVisibility = z.enum(['public', 'unlisted'])
 export const ZShareStatus = z.enum(['active', 'disabled', 'removed'])
@@ -21,12 +21,22 @@ export const ZShare =
 		})
 		.extend(ZModel.shape)
 
+export const ZShareFilter = z.object({
+	ebookId: ZFieldFilter(z.string().uuid(), filterOperators.exact),
+	ownerUserId: ZFieldFilter(z.string().uuid(), filterOperators.exact),
+	visibility: ZFieldFilter(ZShareVisibility, filterOperators.exact),
+	status: ZFieldFilter(ZShareStatus, filterOperators.exact),
+	borrowDurationHours: ZFieldFilter(z.coerce.number().int(), filterOperators.range),
+	maxConcurrentBorrows: ZFieldFilter(z.coerce.number().int(), filterOperators.range),
+	createdAt: ZFieldFilter(z.string().datetime(), filterOperators.range),
+	updatedAt: ZFieldFilter(z.string().datetime(), filterOperators.range),
+})
+
 export const ZStoreShareDTO = z.object({
 	ebookId: z.string().uuid(),
 	titleOverride: z.string().max(255).optional(),
 	description: z.string().optional(),
 	visibility: ZShareVisibility.optional(),
-	status: ZShareStatus.optional(),
 	borrowDurationHours: z.number().int().positive(),
 	maxConcurrentBorrows: z.number().int().positive().optional(),
 })
@@ -35,7 +45,6 @@ export const ZUpdateShareDTO = z.object({
 	titleOverride: z.string().max(255).optional(),
 	description: z.string().optional(),
 	visibility: ZShareVisibility.optional(),
-	status: ZShareStatus.optional(),
 	borrowDurationHours: z.number().int().positive().optional(),
 	maxConcurrentBorrows: z.number().int().positive().optional(),
 })
@@ -52,6 +61,19 @@ export const ZBorrow = z.object({
 	expiredAt: z.string().datetime().optional(),
 	status: ZBorrowStatus,
 	legalAcknowledgedAt: z.string().datetime(),
+	flaggedAt: z.string().datetime().optional(),
+})
+
+export const ZBorrowAccess = z.object({
+	borrowId: z.string().uuid(),
+	ebookId: z.string().uuid(),
+	lease: z.string(),
+	expiresAt: z.string().datetime(),
+})
+
+export const ZBorrowLeaseKey = z.object({
+	algorithm: z.string(),
+	publicKey: z.string(),
 })
 
 export const ZBorrowShareDTO = z.object({
@@ -91,3 +113,37 @@ export const ZCreateShareReportDTO = z.object({
 	reason: ZReportReason,
 	details: z.string().optional(),
 })
+
+export const ZResolveShareReportDTO = z.object({
+	note: z.string().min(1).max(2000),
+	shareStatus: z.enum(['disabled', 'removed']).optional(),
+})
+
+export const ZRejectShareReportDTO = z.object({
+	note: z.string().min(1).max(2000),
+})
+
+export const ZShareReportResolution = z.object({
+	report: ZShareReport,
+	share: ZShare.optional(),
+	revokedBorrows: z.array(ZBorrow),
+	resolvedReports: z.array(ZShareReport),
+})
+
+export const ZReinstateShareDTO = z.object({
+	note: z.string().min(1).max(2000),
+})
+
+export const ZShareReinstatement = z.object({
+	share: ZShare,
+	dismissedReports: z.array(ZShareReport),
+})
+
+export const ZListShareReportsQuery = z.object({
+	status: z.string().optional(),
+	reason: ZReportReason.optional(),
+	limit: z.coerce.number().int().nonnegative().optional(),
+	offset: z.coerce.number().int().nonnegative().optional(),
+	cursor: z.string().optional(),
+	count: z.enum(['true', 'false']).optional(),
+})
@@ -1,5 +1,12 @@
 import { z } from 'zod'
-import { ZGetManyQuery } from './utils.js'
+import {
+	ZAnnotation,
+	ZBookmark,
+	ZReadingProgress,
+	ZUserPreferences,
+	ZUserReaderState,
+} from './reader.js'
+import { ZResponse } from './utils.js'
 
 export const ZSyncEntityType = z.enum([
 	'progress',
@@ -34,6 +41,52 @@ export const ZStoreSyncEventDTO = z.object({
 	idempotencyKey: z.string().min(8),
 })
 
-export const ZSyncEventsQuery = ZGetManyQuery.extend({
+export const ZStoreSyncEventsDTO = z.object({
+	events: z.array(ZStoreSyncEventDTO).min(1).max(100),
+})
+
+export const ZSyncEventsQuery = z.object({
+	limit: z.coerce.number().int().nonnegative().max(200).optional(),
 	since: z.string().datetime().optional(),
+	cursor: z.string().optional(),
+})
+
+export const ZSyncConflict = z.object({
+	entityType: ZSyncEntityType,
+	entityId: z.string().uuid(),
+	current: z.record(z.any()).optional(),
+})
+
+export const ZSyncConflictResponse = ZResponse.extend({
+	status: z.literal(409),
+	message: z
+		.string()
+		.default('sync event conflicts with the current server state'),
+	success: z.literal(false),
+	data: ZSyncConflict,
+})
+
+export const ZSyncEventStatus = z.enum([
+	'applied',
+	'duplicate',
+	'superseded',
+	'conflict',
+	'rejected',
+])
+
+export const ZSyncEventResult = z.object({
+	idempotencyKey: z.string(),
+	status: ZSyncEventStatus,
+	event: ZSyncEvent.optional(),
+	conflict: ZSyncConflict.optional(),
+	message: z.string().optional(),
+})
+
+export const ZSyncSnapshot = z.object({
+	cursor: z.string(),
+	readingProgress: z.array(ZReadingProgress),
+	bookmarks: z.array(ZBookmark),
+	annotations: z.array(ZAnnotation),
+	preferences: ZUserPreferences.optional(),
+	readerState: ZUserReaderState.optional(),
 })
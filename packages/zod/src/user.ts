@@ -1,5 +1,6 @@
 import { z } from 'zod'
-import { ZModel } from './utils.js'
+import { ZShare } from './share.js'
+import { ZFieldFilter, ZModel, filterOperators } from './utils.js'
 
 export const ZUser = z
 	.object({
@@ -9,6 +10,11 @@ export const ZUser = z
 		emailVerifiedAt: z.string().datetime().optional(),
 		lastLoginAt: z.string().datetime().optional(),
 		isAdmin: z.boolean().default(false),
+		suspendedAt: z.string().datetime().optional(),
+		suspendedUntil: z.string().datetime().optional(),
+		suspensionReason: z.string().optional(),
+		bannedAt: z.string().datetime().optional(),
+		banReason: z.string().optional(),
 	})
 	.extend(ZModel.shape)
 
@@ -24,3 +30,33 @@ export const ZUpdateUserDTO = ZUser.pick({
 	email: true,
 	username: true,
 })
+
+export const ZSuspendUserDTO = z.object({
+	reason: z.string().min(1).max(2000),
+	until: z.string().datetime().optional(),
+})
+
+export const ZBanUserDTO = z.object({
+	reason: z.string().min(1).max(2000),
+})
+
+export const ZReinstateUserDTO = z.object({
+	note: z.string().min(1).max(2000),
+})
+
+export const ZUserRestriction = z.object({
+	user: ZUser,
+	disabledShares: z.array(ZShare),
+})
+
+export const ZUserFilter = z.object({
+	email: ZFieldFilter(z.string(), filterOperators.text),
+	username: ZFieldFilter(z.string(), filterOperators.text),
+	isAdmin: ZFieldFilter(z.enum(['true', 'false']), filterOperators.bool),
+	emailVerifiedAt: ZFieldFilter(z.string().datetime(), [...filterOperators.range, 'null']),
+	lastLoginAt: ZFieldFilter(z.string().datetime(), [...filterOperators.range, 'null']),
+	suspendedAt: ZFieldFilter(z.string().datetime(), [...filterOperators.range, 'null']),
+	bannedAt: ZFieldFilter(z.string().datetime(), [...filterOperators.range, 'null']),
+	createdAt: ZFieldFilter(z.string().datetime(), filterOperators.range),
+	updatedAt: ZFieldFilter(z.string().datetime(), filterOperators.range),
+})
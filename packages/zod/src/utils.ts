@@ -8,6 +8,8 @@ export const ZResponse = z.object({
 
 export const ZEmpty = z.object({}).strict()
 
+export const ZFile = z.object({ type: z.literal('file') })
+
 export function ZResponseWithData<T>(schema: z.ZodSchema<T>) {
 	return z.object({ data: schema }).extend(ZResponse.shape)
 }
@@ -19,6 +21,8 @@ export function ZPaginatedResponse<T>(schema: z.ZodSchema<T>) {
 			page: z.number(),
 			limit: z.number().default(20),
 			totalPages: z.number().default(Math.ceil(946 / 20)),
+			nextCursor: z.string().optional(),
+			hasMore: z.boolean(),
 			data: z.array(schema),
 			message: z.string().default('Fetched paginated data successfully!'),
 			status: z.literal(200),
@@ -26,6 +30,19 @@ export function ZPaginatedResponse<T>(schema: z.ZodSchema<T>) {
 	)
 }
 
+export function ZCursorResponse<T>(schema: z.ZodSchema<T>) {
+	return ZResponse.extend(
+		z.object({
+			limit: z.number().default(100),
+			nextCursor: z.string().optional(),
+			hasMore: z.boolean(),
+			data: z.array(schema),
+			message: z.string().default('Fetched data successfully!'),
+			status: z.literal(200),
+		}).shape
+	)
+}
+
 export const ZModel = z.object({
 	id: z.string().uuid(),
 	createdAt: z.string().datetime(),
@@ -39,8 +56,45 @@ export const ZGetManyQuery = z.object({
 	preloads: z.string().optional(),
 	orderBy: z.string().optional(),
 	orderDirection: z.enum(['asc', 'desc']).optional(),
+	cursor: z.string().optional(),
+	count: z.enum(['true', 'false']).optional(),
 })
 
+export const ZFilterOperator = z.enum(['eq', 'ne', 'in', 'gt', 'gte', 'lt', 'lte', 'like', 'null'])
+
+type FilterOperator = z.infer<typeof ZFilterOperator>
+
+// The operators each kind of field accepts; nullable fields also accept `null`.
+export const filterOperators = {
+	text: ['eq', 'ne', 'in', 'like'],
+	exact: ['eq', 'ne', 'in'],
+	range: ['eq', 'ne', 'in', 'gt', 'gte', 'lt', 'lte'],
+	bool: ['eq', 'ne'],
+} satisfies Record<string, FilterOperator[]>
+
+/**
+ * Describes a filterable field of a listing, sent as `filter[field][op]=value`
+ * (or `filter[field]=value` for `eq`). `in` takes a comma-separated list and
+ * `null` takes `true` or `false`.
+ */
+export function ZFieldFilter(value: z.ZodTypeAny, operators: FilterOperator[]) {
+	return z
+		.object(
+			Object.fromEntries(
+				operators.map((operator) => {
+					if (operator === 'in' || operator === 'like') {
+						return [operator, z.string().optional()]
+					}
+					if (operator === 'null') {
+						return [operator, z.enum(['true', 'false']).optional()]
+					}
+					return [operator, value.optional()]
+				})
+			)
+		)
+		.optional()
+}
+
 export const ZPreloadsQuery = z.object({
 	preloads: z.string().optional(),
 })
@@ -67,6 +121,26 @@ export const ZNotFoundResponse = ZResponse.extend({
 	success: z.literal(false),
 })
 
+// Conditional request headers of versioned resources, whose ETag is their
+// rowVersion. If-Match makes a change conditional on the version a client
+// read, and If-None-Match answers a read with 304 when it is unchanged.
+export const ZIfMatchHeaders = z.object({
+	'if-match': z.string().optional(),
+})
+
+export const ZIfNoneMatchHeaders = z.object({
+	'if-none-match': z.string().optional(),
+})
+
+export function ZPreconditionFailedResponse<T>(schema: z.ZodSchema<T>) {
+	return ZResponse.extend({
+		status: z.literal(412),
+		message: z.string().default('resource was modified since it was read'),
+		success: z.literal(false),
+		data: schema,
+	})
+}
+
 export const ZInternalServerErrorResponse = ZResponse.extend({
 	status: z.literal(500),
 	message: z